@@ -6,11 +6,15 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"database/sql"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"text/tabwriter"
@@ -22,8 +26,12 @@ import (
 	"github.com/bbengfort/epistolary/pkg/api/v1"
 	"github.com/bbengfort/epistolary/pkg/server"
 	"github.com/bbengfort/epistolary/pkg/server/config"
+	"github.com/bbengfort/epistolary/pkg/server/db"
 	"github.com/bbengfort/epistolary/pkg/server/db/schema"
+	"github.com/bbengfort/epistolary/pkg/server/epistles"
+	"github.com/bbengfort/epistolary/pkg/server/exports"
 	"github.com/bbengfort/epistolary/pkg/server/fetch"
+	"github.com/bbengfort/epistolary/pkg/server/tokens"
 	"github.com/joho/godotenv"
 	ulid "github.com/oklog/ulid/v2"
 	confire "github.com/rotationalio/confire/usage"
@@ -59,6 +67,12 @@ func main() {
 				Usage:   "password for authenticating client requests",
 				EnvVars: []string{"EPISTOLARY_PASSWORD"},
 			},
+			&cli.StringFlag{
+				Name:    "api-key",
+				Aliases: []string{"K"},
+				Usage:   "personal api key for authenticating client requests instead of a password",
+				EnvVars: []string{"EPISTOLARY_API_KEY"},
+			},
 		},
 		Commands: []*cli.Command{
 			{
@@ -111,6 +125,40 @@ func main() {
 					},
 				},
 			},
+			{
+				Name:     "resync",
+				Usage:    "run one pass syncing epistles with stale metadata",
+				Category: "database",
+				Action:   resync,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "dsn",
+						Aliases: []string{"d", "db"},
+						Usage:   "database dsn to connect to the database on",
+						EnvVars: []string{"DATABASE_URL", "EPISTOLARY_DATABASE_URL"},
+					},
+					&cli.DurationFlag{
+						Name:    "stale",
+						Aliases: []string{"s"},
+						Usage:   "resync epistles that have not been synced for this long",
+						Value:   7 * 24 * time.Hour,
+						EnvVars: []string{"EPISTOLARY_SYNC_STALE_AFTER"},
+					},
+					&cli.IntFlag{
+						Name:    "limit",
+						Aliases: []string{"l"},
+						Usage:   "maximum number of stale epistles to queue",
+						Value:   500,
+					},
+					&cli.Int64Flag{
+						Name:    "max-attempts",
+						Aliases: []string{"m"},
+						Usage:   "number of times a sync is attempted before giving up",
+						Value:   8,
+						EnvVars: []string{"EPISTOLARY_SYNC_MAX_ATTEMPTS"},
+					},
+				},
+			},
 			{
 				Name:     "config",
 				Usage:    "print epistolary configuration guide",
@@ -124,6 +172,128 @@ func main() {
 					},
 				},
 			},
+			{
+				Name:     "dedupe",
+				Usage:    "merge duplicate epistles that have the same normalized or canonical link",
+				Category: "admin",
+				Action:   dedupe,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "dsn",
+						Aliases: []string{"d", "db"},
+						Usage:   "database dsn to connect to the database on",
+						EnvVars: []string{"DATABASE_URL", "EPISTOLARY_DATABASE_URL"},
+					},
+					&cli.BoolFlag{
+						Name:    "dry-run",
+						Aliases: []string{"n"},
+						Usage:   "print the duplicates that would be merged without merging them",
+					},
+				},
+			},
+			{
+				Name:     "users",
+				Usage:    "manage the accounts of epistolary users (requires the admin:cms permission)",
+				Category: "admin",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "list",
+						Usage:  "list users, optionally searching by username, email, or name",
+						Action: adminListUsers,
+						Flags:  adminPageFlags,
+					},
+					{
+						Name:      "show",
+						Usage:     "show the account of a user",
+						ArgsUsage: "id",
+						Action:    adminShowUser,
+					},
+					{
+						Name:      "role",
+						Usage:     "assign a role to a user",
+						ArgsUsage: "id role",
+						Action:    adminSetRole,
+					},
+					{
+						Name:      "disable",
+						Usage:     "prevent a user from logging in without deleting their data",
+						ArgsUsage: "id",
+						Action:    adminSetDisabled(true),
+					},
+					{
+						Name:      "enable",
+						Usage:     "allow a disabled user to login again",
+						ArgsUsage: "id",
+						Action:    adminSetDisabled(false),
+					},
+					{
+						Name:      "impersonate",
+						Usage:     "print a short-lived access token to act as a user for support",
+						ArgsUsage: "id",
+						Action:    adminImpersonate,
+					},
+					{
+						Name:   "roles",
+						Usage:  "list the roles that can be assigned to users",
+						Action: adminListRoles,
+					},
+				},
+			},
+			{
+				Name:     "epistles",
+				Usage:    "browse the epistles of all users (requires the admin:cms permission)",
+				Category: "admin",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "list",
+						Usage:  "list epistles, optionally filtered by a full-text query",
+						Action: adminListEpistles,
+						Flags:  adminPageFlags,
+					},
+				},
+			},
+			{
+				Name:     "oauth-clients",
+				Usage:    "manage the third-party clients that authenticate users with oauth (requires the admin:cms permission)",
+				Category: "admin",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "list",
+						Usage:  "list the registered oauth clients",
+						Action: adminListOAuthClients,
+					},
+					{
+						Name:   "create",
+						Usage:  "register a new oauth client, printing its client id and secret",
+						Action: adminCreateOAuthClient,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "name",
+								Aliases:  []string{"n"},
+								Usage:    "name of the client application",
+								Required: true,
+							},
+							&cli.StringSliceFlag{
+								Name:     "redirect-uri",
+								Aliases:  []string{"r"},
+								Usage:    "uri that users are redirected to after they authorize the client",
+								Required: true,
+							},
+							&cli.BoolFlag{
+								Name:    "public",
+								Aliases: []string{"p"},
+								Usage:   "create a public client without a secret, e.g. a browser extension",
+							},
+						},
+					},
+					{
+						Name:      "delete",
+						Usage:     "remove an oauth client so that it can no longer authenticate users",
+						ArgsUsage: "client_id",
+						Action:    adminDeleteOAuthClient,
+					},
+				},
+			},
 			{
 				Name:     "tokenkey",
 				Usage:    "generate an RSA token key pair and ksuid for JWT token signing",
@@ -142,6 +312,29 @@ func main() {
 						Value:   4096,
 					},
 				},
+				Subcommands: []*cli.Command{
+					{
+						Name:   "rotate",
+						Usage:  "generate a new signing key in the key directory of the server",
+						Action: rotateTokenKey,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "dir",
+								Aliases:  []string{"d"},
+								Usage:    "directory of automatically rotated signing keys",
+								EnvVars:  []string{"EPISTOLARY_TOKEN_KEY_DIR"},
+								Required: true,
+							},
+							&cli.IntFlag{
+								Name:    "size",
+								Aliases: []string{"s"},
+								Usage:   "number of bits for the generated keys",
+								EnvVars: []string{"EPISTOLARY_TOKEN_KEY_SIZE"},
+								Value:   4096,
+							},
+						},
+					},
+				},
 			},
 			{
 				Name:     "status",
@@ -157,6 +350,324 @@ func main() {
 				Action:   register,
 				Flags:    []cli.Flag{},
 			},
+			{
+				Name:      "verify",
+				Usage:     "verify your email address with the token from the verification email",
+				ArgsUsage: "token",
+				Category:  "client",
+				Action:    verifyEmail,
+			},
+			{
+				Name:     "forgot-password",
+				Usage:    "email a password reset token to the address of your account",
+				Category: "client",
+				Action:   forgotPassword,
+			},
+			{
+				Name:      "reset-password",
+				Usage:     "choose a new password with the token from the password reset email",
+				ArgsUsage: "token",
+				Category:  "client",
+				Action:    resetPassword,
+			},
+			{
+				Name:     "account",
+				Usage:    "view and manage your epistolary account",
+				Category: "client",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "show",
+						Usage:  "show your account profile",
+						Action: showAccount,
+					},
+					{
+						Name:   "update",
+						Usage:  "change the name, email, or username of your account",
+						Action: updateAccount,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:    "name",
+								Aliases: []string{"n"},
+								Usage:   "the new full name for your account",
+							},
+							&cli.StringFlag{
+								Name:    "email",
+								Aliases: []string{"e"},
+								Usage:   "the new email address, which must be verified again",
+							},
+							&cli.StringFlag{
+								Name:  "new-username",
+								Usage: "the new username to login with",
+							},
+						},
+					},
+					{
+						Name:   "password",
+						Usage:  "change your password and sign out of all other sessions",
+						Action: changePassword,
+					},
+					{
+						Name:   "delete",
+						Usage:  "permanently delete your account and all of your readings",
+						Action: deleteAccount,
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:    "yes",
+								Aliases: []string{"y"},
+								Usage:   "delete the account without confirming",
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:     "mfa",
+				Usage:    "manage multi-factor authentication with an authenticator app",
+				Category: "client",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "status",
+						Usage:  "show if multi-factor authentication is enabled",
+						Action: mfaStatus,
+					},
+					{
+						Name:   "enroll",
+						Usage:  "create a secret to add to your authenticator app",
+						Action: enrollMFA,
+					},
+					{
+						Name:      "confirm",
+						Usage:     "enable multi-factor authentication with a code from your authenticator",
+						ArgsUsage: "code",
+						Action:    confirmMFA,
+					},
+					{
+						Name:   "disable",
+						Usage:  "disable multi-factor authentication and delete your recovery codes",
+						Action: disableMFA,
+					},
+					{
+						Name:   "recovery-codes",
+						Usage:  "replace your recovery codes with new codes",
+						Action: regenerateRecoveryCodes,
+					},
+				},
+			},
+			{
+				Name:     "sessions",
+				Usage:    "view and revoke the devices you are logged in from",
+				Category: "client",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "list",
+						Usage:  "list the sessions of your account",
+						Action: listSessions,
+					},
+					{
+						Name:      "revoke",
+						Usage:     "log out of the device of a session",
+						ArgsUsage: "id",
+						Action:    revokeSession,
+					},
+				},
+			},
+			{
+				Name:     "apikeys",
+				Usage:    "manage personal api keys for scripts and integrations",
+				Category: "client",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "list",
+						Usage:  "list your api keys and their permissions",
+						Action: listAPIKeys,
+					},
+					{
+						Name:   "create",
+						Usage:  "create an api key with a subset of your permissions",
+						Action: createAPIKey,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "name",
+								Aliases:  []string{"n"},
+								Usage:    "a name to identify the api key",
+								Required: true,
+							},
+							&cli.StringSliceFlag{
+								Name:     "permission",
+								Aliases:  []string{"p"},
+								Usage:    "a permission to grant the api key (e.g. epistles:read), may be repeated",
+								Required: true,
+							},
+							&cli.DurationFlag{
+								Name:    "expires",
+								Aliases: []string{"e"},
+								Usage:   "how long until the api key expires",
+								Value:   90 * 24 * time.Hour,
+							},
+						},
+					},
+					{
+						Name:      "show",
+						Usage:     "show an api key and its permissions",
+						ArgsUsage: "id",
+						Action:    showAPIKey,
+					},
+					{
+						Name:      "update",
+						Usage:     "change the name and permissions of an api key",
+						ArgsUsage: "id",
+						Action:    updateAPIKey,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:    "name",
+								Aliases: []string{"n"},
+								Usage:   "the new name of the api key",
+							},
+							&cli.StringSliceFlag{
+								Name:    "permission",
+								Aliases: []string{"p"},
+								Usage:   "replace the permissions of the api key, may be repeated",
+							},
+						},
+					},
+					{
+						Name:      "revoke",
+						Usage:     "delete an api key so that it can no longer be used",
+						ArgsUsage: "id",
+						Action:    revokeAPIKey,
+					},
+				},
+			},
+			{
+				Name:     "tags",
+				Usage:    "manage the tags used to organize your readings",
+				Category: "client",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "list",
+						Usage:  "list your tags and the number of readings with each tag",
+						Action: listTags,
+					},
+					{
+						Name:      "create",
+						Usage:     "create a new tag",
+						ArgsUsage: "name",
+						Action:    createTag,
+					},
+					{
+						Name:      "rename",
+						Usage:     "rename a tag, keeping it on all of its readings",
+						ArgsUsage: "id name",
+						Action:    renameTag,
+					},
+					{
+						Name:      "delete",
+						Usage:     "delete a tag, removing it from all of its readings",
+						ArgsUsage: "id",
+						Action:    deleteTag,
+					},
+				},
+			},
+			{
+				Name:      "import",
+				Usage:     "import readings from a Pocket, Instapaper, Pinboard or bookmarks export",
+				ArgsUsage: "path",
+				Category:  "client",
+				Action:    importReadings,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "format of the export (pocket-html, pocket-csv, instapaper-csv, pinboard-json, netscape-html); detected if omitted",
+					},
+				},
+			},
+			{
+				Name:     "subscriptions",
+				Aliases:  []string{"subs"},
+				Usage:    "manage the rss and atom feeds whose new posts are queued as readings",
+				Category: "client",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "list",
+						Usage:  "list your subscriptions and when they were last polled",
+						Action: listSubscriptions,
+					},
+					{
+						Name:      "add",
+						Usage:     "subscribe to a feed to queue its new posts",
+						ArgsUsage: "url",
+						Action:    addSubscription,
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:    "paused",
+								Aliases: []string{"p"},
+								Usage:   "add the subscription without polling it until it is resumed",
+							},
+						},
+					},
+					{
+						Name:      "pause",
+						Usage:     "stop polling a subscription without removing it",
+						ArgsUsage: "id",
+						Action:    pauseSubscription(true),
+					},
+					{
+						Name:      "resume",
+						Usage:     "resume polling a paused subscription",
+						ArgsUsage: "id",
+						Action:    pauseSubscription(false),
+					},
+					{
+						Name:      "remove",
+						Usage:     "unsubscribe from a feed, keeping the readings queued from it",
+						ArgsUsage: "id",
+						Action:    removeSubscription,
+					},
+				},
+			},
+			{
+				Name:     "feeds",
+				Usage:    "manage the tokens of the private atom and rss feeds of your reading list",
+				Category: "client",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "list",
+						Usage:  "list your feed tokens and when they were last used",
+						Action: listFeedTokens,
+					},
+					{
+						Name:   "create",
+						Usage:  "create a feed token and print the urls of your feeds",
+						Action: createFeedToken,
+					},
+					{
+						Name:      "revoke",
+						Usage:     "revoke a feed token so its feeds can no longer be read",
+						ArgsUsage: "id",
+						Action:    revokeFeedToken,
+					},
+				},
+			},
+			{
+				Name:     "export",
+				Usage:    "export your readings as json lines, csv, netscape bookmarks or opml",
+				Category: "client",
+				Action:   exportReadings,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "format of the export (jsonl, csv, netscape, opml)",
+						Value:   "jsonl",
+					},
+					&cli.StringFlag{
+						Name:    "out",
+						Aliases: []string{"o"},
+						Usage:   "path to save the export to (uses the filename from the server by default)",
+					},
+				},
+			},
 			{
 				Name:      "fetch",
 				Usage:     "fetch a webpage or icon to see how it is parsed",
@@ -169,6 +680,11 @@ func main() {
 						Aliases: []string{"i"},
 						Usage:   "check if an icon exists",
 					},
+					&cli.BoolFlag{
+						Name:    "content",
+						Aliases: []string{"c"},
+						Usage:   "include the reader-mode content extracted from the page",
+					},
 				},
 			},
 		},
@@ -245,6 +761,52 @@ func schemaVersion(c *cli.Context) (err error) {
 	return nil
 }
 
+func resync(c *cli.Context) (err error) {
+	if err = db.Connect(config.DatabaseConfig{URL: c.String("dsn")}); err != nil {
+		return cli.Exit(err, 1)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	var queued int64
+	if queued, err = epistles.EnqueueStale(ctx, time.Now().Add(-c.Duration("stale")), c.Int("limit")); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	// Process all of the jobs that are ready to run, including jobs queued by the server
+	// that have not yet been picked up by its sync workers.
+	var synced, failed int
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+		job, err := epistles.NextSyncJob(ctx, 5*time.Minute)
+		if err != nil {
+			cancel()
+			if errors.Is(err, sql.ErrNoRows) {
+				break
+			}
+			return cli.Exit(err, 1)
+		}
+
+		var ok bool
+		ok, err = job.Run(ctx, c.Int64("max-attempts"))
+		cancel()
+		if err != nil {
+			return cli.Exit(err, 1)
+		}
+
+		if ok {
+			synced++
+		} else {
+			failed++
+		}
+	}
+
+	fmt.Printf("queued %d stale epistles: %d synced, %d failed\n", queued, synced, failed)
+	return nil
+}
+
 //===========================================================================
 // Admin Actions
 //===========================================================================
@@ -264,6 +826,40 @@ func usage(c *cli.Context) (err error) {
 	return nil
 }
 
+func dedupe(c *cli.Context) (err error) {
+	if err = db.Connect(config.DatabaseConfig{URL: c.String("dsn")}); err != nil {
+		return cli.Exit(err, 1)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	dryRun := c.Bool("dry-run")
+
+	var groups []*epistles.MergeGroup
+	if groups, err = epistles.Dedupe(ctx, dryRun); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	var merged int
+	for _, group := range groups {
+		fmt.Printf("%d %s\n", group.Into, group.Link)
+		for i, fromID := range group.Merged {
+			fmt.Printf("  <- %d %s\n", fromID, group.Links[i])
+		}
+		merged += len(group.Merged)
+	}
+
+	if dryRun {
+		fmt.Printf("found %d duplicate epistles in %d groups (dry run, nothing merged)\n", merged, len(groups))
+		return nil
+	}
+
+	fmt.Printf("merged %d duplicate epistles into %d epistles\n", merged, len(groups))
+	return nil
+}
+
 func generateTokenKey(c *cli.Context) (err error) {
 	// Create ksuid and determine outpath
 	keyid := ulid.Make()
@@ -296,6 +892,19 @@ func generateTokenKey(c *cli.Context) (err error) {
 	return nil
 }
 
+func rotateTokenKey(c *cli.Context) (err error) {
+	store := tokens.NewKeyStore(c.String("dir"), c.Int("size"))
+
+	var keyid ulid.ULID
+	if keyid, _, err = store.Generate(time.Now()); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	fmt.Printf("RSA key id: %s -- saved with PEM encoding to %s\n", keyid, filepath.Join(c.String("dir"), keyid.String()+".pem"))
+	fmt.Fprintln(os.Stderr, "running servers will publish the key within a few minutes and sign tokens with it after the publish delay")
+	return nil
+}
+
 //===========================================================================
 // Client Actions
 //===========================================================================
@@ -339,50 +948,1326 @@ func register(c *cli.Context) (err error) {
 	if err = client.Register(ctx, registration); err != nil {
 		return cli.Exit(err, 1)
 	}
+
+	fmt.Printf("registered %s, check your email for a link to verify your email address\n", registration.Username)
 	return nil
 }
 
-//===========================================================================
-// Debug Actions
-//===========================================================================
+func verifyEmail(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.Exit("specify the token from the verification email", 1)
+	}
 
-func fetchURL(c *cli.Context) (err error) {
-	if c.NArg() == 0 {
-		return cli.Exit("specify at least one URL to fetch", 1)
+	var client api.EpistolaryClient
+	if client, err = api.New(c.String("url")); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err = client.VerifyEmail(ctx, &api.VerifyRequest{Token: c.Args().First()}); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	fmt.Println("email address verified")
+	return nil
+}
+
+func forgotPassword(c *cli.Context) (err error) {
+	req := &api.ForgotPasswordRequest{
+		Email: Prompt("Email:"),
+	}
+
+	var client api.EpistolaryClient
+	if client, err = api.New(c.String("url")); err != nil {
+		return cli.Exit(err, 1)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	docs := make([]*fetch.Document, 0, c.NArg())
-	for i := 0; i < c.NArg(); i++ {
-		var doc *fetch.Document
-		if doc, err = fetch.Fetch(ctx, c.Args().Get(i)); err != nil {
-			return cli.Exit(err, 1)
-		}
+	if err = client.ForgotPassword(ctx, req); err != nil {
+		return cli.Exit(err, 1)
+	}
 
-		if c.Bool("icon") {
-			if doc.FaviconCheck, err = fetch.CheckIcon(ctx, doc.Favicon); err != nil {
-				return cli.Exit(err, 1)
-			}
+	fmt.Println("if an account exists for that email, a password reset link has been sent to it")
+	return nil
+}
 
-			if !doc.FaviconCheck {
-				doc.Favicon = ""
-			}
-		}
+func resetPassword(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.Exit("specify the token from the password reset email", 1)
+	}
 
-		docs = append(docs, doc)
+	req := &api.ResetPasswordRequest{
+		Token:    c.Args().First(),
+		Password: PasswordPrompt("New Password:"),
 	}
 
-	if err = json.NewEncoder(os.Stdout).Encode(docs); err != nil {
+	if PasswordPrompt("Confirm Password:") != req.Password {
+		return cli.Exit("passwords do not match", 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = api.New(c.String("url")); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err = client.ResetPassword(ctx, req); err != nil {
 		return cli.Exit(err, 1)
 	}
+
+	fmt.Println("password reset, you can now login with your new password")
 	return nil
 }
 
-//===========================================================================
-// CLI Helpers
-//===========================================================================
+func showAccount(c *cli.Context) (err error) {
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.User
+	if out, err = client.Profile(ctx); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if err = json.NewEncoder(os.Stdout).Encode(out); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func updateAccount(c *cli.Context) (err error) {
+	if !c.IsSet("name") && !c.IsSet("email") && !c.IsSet("new-username") {
+		return cli.Exit("specify the name, email, or username to change", 1)
+	}
+
+	var (
+		client   api.EpistolaryClient
+		password string
+	)
+	if client, password, err = passwordLogin(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Fetch the current profile so that only the specified fields are changed
+	var user *api.User
+	if user, err = client.Profile(ctx); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if c.IsSet("name") {
+		user.FullName = c.String("name")
+	}
+
+	if c.IsSet("email") {
+		user.Email = c.String("email")
+		user.Password = password
+	}
+
+	if c.IsSet("new-username") {
+		user.Username = c.String("new-username")
+	}
+
+	if user, err = client.UpdateProfile(ctx, user); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if err = json.NewEncoder(os.Stdout).Encode(user); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if user.EmailVerified.IsZero() {
+		fmt.Fprintln(os.Stderr, "check your email for a link to verify your new email address")
+	}
+	return nil
+}
+
+func changePassword(c *cli.Context) (err error) {
+	creds := &api.LoginRequest{
+		Username: c.String("username"),
+		Password: c.String("password"),
+	}
+
+	if creds.Username == "" {
+		creds.Username = Prompt("Username:")
+	}
+
+	if creds.Password == "" {
+		creds.Password = PasswordPrompt("Current Password:")
+	}
+
+	req := &api.ChangePasswordRequest{
+		Password:    creds.Password,
+		NewPassword: PasswordPrompt("New Password:"),
+	}
+
+	if PasswordPrompt("Confirm Password:") != req.NewPassword {
+		return cli.Exit("passwords do not match", 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = api.New(c.String("url")); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if err = loginAs(client, creds); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err = client.ChangePassword(ctx, req); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	fmt.Println("password changed, all other sessions have been signed out")
+	return nil
+}
+
+func deleteAccount(c *cli.Context) (err error) {
+	var (
+		client   api.EpistolaryClient
+		password string
+	)
+	if client, password, err = passwordLogin(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var user *api.User
+	if user, err = client.Profile(ctx); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if !c.Bool("yes") {
+		fmt.Fprintf(os.Stderr, "this will permanently delete %s and all of its readings, notes, tags, and subscriptions\n", user.Username)
+		if Prompt("Type the username to confirm:") != user.Username {
+			return cli.Exit("account not deleted", 1)
+		}
+	}
+
+	if err = client.DeleteAccount(ctx, &api.DeleteAccountRequest{Password: password}); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	fmt.Printf("deleted account %s\n", user.Username)
+	return nil
+}
+
+func mfaStatus(c *cli.Context) (err error) {
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.MFAStatus
+	if out, err = client.MFAStatus(ctx); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if err = json.NewEncoder(os.Stdout).Encode(out); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func enrollMFA(c *cli.Context) (err error) {
+	var (
+		client   api.EpistolaryClient
+		password string
+	)
+	if client, password, err = passwordLogin(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.MFAEnrollment
+	if out, err = client.EnrollMFA(ctx, &api.MFARequest{Password: password}); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	fmt.Fprintln(os.Stderr, "add the secret to your authenticator app then run epistolary mfa confirm with a code from the app")
+	if err = json.NewEncoder(os.Stdout).Encode(out); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func confirmMFA(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.Exit("specify a code from your authenticator app", 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.RecoveryCodes
+	if out, err = client.ConfirmMFA(ctx, &api.MFAConfirmRequest{Code: c.Args().First()}); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	fmt.Fprintln(os.Stderr, "store the recovery codes somewhere safe, they cannot be shown again")
+	for _, code := range out.Codes {
+		fmt.Println(code)
+	}
+	return nil
+}
+
+func disableMFA(c *cli.Context) (err error) {
+	var (
+		client   api.EpistolaryClient
+		password string
+	)
+	if client, password, err = passwordLogin(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err = client.DisableMFA(ctx, &api.MFARequest{Password: password}); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	fmt.Println("multi-factor authentication disabled")
+	return nil
+}
+
+func regenerateRecoveryCodes(c *cli.Context) (err error) {
+	var (
+		client   api.EpistolaryClient
+		password string
+	)
+	if client, password, err = passwordLogin(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.RecoveryCodes
+	if out, err = client.RegenerateRecoveryCodes(ctx, &api.MFARequest{Password: password}); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	fmt.Fprintln(os.Stderr, "store the recovery codes somewhere safe, they cannot be shown again")
+	for _, code := range out.Codes {
+		fmt.Println(code)
+	}
+	return nil
+}
+
+func listSessions(c *cli.Context) (err error) {
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.SessionList
+	if out, err = client.ListSessions(ctx); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	tabs := tabwriter.NewWriter(os.Stdout, 1, 0, 4, ' ', 0)
+	fmt.Fprintln(tabs, "ID\tCurrent\tClient IP\tUser Agent\tLast Used\tExpires")
+	for _, session := range out.Sessions {
+		lastUsed := "never"
+		if !session.LastUsed.IsZero() {
+			lastUsed = session.LastUsed.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tabs, "%s\t%t\t%s\t%s\t%s\t%s\n", session.ID, session.Current, session.ClientIP, session.UserAgent, lastUsed, session.Expires.Format(time.RFC3339))
+	}
+	tabs.Flush()
+	return nil
+}
+
+func revokeSession(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.Exit("specify the id of the session to revoke", 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err = client.RevokeSession(ctx, c.Args().First()); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func listAPIKeys(c *cli.Context) (err error) {
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.APIKeyList
+	if out, err = client.ListAPIKeys(ctx); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	tabs := tabwriter.NewWriter(os.Stdout, 1, 0, 4, ' ', 0)
+	fmt.Fprintln(tabs, "ID\tName\tPermissions\tExpires\tLast Used")
+	for _, key := range out.APIKeys {
+		lastUsed := "never"
+		if !key.LastUsed.IsZero() {
+			lastUsed = key.LastUsed.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tabs, "%d\t%s\t%s\t%s\t%s\n", key.ID, key.Name, strings.Join(key.Permissions, ","), key.Expires.Format(time.RFC3339), lastUsed)
+	}
+	tabs.Flush()
+	return nil
+}
+
+func createAPIKey(c *cli.Context) (err error) {
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	in := &api.APIKey{
+		Name:        c.String("name"),
+		Permissions: c.StringSlice("permission"),
+		Expires:     api.Timestamp{Time: time.Now().Add(c.Duration("expires"))},
+	}
+
+	var out *api.APIKey
+	if out, err = client.CreateAPIKey(ctx, in); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	fmt.Fprintln(os.Stderr, "store the key somewhere safe, it cannot be shown again")
+	if err = json.NewEncoder(os.Stdout).Encode(out); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func showAPIKey(c *cli.Context) (err error) {
+	var keyID int64
+	if keyID, err = apiKeyIDArg(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.APIKey
+	if out, err = client.FetchAPIKey(ctx, keyID); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if err = json.NewEncoder(os.Stdout).Encode(out); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func updateAPIKey(c *cli.Context) (err error) {
+	var keyID int64
+	if keyID, err = apiKeyIDArg(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Fetch the current key so that only the specified fields are changed
+	var key *api.APIKey
+	if key, err = client.FetchAPIKey(ctx, keyID); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if c.IsSet("name") {
+		key.Name = c.String("name")
+	}
+
+	if c.IsSet("permission") {
+		key.Permissions = c.StringSlice("permission")
+	}
+
+	if key, err = client.UpdateAPIKey(ctx, key); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if err = json.NewEncoder(os.Stdout).Encode(key); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func revokeAPIKey(c *cli.Context) (err error) {
+	var keyID int64
+	if keyID, err = apiKeyIDArg(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err = client.DeleteAPIKey(ctx, keyID); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func apiKeyIDArg(c *cli.Context) (int64, error) {
+	if c.NArg() != 1 {
+		return 0, errors.New("specify the id of the api key")
+	}
+
+	keyID, err := strconv.ParseInt(c.Args().First(), 10, 64)
+	if err != nil {
+		return 0, errors.New("the id of the api key must be a number")
+	}
+	return keyID, nil
+}
+
+func listTags(c *cli.Context) (err error) {
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.TagList
+	if out, err = client.ListTags(ctx); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	tabs := tabwriter.NewWriter(os.Stdout, 1, 0, 4, ' ', 0)
+	fmt.Fprintln(tabs, "ID\tName\tReadings")
+	for _, tag := range out.Tags {
+		fmt.Fprintf(tabs, "%d\t%s\t%d\n", tag.ID, tag.Name, tag.Readings)
+	}
+	tabs.Flush()
+	return nil
+}
+
+func createTag(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.Exit("specify the name of the tag to create", 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.Tag
+	if out, err = client.CreateTag(ctx, &api.Tag{Name: c.Args().First()}); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if err = json.NewEncoder(os.Stdout).Encode(out); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func renameTag(c *cli.Context) (err error) {
+	if c.NArg() != 2 {
+		return cli.Exit("specify the id of the tag and its new name", 1)
+	}
+
+	tag := &api.Tag{Name: c.Args().Get(1)}
+	if tag.ID, err = strconv.ParseInt(c.Args().Get(0), 10, 64); err != nil {
+		return cli.Exit("could not parse tag id", 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if tag, err = client.UpdateTag(ctx, tag); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if err = json.NewEncoder(os.Stdout).Encode(tag); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func deleteTag(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.Exit("specify the id of the tag to delete", 1)
+	}
+
+	var tagID int64
+	if tagID, err = strconv.ParseInt(c.Args().First(), 10, 64); err != nil {
+		return cli.Exit("could not parse tag id", 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err = client.DeleteTag(ctx, tagID); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func importReadings(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.Exit("specify the path to the export to import", 1)
+	}
+
+	path := c.Args().First()
+	var f *os.File
+	if f, err = os.Open(path); err != nil {
+		return cli.Exit(err, 1)
+	}
+	defer f.Close()
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	var out *api.ImportSummary
+	if out, err = client.Import(ctx, &api.ImportRequest{Format: c.String("format"), Filename: filepath.Base(path), Data: f}); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	fmt.Printf("imported %d of %d readings from %s export: %d skipped, %d failed\n", out.Imported, out.Total, out.Format, out.Skipped, out.Failed)
+	for _, ierr := range out.Errors {
+		fmt.Printf("  %s: %s\n", ierr.Link, ierr.Error)
+	}
+	return nil
+}
+
+func exportReadings(c *cli.Context) (err error) {
+	var format exports.Format
+	if format, err = exports.ParseFormat(c.String("format")); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	// Write the export to a temporary file so that a failed export does not leave a
+	// partial file behind or overwrite a previous export.
+	var f *os.File
+	if f, err = os.CreateTemp(filepath.Dir(c.String("out")), ".epistolary-export-*"); err != nil {
+		return cli.Exit(err, 1)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	var filename string
+	if filename, err = client.Export(ctx, &api.ExportQuery{Format: string(format)}, f); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if err = f.Close(); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	out := c.String("out")
+	if out == "" {
+		if out = filepath.Base(filename); out == "." || out == string(filepath.Separator) {
+			out = "epistolary-export" + format.Extension()
+		}
+	}
+
+	if err = os.Rename(f.Name(), out); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	fmt.Printf("saved %s export to %s\n", format, out)
+	return nil
+}
+
+func listSubscriptions(c *cli.Context) (err error) {
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.SubscriptionList
+	if out, err = client.ListSubscriptions(ctx); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	tabs := tabwriter.NewWriter(os.Stdout, 1, 0, 4, ' ', 0)
+	fmt.Fprintln(tabs, "ID\tTitle\tLink\tStatus\tPolled")
+	for _, sub := range out.Subscriptions {
+		status := "active"
+		switch {
+		case sub.Paused:
+			status = "paused"
+		case sub.Error != "":
+			status = fmt.Sprintf("failing (%d): %s", sub.Failures, sub.Error)
+		}
+
+		polled := "never"
+		if !sub.Polled.IsZero() {
+			polled = sub.Polled.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tabs, "%d\t%s\t%s\t%s\t%s\n", sub.ID, sub.Title, sub.Link, status, polled)
+	}
+	tabs.Flush()
+	return nil
+}
+
+func addSubscription(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.Exit("specify the url of the feed to subscribe to", 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.Subscription
+	if out, err = client.CreateSubscription(ctx, &api.Subscription{Link: c.Args().First(), Paused: c.Bool("paused")}); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if err = json.NewEncoder(os.Stdout).Encode(out); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func pauseSubscription(paused bool) cli.ActionFunc {
+	return func(c *cli.Context) (err error) {
+		if c.NArg() != 1 {
+			return cli.Exit("specify the id of the subscription", 1)
+		}
+
+		sub := &api.Subscription{Paused: paused}
+		if sub.ID, err = strconv.ParseInt(c.Args().First(), 10, 64); err != nil {
+			return cli.Exit("could not parse subscription id", 1)
+		}
+
+		var client api.EpistolaryClient
+		if client, err = login(c); err != nil {
+			return cli.Exit(err, 1)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if sub, err = client.UpdateSubscription(ctx, sub); err != nil {
+			return cli.Exit(err, 1)
+		}
+
+		if err = json.NewEncoder(os.Stdout).Encode(sub); err != nil {
+			return cli.Exit(err, 1)
+		}
+		return nil
+	}
+}
+
+func removeSubscription(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.Exit("specify the id of the subscription to remove", 1)
+	}
+
+	var subID int64
+	if subID, err = strconv.ParseInt(c.Args().First(), 10, 64); err != nil {
+		return cli.Exit("could not parse subscription id", 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err = client.DeleteSubscription(ctx, subID); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func listFeedTokens(c *cli.Context) (err error) {
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.FeedTokenList
+	if out, err = client.ListFeedTokens(ctx); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	tabs := tabwriter.NewWriter(os.Stdout, 1, 0, 4, ' ', 0)
+	fmt.Fprintln(tabs, "ID\tCreated\tLast Used")
+	for _, token := range out.Tokens {
+		lastUsed := "never"
+		if !token.LastUsed.IsZero() {
+			lastUsed = token.LastUsed.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tabs, "%d\t%s\t%s\n", token.ID, token.Created.Format(time.RFC3339), lastUsed)
+	}
+	tabs.Flush()
+	return nil
+}
+
+func createFeedToken(c *cli.Context) (err error) {
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.FeedToken
+	if out, err = client.CreateFeedToken(ctx); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if err = json.NewEncoder(os.Stdout).Encode(out); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func revokeFeedToken(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.Exit("specify the id of the feed token to revoke", 1)
+	}
+
+	var tokenID int64
+	if tokenID, err = strconv.ParseInt(c.Args().First(), 10, 64); err != nil {
+		return cli.Exit("could not parse feed token id", 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err = client.RevokeFeedToken(ctx, tokenID); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+var adminPageFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "query",
+		Aliases: []string{"q"},
+		Usage:   "search query to filter the results",
+	},
+	&cli.Uint64Flag{
+		Name:    "page-size",
+		Aliases: []string{"s"},
+		Usage:   "number of results to return",
+		Value:   50,
+	},
+	&cli.StringFlag{
+		Name:    "page-token",
+		Aliases: []string{"t"},
+		Usage:   "token of the page of results to return",
+	},
+}
+
+func adminQuery(c *cli.Context) *api.AdminQuery {
+	return &api.AdminQuery{
+		Query: c.String("query"),
+		PageQuery: api.PageQuery{
+			PageSize:  c.Uint64("page-size"),
+			PageToken: c.String("page-token"),
+		},
+	}
+}
+
+func adminListUsers(c *cli.Context) (err error) {
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var out *api.UserPage
+	if out, err = client.AdminListUsers(ctx, adminQuery(c)); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	tabs := tabwriter.NewWriter(os.Stdout, 1, 0, 4, ' ', 0)
+	fmt.Fprintln(tabs, "ID\tUsername\tEmail\tRole\tStatus\tLast Seen")
+	for _, user := range out.Users {
+		status := "active"
+		switch {
+		case !user.Disabled.IsZero():
+			status = "disabled"
+		case user.EmailVerified.IsZero():
+			status = "unverified"
+		}
+
+		seen := "never"
+		if !user.LastSeen.IsZero() {
+			seen = user.LastSeen.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tabs, "%d\t%s\t%s\t%s\t%s\t%s\n", user.ID, user.Username, user.Email, user.Role, status, seen)
+	}
+	tabs.Flush()
+
+	if out.NextPageToken != "" {
+		fmt.Fprintf(os.Stderr, "next page: --page-token %s\n", out.NextPageToken)
+	}
+	return nil
+}
+
+func adminShowUser(c *cli.Context) (err error) {
+	var userID int64
+	if userID, err = userIDArg(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.User
+	if out, err = client.AdminFetchUser(ctx, userID); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if err = json.NewEncoder(os.Stdout).Encode(out); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func adminSetRole(c *cli.Context) (err error) {
+	if c.NArg() != 2 {
+		return cli.Exit("specify the id of the user and the role to assign", 1)
+	}
+
+	var userID int64
+	if userID, err = userIDArg(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.User
+	if out, err = client.AdminSetRole(ctx, userID, c.Args().Get(1)); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	fmt.Printf("%s is now a %s\n", out.Username, out.Role)
+	return nil
+}
+
+func adminSetDisabled(disabled bool) cli.ActionFunc {
+	return func(c *cli.Context) (err error) {
+		var userID int64
+		if userID, err = userIDArg(c); err != nil {
+			return cli.Exit(err, 1)
+		}
+
+		var client api.EpistolaryClient
+		if client, err = login(c); err != nil {
+			return cli.Exit(err, 1)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var out *api.User
+		if disabled {
+			out, err = client.AdminDisableUser(ctx, userID)
+		} else {
+			out, err = client.AdminEnableUser(ctx, userID)
+		}
+
+		if err != nil {
+			return cli.Exit(err, 1)
+		}
+
+		if disabled {
+			fmt.Printf("disabled %s\n", out.Username)
+		} else {
+			fmt.Printf("enabled %s\n", out.Username)
+		}
+		return nil
+	}
+}
+
+func adminImpersonate(c *cli.Context) (err error) {
+	var userID int64
+	if userID, err = userIDArg(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.LoginReply
+	if out, err = client.AdminImpersonate(ctx, userID); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	fmt.Println(out.AccessToken)
+	return nil
+}
+
+func adminListRoles(c *cli.Context) (err error) {
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.RoleList
+	if out, err = client.AdminListRoles(ctx); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	tabs := tabwriter.NewWriter(os.Stdout, 1, 0, 4, ' ', 0)
+	fmt.Fprintln(tabs, "ID\tRole\tPermissions\tDescription")
+	for _, role := range out.Roles {
+		fmt.Fprintf(tabs, "%d\t%s\t%s\t%s\n", role.ID, role.Title, strings.Join(role.Permissions, ", "), role.Description)
+	}
+	tabs.Flush()
+	return nil
+}
+
+func adminListEpistles(c *cli.Context) (err error) {
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var out *api.EpistlePage
+	if out, err = client.AdminListEpistles(ctx, adminQuery(c)); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	tabs := tabwriter.NewWriter(os.Stdout, 1, 0, 4, ' ', 0)
+	fmt.Fprintln(tabs, "ID\tTitle\tLink\tReaders\tSynced")
+	for _, epistle := range out.Epistles {
+		synced := "never"
+		switch {
+		case epistle.SyncError != "":
+			synced = "failed: " + epistle.SyncError
+		case !epistle.Synced.IsZero():
+			synced = epistle.Synced.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tabs, "%d\t%s\t%s\t%d\t%s\n", epistle.ID, epistle.Title, epistle.Link, epistle.Readers, synced)
+	}
+	tabs.Flush()
+
+	if out.NextPageToken != "" {
+		fmt.Fprintf(os.Stderr, "next page: --page-token %s\n", out.NextPageToken)
+	}
+	return nil
+}
+
+func adminListOAuthClients(c *cli.Context) (err error) {
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out *api.OAuthClientList
+	if out, err = client.AdminListOAuthClients(ctx); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	tabs := tabwriter.NewWriter(os.Stdout, 1, 0, 4, ' ', 0)
+	fmt.Fprintln(tabs, "Client ID\tName\tPublic\tRedirect URIs\tCreated")
+	for _, oc := range out.Clients {
+		fmt.Fprintf(tabs, "%s\t%s\t%t\t%s\t%s\n", oc.ClientID, oc.Name, oc.Public, strings.Join(oc.RedirectURIs, ", "), oc.Created.Format(time.RFC3339))
+	}
+	tabs.Flush()
+	return nil
+}
+
+func adminCreateOAuthClient(c *cli.Context) (err error) {
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	in := &api.OAuthClient{
+		Name:         c.String("name"),
+		RedirectURIs: c.StringSlice("redirect-uri"),
+		Public:       c.Bool("public"),
+	}
+
+	var out *api.OAuthClient
+	if out, err = client.AdminCreateOAuthClient(ctx, in); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if out.Secret != "" {
+		fmt.Fprintln(os.Stderr, "store the client secret somewhere safe, it cannot be shown again")
+	}
+
+	if err = json.NewEncoder(os.Stdout).Encode(out); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func adminDeleteOAuthClient(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.Exit("specify the client id of the oauth client to delete", 1)
+	}
+
+	var client api.EpistolaryClient
+	if client, err = login(c); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err = client.AdminDeleteOAuthClient(ctx, c.Args().First()); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+func userIDArg(c *cli.Context) (int64, error) {
+	if c.NArg() < 1 {
+		return 0, errors.New("specify the id of the user")
+	}
+
+	userID, err := strconv.ParseInt(c.Args().First(), 10, 64)
+	if err != nil {
+		return 0, errors.New("the id of the user must be a number")
+	}
+	return userID, nil
+}
+
+//===========================================================================
+// Debug Actions
+//===========================================================================
+
+func fetchURL(c *cli.Context) (err error) {
+	if c.NArg() == 0 {
+		return cli.Exit("specify at least one URL to fetch", 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	docs := make([]*fetch.Document, 0, c.NArg())
+	for i := 0; i < c.NArg(); i++ {
+		var doc *fetch.Document
+		if doc, err = fetch.Fetch(ctx, c.Args().Get(i)); err != nil {
+			return cli.Exit(err, 1)
+		}
+
+		if c.Bool("icon") {
+			if doc.FaviconCheck, err = fetch.CheckIcon(ctx, doc.Favicon); err != nil {
+				return cli.Exit(err, 1)
+			}
+
+			if !doc.FaviconCheck {
+				doc.Favicon = ""
+			}
+		}
+
+		if !c.Bool("content") {
+			doc.Content = nil
+		}
+
+		docs = append(docs, doc)
+	}
+
+	if err = json.NewEncoder(os.Stdout).Encode(docs); err != nil {
+		return cli.Exit(err, 1)
+	}
+	return nil
+}
+
+//===========================================================================
+// CLI Helpers
+//===========================================================================
+
+// Creates an api client and logs in with the username and password from the command
+// line, prompting for the credentials if they were not specified.
+func login(c *cli.Context) (client api.EpistolaryClient, err error) {
+	// Scripts can authenticate with an api key instead of a username and password
+	if key := c.String("api-key"); key != "" {
+		return api.NewWithAPIKey(c.String("url"), key)
+	}
+
+	client, _, err = passwordLogin(c)
+	return client, err
+}
+
+// Logs in with the username and password from the flags, prompting for them if they are
+// not specified. The password is returned for requests that must confirm it.
+func passwordLogin(c *cli.Context) (client api.EpistolaryClient, password string, err error) {
+	if client, err = api.New(c.String("url")); err != nil {
+		return nil, "", err
+	}
+
+	creds := &api.LoginRequest{
+		Username: c.String("username"),
+		Password: c.String("password"),
+	}
+
+	if creds.Username == "" {
+		creds.Username = Prompt("Username:")
+	}
+
+	if creds.Password == "" {
+		creds.Password = PasswordPrompt("Password:")
+	}
+
+	if err = loginAs(client, creds); err != nil {
+		return nil, "", err
+	}
+	return client, creds.Password, nil
+}
+
+// Logs the client in with the credentials, prompting for a code from the authenticator
+// of the user if they have enabled multi-factor authentication.
+func loginAs(client api.EpistolaryClient, creds *api.LoginRequest) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var rep *api.LoginReply
+	if rep, err = client.Login(ctx, creds); err != nil {
+		return err
+	}
+
+	if !rep.MFARequired {
+		return nil
+	}
+
+	in := &api.MFALoginRequest{
+		MFAToken: rep.MFAToken,
+		Code:     Prompt("Authentication Code:"),
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = client.LoginMFA(ctx, in)
+	return err
+}
 
 func Prompt(label string) string {
 	var s string
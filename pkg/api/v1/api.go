@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"database/sql"
+	"io"
 	"time"
 )
 
@@ -13,14 +14,76 @@ import (
 type EpistolaryClient interface {
 	Register(context.Context, *RegisterRequest) error
 	Login(context.Context, *LoginRequest) (*LoginReply, error)
+	LoginMFA(context.Context, *MFALoginRequest) (*LoginReply, error)
 	Logout(context.Context) error
+	VerifyEmail(context.Context, *VerifyRequest) error
+	ForgotPassword(context.Context, *ForgotPasswordRequest) error
+	ResetPassword(context.Context, *ResetPasswordRequest) error
 	Status(context.Context) (*StatusReply, error)
+	SSOProviders(context.Context) (*SSOProviderList, error)
 
-	ListReadings(context.Context, *PageQuery) (*ReadingPage, error)
+	Profile(context.Context) (*User, error)
+	UpdateProfile(context.Context, *User) (*User, error)
+	ChangePassword(context.Context, *ChangePasswordRequest) (*LoginReply, error)
+	DeleteAccount(context.Context, *DeleteAccountRequest) error
+	ListSessions(context.Context) (*SessionList, error)
+	RevokeSession(context.Context, string) error
+	MFAStatus(context.Context) (*MFAStatus, error)
+	EnrollMFA(context.Context, *MFARequest) (*MFAEnrollment, error)
+	ConfirmMFA(context.Context, *MFAConfirmRequest) (*RecoveryCodes, error)
+	DisableMFA(context.Context, *MFARequest) error
+	RegenerateRecoveryCodes(context.Context, *MFARequest) (*RecoveryCodes, error)
+
+	ListReadings(context.Context, *ReadingQuery) (*ReadingPage, error)
+	Search(context.Context, *SearchQuery) (*ReadingPage, error)
 	CreateReading(context.Context, *Reading) (*Reading, error)
 	FetchReading(_ context.Context, id int64) (*Reading, error)
 	UpdateReading(context.Context, *Reading) (*Reading, error)
 	DeleteReading(_ context.Context, id int64) error
+	RestoreReading(_ context.Context, id int64) (*Reading, error)
+	FetchContent(_ context.Context, readingID int64) (*Content, error)
+
+	ListNotes(_ context.Context, readingID int64) (*NoteList, error)
+	CreateNote(context.Context, *Note) (*Note, error)
+	UpdateNote(context.Context, *Note) (*Note, error)
+	DeleteNote(_ context.Context, readingID, noteID int64) error
+
+	ListTags(context.Context) (*TagList, error)
+	CreateTag(context.Context, *Tag) (*Tag, error)
+	FetchTag(_ context.Context, id int64) (*Tag, error)
+	UpdateTag(context.Context, *Tag) (*Tag, error)
+	DeleteTag(_ context.Context, id int64) error
+
+	Import(context.Context, *ImportRequest) (*ImportSummary, error)
+	Export(_ context.Context, _ *ExportQuery, w io.Writer) (filename string, err error)
+
+	ListFeedTokens(context.Context) (*FeedTokenList, error)
+	CreateFeedToken(context.Context) (*FeedToken, error)
+	RevokeFeedToken(_ context.Context, id int64) error
+
+	ListAPIKeys(context.Context) (*APIKeyList, error)
+	CreateAPIKey(context.Context, *APIKey) (*APIKey, error)
+	FetchAPIKey(_ context.Context, id int64) (*APIKey, error)
+	UpdateAPIKey(context.Context, *APIKey) (*APIKey, error)
+	DeleteAPIKey(_ context.Context, id int64) error
+
+	ListSubscriptions(context.Context) (*SubscriptionList, error)
+	CreateSubscription(context.Context, *Subscription) (*Subscription, error)
+	FetchSubscription(_ context.Context, id int64) (*Subscription, error)
+	UpdateSubscription(context.Context, *Subscription) (*Subscription, error)
+	DeleteSubscription(_ context.Context, id int64) error
+
+	AdminListUsers(context.Context, *AdminQuery) (*UserPage, error)
+	AdminFetchUser(_ context.Context, id int64) (*User, error)
+	AdminSetRole(_ context.Context, id int64, role string) (*User, error)
+	AdminDisableUser(_ context.Context, id int64) (*User, error)
+	AdminEnableUser(_ context.Context, id int64) (*User, error)
+	AdminImpersonate(_ context.Context, id int64) (*LoginReply, error)
+	AdminListRoles(context.Context) (*RoleList, error)
+	AdminListEpistles(context.Context, *AdminQuery) (*EpistlePage, error)
+	AdminListOAuthClients(context.Context) (*OAuthClientList, error)
+	AdminCreateOAuthClient(context.Context, *OAuthClient) (*OAuthClient, error)
+	AdminDeleteOAuthClient(_ context.Context, clientID string) error
 }
 
 //===========================================================================
@@ -29,8 +92,15 @@ type EpistolaryClient interface {
 
 // Reply contains standard fields that are used for generic API responses and errors.
 type Reply struct {
-	Success bool   `json:"success"`
-	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+	Success bool          `json:"success"`
+	Error   string        `json:"error,omitempty" yaml:"error,omitempty"`
+	Fields  []*FieldError `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// FieldError describes why the value of a field in the request was invalid.
+type FieldError struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
 }
 
 // StatusReply is returned on status requests. Note that no request is needed.
@@ -46,6 +116,39 @@ type PageQuery struct {
 	PageToken string `url:"page_token,omitempty" form:"page_token" json:"page_token,omitempty"`
 }
 
+// ReadingQuery filters and sorts the readings that are listed. Page tokens are bound to
+// the filter they were created with and cannot be used with a different query.
+type ReadingQuery struct {
+	PageQuery
+	Status         []string  `url:"status,omitempty" form:"status" json:"status,omitempty"`
+	CreatedAfter   time.Time `url:"created_after,omitempty" form:"created_after" json:"created_after,omitempty"`
+	CreatedBefore  time.Time `url:"created_before,omitempty" form:"created_before" json:"created_before,omitempty"`
+	FinishedAfter  time.Time `url:"finished_after,omitempty" form:"finished_after" json:"finished_after,omitempty"`
+	FinishedBefore time.Time `url:"finished_before,omitempty" form:"finished_before" json:"finished_before,omitempty"`
+	Domain         string    `url:"domain,omitempty" form:"domain" json:"domain,omitempty"`
+	Tags           []string  `url:"tag,omitempty" form:"tag" json:"tag,omitempty"`
+	Sort           string    `url:"sort,omitempty" form:"sort" json:"sort,omitempty"`
+	Order          string    `url:"order,omitempty" form:"order" json:"order,omitempty"`
+}
+
+// SearchQuery is a full-text search query that can be paginated with a cursor.
+type SearchQuery struct {
+	Query     string `url:"q" form:"q" json:"q"`
+	PageSize  uint64 `url:"page_size,omitempty" form:"page_size" json:"page_size,omitempty"`
+	PageToken string `url:"page_token,omitempty" form:"page_token" json:"page_token,omitempty"`
+}
+
+// AdminQuery searches the users or epistles that are browsed by administrators.
+type AdminQuery struct {
+	PageQuery
+	Query string `url:"q,omitempty" form:"q" json:"q,omitempty"`
+}
+
+// ExportQuery specifies the format of an export: jsonl (default), csv, netscape or opml.
+type ExportQuery struct {
+	Format string `url:"format,omitempty" form:"format" json:"format,omitempty"`
+}
+
 //===========================================================================
 // Epistolary v1 API Requests and Responses
 //===========================================================================
@@ -62,9 +165,172 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// LoginReply contains the access and refresh tokens of the user. If the user has
+// enabled multi-factor authentication, only an MFA challenge token is returned, which
+// must be sent with a code from the authenticator of the user to complete the login.
 type LoginReply struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+}
+
+// MFALoginRequest completes the login of a user with multi-factor authentication using
+// the challenge token from the login and either a TOTP code or a recovery code.
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+// MFAStatus reports if the user has enabled multi-factor authentication.
+type MFAStatus struct {
+	Enabled       bool  `json:"enabled"`
+	RecoveryCodes int64 `json:"recovery_codes_remaining"`
+}
+
+// MFARequest confirms the password of the user before multi-factor authentication is
+// enrolled or disabled or new recovery codes are generated.
+type MFARequest struct {
+	Password string `json:"password"`
+}
+
+// MFAEnrollment contains the TOTP secret that the user adds to their authenticator app,
+// either by scanning the otpauth URI as a QR code or by entering the secret.
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// MFAConfirmRequest enables multi-factor authentication with a code from the
+// authenticator that the user has enrolled.
+type MFAConfirmRequest struct {
+	Code string `json:"code"`
+}
+
+// RecoveryCodes can each be used once to login if the authenticator is lost. They are
+// only returned when they are generated.
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+// SSOProviderList lists the upstream identity providers that users can login with.
+type SSOProviderList struct {
+	Providers []*SSOProvider `json:"providers"`
+}
+
+// SSOProvider is an upstream identity provider; the user is redirected to the login URL
+// in a browser to login with the provider.
+type SSOProvider struct {
+	Name     string `json:"name"`
+	Title    string `json:"title"`
+	LoginURL string `json:"login_url"`
+}
+
+// VerifyRequest verifies the email address of a user with the token emailed to them.
+type VerifyRequest struct {
+	Token string `json:"token"`
+}
+
+// ForgotPasswordRequest emails a password reset token to the user with the email.
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// ResetPasswordRequest sets a new password using the token emailed to the user.
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// User is the account of the authenticated user. Only the full name, email, and
+// username can be updated; changing the email requires it to be verified again.
+type User struct {
+	ID              int64     `json:"id,omitempty"`
+	FullName        string    `json:"full_name"`
+	Email           string    `json:"email"`
+	EmailVerified   Timestamp `json:"email_verified,omitempty"`
+	Disabled        Timestamp `json:"disabled,omitempty"`
+	Username        string    `json:"username"`
+	Role            string    `json:"role,omitempty"`
+	LastSeen        Timestamp `json:"last_seen,omitempty"`
+	PasswordChanged Timestamp `json:"password_changed,omitempty"`
+	Created         Timestamp `json:"created,omitempty"`
+	Modified        Timestamp `json:"modified,omitempty"`
+
+	// The current password of the user is required to change the email address of the
+	// account; it is only sent in requests and is never returned.
+	Password string `json:"password,omitempty"`
+}
+
+type UserPage struct {
+	Users         []*User `json:"users"`
+	NextPageToken string  `json:"next_page_token"`
+	PrevPageToken string  `json:"prev_page_token"`
+}
+
+// SetRoleRequest assigns the role with the title to a user.
+type SetRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type RoleList struct {
+	Roles []*Role `json:"roles"`
+}
+
+type Role struct {
+	ID          int64    `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+}
+
+type EpistlePage struct {
+	Epistles      []*Epistle `json:"epistles"`
+	NextPageToken string     `json:"next_page_token"`
+	PrevPageToken string     `json:"prev_page_token"`
+}
+
+// Epistle is a link that is being read by one or more users.
+type Epistle struct {
+	ID        int64     `json:"id"`
+	Link      string    `json:"link"`
+	Title     string    `json:"title,omitempty"`
+	SiteName  string    `json:"site_name,omitempty"`
+	Favicon   string    `json:"favicon,omitempty"`
+	Readers   int64     `json:"readers"`
+	Synced    Timestamp `json:"synced,omitempty"`
+	SyncError string    `json:"sync_error,omitempty"`
+	Created   Timestamp `json:"created,omitempty"`
+	Modified  Timestamp `json:"modified,omitempty"`
+}
+
+// ChangePasswordRequest sets a new password for the user, who must confirm their
+// current password. Changing the password revokes all other sessions of the user.
+type ChangePasswordRequest struct {
+	Password    string `json:"password"`
+	NewPassword string `json:"new_password"`
+}
+
+// DeleteAccountRequest confirms the password of the user before their account is
+// permanently deleted.
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+type SessionList struct {
+	Sessions []*Session `json:"sessions"`
+}
+
+// Session is a device the user has logged in from. Revoking the session prevents its
+// refresh token from being used to reauthenticate.
+type Session struct {
+	ID        string    `json:"id"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	ClientIP  string    `json:"client_ip,omitempty"`
+	Current   bool      `json:"current"`
+	LastUsed  Timestamp `json:"last_used,omitempty"`
+	Expires   Timestamp `json:"expires"`
+	Created   Timestamp `json:"created,omitempty"`
 }
 
 type ReadingPage struct {
@@ -80,6 +346,12 @@ type Reading struct {
 	Title       string    `json:"title,omitempty"`
 	Description string    `json:"description,omitempty"`
 	Favicon     string    `json:"favicon,omitempty"`
+	SiteName    string    `json:"site_name,omitempty"`
+	Author      string    `json:"author,omitempty"`
+	Image       string    `json:"image,omitempty"`
+	Canonical   string    `json:"canonical,omitempty"`
+	Published   Timestamp `json:"published,omitempty"`
+	Tags        []string  `json:"tags"`
 	Started     Timestamp `json:"started,omitempty"`
 	Finished    Timestamp `json:"finished,omitempty"`
 	Archived    Timestamp `json:"archived,omitempty"`
@@ -87,6 +359,134 @@ type Reading struct {
 	Modified    Timestamp `json:"modified,omitempty"`
 }
 
+// Content is the reader-mode article extracted from the link of a reading. The HTML is
+// sanitized and the reading time is estimated in minutes.
+type Content struct {
+	ReadingID   int64     `json:"reading_id"`
+	Link        string    `json:"link,omitempty"`
+	Title       string    `json:"title,omitempty"`
+	HTML        string    `json:"html"`
+	Text        string    `json:"text"`
+	WordCount   int64     `json:"word_count"`
+	ReadingTime int64     `json:"reading_time"`
+	Modified    Timestamp `json:"modified,omitempty"`
+}
+
+type NoteList struct {
+	Notes []*Note `json:"notes"`
+}
+
+type Note struct {
+	ID        int64     `json:"id,omitempty"`
+	ReadingID int64     `json:"reading_id,omitempty"`
+	Body      string    `json:"body"`
+	Highlight string    `json:"highlight,omitempty"`
+	Created   Timestamp `json:"created,omitempty"`
+	Modified  Timestamp `json:"modified,omitempty"`
+}
+
+type TagList struct {
+	Tags []*Tag `json:"tags"`
+}
+
+type Tag struct {
+	ID       int64     `json:"id,omitempty"`
+	Name     string    `json:"name"`
+	Readings int64     `json:"readings"`
+	Created  Timestamp `json:"created,omitempty"`
+	Modified Timestamp `json:"modified,omitempty"`
+}
+
+// ImportRequest uploads an export from another service to import as readings. If the
+// format is not specified it is detected from the filename and contents of the export.
+type ImportRequest struct {
+	Format   string
+	Filename string
+	Data     io.Reader
+}
+
+type ImportSummary struct {
+	Format   string         `json:"format"`
+	Total    int            `json:"total"`
+	Imported int            `json:"imported"`
+	Skipped  int            `json:"skipped"`
+	Failed   int            `json:"failed"`
+	Errors   []*ImportError `json:"errors,omitempty"`
+}
+
+type ImportError struct {
+	Link  string `json:"link"`
+	Error string `json:"error"`
+}
+
+type SubscriptionList struct {
+	Subscriptions []*Subscription `json:"subscriptions"`
+}
+
+// Subscription is a feed whose new items are queued as readings. Only the link can be
+// set when the subscription is created and only paused can be updated.
+type Subscription struct {
+	ID       int64     `json:"id,omitempty"`
+	Link     string    `json:"link"`
+	Title    string    `json:"title,omitempty"`
+	SiteLink string    `json:"site_link,omitempty"`
+	Paused   bool      `json:"paused"`
+	Polled   Timestamp `json:"polled,omitempty"`
+	Failures int64     `json:"failures,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	Created  Timestamp `json:"created,omitempty"`
+	Modified Timestamp `json:"modified,omitempty"`
+}
+
+type FeedTokenList struct {
+	Tokens []*FeedToken `json:"feed_tokens"`
+}
+
+// FeedToken authenticates the private feeds of a user's reading list. The token and the
+// URLs of the feeds are only returned when the token is created.
+type FeedToken struct {
+	ID       int64     `json:"id,omitempty"`
+	Token    string    `json:"token,omitempty"`
+	AtomURL  string    `json:"atom_url,omitempty"`
+	RSSURL   string    `json:"rss_url,omitempty"`
+	LastUsed Timestamp `json:"last_used,omitempty"`
+	Created  Timestamp `json:"created,omitempty"`
+}
+
+type APIKeyList struct {
+	APIKeys []*APIKey `json:"api_keys"`
+}
+
+// APIKey is a personal access token that authenticates scripts and integrations with a
+// subset of the user's permissions using the "ApiKey" Authorization scheme. The key is
+// only returned when it is created; if no expiration is given it expires in 90 days.
+type APIKey struct {
+	ID          int64     `json:"id,omitempty"`
+	Name        string    `json:"name"`
+	Key         string    `json:"key,omitempty"`
+	Permissions []string  `json:"permissions"`
+	Expires     Timestamp `json:"expires,omitempty"`
+	LastUsed    Timestamp `json:"last_used,omitempty"`
+	Created     Timestamp `json:"created,omitempty"`
+	Modified    Timestamp `json:"modified,omitempty"`
+}
+
+type OAuthClientList struct {
+	Clients []*OAuthClient `json:"clients"`
+}
+
+// OAuthClient is a third-party application that authenticates users with the OAuth2
+// authorization code flow. Public clients have no secret and must use PKCE; the secret
+// of a confidential client is only returned when the client is created.
+type OAuthClient struct {
+	ClientID     string    `json:"client_id,omitempty"`
+	Name         string    `json:"name"`
+	Secret       string    `json:"client_secret,omitempty"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	Public       bool      `json:"public"`
+	Created      Timestamp `json:"created,omitempty"`
+}
+
 //===========================================================================
 // OpenID Configuration
 //===========================================================================
@@ -112,6 +512,33 @@ type OpenIDConfiguration struct {
 	RequestURIParameterSupported  bool     `json:"request_uri_parameter_supported"`
 }
 
+// OAuthToken is returned from the token endpoint when an authorization code or refresh
+// token is exchanged (RFC 6749 section 5.1). The ID token is only returned when the
+// openid scope was requested.
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// OAuthError is returned from the token and revocation endpoints (RFC 6749 section 5.2).
+type OAuthError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+// UserInfo is returned from the OpenID Connect userinfo endpoint.
+type UserInfo struct {
+	Subject           string `json:"sub"`
+	Name              string `json:"name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     bool   `json:"email_verified,omitempty"`
+}
+
 type Timestamp struct {
 	time.Time
 }
@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
@@ -36,65 +38,789 @@ func New(endpoint string) (_ EpistolaryClient, err error) {
 	return c, nil
 }
 
+// NewWithAPIKey creates a new api.v1 API client that authenticates every request with
+// the personal API key rather than logging in.
+func NewWithAPIKey(endpoint, apiKey string) (_ EpistolaryClient, err error) {
+	var client EpistolaryClient
+	if client, err = New(endpoint); err != nil {
+		return nil, err
+	}
+
+	client.(*APIv1).apiKey = apiKey
+	return client, nil
+}
+
 // APIv1 implements the EpistolaryClient interface.
 type APIv1 struct {
-	endpoint *url.URL
-	client   *http.Client
+	endpoint    *url.URL
+	client      *http.Client
+	accessToken string
+	apiKey      string
+}
+
+// Ensure the API implments the EpistolaryClient interface.
+var _ EpistolaryClient = &APIv1{}
+
+//===========================================================================
+// Client Methods
+//===========================================================================
+
+func (s *APIv1) Register(ctx context.Context, in *RegisterRequest) (err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/register", in, nil); err != nil {
+		return err
+	}
+
+	if _, err = s.Do(req, nil, true); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *APIv1) VerifyEmail(ctx context.Context, in *VerifyRequest) (err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/verify", in, nil); err != nil {
+		return err
+	}
+
+	if _, err = s.Do(req, nil, true); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *APIv1) ForgotPassword(ctx context.Context, in *ForgotPasswordRequest) (err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/forgot-password", in, nil); err != nil {
+		return err
+	}
+
+	if _, err = s.Do(req, nil, true); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *APIv1) ResetPassword(ctx context.Context, in *ResetPasswordRequest) (err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/reset-password", in, nil); err != nil {
+		return err
+	}
+
+	if _, err = s.Do(req, nil, true); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *APIv1) SSOProviders(ctx context.Context) (out *SSOProviderList, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/sso", nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &SSOProviderList{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) Login(ctx context.Context, in *LoginRequest) (out *LoginReply, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/login", in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &LoginReply{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	// Save the access token to authenticate follow up requests; the cookies set by the
+	// server cannot be relied on since they are only sent over secure connections.
+	// TODO: use the refresh token to reauthenticate when the access token expires.
+	s.accessToken = out.AccessToken
+	return out, nil
+}
+
+func (s *APIv1) LoginMFA(ctx context.Context, in *MFALoginRequest) (out *LoginReply, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/login/mfa", in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &LoginReply{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	// Save the access token to authenticate follow up requests.
+	s.accessToken = out.AccessToken
+	return out, nil
+}
+
+func (s *APIv1) Logout(ctx context.Context) (err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/logout", nil, nil); err != nil {
+		return err
+	}
+
+	if _, err = s.Do(req, nil, true); err != nil {
+		return err
+	}
+
+	s.accessToken = ""
+	return nil
+}
+
+func (s *APIv1) Profile(ctx context.Context) (out *User, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/users/me", nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &User{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) UpdateProfile(ctx context.Context, in *User) (out *User, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPut, "/v1/users/me", in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &User{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) ChangePassword(ctx context.Context, in *ChangePasswordRequest) (out *LoginReply, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/users/me/password", in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &LoginReply{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	// The previous tokens are revoked when the password changes
+	s.accessToken = out.AccessToken
+	return out, nil
+}
+
+func (s *APIv1) DeleteAccount(ctx context.Context, in *DeleteAccountRequest) (err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodDelete, "/v1/users/me", in, nil); err != nil {
+		return err
+	}
+
+	if _, err = s.Do(req, nil, true); err != nil {
+		return err
+	}
+
+	s.accessToken = ""
+	return nil
+}
+
+func (s *APIv1) ListSessions(ctx context.Context) (out *SessionList, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/sessions", nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &SessionList{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) RevokeSession(ctx context.Context, id string) (err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/sessions/%s", url.PathEscape(id))
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodDelete, endpoint, nil, nil); err != nil {
+		return err
+	}
+
+	if _, err = s.Do(req, nil, true); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *APIv1) MFAStatus(ctx context.Context) (out *MFAStatus, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/users/me/mfa", nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &MFAStatus{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) EnrollMFA(ctx context.Context, in *MFARequest) (out *MFAEnrollment, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/users/me/mfa", in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &MFAEnrollment{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) ConfirmMFA(ctx context.Context, in *MFAConfirmRequest) (out *RecoveryCodes, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/users/me/mfa/confirm", in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &RecoveryCodes{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) DisableMFA(ctx context.Context, in *MFARequest) (err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/users/me/mfa/disable", in, nil); err != nil {
+		return err
+	}
+
+	if _, err = s.Do(req, nil, true); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *APIv1) RegenerateRecoveryCodes(ctx context.Context, in *MFARequest) (out *RecoveryCodes, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/users/me/mfa/recovery-codes", in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &RecoveryCodes{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) ListReadings(ctx context.Context, in *ReadingQuery) (out *ReadingPage, err error) {
+	var params url.Values
+	if params, err = query.Values(in); err != nil {
+		return nil, fmt.Errorf("could not encode query params: %w", err)
+	}
+
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/reading", nil, &params); err != nil {
+		return nil, err
+	}
+
+	out = &ReadingPage{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) Search(ctx context.Context, in *SearchQuery) (out *ReadingPage, err error) {
+	var params url.Values
+	if params, err = query.Values(in); err != nil {
+		return nil, fmt.Errorf("could not encode query params: %w", err)
+	}
+
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/reading/search", nil, &params); err != nil {
+		return nil, err
+	}
+
+	out = &ReadingPage{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) CreateReading(ctx context.Context, in *Reading) (out *Reading, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/reading", in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &Reading{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) FetchReading(ctx context.Context, id int64) (out *Reading, err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/reading/%d", id)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, endpoint, nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &Reading{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) UpdateReading(ctx context.Context, in *Reading) (out *Reading, err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/reading/%d", in.ID)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPut, endpoint, in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &Reading{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) DeleteReading(ctx context.Context, id int64) (err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/reading/%d", id)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodDelete, endpoint, nil, nil); err != nil {
+		return err
+	}
+
+	if _, err = s.Do(req, nil, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *APIv1) RestoreReading(ctx context.Context, id int64) (out *Reading, err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/reading/%d/restore", id)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, endpoint, nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &Reading{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) FetchContent(ctx context.Context, readingID int64) (out *Content, err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/reading/%d/content", readingID)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, endpoint, nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &Content{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) ListNotes(ctx context.Context, readingID int64) (out *NoteList, err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/reading/%d/notes", readingID)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, endpoint, nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &NoteList{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) CreateNote(ctx context.Context, in *Note) (out *Note, err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/reading/%d/notes", in.ReadingID)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, endpoint, in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &Note{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) UpdateNote(ctx context.Context, in *Note) (out *Note, err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/reading/%d/notes/%d", in.ReadingID, in.ID)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPut, endpoint, in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &Note{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) DeleteNote(ctx context.Context, readingID, noteID int64) (err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/reading/%d/notes/%d", readingID, noteID)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodDelete, endpoint, nil, nil); err != nil {
+		return err
+	}
+
+	if _, err = s.Do(req, nil, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *APIv1) ListTags(ctx context.Context) (out *TagList, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/tags", nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &TagList{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) CreateTag(ctx context.Context, in *Tag) (out *Tag, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/tags", in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &Tag{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) FetchTag(ctx context.Context, id int64) (out *Tag, err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/tags/%d", id)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, endpoint, nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &Tag{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) UpdateTag(ctx context.Context, in *Tag) (out *Tag, err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/tags/%d", in.ID)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPut, endpoint, in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &Tag{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) DeleteTag(ctx context.Context, id int64) (err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/tags/%d", id)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodDelete, endpoint, nil, nil); err != nil {
+		return err
+	}
+
+	if _, err = s.Do(req, nil, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *APIv1) ListFeedTokens(ctx context.Context) (out *FeedTokenList, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/feeds", nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &FeedTokenList{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) CreateFeedToken(ctx context.Context) (out *FeedToken, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/feeds", nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &FeedToken{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
 }
 
-// Ensure the API implments the EpistolaryClient interface.
-var _ EpistolaryClient = &APIv1{}
+func (s *APIv1) RevokeFeedToken(ctx context.Context, id int64) (err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/feeds/%d", id)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodDelete, endpoint, nil, nil); err != nil {
+		return err
+	}
 
-//===========================================================================
-// Client Methods
-//===========================================================================
+	if _, err = s.Do(req, nil, true); err != nil {
+		return err
+	}
+	return nil
+}
 
-func (s *APIv1) Register(ctx context.Context, in *RegisterRequest) (err error) {
+func (s *APIv1) ListAPIKeys(ctx context.Context) (out *APIKeyList, err error) {
 	//  Make the HTTP request
 	var req *http.Request
-	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/register", in, nil); err != nil {
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/apikeys", nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &APIKeyList{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) CreateAPIKey(ctx context.Context, in *APIKey) (out *APIKey, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/apikeys", in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &APIKey{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) FetchAPIKey(ctx context.Context, id int64) (out *APIKey, err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/apikeys/%d", id)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, endpoint, nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &APIKey{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) UpdateAPIKey(ctx context.Context, in *APIKey) (out *APIKey, err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/apikeys/%d", in.ID)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPut, endpoint, in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &APIKey{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) DeleteAPIKey(ctx context.Context, id int64) (err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/apikeys/%d", id)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodDelete, endpoint, nil, nil); err != nil {
 		return err
 	}
 
 	if _, err = s.Do(req, nil, true); err != nil {
 		return err
 	}
-
 	return nil
 }
 
-func (s *APIv1) Login(ctx context.Context, in *LoginRequest) (out *LoginReply, err error) {
+func (s *APIv1) ListSubscriptions(ctx context.Context) (out *SubscriptionList, err error) {
 	//  Make the HTTP request
 	var req *http.Request
-	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/login", in, nil); err != nil {
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/subscriptions", nil, nil); err != nil {
 		return nil, err
 	}
 
-	out = &LoginReply{}
+	out = &SubscriptionList{}
 	if _, err = s.Do(req, out, true); err != nil {
 		return nil, err
 	}
 
-	// TODO: save access and refresh token for follow up request handling
 	return out, nil
 }
 
-func (s *APIv1) Logout(ctx context.Context) (err error) {
+func (s *APIv1) CreateSubscription(ctx context.Context, in *Subscription) (out *Subscription, err error) {
 	//  Make the HTTP request
 	var req *http.Request
-	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/logout", nil, nil); err != nil {
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/subscriptions", in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &Subscription{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) FetchSubscription(ctx context.Context, id int64) (out *Subscription, err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/subscriptions/%d", id)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, endpoint, nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &Subscription{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) UpdateSubscription(ctx context.Context, in *Subscription) (out *Subscription, err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/subscriptions/%d", in.ID)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPut, endpoint, in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &Subscription{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) DeleteSubscription(ctx context.Context, id int64) (err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/subscriptions/%d", id)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodDelete, endpoint, nil, nil); err != nil {
 		return err
 	}
 
 	if _, err = s.Do(req, nil, true); err != nil {
 		return err
 	}
-
-	// TODO: delete any locally stored credentials.
 	return nil
 }
 
-func (s *APIv1) ListReadings(ctx context.Context, in *PageQuery) (out *ReadingPage, err error) {
+func (s *APIv1) AdminListUsers(ctx context.Context, in *AdminQuery) (out *UserPage, err error) {
 	var params url.Values
 	if params, err = query.Values(in); err != nil {
 		return nil, fmt.Errorf("could not encode query params: %w", err)
@@ -102,11 +828,11 @@ func (s *APIv1) ListReadings(ctx context.Context, in *PageQuery) (out *ReadingPa
 
 	//  Make the HTTP request
 	var req *http.Request
-	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/reading", nil, &params); err != nil {
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/admin/users", nil, &params); err != nil {
 		return nil, err
 	}
 
-	out = &ReadingPage{}
+	out = &UserPage{}
 	if _, err = s.Do(req, out, true); err != nil {
 		return nil, err
 	}
@@ -114,14 +840,15 @@ func (s *APIv1) ListReadings(ctx context.Context, in *PageQuery) (out *ReadingPa
 	return out, nil
 }
 
-func (s *APIv1) CreateReading(ctx context.Context, in *Reading) (out *Reading, err error) {
+func (s *APIv1) AdminFetchUser(ctx context.Context, id int64) (out *User, err error) {
 	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/admin/users/%d", id)
 	var req *http.Request
-	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/reading", in, nil); err != nil {
+	if req, err = s.NewRequest(ctx, http.MethodGet, endpoint, nil, nil); err != nil {
 		return nil, err
 	}
 
-	out = &Reading{}
+	out = &User{}
 	if _, err = s.Do(req, out, true); err != nil {
 		return nil, err
 	}
@@ -129,15 +856,15 @@ func (s *APIv1) CreateReading(ctx context.Context, in *Reading) (out *Reading, e
 	return out, nil
 }
 
-func (s *APIv1) FetchReading(ctx context.Context, id int64) (out *Reading, err error) {
+func (s *APIv1) AdminSetRole(ctx context.Context, id int64, role string) (out *User, err error) {
 	//  Make the HTTP request
-	endpoint := fmt.Sprintf("/v1/reading/%d", id)
+	endpoint := fmt.Sprintf("/v1/admin/users/%d/role", id)
 	var req *http.Request
-	if req, err = s.NewRequest(ctx, http.MethodGet, endpoint, nil, nil); err != nil {
+	if req, err = s.NewRequest(ctx, http.MethodPut, endpoint, &SetRoleRequest{Role: role}, nil); err != nil {
 		return nil, err
 	}
 
-	out = &Reading{}
+	out = &User{}
 	if _, err = s.Do(req, out, true); err != nil {
 		return nil, err
 	}
@@ -145,15 +872,15 @@ func (s *APIv1) FetchReading(ctx context.Context, id int64) (out *Reading, err e
 	return out, nil
 }
 
-func (s *APIv1) UpdateReading(ctx context.Context, in *Reading) (out *Reading, err error) {
+func (s *APIv1) AdminDisableUser(ctx context.Context, id int64) (out *User, err error) {
 	//  Make the HTTP request
-	endpoint := fmt.Sprintf("/v1/reading/%d", in.ID)
+	endpoint := fmt.Sprintf("/v1/admin/users/%d/disable", id)
 	var req *http.Request
-	if req, err = s.NewRequest(ctx, http.MethodPut, endpoint, in, nil); err != nil {
+	if req, err = s.NewRequest(ctx, http.MethodPost, endpoint, nil, nil); err != nil {
 		return nil, err
 	}
 
-	out = &Reading{}
+	out = &User{}
 	if _, err = s.Do(req, out, true); err != nil {
 		return nil, err
 	}
@@ -161,9 +888,106 @@ func (s *APIv1) UpdateReading(ctx context.Context, in *Reading) (out *Reading, e
 	return out, nil
 }
 
-func (s *APIv1) DeleteReading(ctx context.Context, id int64) (err error) {
+func (s *APIv1) AdminEnableUser(ctx context.Context, id int64) (out *User, err error) {
 	//  Make the HTTP request
-	endpoint := fmt.Sprintf("/v1/reading/%d", id)
+	endpoint := fmt.Sprintf("/v1/admin/users/%d/enable", id)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, endpoint, nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &User{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) AdminImpersonate(ctx context.Context, id int64) (out *LoginReply, err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/admin/users/%d/impersonate", id)
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, endpoint, nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &LoginReply{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) AdminListRoles(ctx context.Context) (out *RoleList, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/admin/roles", nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &RoleList{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) AdminListEpistles(ctx context.Context, in *AdminQuery) (out *EpistlePage, err error) {
+	var params url.Values
+	if params, err = query.Values(in); err != nil {
+		return nil, fmt.Errorf("could not encode query params: %w", err)
+	}
+
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/admin/epistles", nil, &params); err != nil {
+		return nil, err
+	}
+
+	out = &EpistlePage{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) AdminListOAuthClients(ctx context.Context) (out *OAuthClientList, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/admin/oauth/clients", nil, nil); err != nil {
+		return nil, err
+	}
+
+	out = &OAuthClientList{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) AdminCreateOAuthClient(ctx context.Context, in *OAuthClient) (out *OAuthClient, err error) {
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/admin/oauth/clients", in, nil); err != nil {
+		return nil, err
+	}
+
+	out = &OAuthClient{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *APIv1) AdminDeleteOAuthClient(ctx context.Context, clientID string) (err error) {
+	//  Make the HTTP request
+	endpoint := fmt.Sprintf("/v1/admin/oauth/clients/%s", url.PathEscape(clientID))
 	var req *http.Request
 	if req, err = s.NewRequest(ctx, http.MethodDelete, endpoint, nil, nil); err != nil {
 		return err
@@ -203,6 +1027,86 @@ func (s *APIv1) Status(ctx context.Context) (out *StatusReply, err error) {
 	return out, nil
 }
 
+func (s *APIv1) Import(ctx context.Context, in *ImportRequest) (out *ImportSummary, err error) {
+	// Upload the export as a multipart form
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	if in.Format != "" {
+		if err = form.WriteField("format", in.Format); err != nil {
+			return nil, err
+		}
+	}
+
+	var part io.Writer
+	if part, err = form.CreateFormFile("file", in.Filename); err != nil {
+		return nil, err
+	}
+
+	if _, err = io.Copy(part, in.Data); err != nil {
+		return nil, fmt.Errorf("could not read import data: %w", err)
+	}
+
+	if err = form.Close(); err != nil {
+		return nil, err
+	}
+
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, "/v1/import", nil, nil); err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(body)
+	req.ContentLength = int64(body.Len())
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	out = &ImportSummary{}
+	if _, err = s.Do(req, out, true); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Export streams the export to the writer, returning the filename suggested by the
+// server for the export.
+func (s *APIv1) Export(ctx context.Context, in *ExportQuery, w io.Writer) (filename string, err error) {
+	var params url.Values
+	if params, err = query.Values(in); err != nil {
+		return "", fmt.Errorf("could not encode query params: %w", err)
+	}
+
+	//  Make the HTTP request
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, "/v1/export", nil, &params); err != nil {
+		return "", err
+	}
+
+	// The export is not JSON so the response cannot be handled by Do
+	var rep *http.Response
+	if rep, err = s.client.Do(req); err != nil {
+		return "", fmt.Errorf("could not execute request: %s", err)
+	}
+	defer rep.Body.Close()
+
+	if rep.StatusCode != http.StatusOK {
+		var reply Reply
+		if err = json.NewDecoder(rep.Body).Decode(&reply); err == nil && reply.Error != "" {
+			return "", fmt.Errorf("[%d] %s", rep.StatusCode, reply.Error)
+		}
+		return "", errors.New(rep.Status)
+	}
+
+	if _, params, err := mime.ParseMediaType(rep.Header.Get("Content-Disposition")); err == nil {
+		filename = params["filename"]
+	}
+
+	if _, err = io.Copy(w, rep.Body); err != nil {
+		return "", fmt.Errorf("could not read export: %w", err)
+	}
+	return filename, nil
+}
+
 //===========================================================================
 // Helper Methods
 //===========================================================================
@@ -246,6 +1150,13 @@ func (s *APIv1) NewRequest(ctx context.Context, method, path string, data interf
 	req.Header.Add("Accept-Language", acceptLang)
 	req.Header.Add("Accept-Encoding", acceptEncode)
 	req.Header.Add("Content-Type", contentType)
+
+	switch {
+	case s.apiKey != "":
+		req.Header.Add("Authorization", "ApiKey "+s.apiKey)
+	case s.accessToken != "":
+		req.Header.Add("Authorization", "Bearer "+s.accessToken)
+	}
 	return req, nil
 }
 
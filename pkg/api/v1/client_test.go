@@ -79,6 +79,27 @@ func TestClient(t *testing.T) {
 	require.EqualError(t, err, "[400] bad request")
 }
 
+func TestAPIKey(t *testing.T) {
+	client, err := api.NewWithAPIKey("http://localhost:8000", "supersecretkey")
+	require.NoError(t, err)
+
+	v1, ok := client.(*api.APIv1)
+	require.True(t, ok)
+
+	// Requests should be authenticated with the api key scheme
+	req, err := v1.NewRequest(context.TODO(), http.MethodGet, "/v1/reading", nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "ApiKey supersecretkey", req.Header.Get("Authorization"))
+
+	// Clients without an api key should not send an Authorization header
+	client, err = api.New("http://localhost:8000")
+	require.NoError(t, err)
+
+	req, err = client.(*api.APIv1).NewRequest(context.TODO(), http.MethodGet, "/v1/reading", nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, req.Header.Get("Authorization"))
+}
+
 func TestStatus(t *testing.T) {
 	fixture := &api.StatusReply{
 		Status:  "ok",
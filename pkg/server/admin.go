@@ -0,0 +1,399 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/epistles"
+	"github.com/bbengfort/epistolary/pkg/server/tokens"
+	"github.com/bbengfort/epistolary/pkg/server/users"
+	"github.com/bbengfort/epistolary/pkg/utils/pagination"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// AdminListUsers lists all users, optionally searching for users whose username,
+// email, or full name contains the query.
+func (s *Server) AdminListUsers(c *gin.Context) {
+	var (
+		err      error
+		curPage  *pagination.Cursor
+		nextPage *pagination.Cursor
+		models   []*users.User
+		roles    []*users.Role
+	)
+
+	query := &api.AdminQuery{}
+	if err = c.BindQuery(query); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse page query"))
+		return
+	}
+
+	if curPage, err = parsePageQuery(c, query); err != nil {
+		return
+	}
+
+	if models, nextPage, err = users.List(c.Request.Context(), query.Query, curPage); err != nil {
+		if errors.Is(err, pagination.ErrTokenQueryMismatch) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not list users from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch users"))
+		return
+	}
+
+	// Fetch the roles once rather than for each user on the page
+	if roles, err = users.ListRoles(c.Request.Context()); err != nil {
+		sentry.Error(c).Err(err).Msg("could not list roles from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch users"))
+		return
+	}
+
+	titles := make(map[int64]string, len(roles))
+	for _, role := range roles {
+		titles[role.ID] = role.Title
+	}
+
+	out := &api.UserPage{
+		Users: make([]*api.User, 0, len(models)),
+	}
+
+	if out.NextPageToken, out.PrevPageToken, err = pageTokens(curPage, nextPage); err != nil {
+		sentry.Error(c).Err(err).Msg("could not create page tokens")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch users"))
+		return
+	}
+
+	for _, model := range models {
+		out.Users = append(out.Users, apiUser(model, titles[model.RoleID]))
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (s *Server) AdminFetchUser(c *gin.Context) {
+	var (
+		err  error
+		user *users.User
+	)
+
+	if user, err = s.adminUser(c); err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, apiUser(user, roleTitle(c, user)))
+}
+
+// AdminSetRole assigns a role to the user; administrators cannot change their own role
+// so that they cannot accidentally lock themselves out of the admin API.
+func (s *Server) AdminSetRole(c *gin.Context) {
+	var (
+		err  error
+		in   *api.SetRoleRequest
+		role *users.Role
+		user *users.User
+	)
+
+	if err = c.BindJSON(&in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse set role request"))
+		return
+	}
+
+	if in.Role == "" {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("role is required"))
+		return
+	}
+
+	if user, err = s.adminUser(c); err != nil {
+		return
+	}
+
+	if s.isCurrentUser(c, user) {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("cannot change your own role"))
+		return
+	}
+
+	if role, err = users.RoleFromTitle(c.Request.Context(), in.Role); err != nil {
+		if errors.Is(err, users.ErrUnknownRole) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not fetch role from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not set role"))
+		return
+	}
+
+	if err = user.SetRole(c.Request.Context(), role); err != nil {
+		if errors.Is(err, users.ErrUnknownRole) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not set user role in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not set role"))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiUser(user, role.Title))
+}
+
+// AdminSetDisabled returns a handler that disables or enables the account of the user.
+// Disabled users cannot login or refresh their access tokens.
+func (s *Server) AdminSetDisabled(disabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			err  error
+			user *users.User
+		)
+
+		if user, err = s.adminUser(c); err != nil {
+			return
+		}
+
+		if disabled && s.isCurrentUser(c, user) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse("cannot disable your own account"))
+			return
+		}
+
+		if err = user.SetDisabled(c.Request.Context(), disabled); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				c.JSON(http.StatusNotFound, api.ErrorResponse("user not found"))
+				return
+			}
+
+			sentry.Error(c).Err(err).Msg("could not update user in database")
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not update user"))
+			return
+		}
+
+		c.JSON(http.StatusOK, apiUser(user, roleTitle(c, user)))
+	}
+}
+
+// AdminImpersonate issues an access token for the user so that administrators can see
+// what the user sees when providing support. No refresh token is issued so that the
+// impersonation ends when the access token expires, and the token records which
+// administrator is impersonating the user. The cookies of the administrator are not
+// modified so that the administrator remains logged in as themselves. Impersonation
+// tokens cannot be used to impersonate other users.
+func (s *Server) AdminImpersonate(c *gin.Context) {
+	var (
+		err    error
+		user   *users.User
+		admin  *tokens.Claims
+		token  *jwt.Token
+		access string
+	)
+
+	if admin, err = GetUserClaims(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not fetch user claims")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	// Impersonation tokens cannot be used to impersonate another user, otherwise the
+	// chain of impersonations would hide which administrator is acting as the user.
+	if admin.Impersonator != "" {
+		c.JSON(http.StatusForbidden, api.ErrorResponse("cannot impersonate a user while impersonating another user"))
+		return
+	}
+
+	if user, err = s.adminUser(c); err != nil {
+		return
+	}
+
+	if s.isCurrentUser(c, user) {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("cannot impersonate yourself"))
+		return
+	}
+
+	claims := userClaims(c.Request.Context(), user)
+	claims.Impersonator = admin.Username
+
+	if token, err = s.tokens.CreateAccessToken(claims); err != nil {
+		sentry.Error(c).Err(err).Msg("could not create access token")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not impersonate user"))
+		return
+	}
+
+	if access, err = s.tokens.Sign(token); err != nil {
+		sentry.Error(c).Err(err).Msg("could not sign access token")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not impersonate user"))
+		return
+	}
+
+	log.Info().Str("admin", admin.Username).Int64("user_id", user.ID).Msg("administrator impersonating user")
+	c.JSON(http.StatusOK, &api.LoginReply{AccessToken: access})
+}
+
+// AdminListRoles lists the roles that can be assigned to users and their permissions.
+func (s *Server) AdminListRoles(c *gin.Context) {
+	var (
+		err   error
+		roles []*users.Role
+	)
+
+	if roles, err = users.ListRoles(c.Request.Context()); err != nil {
+		sentry.Error(c).Err(err).Msg("could not list roles from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch roles"))
+		return
+	}
+
+	out := &api.RoleList{
+		Roles: make([]*api.Role, 0, len(roles)),
+	}
+
+	for _, role := range roles {
+		var permissions []*users.Permission
+		if permissions, err = role.Permissions(c.Request.Context(), false); err != nil {
+			sentry.Error(c).Err(err).Msg("could not fetch role permissions from database")
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch roles"))
+			return
+		}
+
+		item := &api.Role{
+			ID:          role.ID,
+			Title:       role.Title,
+			Description: role.Description.String,
+			Permissions: make([]string, 0, len(permissions)),
+		}
+
+		for _, permission := range permissions {
+			item.Permissions = append(item.Permissions, permission.Title)
+		}
+
+		out.Roles = append(out.Roles, item)
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// AdminListEpistles browses the epistles of all users, optionally filtered by a
+// full-text query, along with the number of users that are reading each epistle.
+func (s *Server) AdminListEpistles(c *gin.Context) {
+	var (
+		err      error
+		curPage  *pagination.Cursor
+		nextPage *pagination.Cursor
+		models   []*epistles.Epistle
+	)
+
+	query := &api.AdminQuery{}
+	if err = c.BindQuery(query); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse page query"))
+		return
+	}
+
+	if curPage, err = parsePageQuery(c, query); err != nil {
+		return
+	}
+
+	if models, nextPage, err = epistles.Browse(c.Request.Context(), query.Query, curPage); err != nil {
+		if errors.Is(err, pagination.ErrTokenQueryMismatch) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not browse epistles from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch epistles"))
+		return
+	}
+
+	out := &api.EpistlePage{
+		Epistles: make([]*api.Epistle, 0, len(models)),
+	}
+
+	if out.NextPageToken, out.PrevPageToken, err = pageTokens(curPage, nextPage); err != nil {
+		sentry.Error(c).Err(err).Msg("could not create page tokens")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch epistles"))
+		return
+	}
+
+	for _, model := range models {
+		out.Epistles = append(out.Epistles, &api.Epistle{
+			ID:        model.ID,
+			Link:      model.Link,
+			Title:     model.Title.String,
+			SiteName:  model.SiteName.String,
+			Favicon:   model.Favicon.String,
+			Readers:   model.Readers,
+			Synced:    api.Timestamp{Time: model.Synced.Time},
+			SyncError: model.SyncError.String,
+			Created:   api.Timestamp{Time: model.Created},
+			Modified:  api.Timestamp{Time: model.Modified},
+		})
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// Fetches the user specified by the userID in the route, writing an error response to
+// the request and returning an error if the user could not be fetched.
+func (s *Server) adminUser(c *gin.Context) (user *users.User, err error) {
+	var userID int64
+	if userID, err = strconv.ParseInt(c.Param("userID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("user not found"))
+		return nil, err
+	}
+
+	if user, err = users.UserFromID(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("user not found"))
+			return nil, err
+		}
+
+		sentry.Error(c).Err(err).Msg("could not fetch user from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return nil, err
+	}
+	return user, nil
+}
+
+// Returns true if the user is the authenticated user making the request.
+func (s *Server) isCurrentUser(c *gin.Context, user *users.User) bool {
+	userID, err := GetUserID(c)
+	return err == nil && userID == user.ID
+}
+
+// Parses the page token of the query if one was supplied, otherwise returns the cursor
+// for the first page of results with the requested page size. Writes an error response
+// to the request and returns an error if the page token is invalid.
+func parsePageQuery(c *gin.Context, query *api.AdminQuery) (cursor *pagination.Cursor, err error) {
+	if query.PageToken != "" {
+		if cursor, err = pagination.Parse(query.PageToken); err != nil {
+			sentry.Warn(c).Err(err).Msg("invalid next page token")
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return nil, err
+		}
+		return cursor, nil
+	}
+	return pagination.New(0, 0, uint32(query.PageSize)), nil
+}
+
+// Returns the next and previous page tokens for offset paginated results.
+func pageTokens(curPage, nextPage *pagination.Cursor) (next, prev string, err error) {
+	if nextPage != nil {
+		if next, err = nextPage.PageToken(); err != nil {
+			return "", "", err
+		}
+	}
+
+	if prevPage := epistles.PrevPage(curPage); prevPage != nil {
+		if prev, err = prevPage.PageToken(); err != nil {
+			return "", "", err
+		}
+	}
+	return next, prev, nil
+}
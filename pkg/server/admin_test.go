@@ -0,0 +1,20 @@
+package server_test
+
+import (
+	"net/http"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/server/tokens"
+)
+
+func (suite *epistolaryTestSuite) TestAdminImpersonateImpersonator() {
+	require := suite.Require()
+
+	// An impersonation token cannot be used to impersonate another user
+	claims := &tokens.Claims{Name: "Jane Doe", Email: "jane@example.com", Permissions: []string{"admin:cms"}, Impersonator: "admin"}
+	claims.SetSubjectID(42)
+
+	tks := suite.signClaims(claims)
+	require.Equal(http.StatusForbidden, suite.doRequest(http.MethodPost, "/v1/admin/users/7/impersonate", tks, nil, nil))
+	require.NoError(db.Mock().ExpectationsWereMet())
+}
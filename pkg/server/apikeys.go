@@ -0,0 +1,295 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/apikeys"
+	"github.com/bbengfort/epistolary/pkg/server/tokens"
+	"github.com/bbengfort/epistolary/pkg/server/users"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/gin-gonic/gin"
+)
+
+// ListAPIKeys returns the user's API keys; the keys themselves are only available when
+// the key is created.
+func (s *Server) ListAPIKeys(c *gin.Context) {
+	var (
+		err    error
+		userID int64
+		keys   []*apikeys.Key
+	)
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if keys, err = apikeys.List(c.Request.Context(), userID); err != nil {
+		sentry.Error(c).Err(err).Msg("could not list api keys from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch api keys"))
+		return
+	}
+
+	out := &api.APIKeyList{
+		APIKeys: make([]*api.APIKey, 0, len(keys)),
+	}
+
+	for _, key := range keys {
+		out.APIKeys = append(out.APIKeys, apiKey(key))
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateAPIKey creates a new API key with a subset of the user's permissions and
+// returns the key, which cannot be retrieved again.
+func (s *Server) CreateAPIKey(c *gin.Context) {
+	var (
+		err    error
+		in     *api.APIKey
+		userID int64
+	)
+
+	if err = c.BindJSON(&in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse api key"))
+		return
+	}
+
+	if in.ID != 0 || in.Key != "" {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("cannot specify the id or key of a new api key"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	key := &apikeys.Key{
+		UserID:      userID,
+		Name:        in.Name,
+		Permissions: in.Permissions,
+		Expires:     in.Expires.Time,
+	}
+
+	if err = key.Create(c.Request.Context()); err != nil {
+		if isAPIKeyValidationError(err) || errors.Is(err, apikeys.ErrTooManyKeys) || errors.Is(err, apikeys.ErrInvalidExpiration) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not create api key")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not create api key"))
+		return
+	}
+
+	out := apiKey(key)
+	out.Key = key.Secret
+	c.JSON(http.StatusCreated, out)
+}
+
+// FetchAPIKey returns the user's API key without the key itself.
+func (s *Server) FetchAPIKey(c *gin.Context) {
+	var (
+		err    error
+		userID int64
+		keyID  int64
+		key    *apikeys.Key
+	)
+
+	if keyID, err = strconv.ParseInt(c.Param("keyID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("api key not found"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if key, err = apikeys.Get(c.Request.Context(), keyID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("api key not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not fetch api key")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch api key"))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiKey(key))
+}
+
+// UpdateAPIKey changes the name and permissions of the user's API key; the key and its
+// expiration cannot be changed.
+func (s *Server) UpdateAPIKey(c *gin.Context) {
+	var (
+		err    error
+		in     *api.APIKey
+		userID int64
+		keyID  int64
+	)
+
+	if keyID, err = strconv.ParseInt(c.Param("keyID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("api key not found"))
+		return
+	}
+
+	if err = c.BindJSON(&in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse api key"))
+		return
+	}
+
+	if in.ID != 0 && in.ID != keyID {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("api key id does not match url"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	key := &apikeys.Key{
+		ID:          keyID,
+		UserID:      userID,
+		Name:        in.Name,
+		Permissions: in.Permissions,
+	}
+
+	if err = key.Update(c.Request.Context()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("api key not found"))
+			return
+		}
+
+		if isAPIKeyValidationError(err) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not update api key")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not update api key"))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiKey(key))
+}
+
+// DeleteAPIKey revokes the user's API key so that it can no longer be used.
+func (s *Server) DeleteAPIKey(c *gin.Context) {
+	var (
+		err    error
+		userID int64
+		keyID  int64
+	)
+
+	if keyID, err = strconv.ParseInt(c.Param("keyID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("api key not found"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if err = apikeys.Delete(c.Request.Context(), keyID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("api key not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not delete api key")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not delete api key"))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// RequireLogin rejects requests that were authenticated with an API key, with an
+// impersonation token, or with a token issued to an OAuth client. It protects the
+// endpoints that manage the account, sessions, and API keys of the user so that a
+// leaked API key cannot be used to take over the account or to escalate its own
+// permissions, and so that administrators impersonating the user and third-party
+// clients cannot modify the credentials of the user. Must be used after Authenticate.
+func (s *Server) RequireLogin(c *gin.Context) {
+	claims, err := GetUserClaims(c)
+	if err != nil || claims.APIKeyID != 0 {
+		c.AbortWithStatusJSON(http.StatusForbidden, api.ErrorResponse("api keys cannot be used for this request, please login"))
+		return
+	}
+
+	if claims.Impersonator != "" {
+		c.AbortWithStatusJSON(http.StatusForbidden, api.ErrorResponse("impersonated users cannot make this request"))
+		return
+	}
+
+	if claims.ClientID != "" {
+		c.AbortWithStatusJSON(http.StatusForbidden, api.ErrorResponse("oauth clients cannot make this request, please login"))
+		return
+	}
+	c.Next()
+}
+
+// Authenticates the API key and creates claims for its user that are limited to the
+// permissions of the key.
+func (s *Server) apiKeyClaims(c *gin.Context, secret string) (_ *tokens.Claims, err error) {
+	var key *apikeys.Key
+	if key, err = apikeys.Authenticate(c.Request.Context(), secret); err != nil {
+		if !errors.Is(err, apikeys.ErrInvalidKey) {
+			sentry.Error(c).Err(err).Msg("could not authenticate api key")
+		}
+		return nil, err
+	}
+
+	var user *users.User
+	if user, err = users.UserFromID(c.Request.Context(), key.UserID); err != nil {
+		sentry.Error(c).Err(err).Msg("could not retreive user of api key")
+		return nil, err
+	}
+
+	if user.IsDisabled() {
+		return nil, ErrUserDisabled
+	}
+
+	claims := userClaims(c.Request.Context(), user)
+	claims.Permissions = key.Permissions
+	claims.APIKeyID = key.ID
+	return claims, nil
+}
+
+func isAPIKeyValidationError(err error) bool {
+	return errors.Is(err, apikeys.ErrNameRequired) ||
+		errors.Is(err, apikeys.ErrNameLength) ||
+		errors.Is(err, apikeys.ErrNoPermissions) ||
+		errors.Is(err, apikeys.ErrInvalidPermissions)
+}
+
+func apiKey(key *apikeys.Key) *api.APIKey {
+	return &api.APIKey{
+		ID:          key.ID,
+		Name:        key.Name,
+		Permissions: key.Permissions,
+		Expires:     api.Timestamp{Time: key.Expires},
+		LastUsed:    api.Timestamp{Time: key.LastUsed.Time},
+		Created:     api.Timestamp{Time: key.Created},
+		Modified:    api.Timestamp{Time: key.Modified},
+	}
+}
@@ -0,0 +1,326 @@
+/*
+Package apikeys manages the personal API keys that users create to authenticate scripts
+and integrations without storing their password. Each key is granted a subset of the
+permissions of its user and expires; only the hash of the key is stored.
+*/
+package apikeys
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/utils/secrets"
+	"github.com/lib/pq"
+)
+
+// Limits on the API keys of a user.
+const (
+	MaxKeys       = 16
+	MaxNameLength = 255
+	DefaultTTL    = 90 * 24 * time.Hour
+	MaxTTL        = 366 * 24 * time.Hour
+)
+
+var (
+	ErrIDRequired         = errors.New("an api key id is required")
+	ErrInvalidKey         = errors.New("invalid or expired api key")
+	ErrTooManyKeys        = errors.New("too many api keys, revoke an unused key first")
+	ErrNameRequired       = errors.New("api key name is required")
+	ErrNameLength         = errors.New("api key name must be no more than 255 characters")
+	ErrNoPermissions      = errors.New("api key must be granted at least one permission")
+	ErrInvalidPermissions = errors.New("api keys can only be granted permissions that the user has")
+	ErrInvalidExpiration  = errors.New("api key must expire in the future and within a year")
+)
+
+// Key is a personal API key of a user. The secret is only available when the key is
+// created; only its hash is stored in the database.
+type Key struct {
+	ID          int64
+	UserID      int64
+	Name        string
+	Secret      string
+	Permissions []string
+	Expires     time.Time
+	LastUsed    sql.NullTime
+	Created     time.Time
+	Modified    time.Time
+}
+
+// Validate the name and permissions of the key, removing duplicate permissions.
+func (k *Key) Validate() error {
+	k.Name = strings.TrimSpace(k.Name)
+	if k.Name == "" {
+		return ErrNameRequired
+	}
+
+	if utf8.RuneCountInString(k.Name) > MaxNameLength {
+		return ErrNameLength
+	}
+
+	perms := make([]string, 0, len(k.Permissions))
+	seen := make(map[string]struct{}, len(k.Permissions))
+	for _, perm := range k.Permissions {
+		if _, ok := seen[perm]; !ok {
+			seen[perm] = struct{}{}
+			perms = append(perms, perm)
+		}
+	}
+
+	if k.Permissions = perms; len(k.Permissions) == 0 {
+		return ErrNoPermissions
+	}
+	return nil
+}
+
+const (
+	countKeysSQL  = "SELECT count(id) FROM api_keys WHERE user_id=$1"
+	createKeySQL  = "INSERT INTO api_keys (user_id, name, token, expires) VALUES ($1, $2, $3, $4) RETURNING id, created, modified"
+	grantPermsSQL = "INSERT INTO api_key_permissions (api_key_id, permission_id) SELECT $1, p.id FROM permissions p JOIN user_permissions up ON up.permission=p.title AND up.user_id=$2 WHERE p.title=ANY($3)"
+	clearPermsSQL = "DELETE FROM api_key_permissions WHERE api_key_id=$1"
+)
+
+// Create the API key for its user. The key may only be granted permissions that its
+// user has, otherwise ErrInvalidPermissions is returned. If the key has no expiration
+// then it expires after the DefaultTTL. The secret of the key must be given to the
+// user since it cannot be recovered.
+func (k *Key) Create(ctx context.Context) (err error) {
+	if k.UserID == 0 {
+		return ErrIDRequired
+	}
+
+	if err = k.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if k.Expires.IsZero() {
+		k.Expires = now.Add(DefaultTTL)
+	}
+
+	if !k.Expires.After(now) || k.Expires.After(now.Add(MaxTTL)) {
+		return ErrInvalidExpiration
+	}
+
+	if k.Secret, err = secrets.New(secrets.DefaultLength); err != nil {
+		return err
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var count int64
+	if err = tx.QueryRow(countKeysSQL, k.UserID).Scan(&count); err != nil {
+		return err
+	}
+
+	if count >= MaxKeys {
+		return ErrTooManyKeys
+	}
+
+	if err = tx.QueryRow(createKeySQL, k.UserID, k.Name, secrets.Hash(k.Secret), k.Expires).Scan(&k.ID, &k.Created, &k.Modified); err != nil {
+		return err
+	}
+
+	if err = k.grant(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const (
+	updateKeySQL = "UPDATE api_keys SET name=$3 WHERE id=$1 AND user_id=$2 RETURNING expires, last_used, created, modified"
+)
+
+// Update the name and permissions of the API key; the expiration of a key cannot be
+// changed. Returns sql.ErrNoRows if the user does not have the key.
+func (k *Key) Update(ctx context.Context) (err error) {
+	if k.ID == 0 || k.UserID == 0 {
+		return ErrIDRequired
+	}
+
+	if err = k.Validate(); err != nil {
+		return err
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow(updateKeySQL, k.ID, k.UserID, k.Name).Scan(&k.Expires, &k.LastUsed, &k.Created, &k.Modified); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(clearPermsSQL, k.ID); err != nil {
+		return err
+	}
+
+	if err = k.grant(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Grants the validated permissions to the key, ensuring that every permission is held
+// by the user of the key.
+func (k *Key) grant(tx *sql.Tx) (err error) {
+	var result sql.Result
+	if result, err = tx.Exec(grantPermsSQL, k.ID, k.UserID, pq.Array(k.Permissions)); err != nil {
+		return err
+	}
+
+	if nRows, _ := result.RowsAffected(); nRows != int64(len(k.Permissions)) {
+		return ErrInvalidPermissions
+	}
+	return nil
+}
+
+const (
+	listKeysSQL = "SELECT k.id, k.user_id, k.name, k.expires, k.last_used, k.created, k.modified, COALESCE(array_agg(p.title ORDER BY p.title) FILTER (WHERE p.title IS NOT NULL), '{}') FROM api_keys k LEFT JOIN api_key_permissions kp ON kp.api_key_id=k.id LEFT JOIN permissions p ON p.id=kp.permission_id"
+)
+
+// List the API keys of the user, oldest first. The secrets of the keys are empty.
+func List(ctx context.Context, userID int64) (keys []*Key, err error) {
+	if userID == 0 {
+		return nil, ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(listKeysSQL+" WHERE k.user_id=$1 GROUP BY k.id ORDER BY k.created, k.id", userID); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys = make([]*Key, 0)
+	for rows.Next() {
+		k := &Key{}
+		if err = rows.Scan(&k.ID, &k.UserID, &k.Name, &k.Expires, &k.LastUsed, &k.Created, &k.Modified, pq.Array(&k.Permissions)); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return keys, nil
+}
+
+// Get the API key of the user. Returns sql.ErrNoRows if the user does not have the key.
+func Get(ctx context.Context, keyID, userID int64) (k *Key, err error) {
+	if keyID == 0 || userID == 0 {
+		return nil, ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	k = &Key{}
+	if err = tx.QueryRow(listKeysSQL+" WHERE k.id=$1 AND k.user_id=$2 GROUP BY k.id", keyID, userID).Scan(&k.ID, &k.UserID, &k.Name, &k.Expires, &k.LastUsed, &k.Created, &k.Modified, pq.Array(&k.Permissions)); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return k, nil
+}
+
+const (
+	deleteKeySQL = "DELETE FROM api_keys WHERE id=$1 AND user_id=$2"
+)
+
+// Delete the user's API key so that it can no longer be used. Returns sql.ErrNoRows if
+// the user does not have the key.
+func Delete(ctx context.Context, keyID, userID int64) (err error) {
+	if keyID == 0 || userID == 0 {
+		return ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(deleteKeySQL, keyID, userID); err != nil {
+		return err
+	}
+
+	if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+const (
+	authenticateSQL = "UPDATE api_keys SET last_used=NOW() WHERE token=$1 AND expires > NOW() RETURNING id, user_id, name, expires, last_used, created, modified"
+	keyPermsSQL     = "SELECT p.title FROM api_key_permissions kp JOIN permissions p ON p.id=kp.permission_id JOIN user_permissions up ON up.permission=p.title AND up.user_id=$2 WHERE kp.api_key_id=$1 ORDER BY p.title"
+)
+
+// Authenticate looks up the API key with the secret and records that it was used. The
+// permissions of the returned key are limited to the permissions its user currently
+// has, so a key loses any permission that is removed from the role of its user.
+// Returns ErrInvalidKey if the key does not exist, has been deleted, or has expired.
+func Authenticate(ctx context.Context, secret string) (k *Key, err error) {
+	if secret == "" {
+		return nil, ErrInvalidKey
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	k = &Key{}
+	if err = tx.QueryRow(authenticateSQL, secrets.Hash(secret)).Scan(&k.ID, &k.UserID, &k.Name, &k.Expires, &k.LastUsed, &k.Created, &k.Modified); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidKey
+		}
+		return nil, err
+	}
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(keyPermsSQL, k.ID, k.UserID); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	k.Permissions = make([]string, 0)
+	for rows.Next() {
+		var perm string
+		if err = rows.Scan(&perm); err != nil {
+			return nil, err
+		}
+		k.Permissions = append(k.Permissions, perm)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
@@ -0,0 +1,25 @@
+package apikeys_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bbengfort/epistolary/pkg/server/apikeys"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	key := &apikeys.Key{Name: "  backup script ", Permissions: []string{"epistles:read", "epistles:read", "epistles:update"}}
+	require.NoError(t, key.Validate())
+	require.Equal(t, "backup script", key.Name)
+	require.Equal(t, []string{"epistles:read", "epistles:update"}, key.Permissions, "duplicate permissions should be removed")
+
+	key = &apikeys.Key{Permissions: []string{"epistles:read"}}
+	require.ErrorIs(t, key.Validate(), apikeys.ErrNameRequired)
+
+	key.Name = strings.Repeat("k", apikeys.MaxNameLength+1)
+	require.ErrorIs(t, key.Validate(), apikeys.ErrNameLength)
+
+	key = &apikeys.Key{Name: "backup script"}
+	require.ErrorIs(t, key.Validate(), apikeys.ErrNoPermissions)
+}
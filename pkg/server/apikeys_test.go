@@ -0,0 +1,22 @@
+package server_test
+
+import (
+	"net/http"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/server/tokens"
+)
+
+func (suite *epistolaryTestSuite) TestRequireLoginImpersonator() {
+	require := suite.Require()
+
+	// Impersonation tokens cannot be used to manage the account of the user
+	claims := &tokens.Claims{Name: "Jane Doe", Email: "jane@example.com", Impersonator: "admin"}
+	claims.SetSubjectID(42)
+
+	tks := suite.signClaims(claims)
+	require.Equal(http.StatusForbidden, suite.doRequest(http.MethodDelete, "/v1/users/me", tks, &api.DeleteAccountRequest{Password: testPassword}, nil))
+	require.Equal(http.StatusForbidden, suite.doRequest(http.MethodGet, "/v1/apikeys", tks, nil, nil))
+	require.NoError(db.Mock().ExpectationsWereMet())
+}
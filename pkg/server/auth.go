@@ -1,11 +1,13 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"net/http"
 	"regexp"
 	"time"
+	"unicode/utf8"
 
 	"github.com/bbengfort/epistolary/pkg/api/v1"
 	"github.com/bbengfort/epistolary/pkg/server/passwd"
@@ -25,10 +27,10 @@ func (s *Server) Register(c *gin.Context) {
 		return
 	}
 
-	// Check required fields
-	if in.Email == "" || in.Username == "" || in.Password == "" {
-		c.Error(errors.New("missing required field"))
-		c.JSON(http.StatusBadRequest, api.ErrorResponse("email, username, and password are required"))
+	// Check the fields and the password strength before creating the derived key
+	if err := users.ValidateRegistration(in.Email, in.Username, in.Password); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, validationErrorResponse(err))
 		return
 	}
 
@@ -53,22 +55,37 @@ func (s *Server) Register(c *gin.Context) {
 	}
 
 	// Store the user in the database
-	// TODO: handle uniqueness constraints (e.g. username already taken; email already registered, etc.)
 	if err = user.Create(c.Request.Context()); err != nil {
+		if errors.Is(err, users.ErrUsernameTaken) || errors.Is(err, users.ErrEmailTaken) {
+			c.JSON(http.StatusConflict, validationErrorResponse(err))
+			return
+		}
+
+		var verrs users.ValidationErrors
+		if errors.As(err, &verrs) {
+			c.JSON(http.StatusBadRequest, validationErrorResponse(err))
+			return
+		}
+
 		sentry.Error(c).Err(err).Msg("could not create user in database")
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not register user"))
 		return
 	}
 
+	// The user cannot login until they verify their email address; if the email cannot
+	// be sent the user can request another by logging in or resetting their password.
+	s.sendVerification(c, user)
+
 	c.JSON(http.StatusNoContent, nil)
 }
 
 func (s *Server) Login(c *gin.Context) {
 	var (
-		err  error
-		in   *api.LoginRequest
-		out  *api.LoginReply
-		user *users.User
+		err    error
+		in     *api.LoginRequest
+		out    *api.LoginReply
+		user   *users.User
+		factor *users.MFA
 	)
 
 	if err := c.BindJSON(&in); err != nil {
@@ -83,38 +100,97 @@ func (s *Server) Login(c *gin.Context) {
 		return
 	}
 
+	// Refuse logins from IP addresses and for usernames with too many recent failures
+	// before any work is done, in particular before the derived key is verified.
+	ip := c.ClientIP()
+	if retryAfter := s.logins.Check(ip, in.Username); retryAfter > 0 {
+		tooManyLogins(c, retryAfter)
+		return
+	}
+
 	// Fetch the user from the database
 	if user, err = users.UserFromUsername(c.Request.Context(), in.Username, true); err != nil {
 		c.Error(err)
+		s.failedLogin(c, ip, in.Username)
 		c.JSON(http.StatusUnauthorized, api.ErrorResponse("authentication failed"))
 		return
 	}
 
+	// Accounts with too many consecutive failed logins are locked until the lockout
+	// expires, even if the correct password is given.
+	if user.IsLocked() {
+		tooManyLogins(c, time.Until(user.LockedUntil.Time))
+		return
+	}
+
 	// Verify the derived key for the user
 	// NOTE: if the user is not verified we MUST not proceed with the rest of the function!
 	if verified, err := passwd.VerifyDerivedKey(user.Password, in.Password); err != nil || !verified {
 		if err != nil {
 			c.Error(err)
 		}
+
+		s.failedLogin(c, ip, in.Username)
+		if err = user.FailedLogin(c.Request.Context(), s.conf.Login.MaxConsecutive, s.conf.Login.Lockout); err != nil {
+			sentry.Warn(c).Err(err).Msg("could not record failed login")
+		}
+
 		c.JSON(http.StatusUnauthorized, api.ErrorResponse("authentication failed"))
 		return
 	}
 
-	// Create the access and refresh tokens from the claims
-	out = &api.LoginReply{}
-	claims := &tokens.Claims{
-		Name:     user.FullName.String,
-		Username: user.Username,
-		Email:    user.Email,
+	// Disabled users cannot login until their account is enabled by an administrator.
+	if user.IsDisabled() {
+		c.JSON(http.StatusForbidden, api.ErrorResponse("account has been disabled"))
+		return
 	}
-	claims.SetSubjectID(user.ID)
 
-	// Role and permissions should already be on the user from the earlier request.
-	role, _ := user.Role(c.Request.Context(), false)
-	claims.Role = role.Title
-	claims.Permissions, _ = user.Permissions(c.Request.Context(), false)
+	// Users must verify their email address before they can login; resend the
+	// verification email in case the previous email was lost or has expired.
+	if !user.EmailVerified.Valid {
+		s.sendVerification(c, user)
+		c.JSON(http.StatusForbidden, api.ErrorResponse("email address has not been verified, check your email for a verification link"))
+		return
+	}
 
-	if out.AccessToken, out.RefreshToken, err = s.tokens.CreateTokens(claims); err != nil {
+	// Users that have enabled multi-factor authentication must complete the login with
+	// a code from their authenticator, so only an MFA challenge token is returned.
+	if factor, err = users.GetMFA(c.Request.Context(), user.ID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		sentry.Error(c).Err(err).Msg("could not fetch mfa of user")
+		c.JSON(http.StatusUnauthorized, api.ErrorResponse("authentication failed"))
+		return
+	}
+
+	if factor.IsEnabled() {
+		out = &api.LoginReply{MFARequired: true}
+		if out.MFAToken, err = s.tokens.CreateMFAToken(user.ID); err != nil {
+			sentry.Error(c).Err(err).Msg("could not create mfa challenge token")
+			c.JSON(http.StatusUnauthorized, api.ErrorResponse("authentication failed"))
+			return
+		}
+		c.JSON(http.StatusOK, out)
+		return
+	}
+
+	// The user has been authenticated so forget their previous failed logins; users with
+	// MFA enabled are only authenticated once they have completed the challenge.
+	s.logins.Succeed(in.Username)
+	if err = user.ResetFailedLogins(c.Request.Context()); err != nil {
+		sentry.Warn(c).Err(err).Msg("could not reset failed logins")
+	}
+
+	s.completeLogin(c, user)
+}
+
+// Creates the access and refresh tokens of a new session for the user once they have
+// been authenticated, sets them as cookies for the front-end, and returns them.
+func (s *Server) completeLogin(c *gin.Context, user *users.User) {
+	var err error
+
+	// Create the access and refresh tokens from the claims for a new session
+	out := &api.LoginReply{}
+	claims := userClaims(c.Request.Context(), user)
+	if out.AccessToken, out.RefreshToken, err = s.issueTokens(c, user.ID, claims); err != nil {
 		sentry.Error(c).Err(err).Msg("could not create access and refresh tokens")
 		c.JSON(http.StatusUnauthorized, api.ErrorResponse("authentication failed"))
 		return
@@ -136,13 +212,175 @@ func (s *Server) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, out)
 }
 
+// Records a failed login from the IP address for the username, logging a warning if
+// the failure caused the IP address or username to be locked out.
+func (s *Server) failedLogin(c *gin.Context, ip, username string) {
+	if lockout := s.logins.Fail(ip, username); lockout > 0 {
+		sentry.Warn(c).Str("client_ip", ip).Str("username", username).Str("lockout", lockout.String()).Msg("too many failed logins")
+	}
+}
+
+// Logout revokes the session of the access or refresh token so that the refresh token
+// cannot be used again, even if it was copied from the cookies, then clears the cookies.
 func (s *Server) Logout(c *gin.Context) {
+	if claims := s.sessionClaims(c); claims != nil {
+		if userID, err := claims.SubjectID(); err == nil {
+			if err = users.RevokeSession(c.Request.Context(), claims.ID, userID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+				sentry.Error(c).Err(err).Msg("could not revoke session")
+			}
+		}
+	}
+
 	// Clear cookies but setting the access and refresh tokens to having expired.
 	c.SetCookie(AccessTokenCookie, "", -1, "/", s.conf.Token.CookieDomain, true, true)
 	c.SetCookie(RefreshTokenCookie, "", -1, "/", s.conf.Token.CookieDomain, true, true)
 	c.Status(http.StatusNoContent)
 }
 
+// VerifyEmail marks the email address of the user as verified using the token that
+// was emailed to them when they registered.
+func (s *Server) VerifyEmail(c *gin.Context) {
+	var (
+		err error
+		in  *api.VerifyRequest
+	)
+
+	if err = c.BindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse verify request"))
+		return
+	}
+
+	if in.Token == "" {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("token is required"))
+		return
+	}
+
+	if _, err = users.VerifyEmail(c.Request.Context(), in.Token); err != nil {
+		if errors.Is(err, users.ErrInvalidToken) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not verify email address")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// ForgotPassword emails a password reset token to the user with the email address. To
+// prevent the enumeration of accounts, the response is the same whether or not a user
+// with the email address exists.
+func (s *Server) ForgotPassword(c *gin.Context) {
+	var (
+		err   error
+		in    *api.ForgotPasswordRequest
+		user  *users.User
+		token string
+	)
+
+	if err = c.BindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse forgot password request"))
+		return
+	}
+
+	if in.Email == "" {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("email is required"))
+		return
+	}
+
+	if user, err = users.UserFromEmail(c.Request.Context(), in.Email, false); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNoContent, nil)
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not fetch user by email")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if token, err = user.CreateResetToken(c.Request.Context()); err != nil {
+		if errors.Is(err, users.ErrTokenRecentlySent) {
+			c.JSON(http.StatusNoContent, nil)
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not create password reset token")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	// The failure to send the email is only logged so that the response does not reveal
+	// that an account exists with the email address.
+	if err = s.sendResetEmail(c.Request.Context(), user, token); err != nil {
+		sentry.Error(c).Err(err).Msg("could not send password reset email")
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// ResetPassword sets a new password for the user using the token that was emailed to
+// them by ForgotPassword.
+func (s *Server) ResetPassword(c *gin.Context) {
+	var (
+		err error
+		in  *api.ResetPasswordRequest
+	)
+
+	if err = c.BindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse reset password request"))
+		return
+	}
+
+	if in.Token == "" || in.Password == "" {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("token and password are required"))
+		return
+	}
+
+	if err = users.ValidatePassword(in.Password); err != nil {
+		c.JSON(http.StatusBadRequest, validationErrorResponse(users.ValidationErrors{{Field: "password", Err: err}}))
+		return
+	}
+
+	var password string
+	if password, err = passwd.CreateDerivedKey(in.Password); err != nil {
+		sentry.Error(c).Err(err).Msg("could not create derived key")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not reset password"))
+		return
+	}
+
+	if _, err = users.ResetPassword(c.Request.Context(), in.Token, password); err != nil {
+		if errors.Is(err, users.ErrInvalidToken) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not reset password")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not reset password"))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Creates a verification token for the user and emails it to them. Errors are logged
+// rather than returned since the user can always request another verification email.
+func (s *Server) sendVerification(c *gin.Context, user *users.User) {
+	token, err := user.CreateVerificationToken(c.Request.Context())
+	if err != nil {
+		if !errors.Is(err, users.ErrTokenRecentlySent) {
+			sentry.Error(c).Err(err).Msg("could not create email verification token")
+		}
+		return
+	}
+
+	if err = s.sendVerificationEmail(c.Request.Context(), user, token); err != nil {
+		sentry.Error(c).Err(err).Msg("could not send email verification")
+	}
+}
+
 const (
 	authorization      = "Authorization"
 	UserClaims         = "user_claims"
@@ -150,35 +388,26 @@ const (
 	RefreshTokenCookie = "refresh_token"
 )
 
-// used to extract the access token from the header
+// used to extract the access token or api key from the header
 var (
 	bearer = regexp.MustCompile(`^\s*[Bb]earer\s+([a-zA-Z0-9_\-\.]+)\s*$`)
+	apikey = regexp.MustCompile(`^\s*[Aa]pi[Kk]ey\s+([a-zA-Z0-9]+)\s*$`)
 )
 
-func (s *Server) Authenticate(c *gin.Context) {
-	var (
-		err    error
-		ats    string
-		claims *tokens.Claims
-	)
-
-	// Parse and verify JWT token in authorization header.
-	if ats, err = GetAccessToken(c); err == nil {
-		// If there is an access token, verify that it is valid.
-		claims, err = s.tokens.Verify(ats)
-	}
+// Errors returned when reauthenticating with a refresh token whose session has been
+// revoked, that was issued before the password of the user was changed, whose user
+// has since been disabled, or that was issued to a different client.
+var (
+	ErrTokenRevoked = errors.New("token has been revoked")
+	ErrUserDisabled = errors.New("account has been disabled")
+	ErrWrongClient  = errors.New("token was not issued to the client")
+)
 
-	// If there are no claims, attempt to reauthenticate with the refresh token
-	if claims == nil {
-		// If the access token is no longer valid or not present, attempt to
-		// reauthenticate with the refresh token.
-		var rerr error
-		if claims, rerr = s.Reauthenticate(c); rerr != nil {
-			c.Error(err)
-			c.Error(rerr)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, api.ErrorResponse("authentication required"))
-			return
-		}
+func (s *Server) Authenticate(c *gin.Context) {
+	claims, err := s.authenticate(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, api.ErrorResponse("authentication required"))
+		return
 	}
 
 	// Add claims to context for use in downstream processing and continue
@@ -197,57 +426,121 @@ func (s *Server) Authenticate(c *gin.Context) {
 	c.Next()
 }
 
+// Returns the claims of the API key or access token of the request, reauthenticating
+// with the refresh token if the access token is missing or invalid. Unlike the
+// Authenticate middleware the request is not aborted if it is not authenticated.
+func (s *Server) authenticate(c *gin.Context) (claims *tokens.Claims, err error) {
+	// Scripts and integrations authenticate with an API key instead of a JWT token; an
+	// invalid API key is never reauthenticated with the refresh token.
+	if key, ok := GetAPIKey(c); ok {
+		if claims, err = s.apiKeyClaims(c, key); err != nil {
+			c.Error(err)
+			return nil, err
+		}
+		return claims, nil
+	}
+
+	// Parse and verify JWT token in authorization header.
+	var ats string
+	if ats, err = GetAccessToken(c); err == nil {
+		if claims, err = s.tokens.Verify(ats); err == nil {
+			return claims, nil
+		}
+	}
+
+	// If the access token is no longer valid or not present, attempt to
+	// reauthenticate with the refresh token.
+	var rerr error
+	if claims, rerr = s.Reauthenticate(c); rerr != nil {
+		c.Error(err)
+		c.Error(rerr)
+		return nil, rerr
+	}
+	return claims, nil
+}
+
 func (s *Server) Reauthenticate(c *gin.Context) (_ *tokens.Claims, err error) {
-	// Collect and verify the refresh token from the request
+	// Collect the refresh token from the request
 	var refresh string
 	if refresh, err = GetRefreshToken(c); err != nil {
 		return nil, err
 	}
 
+	var (
+		claims                    *tokens.Claims
+		accessToken, refreshToken string
+	)
+	if claims, accessToken, refreshToken, err = s.refreshTokens(c, refresh, ""); err != nil {
+		return nil, err
+	}
+
+	// Set the access and refresh tokens as cookies for the front-end
+	if err := SetAuthCookies(c, accessToken, refreshToken, s.conf.Token.CookieDomain); err != nil {
+		sentry.Error(c).Err(err).Msg("could not set access and refresh token cookies")
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Verifies the refresh token and issues new access and refresh tokens for its session
+// with the current claims of its user. The client ID must match the OAuth client that
+// the token was issued to, or be empty for first-party tokens. Returns ErrTokenRevoked
+// if the session has been revoked or the password of the user has been changed since
+// the token was issued.
+func (s *Server) refreshTokens(c *gin.Context, refresh, clientID string) (claims *tokens.Claims, accessToken, refreshToken string, err error) {
 	var refreshClaims *tokens.Claims
 	if refreshClaims, err = s.tokens.Verify(refresh); err != nil {
-		return nil, err
+		return nil, "", "", err
+	}
+
+	if refreshClaims.ClientID != clientID {
+		return nil, "", "", ErrWrongClient
 	}
 
 	// Fetch the user from the subject ID of the refreshClaims
 	var userID int64
 	if userID, err = refreshClaims.SubjectID(); err != nil {
 		sentry.Error(c).Err(err).Msg("could not parse subject id from refresh claims")
-		return nil, err
+		return nil, "", "", err
 	}
 
 	var user *users.User
 	if user, err = users.UserFromID(c.Request.Context(), userID); err != nil {
 		sentry.Error(c).Err(err).Msg("could not retreive user from id on claims")
-		return nil, err
+		return nil, "", "", err
 	}
 
-	claims := &tokens.Claims{
-		Name:     user.FullName.String,
-		Username: user.Username,
-		Email:    user.Email,
+	if user.IsDisabled() {
+		return nil, "", "", ErrUserDisabled
 	}
-	claims.SetSubjectID(user.ID)
 
-	// Role and permissions should already be on the user from the earlier request.
-	role, _ := user.Role(c.Request.Context(), false)
-	claims.Role = role.Title
-	claims.Permissions, _ = user.Permissions(c.Request.Context(), false)
+	// Changing the password revokes all refresh tokens issued before the change; the
+	// issued at timestamp of the token only has second precision.
+	if user.PasswordChanged.Valid && refreshClaims.IssuedAt != nil {
+		if refreshClaims.IssuedAt.Before(user.PasswordChanged.Time.Truncate(time.Second)) {
+			return nil, "", "", ErrTokenRevoked
+		}
+	}
 
-	var accessToken, refreshToken string
-	if accessToken, refreshToken, err = s.tokens.CreateTokens(claims); err != nil {
-		sentry.Error(c).Err(err).Msg("could not create access and refresh tokens")
-		return nil, err
+	// Keep the ID of the refresh token so that the session is refreshed rather than a
+	// new session created; if the session has been revoked the tokens are rejected.
+	// Tokens issued to clients remain limited to the scope that the user authorized.
+	if refreshClaims.ClientID != "" {
+		claims = oauthClaims(c.Request.Context(), user, refreshClaims.ClientID, refreshClaims.Scope)
+	} else {
+		claims = userClaims(c.Request.Context(), user)
 	}
+	claims.ID = refreshClaims.ID
 
-	// Set the access and refresh tokens as cookies for the front-end
-	if err := SetAuthCookies(c, accessToken, refreshToken, s.conf.Token.CookieDomain); err != nil {
-		sentry.Error(c).Err(err).Msg("could not set access and refresh token cookies")
-		return nil, err
+	if accessToken, refreshToken, err = s.issueTokens(c, userID, claims); err != nil {
+		if !errors.Is(err, ErrTokenRevoked) {
+			sentry.Error(c).Err(err).Msg("could not create access and refresh tokens")
+		}
+		return nil, "", "", err
 	}
 
 	log.Debug().Int64("userID", userID).Msg("user reauthenticated")
-	return claims, nil
+	return claims, accessToken, refreshToken, nil
 }
 
 func (s *Server) Authorize(permissions ...string) gin.HandlerFunc {
@@ -298,6 +591,14 @@ func GetAccessToken(c *gin.Context) (tks string, err error) {
 	return "", errors.New("no access token found in request")
 }
 
+// GetAPIKey returns the API key from the ApiKey scheme of the Authorization header.
+func GetAPIKey(c *gin.Context) (key string, ok bool) {
+	if match := apikey.FindStringSubmatch(c.GetHeader(authorization)); len(match) == 2 {
+		return match[1], true
+	}
+	return "", false
+}
+
 func GetRefreshToken(c *gin.Context) (tks string, err error) {
 	if tks, err = c.Cookie(RefreshTokenCookie); err != nil {
 		return "", errors.New("no refresh token found in request")
@@ -353,3 +654,99 @@ func SetAuthCookies(c *gin.Context, accessToken, refreshToken, domain string) (e
 
 	return nil
 }
+
+// Maximum length of the user agent stored with a session.
+const maxUserAgentLength = 512
+
+// Creates and signs the access and refresh tokens for the claims and records the
+// session of the refresh token. If the claims already have an ID the session with that
+// ID is refreshed, returning ErrTokenRevoked if the session has been revoked or has
+// expired; otherwise a new session is created for the user.
+func (s *Server) issueTokens(c *gin.Context, userID int64, claims *tokens.Claims) (accessToken, refreshToken string, err error) {
+	refresh := claims.ID != ""
+	if accessToken, refreshToken, err = s.tokens.CreateTokens(claims); err != nil {
+		return "", "", err
+	}
+
+	session := &users.Session{
+		ID:        claims.ID,
+		UserID:    userID,
+		ClientID:  sql.NullString{Valid: claims.ClientID != "", String: claims.ClientID},
+		UserAgent: sql.NullString{Valid: c.Request.UserAgent() != "", String: truncate(c.Request.UserAgent(), maxUserAgentLength)},
+		ClientIP:  sql.NullString{Valid: c.ClientIP() != "", String: c.ClientIP()},
+	}
+
+	if session.Expires, err = tokens.ExpiresAt(refreshToken); err != nil {
+		return "", "", err
+	}
+
+	if refresh {
+		if err = session.Refresh(c.Request.Context()); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", "", ErrTokenRevoked
+			}
+			return "", "", err
+		}
+		return accessToken, refreshToken, nil
+	}
+
+	if err = session.Create(c.Request.Context()); err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+// Returns the claims of the refresh or access token in the request without validating
+// them, so that the session of expired tokens can still be identified. Returns nil if
+// there is no token or its signature cannot be verified.
+func (s *Server) sessionClaims(c *gin.Context) *tokens.Claims {
+	for _, get := range []func(*gin.Context) (string, error){GetRefreshToken, GetAccessToken} {
+		if tks, err := get(c); err == nil {
+			if claims, err := s.tokens.Parse(tks); err == nil && claims.ID != "" {
+				return claims
+			}
+		}
+	}
+	return nil
+}
+
+// Truncates the string to at most n bytes without splitting a multi-byte character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// Creates the claims for the access and refresh tokens of the user.
+func userClaims(ctx context.Context, user *users.User) *tokens.Claims {
+	claims := &tokens.Claims{
+		Name:     user.FullName.String,
+		Username: user.Username,
+		Email:    user.Email,
+	}
+	claims.SetSubjectID(user.ID)
+
+	// Role and permissions should already be on the user from the earlier request.
+	role, _ := user.Role(ctx, false)
+	claims.Role = role.Title
+	claims.Permissions, _ = user.Permissions(ctx, false)
+	return claims
+}
+
+// Returns an error response that reports which fields of the request were invalid.
+func validationErrorResponse(err error) api.Reply {
+	rep := api.ErrorResponse(err)
+
+	var verrs users.ValidationErrors
+	if errors.As(err, &verrs) {
+		rep.Fields = make([]*api.FieldError, 0, len(verrs))
+		for _, ferr := range verrs {
+			rep.Fields = append(rep.Fields, &api.FieldError{Field: ferr.Field, Error: ferr.Error()})
+		}
+	}
+	return rep
+}
@@ -0,0 +1,74 @@
+package server_test
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/server/passwd"
+)
+
+func (suite *epistolaryTestSuite) TestLoginMFAFailures() {
+	require := suite.Require()
+	derivedKey, err := passwd.CreateDerivedKey(testPassword)
+	require.NoError(err, "could not create derived key")
+
+	// Logging in with the correct password does not forget the invalid codes given for
+	// the MFA challenge, otherwise the code could be brute-forced by logging in again.
+	now := time.Now()
+	mock := db.Mock()
+	for i := 1; i <= suite.conf.Login.MaxUserFailures; i++ {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id, full_name, email, email_verified, disabled, password, role_id, last_seen, pwchanged, failed_logins, locked_until, created, modified FROM users WHERE username=").
+			WithArgs("mallory").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "full_name", "email", "email_verified", "disabled", "password", "role_id", "last_seen", "pwchanged", "failed_logins", "locked_until", "created", "modified"}).
+				AddRow(7, "Mallory", "mallory@example.com", now, nil, derivedKey, 2, now, now, i-1, nil, now, now))
+		mock.ExpectQuery("SELECT id, title, description, created, modified FROM roles WHERE id=").
+			WithArgs(int64(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "created", "modified"}).AddRow(2, "Reader", nil, now, now))
+		mock.ExpectQuery("SELECT permission FROM user_permissions WHERE user_id=").
+			WithArgs(int64(7)).
+			WillReturnRows(sqlmock.NewRows([]string{"permission"}).AddRow("epistles:read"))
+		mock.ExpectCommit()
+		suite.expectMFA(7)
+
+		out := &api.LoginReply{}
+		require.Equal(http.StatusOK, suite.doRequest(http.MethodPost, "/v1/login", "", &api.LoginRequest{Username: "mallory", Password: testPassword}, out))
+		require.True(out.MFARequired)
+		require.Empty(out.AccessToken)
+		require.NoError(mock.ExpectationsWereMet())
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT full_name, email, email_verified, disabled, username, role_id, last_seen, pwchanged, failed_logins, locked_until, created, modified FROM users WHERE id=").
+			WithArgs(int64(7)).
+			WillReturnRows(sqlmock.NewRows([]string{"full_name", "email", "email_verified", "disabled", "username", "role_id", "last_seen", "pwchanged", "failed_logins", "locked_until", "created", "modified"}).
+				AddRow("Mallory", "mallory@example.com", now, nil, "mallory", 2, now, now, i-1, nil, now, now))
+		mock.ExpectCommit()
+		suite.expectMFA(7)
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE users SET failed_logins=").
+			WithArgs(int64(7), suite.conf.Login.MaxConsecutive, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"failed_logins", "locked_until"}).AddRow(i, nil))
+		mock.ExpectCommit()
+
+		require.Equal(http.StatusUnauthorized, suite.doRequest(http.MethodPost, "/v1/login/mfa", "", &api.MFALoginRequest{MFAToken: out.MFAToken, Code: "12345"}, nil))
+		require.NoError(mock.ExpectationsWereMet())
+	}
+
+	// The username is locked out even though the correct password is given
+	require.Equal(http.StatusTooManyRequests, suite.doRequest(http.MethodPost, "/v1/login", "", &api.LoginRequest{Username: "mallory", Password: testPassword}, nil))
+	require.NoError(mock.ExpectationsWereMet())
+}
+
+// Expects the enabled MFA of the user to be fetched from the database.
+func (suite *epistolaryTestSuite) expectMFA(userID int64) {
+	now := time.Now()
+	mock := db.Mock()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT secret, enabled, last_counter, created, modified FROM user_mfa WHERE user_id=").
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"secret", "enabled", "last_counter", "created", "modified"}).AddRow("JBSWY3DPEHPK3PXP", now, 0, now, now))
+	mock.ExpectCommit()
+}
@@ -1,10 +1,15 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/bbengfort/epistolary/pkg"
 	"github.com/bbengfort/epistolary/pkg/utils/logger"
+	"github.com/bbengfort/epistolary/pkg/utils/mailer"
 	"github.com/bbengfort/epistolary/pkg/utils/sentry"
 	"github.com/gin-gonic/gin"
 	"github.com/rotationalio/confire"
@@ -13,16 +18,22 @@ import (
 )
 
 type Config struct {
-	Maintenance  bool                `split_words:"true" default:"false"`
-	BindAddr     string              `split_words:"true" default:":8000"`
-	Mode         GinMode             `split_words:"true" default:"release"`
-	LogLevel     logger.LevelDecoder `split_words:"true" default:"info"`
-	ConsoleLog   bool                `split_words:"true" default:"false"`
-	AllowOrigins []string            `split_words:"true" default:"https://epistolary.app"`
-	Database     DatabaseConfig
-	Token        TokenConfig
-	Sentry       sentry.Config
-	processed    bool
+	Maintenance    bool                `split_words:"true" default:"false"`
+	BindAddr       string              `split_words:"true" default:":8000"`
+	Mode           GinMode             `split_words:"true" default:"release"`
+	LogLevel       logger.LevelDecoder `split_words:"true" default:"info"`
+	ConsoleLog     bool                `split_words:"true" default:"false"`
+	AllowOrigins   []string            `split_words:"true" default:"https://epistolary.app"`
+	TrustedProxies []string            `split_words:"true" desc:"ip addresses or cidrs of proxies whose forwarded headers are trusted for the client ip; none by default, so behind a reverse proxy all clients share the ip of the proxy when failed logins are throttled unless it is listed"`
+	Database       DatabaseConfig
+	Token          TokenConfig
+	Login          LoginConfig
+	SSO            SSOConfig
+	Sync           SyncConfig
+	Subscriptions  SubscriptionConfig
+	Email          mailer.Config
+	Sentry         sentry.Config
+	processed      bool
 }
 
 type DatabaseConfig struct {
@@ -31,11 +42,119 @@ type DatabaseConfig struct {
 	Testing  bool   `split_words:"true" default:"false"`
 }
 
+// TokenConfig specifies the keys that sign JWT tokens. If a key directory is specified,
+// a new signing key is generated in the directory every rotation interval; the key is
+// published in the JWKS for the publish delay before it is used to sign tokens. Keys
+// from the map are never rotated but can still be used to verify tokens.
 type TokenConfig struct {
-	Keys         map[string]string `desc:"a map of key ID to key path"`
-	Audience     string            `default:"https://epistolary.app"`
-	Issuer       string            `default:"https://api.epistolary.app"`
-	CookieDomain string            `split_words:"true" default:"epistolary.app"`
+	Keys             map[string]string `desc:"a map of key ID to key path"`
+	KeyDir           string            `split_words:"true" desc:"directory of automatically rotated signing keys"`
+	KeySize          int               `split_words:"true" default:"4096" desc:"number of bits of generated signing keys"`
+	RotationInterval time.Duration     `split_words:"true" default:"720h" desc:"how often a new signing key is generated"`
+	PublishDelay     time.Duration     `split_words:"true" default:"1h" desc:"how long a new signing key is published before it is used"`
+	Audience         string            `default:"https://epistolary.app"`
+	Issuer           string            `default:"https://api.epistolary.app"`
+	CookieDomain     string            `split_words:"true" default:"epistolary.app"`
+}
+
+// Validate that a new key is published before the next key is generated.
+func (c TokenConfig) Validate() error {
+	if c.KeyDir == "" {
+		return nil
+	}
+
+	if c.KeySize < 2048 {
+		return fmt.Errorf("invalid configuration: token key size must be at least 2048 bits")
+	}
+
+	if c.PublishDelay < 0 || c.RotationInterval <= c.PublishDelay {
+		return fmt.Errorf("invalid configuration: token rotation interval must be longer than the publish delay")
+	}
+	return nil
+}
+
+// LoginConfig protects logins from brute-force attacks by locking out IP addresses and
+// usernames with too many failed logins within a sliding window, and by locking the
+// accounts of users with too many consecutive failed logins. Limits of 0 are disabled.
+type LoginConfig struct {
+	Window          time.Duration `default:"15m" desc:"sliding window in which failed logins from an IP address or for a username are counted"`
+	Lockout         time.Duration `default:"15m" desc:"how long logins are refused once a limit is reached"`
+	MaxIPFailures   int           `split_words:"true" default:"20" desc:"failed logins from an IP address within the window before it is locked out"`
+	MaxUserFailures int           `split_words:"true" default:"5" desc:"failed logins for a username within the window before it is locked out"`
+	MaxConsecutive  int           `split_words:"true" default:"10" desc:"consecutive failed logins stored on an account before the account is locked out"`
+}
+
+// SSOConfig allows users to login with upstream OpenID Connect identity providers such
+// as the identity provider of their company instead of with an Epistolary password.
+type SSOConfig struct {
+	Providers SSOProviders `desc:"json list of openid connect providers with a name, title, issuer, client_id, client_secret, and auto_provision"`
+}
+
+// SSOProvider is an upstream OpenID Connect identity provider. Users are matched by the
+// verified email address of the provider; if AutoProvision is true users without an
+// account are registered, otherwise they must register before logging in.
+type SSOProvider struct {
+	Name          string `json:"name"`
+	Title         string `json:"title"`
+	Issuer        string `json:"issuer"`
+	ClientID      string `json:"client_id"`
+	ClientSecret  string `json:"client_secret"`
+	AutoProvision bool   `json:"auto_provision"`
+}
+
+// SSOProviders are decoded from a JSON list since the environment cannot otherwise
+// describe a list of structs.
+type SSOProviders []SSOProvider
+
+// Matches the provider names that can be used in URLs.
+var providerName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+func (p *SSOProviders) Decode(value string) error {
+	providers := make(SSOProviders, 0)
+	if strings.TrimSpace(value) != "" {
+		if err := json.Unmarshal([]byte(value), &providers); err != nil {
+			return fmt.Errorf("could not parse sso providers: %w", err)
+		}
+	}
+
+	*p = providers
+	return p.Validate()
+}
+
+func (p SSOProviders) Validate() error {
+	names := make(map[string]struct{}, len(p))
+	for _, provider := range p {
+		if !providerName.MatchString(provider.Name) {
+			return fmt.Errorf("sso provider name %q must be a lowercase slug", provider.Name)
+		}
+
+		if _, ok := names[provider.Name]; ok {
+			return fmt.Errorf("sso provider %q is configured more than once", provider.Name)
+		}
+		names[provider.Name] = struct{}{}
+
+		if provider.Issuer == "" || provider.ClientID == "" {
+			return fmt.Errorf("sso provider %q requires an issuer and client id", provider.Name)
+		}
+	}
+	return nil
+}
+
+// SyncConfig manages the background workers that sync epistle metadata. If there are
+// no workers then epistles are queued to be synced but are never synced.
+type SyncConfig struct {
+	Workers        int           `default:"4" desc:"number of background workers that sync epistle metadata"`
+	Interval       time.Duration `default:"30s" desc:"how often idle workers check for sync jobs that are ready to retry"`
+	MaxAttempts    int64         `split_words:"true" default:"8" desc:"number of times a sync is attempted before giving up"`
+	StaleAfter     time.Duration `split_words:"true" default:"168h" desc:"resync epistles that have not been synced for this long, 0 disables resync"`
+	ResyncInterval time.Duration `split_words:"true" default:"1h" desc:"how often to check for stale epistles to resync"`
+}
+
+// SubscriptionConfig manages the poller that queues new items from the feeds that users
+// are subscribed to. If the interval is zero then subscriptions are never polled.
+type SubscriptionConfig struct {
+	Interval  time.Duration `default:"5m" desc:"how often to check for subscriptions that are due to be polled, 0 disables polling"`
+	PollEvery time.Duration `split_words:"true" default:"1h" desc:"how long to wait between polls of each subscription"`
 }
 
 // New creates a new Config object from environment variables prefixed with EPISTOLARY.
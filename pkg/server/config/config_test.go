@@ -4,6 +4,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bbengfort/epistolary/pkg/server/config"
 	"github.com/rs/zerolog"
@@ -17,13 +18,38 @@ var testEnv = map[string]string{
 	"EPISTOLARY_LOG_LEVEL":                "debug",
 	"EPISTOLARY_CONSOLE_LOG":              "true",
 	"EPISTOLARY_ALLOW_ORIGINS":            "https://epistolary.app",
+	"EPISTOLARY_TRUSTED_PROXIES":          "10.0.0.0/8,192.168.1.2",
 	"EPISTOLARY_DATABASE_URL":             "postgres://localhost:5432/epistolary?sslmode=disable",
 	"EPISTOLARY_DATABASE_READ_ONLY":       "true",
 	"EPISTOLARY_DATABASE_TESTING":         "true",
 	"EPISTOLARY_TOKEN_KEYS":               "01GECSDK5WJ7XWASQ0PMH6K41K:testdata/01GECSDK5WJ7XWASQ0PMH6K41K.pem,01GECSJGDCDN368D0EENX23C7R:testdata/01GECSJGDCDN368D0EENX23C7R.pem",
+	"EPISTOLARY_TOKEN_KEY_DIR":            "tmp/keys",
+	"EPISTOLARY_TOKEN_KEY_SIZE":           "2048",
+	"EPISTOLARY_TOKEN_ROTATION_INTERVAL":  "168h",
+	"EPISTOLARY_TOKEN_PUBLISH_DELAY":      "2h",
 	"EPISTOLARY_TOKEN_AUDIENCE":           "http://localhost:3000",
 	"EPISTOLARY_TOKEN_ISSUER":             "http://localhost:8000",
 	"EPISTOLARY_TOKEN_COOKIE_DOMAIN":      "localhost",
+	"EPISTOLARY_LOGIN_WINDOW":             "10m",
+	"EPISTOLARY_LOGIN_LOCKOUT":            "30m",
+	"EPISTOLARY_LOGIN_MAX_IP_FAILURES":    "50",
+	"EPISTOLARY_LOGIN_MAX_USER_FAILURES":  "3",
+	"EPISTOLARY_LOGIN_MAX_CONSECUTIVE":    "8",
+	"EPISTOLARY_SSO_PROVIDERS":            `[{"name": "acme", "title": "Acme Corp", "issuer": "https://login.acme.test", "client_id": "epistolary", "client_secret": "supersecret", "auto_provision": true}]`,
+	"EPISTOLARY_SYNC_WORKERS":             "2",
+	"EPISTOLARY_SYNC_INTERVAL":            "1m",
+	"EPISTOLARY_SYNC_MAX_ATTEMPTS":        "5",
+	"EPISTOLARY_SYNC_STALE_AFTER":         "72h",
+	"EPISTOLARY_SYNC_RESYNC_INTERVAL":     "30m",
+	"EPISTOLARY_SUBSCRIPTIONS_INTERVAL":   "10m",
+	"EPISTOLARY_SUBSCRIPTIONS_POLL_EVERY": "2h",
+	"EPISTOLARY_EMAIL_BACKEND":            "smtp",
+	"EPISTOLARY_EMAIL_SENDER":             "Epistolary <noreply@localhost>",
+	"EPISTOLARY_EMAIL_HOST":               "localhost",
+	"EPISTOLARY_EMAIL_PORT":               "2525",
+	"EPISTOLARY_EMAIL_USERNAME":           "mailer",
+	"EPISTOLARY_EMAIL_PASSWORD":           "supersecret",
+	"EPISTOLARY_EMAIL_DUMP_DIR":           "tmp/emails",
 	"EPISTOLARY_SENTRY_DSN":               "http://testing.sentry.test/1234",
 	"EPISTOLARY_SENTRY_SERVER_NAME":       "tnode",
 	"EPISTOLARY_SENTRY_ENVIRONMENT":       "testing",
@@ -60,10 +86,41 @@ func TestConfig(t *testing.T) {
 	require.True(t, conf.Database.ReadOnly)
 	require.True(t, conf.Database.Testing)
 	require.Len(t, conf.AllowOrigins, 1)
+	require.Equal(t, []string{"10.0.0.0/8", "192.168.1.2"}, conf.TrustedProxies)
 	require.Len(t, conf.Token.Keys, 2)
+	require.Equal(t, testEnv["EPISTOLARY_TOKEN_KEY_DIR"], conf.Token.KeyDir)
+	require.Equal(t, 2048, conf.Token.KeySize)
+	require.Equal(t, 168*time.Hour, conf.Token.RotationInterval)
+	require.Equal(t, 2*time.Hour, conf.Token.PublishDelay)
 	require.Equal(t, testEnv["EPISTOLARY_TOKEN_AUDIENCE"], conf.Token.Audience)
 	require.Equal(t, testEnv["EPISTOLARY_TOKEN_ISSUER"], conf.Token.Issuer)
 	require.Equal(t, testEnv["EPISTOLARY_TOKEN_COOKIE_DOMAIN"], conf.Token.CookieDomain)
+	require.Equal(t, 10*time.Minute, conf.Login.Window)
+	require.Equal(t, 30*time.Minute, conf.Login.Lockout)
+	require.Equal(t, 50, conf.Login.MaxIPFailures)
+	require.Equal(t, 3, conf.Login.MaxUserFailures)
+	require.Equal(t, 8, conf.Login.MaxConsecutive)
+	require.Len(t, conf.SSO.Providers, 1)
+	require.Equal(t, "acme", conf.SSO.Providers[0].Name)
+	require.Equal(t, "Acme Corp", conf.SSO.Providers[0].Title)
+	require.Equal(t, "https://login.acme.test", conf.SSO.Providers[0].Issuer)
+	require.Equal(t, "epistolary", conf.SSO.Providers[0].ClientID)
+	require.Equal(t, "supersecret", conf.SSO.Providers[0].ClientSecret)
+	require.True(t, conf.SSO.Providers[0].AutoProvision)
+	require.Equal(t, 2, conf.Sync.Workers)
+	require.Equal(t, 1*time.Minute, conf.Sync.Interval)
+	require.Equal(t, int64(5), conf.Sync.MaxAttempts)
+	require.Equal(t, 72*time.Hour, conf.Sync.StaleAfter)
+	require.Equal(t, 30*time.Minute, conf.Sync.ResyncInterval)
+	require.Equal(t, 10*time.Minute, conf.Subscriptions.Interval)
+	require.Equal(t, 2*time.Hour, conf.Subscriptions.PollEvery)
+	require.Equal(t, testEnv["EPISTOLARY_EMAIL_BACKEND"], conf.Email.Backend)
+	require.Equal(t, testEnv["EPISTOLARY_EMAIL_SENDER"], conf.Email.Sender)
+	require.Equal(t, testEnv["EPISTOLARY_EMAIL_HOST"], conf.Email.Host)
+	require.Equal(t, 2525, conf.Email.Port)
+	require.Equal(t, testEnv["EPISTOLARY_EMAIL_USERNAME"], conf.Email.Username)
+	require.Equal(t, testEnv["EPISTOLARY_EMAIL_PASSWORD"], conf.Email.Password)
+	require.Equal(t, testEnv["EPISTOLARY_EMAIL_DUMP_DIR"], conf.Email.DumpDir)
 	require.Equal(t, testEnv["EPISTOLARY_SENTRY_DSN"], conf.Sentry.DSN)
 	require.Equal(t, testEnv["EPISTOLARY_SENTRY_SERVER_NAME"], conf.Sentry.ServerName)
 	require.Equal(t, testEnv["EPISTOLARY_SENTRY_ENVIRONMENT"], conf.Sentry.Environment)
@@ -75,6 +132,41 @@ func TestConfig(t *testing.T) {
 	require.True(t, strings.HasPrefix(conf.Sentry.GetRelease(), "epistolary@"))
 }
 
+func TestSSOProviders(t *testing.T) {
+	var providers config.SSOProviders
+	require.NoError(t, providers.Decode(""))
+	require.Len(t, providers, 0)
+
+	require.NoError(t, providers.Decode(`[{"name": "acme", "issuer": "https://login.acme.test", "client_id": "epistolary"}, {"name": "google", "issuer": "https://accounts.google.com", "client_id": "epistolary"}]`))
+	require.Len(t, providers, 2)
+
+	invalid := []string{
+		`{"name": "acme"}`,
+		`[{"name": "Acme Corp", "issuer": "https://login.acme.test", "client_id": "epistolary"}]`,
+		`[{"name": "acme", "client_id": "epistolary"}]`,
+		`[{"name": "acme", "issuer": "https://login.acme.test"}]`,
+		`[{"name": "acme", "issuer": "https://login.acme.test", "client_id": "epistolary"}, {"name": "acme", "issuer": "https://login.acme.test", "client_id": "epistolary"}]`,
+	}
+
+	for _, value := range invalid {
+		require.Error(t, providers.Decode(value), value)
+	}
+}
+
+func TestTokenConfig(t *testing.T) {
+	conf := config.TokenConfig{KeySize: 1024, RotationInterval: time.Hour, PublishDelay: 2 * time.Hour}
+	require.NoError(t, conf.Validate(), "rotation is not validated without a key directory")
+
+	conf.KeyDir = "tmp/keys"
+	require.Error(t, conf.Validate(), "key size is too small")
+
+	conf.KeySize = 2048
+	require.Error(t, conf.Validate(), "publish delay is longer than the rotation interval")
+
+	conf.RotationInterval = 720 * time.Hour
+	require.NoError(t, conf.Validate())
+}
+
 func TestRequiredConfig(t *testing.T) {
 	t.Skip("not working for unknown reason")
 	required := []string{
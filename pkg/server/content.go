@@ -0,0 +1,60 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/epistles"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/gin-gonic/gin"
+)
+
+// FetchContent returns the reader-mode article extracted from the epistle of the
+// reading when it was last synced so that the reading can be read offline.
+func (s *Server) FetchContent(c *gin.Context) {
+	var (
+		err       error
+		readingID int64
+		userID    int64
+		content   *epistles.Content
+	)
+
+	if readingID, err = strconv.ParseInt(c.Param("readingID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("reading not found"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if content, err = epistles.GetContent(c.Request.Context(), readingID, userID); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, api.ErrorResponse("reading not found"))
+		case errors.Is(err, epistles.ErrNoContent):
+			c.JSON(http.StatusNotFound, api.ErrorResponse("reading content is not available yet"))
+		default:
+			sentry.Error(c).Err(err).Msg("could not fetch reading content from database")
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch reading content"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, &api.Content{
+		ReadingID:   content.EpistleID,
+		Link:        content.Link,
+		Title:       content.Title.String,
+		HTML:        content.HTML,
+		Text:        content.Text,
+		WordCount:   content.WordCount,
+		ReadingTime: content.ReadingTime,
+		Modified:    api.Timestamp{Time: content.Modified},
+	})
+}
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS reading_deleted_idx;
+ALTER TABLE reading DROP COLUMN IF EXISTS deleted;
+
+COMMIT;
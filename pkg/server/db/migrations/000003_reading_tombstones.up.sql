@@ -0,0 +1,13 @@
+/*
+ * Soft deletes for readings so that a user can undo an accidental delete.
+ */
+BEGIN;
+
+-- A reading is tombstoned when deleted is not null; it is purged after the undo window
+ALTER TABLE reading ADD COLUMN deleted TIMESTAMPTZ DEFAULT NULL;
+
+-- Speeds up the purge of tombstoned readings whose undo window has passed
+CREATE INDEX IF NOT EXISTS reading_deleted_idx ON reading (deleted)
+    WHERE deleted IS NOT NULL;
+
+COMMIT;
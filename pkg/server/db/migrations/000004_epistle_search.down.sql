@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS epistles_search_idx;
+ALTER TABLE epistles DROP COLUMN IF EXISTS search;
+
+COMMIT;
@@ -0,0 +1,15 @@
+/*
+ * Full-text search over the epistles that users are reading.
+ */
+BEGIN;
+
+-- Weighted search document: titles rank above descriptions which rank above the link
+ALTER TABLE epistles ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce(link, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS epistles_search_idx ON epistles USING GIN (search);
+
+COMMIT;
@@ -0,0 +1,52 @@
+/*
+ * Per-user tags that can be used to organize readings into collections.
+ */
+BEGIN;
+
+-- Tags are owned by a user and their names are unique for that user
+CREATE TABLE IF NOT EXISTS tags (
+    id          SERIAL PRIMARY KEY,
+    user_id     INTEGER NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    created     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+-- Maps the tags to the readings of the user that owns the tag
+CREATE TABLE IF NOT EXISTS reading_tags (
+    epistle_id    INTEGER NOT NULL,
+    user_id       INTEGER NOT NULL,
+    tag_id        INTEGER NOT NULL,
+    created       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (epistle_id, user_id, tag_id)
+);
+
+ALTER TABLE tags ADD CONSTRAINT fk_tags_user
+    FOREIGN KEY (user_id) REFERENCES users (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE reading_tags ADD CONSTRAINT fk_reading_tags_reading
+    FOREIGN KEY (epistle_id, user_id) REFERENCES reading (epistle_id, user_id)
+    ON DELETE CASCADE;
+
+ALTER TABLE reading_tags ADD CONSTRAINT fk_reading_tags_tag
+    FOREIGN KEY (tag_id) REFERENCES tags (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS reading_tags_tag_idx ON reading_tags (tag_id);
+
+-- Tags modified timestamp
+CREATE TRIGGER set_tags_modified
+BEFORE UPDATE ON tags
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+-- Reading Tags modified timestamp
+CREATE TRIGGER set_reading_tags_modified
+BEFORE UPDATE ON reading_tags
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+COMMIT;
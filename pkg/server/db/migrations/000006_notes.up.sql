@@ -0,0 +1,28 @@
+/*
+ * Notes and highlights that a user has made on their reading of an epistle.
+ */
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS notes (
+    id          SERIAL PRIMARY KEY,
+    epistle_id  INTEGER NOT NULL,
+    user_id     INTEGER NOT NULL,
+    body        TEXT NOT NULL DEFAULT '',
+    highlight   TEXT DEFAULT NULL,
+    created     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE notes ADD CONSTRAINT fk_notes_reading
+    FOREIGN KEY (epistle_id, user_id) REFERENCES reading (epistle_id, user_id)
+    ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS notes_reading_idx ON notes (epistle_id, user_id);
+
+-- Notes modified timestamp
+CREATE TRIGGER set_notes_modified
+BEFORE UPDATE ON notes
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+COMMIT;
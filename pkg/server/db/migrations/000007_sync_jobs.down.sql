@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS sync_jobs;
+
+ALTER TABLE epistles DROP COLUMN IF EXISTS sync_error;
+ALTER TABLE epistles DROP COLUMN IF EXISTS sync_attempts;
+ALTER TABLE epistles DROP COLUMN IF EXISTS synced;
+
+COMMIT;
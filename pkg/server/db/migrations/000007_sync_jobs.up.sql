@@ -0,0 +1,44 @@
+/*
+ * Durable queue of jobs to sync epistle metadata in the background.
+ */
+BEGIN;
+
+-- Record the sync state of each epistle
+ALTER TABLE epistles ADD COLUMN synced TIMESTAMPTZ DEFAULT NULL;
+ALTER TABLE epistles ADD COLUMN sync_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE epistles ADD COLUMN sync_error TEXT DEFAULT NULL;
+
+-- Each epistle has at most one pending sync job; workers lease a job by setting the
+-- locked_until timestamp so that an abandoned job can be picked up by another worker.
+CREATE TABLE IF NOT EXISTS sync_jobs (
+    id              SERIAL PRIMARY KEY,
+    epistle_id      INTEGER NOT NULL UNIQUE,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    run_after       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until    TIMESTAMPTZ DEFAULT NULL,
+    last_error      TEXT DEFAULT NULL,
+    created         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE sync_jobs ADD CONSTRAINT fk_sync_jobs_epistle
+    FOREIGN KEY (epistle_id) REFERENCES epistles (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS sync_jobs_run_after_idx ON sync_jobs (run_after);
+
+-- Sync Jobs modified timestamp
+CREATE TRIGGER set_sync_jobs_modified
+BEFORE UPDATE ON sync_jobs
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+-- Epistles that already have metadata were synced inline when they were created
+UPDATE epistles SET synced=modified, sync_attempts=1
+    WHERE title IS NOT NULL OR description IS NOT NULL OR favicon IS NOT NULL;
+
+-- Queue any epistles that were never successfully synced
+INSERT INTO sync_jobs (epistle_id)
+    SELECT id FROM epistles WHERE synced IS NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS epistles_synced_idx;
+
+ALTER TABLE epistles DROP COLUMN IF EXISTS last_modified;
+ALTER TABLE epistles DROP COLUMN IF EXISTS etag;
+
+COMMIT;
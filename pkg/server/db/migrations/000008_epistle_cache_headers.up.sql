@@ -0,0 +1,11 @@
+/*
+ * Cache validators for making conditional requests when resyncing epistles.
+ */
+BEGIN;
+
+ALTER TABLE epistles ADD COLUMN etag TEXT DEFAULT NULL;
+ALTER TABLE epistles ADD COLUMN last_modified TEXT DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS epistles_synced_idx ON epistles (synced);
+
+COMMIT;
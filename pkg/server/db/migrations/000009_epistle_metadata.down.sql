@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE epistles DROP COLUMN IF EXISTS canonical;
+ALTER TABLE epistles DROP COLUMN IF EXISTS image;
+ALTER TABLE epistles DROP COLUMN IF EXISTS published;
+ALTER TABLE epistles DROP COLUMN IF EXISTS author;
+ALTER TABLE epistles DROP COLUMN IF EXISTS site_name;
+
+COMMIT;
@@ -0,0 +1,16 @@
+/*
+ * Additional metadata about epistles from OpenGraph, Twitter Cards and JSON-LD.
+ */
+BEGIN;
+
+ALTER TABLE epistles ADD COLUMN site_name VARCHAR(255) DEFAULT NULL;
+ALTER TABLE epistles ADD COLUMN author VARCHAR(255) DEFAULT NULL;
+ALTER TABLE epistles ADD COLUMN published TIMESTAMPTZ DEFAULT NULL;
+ALTER TABLE epistles ADD COLUMN image TEXT DEFAULT NULL;
+ALTER TABLE epistles ADD COLUMN canonical TEXT DEFAULT NULL;
+
+-- Clear the cache validators so the next resync fetches the new metadata rather than
+-- receiving a not modified response.
+UPDATE epistles SET etag=NULL, last_modified=NULL;
+
+COMMIT;
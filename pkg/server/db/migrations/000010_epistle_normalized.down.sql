@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS epistles_normalized_idx;
+
+ALTER TABLE epistles DROP COLUMN IF EXISTS normalized;
+
+COMMIT;
@@ -0,0 +1,11 @@
+/*
+ * Normalized link keys used to detect duplicate epistles. Existing epistles are keyed
+ * when they are resynced or by running the epistolary dedupe command.
+ */
+BEGIN;
+
+ALTER TABLE epistles ADD COLUMN normalized VARCHAR(2000) DEFAULT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS epistles_normalized_idx ON epistles (normalized);
+
+COMMIT;
@@ -0,0 +1,30 @@
+/*
+ * Reader-mode content extracted from epistles so they can be read offline.
+ */
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS epistle_content (
+    epistle_id      INTEGER PRIMARY KEY,
+    html            TEXT NOT NULL DEFAULT '',
+    text            TEXT NOT NULL DEFAULT '',
+    word_count      INTEGER NOT NULL DEFAULT 0,
+    reading_time    INTEGER NOT NULL DEFAULT 0,
+    created         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE epistle_content ADD CONSTRAINT fk_epistle_content_epistle
+    FOREIGN KEY (epistle_id) REFERENCES epistles (id)
+    ON DELETE CASCADE;
+
+-- Epistle content modified timestamp
+CREATE TRIGGER set_epistle_content_modified
+BEFORE UPDATE ON epistle_content
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+-- Clear the cache validators so the next resync extracts the content rather than
+-- receiving a not modified response.
+UPDATE epistles SET etag=NULL, last_modified=NULL;
+
+COMMIT;
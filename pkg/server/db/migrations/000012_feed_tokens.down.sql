@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS feed_tokens;
+
+COMMIT;
@@ -0,0 +1,28 @@
+/*
+ * Tokens that authenticate the private feeds of a user's reading list. Only the hash of
+ * the token is stored since the token is a bearer credential in the feed URL.
+ */
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS feed_tokens (
+    id          SERIAL PRIMARY KEY,
+    user_id     INTEGER NOT NULL,
+    token       BYTEA UNIQUE NOT NULL,
+    last_used   TIMESTAMPTZ DEFAULT NULL,
+    created     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE feed_tokens ADD CONSTRAINT fk_feed_tokens_user
+    FOREIGN KEY (user_id) REFERENCES users (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS feed_tokens_user_idx ON feed_tokens (user_id);
+
+-- Feed tokens modified timestamp
+CREATE TRIGGER set_feed_tokens_modified
+BEFORE UPDATE ON feed_tokens
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS subscription_items;
+DROP TABLE IF EXISTS subscriptions;
+
+COMMIT;
@@ -0,0 +1,48 @@
+/*
+ * Subscriptions to RSS and Atom feeds whose new items are queued as readings for the
+ * user. The items that have already been seen are recorded so that each post is only
+ * queued once even if the user later deletes or archives the reading.
+ */
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id              SERIAL PRIMARY KEY,
+    user_id         INTEGER NOT NULL,
+    link            TEXT NOT NULL,
+    title           TEXT DEFAULT NULL,
+    site_link       TEXT DEFAULT NULL,
+    etag            TEXT DEFAULT NULL,
+    last_modified   TEXT DEFAULT NULL,
+    paused          BOOLEAN NOT NULL DEFAULT false,
+    polled          TIMESTAMPTZ DEFAULT NULL,
+    failures        INTEGER NOT NULL DEFAULT 0,
+    error           TEXT DEFAULT NULL,
+    created         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, link)
+);
+
+ALTER TABLE subscriptions ADD CONSTRAINT fk_subscriptions_user
+    FOREIGN KEY (user_id) REFERENCES users (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS subscriptions_polled_idx ON subscriptions (polled NULLS FIRST) WHERE NOT paused;
+
+CREATE TABLE IF NOT EXISTS subscription_items (
+    subscription_id INTEGER NOT NULL,
+    guid            TEXT NOT NULL,
+    created         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, guid)
+);
+
+ALTER TABLE subscription_items ADD CONSTRAINT fk_subscription_items_subscription
+    FOREIGN KEY (subscription_id) REFERENCES subscriptions (id)
+    ON DELETE CASCADE;
+
+-- Subscriptions modified timestamp
+CREATE TRIGGER set_subscriptions_modified
+BEFORE UPDATE ON subscriptions
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+COMMIT;
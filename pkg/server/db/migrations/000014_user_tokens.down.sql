@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS reset_tokens;
+DROP TABLE IF EXISTS verification_tokens;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified;
+
+COMMIT;
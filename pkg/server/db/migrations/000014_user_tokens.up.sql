@@ -0,0 +1,40 @@
+/*
+ * Single-use tokens that are emailed to users to verify their email address and to reset
+ * a forgotten password. Only the hash of each token is stored since the token is a
+ * bearer credential. Users that registered before email verification are verified.
+ */
+BEGIN;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified TIMESTAMPTZ DEFAULT NULL;
+UPDATE users SET email_verified=created;
+
+CREATE TABLE IF NOT EXISTS verification_tokens (
+    id          SERIAL PRIMARY KEY,
+    user_id     INTEGER NOT NULL,
+    email       VARCHAR(255) NOT NULL,
+    token       BYTEA UNIQUE NOT NULL,
+    expires     TIMESTAMPTZ NOT NULL,
+    created     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE verification_tokens ADD CONSTRAINT fk_verification_tokens_user
+    FOREIGN KEY (user_id) REFERENCES users (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS verification_tokens_user_idx ON verification_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS reset_tokens (
+    id          SERIAL PRIMARY KEY,
+    user_id     INTEGER NOT NULL,
+    token       BYTEA UNIQUE NOT NULL,
+    expires     TIMESTAMPTZ NOT NULL,
+    created     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE reset_tokens ADD CONSTRAINT fk_reset_tokens_user
+    FOREIGN KEY (user_id) REFERENCES users (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS reset_tokens_user_idx ON reset_tokens (user_id);
+
+COMMIT;
@@ -0,0 +1,9 @@
+/*
+ * Administrators can disable the accounts of users, preventing them from logging in or
+ * refreshing their access tokens without deleting any of their data.
+ */
+BEGIN;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled TIMESTAMPTZ DEFAULT NULL;
+
+COMMIT;
@@ -0,0 +1,31 @@
+/*
+ * Sessions track the refresh tokens issued to each device a user logs in from so that
+ * sessions can be revoked before their refresh tokens expire. The id of a session is
+ * the ID (jti) shared by the access and refresh tokens of the session.
+ */
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id          VARCHAR(26) PRIMARY KEY,
+    user_id     INTEGER NOT NULL,
+    user_agent  VARCHAR(512) DEFAULT NULL,
+    client_ip   VARCHAR(64) DEFAULT NULL,
+    last_used   TIMESTAMPTZ DEFAULT NULL,
+    expires     TIMESTAMPTZ NOT NULL,
+    created     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE sessions ADD CONSTRAINT fk_sessions_user
+    FOREIGN KEY (user_id) REFERENCES users (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS sessions_user_idx ON sessions (user_id);
+
+-- Sessions modified timestamp
+CREATE TRIGGER set_sessions_modified
+BEFORE UPDATE ON sessions
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+COMMIT;
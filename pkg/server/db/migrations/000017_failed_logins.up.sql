@@ -0,0 +1,10 @@
+/*
+ * Count the consecutive failed logins of each user so that accounts can be temporarily
+ * locked out after too many failed attempts. The count is reset on a successful login.
+ */
+BEGIN;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS failed_logins INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ DEFAULT NULL;
+
+COMMIT;
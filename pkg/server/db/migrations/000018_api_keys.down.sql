@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS api_key_permissions;
+DROP TABLE IF EXISTS api_keys;
+
+COMMIT;
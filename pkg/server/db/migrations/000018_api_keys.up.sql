@@ -0,0 +1,53 @@
+/*
+ * API keys are long-lived personal access tokens for scripts and integrations. Only the
+ * hash of the key is stored since the key is a bearer credential. Each key is granted
+ * a subset of the permissions of its user when it is created.
+ */
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id          SERIAL PRIMARY KEY,
+    user_id     INTEGER NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    token       BYTEA UNIQUE NOT NULL,
+    expires     TIMESTAMPTZ NOT NULL,
+    last_used   TIMESTAMPTZ DEFAULT NULL,
+    created     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS api_key_permissions (
+    api_key_id    INTEGER NOT NULL,
+    permission_id INTEGER NOT NULL,
+    created       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (api_key_id, permission_id)
+);
+
+ALTER TABLE api_keys ADD CONSTRAINT fk_api_keys_user
+    FOREIGN KEY (user_id) REFERENCES users (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE api_key_permissions ADD CONSTRAINT fk_api_key_permissions_api_key
+    FOREIGN KEY (api_key_id) REFERENCES api_keys (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE api_key_permissions ADD CONSTRAINT fk_api_key_permissions_permission
+    FOREIGN KEY (permission_id) REFERENCES permissions (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS api_keys_user_idx ON api_keys (user_id);
+
+-- API keys modified timestamp
+CREATE TRIGGER set_api_keys_modified
+BEFORE UPDATE ON api_keys
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+-- API key permissions modified timestamp
+CREATE TRIGGER set_api_key_permissions_modified
+BEFORE UPDATE ON api_key_permissions
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS oauth_codes;
+DROP TABLE IF EXISTS oauth_clients;
+
+COMMIT;
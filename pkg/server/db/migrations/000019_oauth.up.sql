@@ -0,0 +1,54 @@
+/*
+ * OAuth2 clients are third-party applications (such as the browser extension) that are
+ * registered by an administrator to authenticate users with the authorization code
+ * flow. Public clients have no secret and must use PKCE; only the hash of the secret of
+ * a confidential client is stored. Authorization codes are single use and short lived;
+ * only the hash of the code is stored.
+ */
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS oauth_clients (
+    id            SERIAL PRIMARY KEY,
+    client_id     VARCHAR(26) UNIQUE NOT NULL,
+    name          VARCHAR(255) NOT NULL,
+    secret        BYTEA DEFAULT NULL,
+    redirect_uris TEXT[] NOT NULL,
+    created       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS oauth_codes (
+    code             BYTEA PRIMARY KEY,
+    client_id        INTEGER NOT NULL,
+    user_id          INTEGER NOT NULL,
+    redirect_uri     TEXT NOT NULL,
+    scope            TEXT NOT NULL DEFAULT '',
+    nonce            TEXT NOT NULL DEFAULT '',
+    challenge        VARCHAR(128) NOT NULL,
+    challenge_method VARCHAR(8) NOT NULL,
+    expires          TIMESTAMPTZ NOT NULL,
+    created          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE oauth_codes ADD CONSTRAINT fk_oauth_codes_client
+    FOREIGN KEY (client_id) REFERENCES oauth_clients (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE oauth_codes ADD CONSTRAINT fk_oauth_codes_user
+    FOREIGN KEY (user_id) REFERENCES users (id)
+    ON DELETE CASCADE;
+
+-- OAuth clients modified timestamp
+CREATE TRIGGER set_oauth_clients_modified
+BEFORE UPDATE ON oauth_clients
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+-- OAuth codes modified timestamp
+CREATE TRIGGER set_oauth_codes_modified
+BEFORE UPDATE ON oauth_codes
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+COMMIT;
@@ -0,0 +1,31 @@
+/*
+ * Identities link the accounts of users to the subject of an upstream OpenID Connect
+ * identity provider that the user logs in with. The subject is unique to the provider
+ * so the user is found by their identity even if their email address changes.
+ */
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    provider    VARCHAR(32) NOT NULL,
+    subject     VARCHAR(255) NOT NULL,
+    user_id     INTEGER NOT NULL,
+    email       VARCHAR(255) NOT NULL,
+    last_login  TIMESTAMPTZ DEFAULT NULL,
+    created     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, subject)
+);
+
+ALTER TABLE user_identities ADD CONSTRAINT fk_user_identities_user
+    FOREIGN KEY (user_id) REFERENCES users (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS user_identities_user_idx ON user_identities (user_id);
+
+-- User identities modified timestamp
+CREATE TRIGGER set_user_identities_modified
+BEFORE UPDATE ON user_identities
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+COMMIT;
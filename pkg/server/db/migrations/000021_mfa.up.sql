@@ -0,0 +1,48 @@
+/*
+ * Users can enable multi-factor authentication with a time-based one-time password
+ * (TOTP) authenticator app. The secret is created when the user enrolls and MFA is only
+ * enabled once the user has confirmed a code from the authenticator. The last time step
+ * that was used to login is stored so that codes cannot be replayed. Recovery codes are
+ * single use; only the hash of each code is stored.
+ */
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id      INTEGER PRIMARY KEY,
+    secret       VARCHAR(64) NOT NULL,
+    enabled      TIMESTAMPTZ DEFAULT NULL,
+    last_counter BIGINT NOT NULL DEFAULT 0,
+    created      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    code        BYTEA PRIMARY KEY,
+    user_id     INTEGER NOT NULL,
+    created     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE user_mfa ADD CONSTRAINT fk_user_mfa_user
+    FOREIGN KEY (user_id) REFERENCES users (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE recovery_codes ADD CONSTRAINT fk_recovery_codes_user
+    FOREIGN KEY (user_id) REFERENCES users (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS recovery_codes_user_idx ON recovery_codes (user_id);
+
+-- User MFA modified timestamp
+CREATE TRIGGER set_user_mfa_modified
+BEFORE UPDATE ON user_mfa
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+-- Recovery codes modified timestamp
+CREATE TRIGGER set_recovery_codes_modified
+BEFORE UPDATE ON recovery_codes
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE sessions DROP CONSTRAINT IF EXISTS fk_sessions_oauth_client;
+ALTER TABLE sessions DROP COLUMN IF EXISTS client_id;
+
+COMMIT;
@@ -0,0 +1,14 @@
+/*
+ * Sessions created when a user authorizes an OAuth client record the client ID so that
+ * its refresh tokens can only be used or revoked by the same client. The sessions of a
+ * client are deleted when the client is deleted.
+ */
+BEGIN;
+
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS client_id VARCHAR(26) DEFAULT NULL;
+
+ALTER TABLE sessions ADD CONSTRAINT fk_sessions_oauth_client
+    FOREIGN KEY (client_id) REFERENCES oauth_clients (client_id)
+    ON DELETE CASCADE;
+
+COMMIT;
@@ -0,0 +1,14 @@
+/*
+ * Subscriptions record when their feed was last fetched successfully, since the poll
+ * time is also updated when the feed cannot be fetched. The first successful poll only
+ * records the items that are already in the feed without queueing them as readings.
+ */
+BEGIN;
+
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS succeeded TIMESTAMPTZ DEFAULT NULL;
+
+-- Subscriptions that have already seen items have been fetched successfully
+UPDATE subscriptions s SET succeeded=s.polled
+    WHERE s.polled IS NOT NULL AND (s.failures=0 OR EXISTS (SELECT 1 FROM subscription_items i WHERE i.subscription_id=s.id));
+
+COMMIT;
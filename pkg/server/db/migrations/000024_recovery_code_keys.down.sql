@@ -0,0 +1,23 @@
+BEGIN;
+
+DROP TABLE IF EXISTS recovery_codes;
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    code        BYTEA PRIMARY KEY,
+    user_id     INTEGER NOT NULL,
+    created     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE recovery_codes ADD CONSTRAINT fk_recovery_codes_user
+    FOREIGN KEY (user_id) REFERENCES users (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS recovery_codes_user_idx ON recovery_codes (user_id);
+
+CREATE TRIGGER set_recovery_codes_modified
+BEFORE UPDATE ON recovery_codes
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+COMMIT;
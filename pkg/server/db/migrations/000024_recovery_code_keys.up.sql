@@ -0,0 +1,30 @@
+/*
+ * Recovery codes are stored as salted derived keys rather than as unsalted hashes since
+ * they are short enough to be brute-forced from a leaked database. The existing hashes
+ * cannot be converted so users must generate new recovery codes.
+ */
+BEGIN;
+
+DROP TABLE IF EXISTS recovery_codes;
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id          SERIAL PRIMARY KEY,
+    user_id     INTEGER NOT NULL,
+    code        VARCHAR(255) NOT NULL,
+    created     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE recovery_codes ADD CONSTRAINT fk_recovery_codes_user
+    FOREIGN KEY (user_id) REFERENCES users (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS recovery_codes_user_idx ON recovery_codes (user_id);
+
+-- Recovery codes modified timestamp
+CREATE TRIGGER set_recovery_codes_modified
+BEFORE UPDATE ON recovery_codes
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_modified_timestamp();
+
+COMMIT;
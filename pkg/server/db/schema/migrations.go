@@ -4,6 +4,50 @@
 // 000001_initial_schema.up.sql (5.036kB)
 // 000002_default_roles.down.sql (81B)
 // 000002_default_roles.up.sql (875B)
+// 000003_reading_tombstones.down.sql (110B)
+// 000003_reading_tombstones.up.sql (427B)
+// 000004_epistle_search.down.sql (110B)
+// 000004_epistle_search.up.sql (534B)
+// 000005_tags.down.sql (79B)
+// 000005_tags.up.sql (1.623kB)
+// 000006_notes.down.sql (45B)
+// 000006_notes.up.sql (805B)
+// 000007_sync_jobs.down.sql (214B)
+// 000007_sync_jobs.up.sql (1.67kB)
+// 000008_epistle_cache_headers.down.sql (167B)
+// 000008_epistle_cache_headers.up.sql (292B)
+// 000009_epistle_metadata.down.sql (280B)
+// 000009_epistle_metadata.up.sql (602B)
+// 000010_epistle_normalized.down.sql (119B)
+// 000010_epistle_normalized.up.sql (338B)
+// 000011_epistle_content.down.sql (55B)
+// 000011_epistle_content.up.sql (989B)
+// 000012_feed_tokens.down.sql (51B)
+// 000012_feed_tokens.up.sql (865B)
+// 000013_subscriptions.down.sql (94B)
+// 000013_subscriptions.up.sql (1.715kB)
+// 000014_user_tokens.down.sql (150B)
+// 000014_user_tokens.up.sql (1.389kB)
+// 000015_user_disabled.down.sql (67B)
+// 000015_user_disabled.up.sql (260B)
+// 000016_sessions.down.sql (48B)
+// 000016_sessions.up.sql (1.007kB)
+// 000017_failed_logins.down.sql (126B)
+// 000017_failed_logins.up.sql (367B)
+// 000018_api_keys.down.sql (90B)
+// 000018_api_keys.up.sql (1.78kB)
+// 000019_oauth.down.sql (87B)
+// 000019_oauth.up.sql (1.918kB)
+// 000020_user_identities.down.sql (55B)
+// 000020_user_identities.up.sql (1.059kB)
+// 000021_mfa.down.sql (85B)
+// 000021_mfa.up.sql (1.639kB)
+// 000022_oauth_sessions.down.sql (144B)
+// 000022_oauth_sessions.up.sql (482B)
+// 000023_subscription_succeeded.down.sql (76B)
+// 000023_subscription_succeeded.up.sql (621B)
+// 000024_recovery_code_keys.down.sql (633B)
+// 000024_recovery_code_keys.up.sql (960B)
 
 package schema
 
@@ -151,6 +195,886 @@ func _000002_default_rolesUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __000003_reading_tombstonesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x6e\x00\x91\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x61\x64\x69\x6e\x67\x5f\x64\x65\x6c\x65\x74\x65\x64\x5f\x69\x64\x78\x3b\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x72\x65\x61\x64\x69\x6e\x67\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x64\x65\x6c\x65\x74\x65\x64\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x5d\x90\x72\x5f\x6e\x00\x00\x00")
+
+func _000003_reading_tombstonesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000003_reading_tombstonesDownSql,
+		"000003_reading_tombstones.down.sql",
+	)
+}
+
+func _000003_reading_tombstonesDownSql() (*asset, error) {
+	bytes, err := _000003_reading_tombstonesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000003_reading_tombstones.down.sql", size: 110, mode: os.FileMode(0644), modTime: time.Unix(1792289200, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7, 0x6c, 0x13, 0x69, 0xde, 0x60, 0x62, 0x14, 0xaf, 0xf0, 0xfe, 0x3f, 0xc7, 0x3a, 0x2c, 0x6b, 0x18, 0x18, 0x26, 0x0, 0xb5, 0x2b, 0x26, 0xd2, 0xcc, 0xb8, 0x2d, 0xbf, 0x86, 0x37, 0xea, 0xee}}
+	return a, nil
+}
+
+var __000003_reading_tombstonesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\x8e\xcd\x6e\xc2\x30\x10\x84\xef\x7e\x8a\x39\xb6\x48\x94\x07\xe0\x64\x88\x69\x2d\xe5\xa7\x22\x46\x45\xbd\x20\x17\x6f\x48\xa4\xd4\x8e\xb2\x8e\xd2\xc7\xaf\x80\x10\xb5\xc7\x9d\xd5\x7c\xf3\xad\x16\x02\x0b\x94\xa1\x8a\x70\xd4\x52\x24\x46\x15\x7a\xf4\x64\x5d\xe3\x2f\x0c\x0e\x88\xb5\x8d\xb0\x18\x98\x7a\x9c\xad\xc7\xe0\x5d\x80\xf5\xb0\xe7\x73\xe3\xc8\x47\xdb\x4e\xd5\x17\x81\xc5\x4a\x6c\xd4\xab\xce\xd7\x42\x2c\x97\x90\x0f\x0e\x1a\x46\x0c\xdf\x5f\x1c\x83\x27\x87\xb1\x26\x3f\x75\xdc\xf5\xe5\x43\x84\x1f\xda\x76\x8d\x26\x5e\xef\x6e\xe8\x2f\xe4\x60\xab\x48\x3d\x62\x4d\xf7\xc9\xb1\xf1\x2e\x8c\x42\xa6\x46\xed\x61\xe4\x26\x55\x33\x5e\x26\x09\xb6\x45\x7a\xc8\xf2\x19\x6b\x74\xa6\x4a\x23\xb3\x77\xf3\x89\x44\xed\xe4\x21\x35\xc8\x0f\x69\x7a\x37\x2b\x3b\x22\xc7\x18\xba\x1b\xfe\xb6\x87\x50\xfd\x75\x9c\xd0\x8c\xb1\x0e\xfc\xcf\x00\xb5\x65\x74\x96\x99\x9c\xd8\xee\x95\x34\x0a\x3a\x4f\xd4\x11\x7a\x87\xbc\x30\x50\x47\x5d\x9a\xf2\x01\x38\x4d\x42\xa7\xc6\xfd\xa0\xc8\x1f\x31\x9e\xa6\xfc\x59\x00\xc0\xc7\x9b\xda\xab\xd9\x5d\x97\x37\xd0\xa4\xbb\x2d\xb2\x4c\x9b\xb5\xf8\x1d\x00\xec\x9f\x18\x78\xab\x01\x00\x00")
+
+func _000003_reading_tombstonesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000003_reading_tombstonesUpSql,
+		"000003_reading_tombstones.up.sql",
+	)
+}
+
+func _000003_reading_tombstonesUpSql() (*asset, error) {
+	bytes, err := _000003_reading_tombstonesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000003_reading_tombstones.up.sql", size: 427, mode: os.FileMode(0644), modTime: time.Unix(1792289200, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1b, 0x8a, 0x41, 0xd5, 0x1c, 0x20, 0x9d, 0x70, 0x59, 0x83, 0xcf, 0x4d, 0x89, 0x78, 0x83, 0x91, 0x39, 0x50, 0xc7, 0x57, 0xba, 0xc1, 0x8c, 0x21, 0x3e, 0xe9, 0xe, 0x25, 0x58, 0x56, 0xbf, 0x47}}
+	return a, nil
+}
+
+var __000004_epistle_searchDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x6e\x00\x91\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x65\x70\x69\x73\x74\x6c\x65\x73\x5f\x73\x65\x61\x72\x63\x68\x5f\x69\x64\x78\x3b\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x65\x70\x69\x73\x74\x6c\x65\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x65\x61\x72\x63\x68\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x75\x8f\xa0\xdf\x6e\x00\x00\x00")
+
+func _000004_epistle_searchDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000004_epistle_searchDownSql,
+		"000004_epistle_search.down.sql",
+	)
+}
+
+func _000004_epistle_searchDownSql() (*asset, error) {
+	bytes, err := _000004_epistle_searchDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000004_epistle_search.down.sql", size: 110, mode: os.FileMode(0644), modTime: time.Unix(1792289294, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xba, 0x15, 0xe1, 0xe4, 0x3a, 0x1d, 0x5d, 0x36, 0xe8, 0xfa, 0x98, 0x77, 0xf2, 0x3c, 0xd3, 0x37, 0x50, 0xee, 0x2d, 0xbe, 0xca, 0xf5, 0x42, 0x5f, 0x81, 0x96, 0x62, 0xaa, 0x1b, 0xda, 0xbe, 0x44}}
+	return a, nil
+}
+
+var __000004_epistle_searchUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\xd0\x4d\xaf\xa2\x30\x18\x05\xe0\x7d\x7f\xc5\xd9\x01\x46\xc7\xfd\xb0\xaa\x50\x09\x09\x96\x04\xea\xe8\xac\x0c\x17\xde\x48\x23\x82\x69\xeb\xc7\xc2\x1f\x7f\xa3\xc6\x8f\xdd\xcd\x5d\xb7\x79\xce\x79\xcf\x74\xc4\x30\xc2\xfc\xd8\x75\x13\x47\x17\x07\x4b\x95\xa9\x5b\x0c\x27\x32\x70\x2d\x81\x0e\xda\xba\x8e\x2c\x5c\x5b\x39\x1c\x2d\x19\x8b\xca\x10\x0c\x55\x8d\xee\xb7\x7f\x18\x46\x53\x36\x13\x49\x2a\x43\xc6\x26\x13\xac\x48\x6f\x5b\x47\xcd\x13\x6a\x86\xfa\xb8\xa7\xde\xfd\x85\xd3\x77\xc7\x54\xfd\x0e\xd5\xd7\x70\x22\x34\x64\x6b\xa3\x0f\x4e\x0f\xbd\xc5\xb9\xd5\x75\xfb\xf9\x7a\x4b\xef\x74\xbf\x63\x3c\x53\xa2\x80\xe2\xb3\x4c\xbc\xeb\xf0\x38\x46\x94\x67\xcb\x85\x7c\x26\xa9\xf2\x9f\x88\x54\x5e\x20\x11\x52\x14\x5c\x89\x18\x3c\x5b\xf1\xff\x25\x78\x09\x9f\x01\x80\x25\x77\xbe\xf7\xf3\xdd\xb0\x71\xf6\x44\xb5\x1b\x8c\xef\x51\xbf\xed\xb4\x6d\xbd\x31\xea\xa1\xea\xc8\xd6\xe4\xdf\xcb\x8e\xe1\x79\x41\x30\x86\xc7\xbd\x00\xd7\xeb\x2f\x89\x8f\xeb\x5e\xd0\xec\x47\xc8\xea\xfd\xa1\xa3\x4f\xe7\xb6\xc1\x0b\x88\xbc\x80\x05\x28\x55\x5e\x88\x38\x64\x2c\x2a\x04\x57\x02\xa9\x8c\xc5\x1a\xe9\x1c\x32\x57\x10\xeb\xb4\x54\xe5\x6b\xa9\xcd\x63\x9e\x8d\x6e\x2e\xc8\xe5\x7b\xc0\x65\x99\xca\x04\x49\x2a\xe1\x3f\x7e\x04\x21\x63\x51\xbe\x58\xa4\x2a\x64\xdf\x03\x00\x76\x73\xb0\x9b\x16\x02\x00\x00")
+
+func _000004_epistle_searchUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000004_epistle_searchUpSql,
+		"000004_epistle_search.up.sql",
+	)
+}
+
+func _000004_epistle_searchUpSql() (*asset, error) {
+	bytes, err := _000004_epistle_searchUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000004_epistle_search.up.sql", size: 534, mode: os.FileMode(0644), modTime: time.Unix(1792289294, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd7, 0xdb, 0x8a, 0x40, 0x6, 0x85, 0x17, 0x1, 0xf3, 0x80, 0x62, 0xb6, 0x31, 0x28, 0xc1, 0xf, 0xe, 0xc1, 0xd6, 0xed, 0x62, 0xb, 0x4c, 0x25, 0x81, 0x69, 0x0, 0x1e, 0x31, 0x44, 0xc9, 0xce}}
+	return a, nil
+}
+
+var __000005_tagsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x4f\x00\xb0\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x61\x64\x69\x6e\x67\x5f\x74\x61\x67\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x74\x61\x67\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x7c\xf4\x88\xbf\x4f\x00\x00\x00")
+
+func _000005_tagsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000005_tagsDownSql,
+		"000005_tags.down.sql",
+	)
+}
+
+func _000005_tagsDownSql() (*asset, error) {
+	bytes, err := _000005_tagsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000005_tags.down.sql", size: 79, mode: os.FileMode(0644), modTime: time.Unix(1792289466, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb0, 0x2b, 0xdc, 0xa8, 0x7c, 0x18, 0xf1, 0x6d, 0xe1, 0x80, 0x79, 0xb, 0x17, 0x9c, 0x4d, 0x94, 0xf, 0x39, 0x84, 0x6b, 0xe7, 0x59, 0x46, 0x7d, 0x79, 0xb0, 0x91, 0x80, 0x26, 0x8a, 0x36, 0x65}}
+	return a, nil
+}
+
+var __000005_tagsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x94\xc1\x4f\xdb\x30\x14\xc6\xef\xfe\x2b\xde\x31\x45\x65\x48\x93\x38\x71\x32\xc9\x6b\xb1\x96\x38\x9d\xe3\x0c\xd8\x25\x32\x8d\x1b\xac\xd1\x84\x25\xae\xd8\xf6\xd7\x4f\x4e\xdc\x10\xa0\xa0\x31\xd1\x43\xd5\x34\x3f\x7f\xef\x7b\xdf\x7b\xc9\xc9\x11\x81\x23\x58\xe9\xf6\x78\xd7\xe9\x16\xac\xaa\x3a\xb0\xb7\xca\xc2\x5a\xd5\x70\xa3\x61\xd7\xe9\x12\x6c\x03\x4d\x5b\xa9\xda\xfc\xd1\xd0\x6a\x55\x9a\xba\xea\xc0\xd4\xb6\x81\x75\x73\x77\xa7\xd7\xd6\x34\x75\xf7\x89\xc0\xd1\x09\x39\xc7\x25\xe3\x67\x84\x1c\x1f\x83\x74\x5a\xaa\xd5\xd0\x3c\xd4\xba\x84\x9b\xdf\xa0\x9c\x5c\x0b\xaa\x2e\xc1\xde\x6a\xd3\x42\xad\xb6\x7a\x60\x76\xb5\xf9\xb9\xd3\xb0\x69\xda\xa1\xbc\x03\x49\x28\x90\x4a\x04\x49\xcf\x63\x04\xb6\x00\x9e\x4a\xc0\x2b\x96\xc9\x6c\x30\x1a\x10\x00\x00\x53\xc2\xf8\xc9\x50\x30\x1a\xc3\x4a\xb0\x84\x8a\x6b\xf8\x82\xd7\xf3\x9e\x71\x72\x85\x07\x19\x97\xb8\x44\xd1\xab\xf1\x3c\x8e\x07\xc2\x59\xd9\xab\x7c\xa3\x22\xbc\xa0\x22\xf8\x7c\x7a\x3a\x7b\x86\xad\x5b\xad\xac\x1e\x84\x24\x4b\x30\x93\x34\x59\xc9\xef\x23\x05\x11\x2e\x68\x1e\x4b\xe0\xe9\x65\x30\x1b\xa4\xb7\x4d\x69\x36\x46\x97\xef\x39\x93\x73\xf6\x35\x47\x08\xbc\xf1\x79\x1f\xd5\x8c\xcc\x86\x68\x13\x75\xef\xc6\xa4\xfd\xbc\x9a\xfe\xf7\x38\x99\x66\xd3\x5f\xbb\xa3\x43\x98\xcd\x43\x3d\xe2\x6f\x85\xea\x15\x8a\x49\xb8\xfa\xde\x74\xf6\x4e\xfb\xec\x0e\x47\x37\x0d\xf7\x35\xc6\xaa\xea\x11\x79\x85\x99\x66\xfb\x7f\xe9\xfe\xfb\xa9\xc9\x86\x40\xf0\xd8\xe4\x7c\xdf\xcc\xdc\x3b\x1e\x22\xa7\xb1\x44\xe1\x23\xeb\xc3\xa1\x51\x04\x61\xca\x33\x29\x28\xe3\x12\x36\x3f\xfa\xcc\x0a\x77\xb8\x6f\x65\x91\x0a\x64\x4b\xee\x16\x70\x9c\xe1\x0c\x04\x2e\x50\x20\x0f\x31\xeb\xcb\x74\x10\x98\x72\xd6\xf3\x29\x87\x08\x63\x94\x08\x21\xcd\x42\x1a\xe1\xb3\xa2\x4f\x26\xf3\xb2\xf8\xf4\xf6\xfe\xe2\xa5\x8f\x03\x6d\x3e\xf1\xe4\x0f\x1e\x06\x3f\xdc\xa6\x55\x07\x2c\xfa\xd0\xa7\xae\x1c\xfc\x76\x50\x7e\xa3\x19\x8f\xf0\xea\x8d\x8d\x76\x5f\x85\x29\x7f\xb9\x2e\xa6\xff\x8f\x55\x27\xaf\xad\x71\xa9\xac\xd9\xea\xce\xaa\xed\xfd\xf8\xdc\x08\xb6\x74\xeb\xdd\x69\xeb\x04\xbb\x62\x8f\x92\x73\x74\xad\x40\xbe\x8a\x1c\x97\x72\x70\xb7\xc9\x22\x15\x80\x34\xbc\x00\x91\x5e\x12\xbc\xc2\x30\x97\x08\x2b\x91\x86\x18\xe5\x02\xc1\xb6\xa6\xaa\x74\x5b\x38\xb9\xbd\x52\x31\x16\x0d\xfc\xf3\x2e\xfc\x64\xde\xe1\xed\x49\xe7\xaf\x7b\x9c\x62\x1f\xe0\x35\x4c\x93\x84\xc9\x33\xf2\x77\x00\x59\x31\x32\xf7\x57\x06\x00\x00")
+
+func _000005_tagsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000005_tagsUpSql,
+		"000005_tags.up.sql",
+	)
+}
+
+func _000005_tagsUpSql() (*asset, error) {
+	bytes, err := _000005_tagsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000005_tags.up.sql", size: 1623, mode: os.FileMode(0644), modTime: time.Unix(1792289466, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf4, 0x22, 0xf1, 0xe5, 0x7, 0x59, 0x77, 0xa5, 0x36, 0x7b, 0xa0, 0xd9, 0x1d, 0x44, 0x7e, 0xe0, 0xe2, 0xe0, 0x3d, 0xff, 0xa6, 0x94, 0x3e, 0x3e, 0xd2, 0x3d, 0x5c, 0x9f, 0x94, 0xdd, 0x5c, 0x44}}
+	return a, nil
+}
+
+var __000006_notesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x2d\x00\xd2\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6e\x6f\x74\x65\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x4e\x02\x13\x1b\x2d\x00\x00\x00")
+
+func _000006_notesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000006_notesDownSql,
+		"000006_notes.down.sql",
+	)
+}
+
+func _000006_notesDownSql() (*asset, error) {
+	bytes, err := _000006_notesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000006_notes.down.sql", size: 45, mode: os.FileMode(0644), modTime: time.Unix(1792290027, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3, 0xbb, 0x16, 0x42, 0x5d, 0x2f, 0x15, 0x4d, 0xa, 0x1e, 0x80, 0x34, 0xc0, 0xc3, 0xa1, 0x2e, 0x67, 0x6b, 0xc4, 0x3b, 0xce, 0xab, 0xdc, 0xea, 0x2e, 0x30, 0x5a, 0xc5, 0xbf, 0x30, 0xc0, 0x7e}}
+	return a, nil
+}
+
+var __000006_notesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x92\xcf\x8e\x9b\x30\x18\xc4\xef\x7e\x8a\xb9\x6d\x12\xed\x76\x1f\x20\x27\x07\x3e\x52\xab\x60\x47\xc6\x68\xb3\xbd\x20\x5a\x1c\xb0\xba\x81\x08\x5c\xa9\x7d\xfb\x8a\xbf\x39\x34\x87\xe5\x88\x7f\x9e\xf1\x37\xf3\xbd\xee\x18\x76\x90\xad\xb7\x3d\x8a\xa6\x44\xed\xaa\xfa\xc3\x55\xb5\xef\xe1\xeb\xc2\xa3\xc0\xef\xde\x76\xa8\x8b\x1e\xd7\xa2\xb4\x68\x1b\xf8\xda\xba\x0e\x9d\x2d\x4a\xd7\x54\x68\x2f\x28\x1a\xd8\x9b\xeb\xfd\x87\xfd\xc2\xb0\x7b\x65\x07\x3a\x0a\xb9\x67\x2c\xd0\xc4\x0d\xc1\xf0\x43\x4c\x10\x11\xa4\x32\xa0\xb3\x48\x4d\x8a\x66\x34\xdc\x30\x00\x70\x25\xd6\x2f\x25\x2d\x78\x8c\x93\x16\x09\xd7\xef\xf8\x46\xef\xcf\x23\x33\xeb\xe7\x03\x2b\xa4\xa1\x23\xe9\x51\x4e\x66\x71\x3c\x11\xc3\x33\xf3\x59\xea\x31\xf1\xa3\x2d\xff\x2e\x3e\x86\xce\x66\x3d\x46\x48\x11\xcf\x62\x83\xa7\xa7\x89\x5c\x43\x58\xc8\x05\xb8\x8b\xfd\xec\x6c\xe1\xed\x64\x67\x44\x42\xa9\xe1\xc9\xc9\x7c\xff\x5f\x53\xaa\xb7\xcd\x76\xba\x73\x6d\x4b\x77\x71\xb6\xfc\xdc\x1d\xb6\xdd\x33\xc6\x63\x43\x7a\x4e\x70\xca\x8c\x87\x21\x02\x25\x53\xa3\xb9\x90\x06\x97\x5f\xf9\xf8\x3f\x9f\xfb\x18\x8d\x22\xa5\x49\x1c\xe5\x10\x1f\x36\xf7\xe8\x9e\x97\x90\xb6\xd0\x14\x91\x26\x19\x50\xba\x16\xf9\x10\x1c\xe5\x94\x44\x48\x31\x19\x42\xc0\xd3\x80\x87\x74\xef\x56\xc8\x90\xce\x8f\xba\x5d\xde\x93\xbb\xf2\x0f\x94\x5c\x0a\x7f\xe4\xb1\x67\xec\xe5\x65\x5e\xc1\x35\x22\xef\xae\xb6\xf7\xc5\xf5\xb6\x6e\x91\x16\xc7\xa1\xd3\xde\xfa\x79\xe2\x85\x65\x07\x1a\x06\x46\x76\x0a\x07\x70\x31\x63\x91\xd2\x20\x1e\x7c\x85\x56\x6f\x8c\xce\x14\x64\x86\x70\xd2\x2a\xa0\x30\xd3\x04\xdf\xb9\xaa\xb2\x5d\x3e\x08\x2e\x52\xf9\x6a\xbb\x19\xd2\x0f\x54\x92\x08\xb3\x67\xff\x06\x00\x9a\x8f\x7e\x1e\x25\x03\x00\x00")
+
+func _000006_notesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000006_notesUpSql,
+		"000006_notes.up.sql",
+	)
+}
+
+func _000006_notesUpSql() (*asset, error) {
+	bytes, err := _000006_notesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000006_notes.up.sql", size: 805, mode: os.FileMode(0644), modTime: time.Unix(1792290027, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x69, 0x64, 0x87, 0x96, 0xa6, 0x8f, 0x5d, 0x69, 0x2a, 0xc7, 0x4, 0x28, 0x9d, 0xf2, 0x24, 0x20, 0xab, 0x2, 0x65, 0x1f, 0x49, 0xf9, 0xfb, 0xc8, 0x57, 0xb9, 0xf9, 0x5f, 0x22, 0x85, 0x49, 0x84}}
+	return a, nil
+}
+
+var __000007_sync_jobsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\xae\xcc\x4b\x8e\xcf\xca\x4f\x2a\xb6\xe6\xe2\x72\xf4\x09\x71\x0d\x82\xaa\x48\x2d\xc8\x2c\x2e\xc9\x49\x2d\x56\x00\xeb\x72\xf6\xf7\x09\xf5\xf5\x43\xd7\x96\x5a\x54\x94\x5f\x64\x4d\xb2\xb6\xc4\x92\x92\xd4\xdc\x82\x92\x62\x92\x75\xa6\xa6\x58\x73\x71\x39\xfb\xfb\xfa\x7a\x86\x58\x73\x01\x06\x00\xbe\xc1\x72\x56\xd6\x00\x00\x00")
+
+func _000007_sync_jobsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000007_sync_jobsDownSql,
+		"000007_sync_jobs.down.sql",
+	)
+}
+
+func _000007_sync_jobsDownSql() (*asset, error) {
+	bytes, err := _000007_sync_jobsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000007_sync_jobs.down.sql", size: 214, mode: os.FileMode(0644), modTime: time.Unix(1792290121, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x62, 0x2e, 0xb2, 0x9d, 0x37, 0xf2, 0xb3, 0xd6, 0xbf, 0x83, 0x68, 0x5d, 0xb4, 0xf0, 0xb8, 0x7f, 0xd0, 0x83, 0x66, 0xe, 0x6, 0x79, 0x6e, 0x54, 0xcf, 0x4, 0xdc, 0x5d, 0xca, 0xaf, 0xe4, 0xc6}}
+	return a, nil
+}
+
+var __000007_sync_jobsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x54\x4d\x73\xa3\x38\x10\xbd\xf3\x2b\xfa\xe8\xa4\x26\x33\xbb\x67\xd7\x1c\x08\xb4\x33\xec\x62\xc8\x08\x51\xc9\xec\x85\x92\xa1\x1d\x6b\x83\x85\x57\x12\xc9\xfa\xdf\x4f\x49\x7c\xd8\x4e\x2a\x55\xf1\xcd\xe8\xf5\xd3\xeb\x7e\x4f\xfd\xed\x3a\x80\x6b\x88\x7b\x2d\x36\x2d\xc1\x7f\x3d\xf5\x04\xdd\x16\xfe\xed\x36\x06\x6c\x07\xe6\xa8\x6a\xa0\x83\x34\xb6\x25\xd8\x93\x15\x8d\xb0\x02\xa4\x02\xbb\x23\xd8\x88\xfa\xf9\x49\x77\xbd\x6a\xbe\x06\x70\xfd\x2d\xb8\xc5\xbb\x24\x5b\x06\xc1\xcd\x0d\x30\xaa\x3b\xdd\x78\x94\xa7\x30\x56\x58\x4f\x4c\xa2\xde\x4d\x84\x41\x98\x72\x64\xc0\xc3\xdb\x14\xa7\x6f\x06\xc2\x38\x86\x28\x4f\xcb\x75\xe6\x6f\xa7\x06\x78\xb2\xc6\x82\x87\xeb\x7b\xfe\x0f\xc4\xb8\x0a\xcb\x94\x43\x56\xa6\xe9\xf2\x53\x04\x95\xb0\x96\xf6\x07\x6b\x20\xc9\x38\xde\x21\x83\x2c\x1f\xea\x67\xb2\x3f\x3e\xc9\x44\x5a\x77\x1a\x38\x3e\xf2\x37\x3a\x5c\xcb\x78\xd6\x19\xec\x84\x01\x61\x61\xdf\x19\x0b\x9d\x22\x38\x90\x6a\xa4\x7a\xf2\x82\xdc\x70\x97\xf0\xda\xe9\x67\xd2\x06\x5a\x12\x86\x40\xb8\x8f\xb0\x39\x82\x21\x6b\x1d\xd0\xee\xc8\x91\xb6\x5d\xfd\x4c\x4d\xd5\x2b\x2b\x5b\xb0\x72\x4f\xc6\x8a\xfd\x01\x4c\x07\x76\x27\x2c\x08\x05\x62\x23\x54\xd3\x29\x6a\x3c\x43\x2d\x14\x6c\x08\x0e\xd2\x95\x41\x7f\x70\x94\x42\x75\x76\x47\x7a\xbc\xf1\x6b\x10\x31\x0c\x39\x8e\xbd\x26\x2b\x3f\x0e\x7c\x4c\x0a\x5e\x78\x79\x95\xf7\x7e\x11\x00\x00\xc8\x06\x2e\x7e\x05\xb2\x24\x4c\xe1\x9e\x25\xeb\x90\xfd\x82\xbf\xf1\xd7\x17\x8f\x1b\xdb\xae\x26\xfc\xbb\x49\x97\x59\xf2\xb3\xc4\x01\x3c\xfb\x01\x1f\x80\x67\x5b\x06\xbc\xee\x55\x25\xb6\x96\xf4\x88\x3f\x8f\xc3\xbb\x9a\x2c\x7f\x58\x5c\x0d\x75\x17\xb3\x7b\x53\x37\xc3\xcb\x34\x1d\xd1\xc2\xd8\xd1\x60\xf7\xf7\xbd\xcb\x03\xac\xd6\x24\x2c\x9d\xc6\xf2\x59\x31\xfb\xae\x91\x5b\x49\xcd\xe7\xeb\x82\xab\x65\x70\x11\xcb\x93\x39\x43\xc2\xb3\x82\xb3\x30\xc9\x38\x6c\x9f\xab\xf9\xac\x1a\xad\xf0\x62\x57\x39\xc3\xe4\x2e\x73\x3e\xc1\xe2\xe4\xd1\x15\x30\x5c\x21\xc3\x2c\xc2\xe2\x14\xf6\x85\x6c\xae\x7c\x55\x9e\x41\x8c\x29\x72\x84\x28\x2c\xa2\x30\xc6\x65\x30\x65\x26\xc9\x62\x7c\xfc\x28\x33\xd5\x6c\x54\x25\x9b\xff\x21\x1f\x1f\xcd\x10\xa7\xf9\xcc\x75\x75\x73\x03\x85\x7b\x08\x7f\xb9\xa3\x79\x32\x73\xbc\xe7\x84\xb2\xe4\xce\xa5\xc8\x90\x3d\x6b\x70\xc2\x07\xb7\xe8\xfa\x83\xf2\x3e\x76\xe0\xf3\xeb\x82\x55\xce\x00\xc3\xe8\x07\xb0\xfc\x21\xc0\x47\x8c\x4a\x8e\x70\xcf\xf2\x08\xe3\x92\x21\x58\x2d\x9f\x9e\x48\x57\x8e\x78\xa2\xab\xe6\xeb\x17\xa3\x44\x9c\x26\x33\x3c\xb5\x56\x93\x68\x8e\xb0\x13\x2f\x67\x9b\xf0\x95\x34\x4d\x6b\x4a\xaa\x56\x2a\x82\xd7\x1d\xf9\xf5\x78\x1c\x0e\xc7\xc0\x04\xa3\xcc\x79\xdc\x05\xf2\xb1\xf0\xfb\x24\xe1\xcb\xe5\xbe\xfa\xfe\xa7\xb7\xe3\xe1\x07\x3a\xc9\xd2\xad\x95\xa4\x38\x85\x25\x67\xd0\x90\xa9\xb5\x3c\x58\xd9\xa9\xb7\x47\x5b\xf1\x22\xeb\xcb\xcf\x43\x57\x3f\xfd\x8a\x17\xea\x78\x72\xde\xf7\xe7\xc5\x2a\x7a\x21\x0d\xa6\xaf\x6b\x32\x66\xdb\xb7\xed\x71\xd4\x18\x24\x59\x81\x8c\xbb\xb7\x9a\x9f\xdb\x3a\x72\x54\x53\x76\x0a\x4c\x31\xe2\x6e\x6f\xac\x58\xbe\x3e\x5d\x31\x34\x31\x0e\xca\x49\x1d\xd6\x66\x94\xaf\xd7\x09\x5f\x06\xbf\x07\x00\x27\x2b\x14\x56\x86\x06\x00\x00")
+
+func _000007_sync_jobsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000007_sync_jobsUpSql,
+		"000007_sync_jobs.up.sql",
+	)
+}
+
+func _000007_sync_jobsUpSql() (*asset, error) {
+	bytes, err := _000007_sync_jobsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000007_sync_jobs.up.sql", size: 1670, mode: os.FileMode(0644), modTime: time.Unix(1792290121, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc2, 0xca, 0x33, 0x68, 0xed, 0x66, 0xe7, 0x77, 0xf2, 0xef, 0x65, 0x3, 0xdd, 0xd0, 0x2d, 0xc5, 0xd3, 0xa9, 0x81, 0x2, 0xa, 0xb3, 0x67, 0x89, 0x68, 0x7c, 0xbc, 0xb4, 0x8c, 0x45, 0xef, 0x2d}}
+	return a, nil
+}
+
+var __000008_epistle_cache_headersDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x2d\xc8\x2c\x2e\xc9\x49\x2d\x8e\x2f\xae\xcc\x4b\x4e\x4d\x89\xcf\x4c\xa9\xb0\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x85\xab\x50\x00\xeb\x77\xf6\xf7\x09\xf5\xf5\x43\x32\x20\x27\xb1\xb8\x24\x3e\x37\x3f\x25\x33\x2d\x33\x35\xc5\x9a\x14\x9d\xa9\x25\x89\xe9\xd6\x5c\x5c\xce\xfe\xbe\xbe\x9e\x21\xd6\x5c\x80\x01\x00\x73\x72\x74\x39\xa7\x00\x00\x00")
+
+func _000008_epistle_cache_headersDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000008_epistle_cache_headersDownSql,
+		"000008_epistle_cache_headers.down.sql",
+	)
+}
+
+func _000008_epistle_cache_headersDownSql() (*asset, error) {
+	bytes, err := _000008_epistle_cache_headersDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000008_epistle_cache_headers.down.sql", size: 167, mode: os.FileMode(0644), modTime: time.Unix(1792290206, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe1, 0xb2, 0x94, 0x1d, 0xc7, 0xec, 0x31, 0xac, 0x14, 0x1d, 0x59, 0x12, 0xcf, 0xb3, 0xf1, 0x1a, 0x4a, 0x6, 0x3c, 0x5e, 0x8, 0x9e, 0x11, 0x5c, 0xe1, 0x1f, 0x25, 0x93, 0x1b, 0xda, 0x1d, 0x2c}}
+	return a, nil
+}
+
+var __000008_epistle_cache_headersUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x8e\x4d\x6a\xc3\x30\x14\x84\xf7\x3a\xc5\x2c\x5b\x2f\x9a\x03\x78\xa5\xd8\x4a\x11\xc8\x32\x24\xcf\xe0\x9d\x11\xd6\x4b\x22\xea\x48\xad\xa5\xfe\xdd\xbe\x84\x42\xba\x29\x64\x3b\x7c\xf3\xcd\x6c\x2a\x81\x0a\x8d\x9b\xcf\x8c\x0f\xb7\x04\xef\x4a\x5a\x33\x8e\x69\xc5\xc5\xbd\x84\x78\xc2\x9c\xa2\x0f\x25\xa4\xe8\x16\xac\xfc\xf6\xce\xb9\x64\x7c\x9e\x39\x62\xe5\xfc\x1d\xe7\x2b\xc3\xaf\x21\x97\x85\xf3\x93\x40\xb5\x11\x5b\xf5\xac\x6d\x2d\x84\x34\xa4\xf6\x20\xb9\x35\xea\x46\x40\xb6\x2d\x9a\xde\x0c\x9d\x05\x17\x77\x02\xa9\x91\xd0\xaa\x9d\x1c\x0c\xc1\x0e\xc6\xd4\x77\x7b\x8b\xcb\x65\xba\x24\x1f\x8e\x81\xfd\x7f\x02\xd1\xec\x95\x24\x05\x6d\x5b\x35\x42\xef\x60\x7b\x82\x1a\xf5\x81\x0e\x37\xe1\x74\xfd\xce\x7e\x0a\xfe\x0b\xbd\xfd\xdb\x79\xf8\xcd\x1f\x6b\x21\x9a\xbe\xeb\x34\xd5\xe2\x67\x00\x96\x30\x31\xdd\x24\x01\x00\x00")
+
+func _000008_epistle_cache_headersUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000008_epistle_cache_headersUpSql,
+		"000008_epistle_cache_headers.up.sql",
+	)
+}
+
+func _000008_epistle_cache_headersUpSql() (*asset, error) {
+	bytes, err := _000008_epistle_cache_headersUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000008_epistle_cache_headers.up.sql", size: 292, mode: os.FileMode(0644), modTime: time.Unix(1792290206, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xbe, 0xdf, 0x62, 0xdc, 0xec, 0x67, 0xed, 0xe9, 0xe3, 0xf1, 0x56, 0xa9, 0xbd, 0x2e, 0x74, 0x83, 0xee, 0x18, 0xd3, 0xf, 0x77, 0x7c, 0xd9, 0xed, 0x58, 0xb1, 0x9f, 0x29, 0x1a, 0x68, 0x1e, 0xe6}}
+	return a, nil
+}
+
+var __000009_epistle_metadataDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\xcb\x51\x0e\x82\x30\x0c\x00\xd0\xff\x9e\xa2\xf7\xd8\x17\xe0\x34\x4b\x36\x66\x60\x26\xfe\x99\x8a\x8d\x34\x19\x83\xb8\x71\x7f\xcf\xd0\x03\xbc\xde\xde\xdc\x68\x00\x3a\x9f\xec\x84\xa9\xeb\xbd\x45\x3e\xa4\xb6\xcc\x15\x2f\x53\xbc\xe3\x10\xfd\x23\x8c\xe8\xae\x68\x9f\x6e\x4e\x33\x2e\x54\xf6\x22\x0b\x65\xa3\x51\xb2\xd1\x97\x55\xe2\x38\xdf\x59\xea\xca\x1f\x95\xa2\xb3\xad\xfb\x4f\x45\xaa\x34\x7e\x15\xda\xd8\x00\x0c\x31\x04\x97\x0c\xfc\x07\x00\xa4\x33\xdd\x89\x18\x01\x00\x00")
+
+func _000009_epistle_metadataDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000009_epistle_metadataDownSql,
+		"000009_epistle_metadata.down.sql",
+	)
+}
+
+func _000009_epistle_metadataDownSql() (*asset, error) {
+	bytes, err := _000009_epistle_metadataDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000009_epistle_metadata.down.sql", size: 280, mode: os.FileMode(0644), modTime: time.Unix(1792290322, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x39, 0xc4, 0xba, 0x78, 0xe, 0x10, 0xc4, 0xfb, 0x5, 0xc7, 0x57, 0x3, 0xc4, 0x9d, 0x51, 0xcd, 0xec, 0x5f, 0xc0, 0xa7, 0x34, 0x5e, 0x4a, 0x66, 0x96, 0x68, 0x2d, 0x25, 0xf, 0xe6, 0xb9, 0x8b}}
+	return a, nil
+}
+
+var __000009_epistle_metadataUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\xd1\x4d\x6b\xdb\x40\x10\xc6\xf1\xfb\x7e\x8a\xe7\xd8\x9a\x38\x81\x42\x4e\x26\x87\x8d\xa4\xa6\x2e\x7a\x09\xf6\xaa\x94\x5e\xc2\x58\x3b\xb6\x16\xa4\x5d\xb1\x3b\xb6\xdb\x6f\x5f\x54\x5c\xdc\x43\xa1\x98\x5e\x07\x9e\x1f\x7f\x98\x87\x85\xc2\x02\xda\x5a\x27\x2e\x78\x1a\x30\xb2\x90\x25\x21\xd0\x2e\x1c\x05\x3c\xb9\x24\x03\x27\xec\x63\x18\xd1\x4c\xec\x5f\x22\x4d\xfd\x1d\xcc\xd9\x89\x70\x44\x46\xd1\x26\x90\xb7\xf8\xbc\x6d\xea\x65\x99\xdf\x2b\x2c\x1e\xd4\x73\xf1\xb2\xae\x57\x4a\xe9\xd2\x14\x1b\x18\xfd\x5c\x16\x57\x4b\xe7\x39\xb2\xa6\x6c\xab\x1a\xc9\x09\xbf\x79\x1a\x19\x5f\xf4\x26\xfb\xa4\x37\xef\x3e\x3c\x3e\xbe\x47\x5e\x7c\xd4\x6d\x69\x50\xb7\x65\xb9\xfa\x27\x42\x47\xe9\x43\xfc\x1f\x61\x3a\xee\x06\x97\x7a\xb6\x30\xeb\xaa\xd8\x1a\x5d\xbd\x9a\x6f\x37\x1a\x6e\xa4\x03\xc3\x14\x5f\xcd\x8d\xc3\x8e\x7c\xf0\xae\xa3\xe1\x6f\x63\xb5\x5c\x22\x1b\x98\x22\xa4\x67\x74\xd4\xf5\x8c\x13\x0d\xce\x92\x84\x98\x90\xc2\xaf\xbb\xe7\xef\x82\xc8\xe9\x87\xef\xb0\x67\xe9\x7a\x4e\x97\xfb\xf9\xfa\xd1\x48\xd2\xf3\xec\x90\x9f\xd5\xc8\x1d\xbb\x93\xf3\x07\x10\x7c\x10\x8c\xc1\xba\xbd\x63\x3b\x3b\x53\xf0\x89\xef\x55\xfb\x9a\x6b\xf3\x47\xf4\xb6\x30\x60\xa1\xc3\xd3\xdc\x76\x87\x81\x92\xbc\xfd\x9e\x3d\x5d\x7a\xb3\xa6\xaa\xd6\x66\xa5\x7e\x0e\x00\x53\x56\xcb\xb9\x5a\x02\x00\x00")
+
+func _000009_epistle_metadataUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000009_epistle_metadataUpSql,
+		"000009_epistle_metadata.up.sql",
+	)
+}
+
+func _000009_epistle_metadataUpSql() (*asset, error) {
+	bytes, err := _000009_epistle_metadataUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000009_epistle_metadata.up.sql", size: 602, mode: os.FileMode(0644), modTime: time.Unix(1792290343, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa2, 0x9c, 0x46, 0x28, 0x7d, 0xa2, 0xbe, 0xa4, 0x63, 0x0, 0xc2, 0xf6, 0x7b, 0x7d, 0xc8, 0x5b, 0xcf, 0xfe, 0x38, 0xe3, 0x3a, 0xba, 0x4e, 0x30, 0x35, 0xc0, 0x5, 0xe5, 0x21, 0xbf, 0xeb, 0x55}}
+	return a, nil
+}
+
+var __000010_epistle_normalizedDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x77\x00\x88\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x65\x70\x69\x73\x74\x6c\x65\x73\x5f\x6e\x6f\x72\x6d\x61\x6c\x69\x7a\x65\x64\x5f\x69\x64\x78\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x65\x70\x69\x73\x74\x6c\x65\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6e\x6f\x72\x6d\x61\x6c\x69\x7a\x65\x64\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xaa\xdb\xff\x4d\x77\x00\x00\x00")
+
+func _000010_epistle_normalizedDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000010_epistle_normalizedDownSql,
+		"000010_epistle_normalized.down.sql",
+	)
+}
+
+func _000010_epistle_normalizedDownSql() (*asset, error) {
+	bytes, err := _000010_epistle_normalizedDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000010_epistle_normalized.down.sql", size: 119, mode: os.FileMode(0644), modTime: time.Unix(1792290423, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x71, 0x73, 0x2b, 0xe2, 0xa, 0xca, 0xfa, 0x7d, 0x18, 0xb2, 0x8b, 0x36, 0xc1, 0x45, 0x4c, 0xb0, 0xdb, 0x8b, 0x16, 0xa2, 0x2c, 0x92, 0x11, 0xd0, 0xa8, 0x90, 0xe6, 0x25, 0x71, 0x32, 0x18, 0x6}}
+	return a, nil
+}
+
+var __000010_epistle_normalizedUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x44\xcf\x31\x6f\xc2\x30\x14\x04\xe0\xdd\xbf\xe2\x46\x60\x00\xd4\x35\x93\x49\x4c\x6b\xc9\x38\x6a\x70\x2a\x36\x94\xc6\x4f\xc5\x22\x38\xc8\x76\x54\xd2\x5f\x5f\xa5\xaa\xc8\x7a\x4f\xf7\x9d\xde\x66\xc5\xb0\x82\xee\xc3\xad\xe9\xdc\x0f\x59\x74\xce\x5f\x71\xa5\x31\x62\x88\x64\x91\x7a\x58\x4a\xd4\x26\xd8\xe1\xde\xb9\xb6\x49\x04\xba\xbb\x98\x3a\x8a\x6b\x88\x87\x8b\xc9\xf9\xaf\x67\x84\x26\xd0\xd4\x26\x3b\xb1\xdf\x17\xf2\x48\x17\x1a\xff\xe2\x40\x71\xf4\x2d\x59\xf4\x01\x9f\x23\xc2\xe0\xfd\x54\x4d\x97\x7f\xb1\xef\x9a\x30\xc2\x92\x1d\xee\x84\xb6\xbf\xdd\x1a\x6f\xd7\x0c\xab\x0d\xdb\x89\x57\xa9\x33\xc6\xb8\x32\xa2\x82\xe1\x3b\x25\xe6\x45\x5e\x14\xc8\x4b\x55\x1f\x34\xfc\xfc\xc6\x07\xaf\xf2\x37\x5e\x2d\x5e\xb6\xdb\xed\x12\x85\xd8\xf3\x5a\x19\xe8\x5a\xa9\x8c\xb1\xbc\x12\xdc\x08\xd4\x5a\xbe\xd7\x02\x52\x17\xe2\x04\xb9\x87\x2e\x0d\xc4\x49\x1e\xcd\xf1\xa9\x9f\x67\xf2\xec\xec\x03\xa5\x9e\x87\x17\xf3\x6d\x99\x31\x96\x97\x87\x83\x34\x19\xfb\x1d\x00\x9a\x10\xe6\xbe\x52\x01\x00\x00")
+
+func _000010_epistle_normalizedUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000010_epistle_normalizedUpSql,
+		"000010_epistle_normalized.up.sql",
+	)
+}
+
+func _000010_epistle_normalizedUpSql() (*asset, error) {
+	bytes, err := _000010_epistle_normalizedUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000010_epistle_normalized.up.sql", size: 338, mode: os.FileMode(0644), modTime: time.Unix(1792290423, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xeb, 0xd8, 0x8e, 0x90, 0xb, 0xb9, 0xb5, 0xa2, 0x29, 0x20, 0x19, 0x79, 0xf2, 0x55, 0x17, 0x88, 0x2c, 0x5, 0x71, 0x74, 0xe5, 0x98, 0x6, 0x2e, 0x49, 0x1, 0x4a, 0x38, 0x95, 0xa3, 0xb2, 0xfb}}
+	return a, nil
+}
+
+var __000011_epistle_contentDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x37\x00\xc8\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x65\x70\x69\x73\x74\x6c\x65\x5f\x63\x6f\x6e\x74\x65\x6e\x74\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x62\x95\x18\xbd\x37\x00\x00\x00")
+
+func _000011_epistle_contentDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000011_epistle_contentDownSql,
+		"000011_epistle_content.down.sql",
+	)
+}
+
+func _000011_epistle_contentDownSql() (*asset, error) {
+	bytes, err := _000011_epistle_contentDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000011_epistle_content.down.sql", size: 55, mode: os.FileMode(0644), modTime: time.Unix(1792290812, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8d, 0xae, 0x98, 0x1, 0x69, 0x94, 0xb8, 0x43, 0x6e, 0x8a, 0xd4, 0x6d, 0xcc, 0xa5, 0x99, 0x5b, 0x46, 0xe7, 0x9, 0x97, 0x14, 0x69, 0xb0, 0x51, 0x16, 0xb0, 0xdc, 0x5a, 0x4e, 0xa7, 0xa, 0x9d}}
+	return a, nil
+}
+
+var __000011_epistle_contentUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x53\xc1\x72\xe2\x3a\x10\xbc\xeb\x2b\xfa\x16\x48\x85\xe4\xdd\xa9\x77\x70\xec\x81\x75\x2d\xd8\x94\x10\x95\x64\x2f\x94\xd6\x1e\x40\xb5\x58\x4e\x49\xda\x6c\xf2\xf7\x5b\x32\xd8\xc9\x26\x17\x74\x93\xdc\x3d\xee\xe9\xe9\xb9\xbb\x16\xb8\x86\x64\x5d\xb3\x9b\x34\x6d\xcd\xa8\x5a\x1b\xd8\x06\xf0\x6b\x70\xba\x0a\x5c\x63\xe7\xda\x06\xfc\x6c\x7c\x38\xb2\x87\x6f\x11\x0e\xfc\x86\x4a\x5b\xfc\x64\x38\xd6\x35\xda\xdd\xee\x68\x2c\xdf\x0a\x5c\xdf\x89\x7b\x9a\xe7\xc5\x54\x88\x54\x52\xa2\x08\x2a\xb9\x5f\x10\xf2\x19\x8a\x52\x81\x1e\xf3\xb5\x5a\xf7\xc5\xb6\xfd\xbf\x46\x02\xc0\xf0\x6a\xea\x78\x03\xf2\x42\xd1\x9c\x24\x56\x32\x5f\x26\xf2\x09\xdf\xe9\xe9\xa6\x03\x1e\x42\x73\xc4\x87\xa3\xe8\x51\x75\xe5\x8b\xcd\x62\x81\x8c\x66\xc9\x66\xa1\x70\x75\x75\x42\x07\x7e\x0d\x97\xa3\xff\xb4\xae\xde\x56\xed\x6f\x1b\xfe\x15\xf1\x85\xf0\xdf\x09\x1f\xfb\x37\x76\xbf\x0d\xa6\xe1\x4b\xf0\x95\x63\x1d\x4d\xed\x8f\xca\x97\xb4\x56\xc9\x72\xa5\x7e\x7c\xe5\x14\xe5\xc3\x68\x7c\xe2\x35\x6d\x6d\x76\x86\xeb\xcb\x79\x62\x3c\x15\x22\x59\x28\x92\xe7\x19\x7c\x76\x3d\xc9\x32\xa4\x65\xb1\x56\x32\xc9\x0b\x85\xdd\xaf\xed\x27\x44\x7f\xef\x04\xcc\x4a\x49\xf9\xbc\x88\x53\xc0\xa8\x07\x9a\x7a\x0c\x49\x33\x92\x54\xa4\x34\xcc\xd5\x63\x64\xea\x71\xc7\x2a\x0b\x64\xb4\x20\x45\x48\x93\x75\x9a\x64\x34\x15\x62\x32\x01\x9d\xf8\x43\xd8\x86\xee\xa2\x8d\x3e\xe8\xe6\x79\x88\x8f\xcc\xe7\xd1\x4f\xcf\xe1\x8b\xbc\x9e\x25\xee\x29\xaa\xc3\x66\x95\x45\x4a\x59\xe0\x13\x52\xcc\x4a\x09\x4a\xd2\x6f\x90\xe5\x83\xa0\x47\x4a\x37\x8a\xb0\x92\x65\x4a\xd9\x46\x12\x82\x33\xfb\x3d\xbb\xad\xe7\xf7\xa2\xdb\x41\xca\x28\x1a\x39\x99\x20\x3d\xb2\x76\x31\xfc\xa8\x74\x75\x60\xbc\xe8\xa3\xa9\x75\x68\x5d\xbf\x14\xb0\x31\x6a\x8e\xfd\x9b\xad\xfa\xfd\xf1\xdd\x87\xb3\x0e\x38\x1d\x0e\x1c\x6b\x68\x1b\x6d\x70\x5c\xb1\x79\x31\x76\x0f\x0d\xdb\x7e\xb0\xc1\xb1\x7f\x6e\xad\xe7\x5b\x71\xee\xe9\xdc\x90\xc7\x9a\x14\x38\xe8\xfd\xff\x71\xe0\x37\x38\x6a\xff\x2e\xb9\x7b\x9b\x0a\x91\x96\xcb\x65\xae\xa6\xe2\xef\x00\xdd\xce\xa5\x5a\xdd\x03\x00\x00")
+
+func _000011_epistle_contentUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000011_epistle_contentUpSql,
+		"000011_epistle_content.up.sql",
+	)
+}
+
+func _000011_epistle_contentUpSql() (*asset, error) {
+	bytes, err := _000011_epistle_contentUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000011_epistle_content.up.sql", size: 989, mode: os.FileMode(0644), modTime: time.Unix(1792290812, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xcc, 0xb6, 0x25, 0x56, 0xc2, 0xfa, 0xb6, 0x84, 0x55, 0x7c, 0xbe, 0x0, 0x4b, 0x87, 0xbb, 0x12, 0xe9, 0x68, 0x75, 0x8b, 0xf4, 0xe7, 0x80, 0x4d, 0x26, 0x79, 0xce, 0xf0, 0xd4, 0x94, 0xf8, 0x30}}
+	return a, nil
+}
+
+var __000012_feed_tokensDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x33\x00\xcc\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x66\x65\x65\x64\x5f\x74\x6f\x6b\x65\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x31\x72\x2e\xb0\x33\x00\x00\x00")
+
+func _000012_feed_tokensDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000012_feed_tokensDownSql,
+		"000012_feed_tokens.down.sql",
+	)
+}
+
+func _000012_feed_tokensDownSql() (*asset, error) {
+	bytes, err := _000012_feed_tokensDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000012_feed_tokens.down.sql", size: 51, mode: os.FileMode(0644), modTime: time.Unix(1792291152, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3f, 0x65, 0xf6, 0xfd, 0x1e, 0x8, 0x7d, 0x28, 0xfc, 0x4e, 0xd1, 0x45, 0xf2, 0xca, 0xc6, 0xb0, 0xe6, 0x7c, 0xdd, 0xdf, 0x25, 0x8c, 0x8a, 0xfe, 0xdf, 0xb1, 0x68, 0x40, 0xa9, 0xc7, 0xe1, 0xca}}
+	return a, nil
+}
+
+var __000012_feed_tokensUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x92\x51\x6f\xda\x30\x14\x85\xdf\xfd\x2b\xce\xdb\xa0\x5a\xdb\x1f\xc0\x93\x49\x6e\x98\xb5\xe0\x30\xc7\x51\xdb\xbd\x44\x1e\x31\x60\x15\x42\x15\x9b\x69\xfb\xf7\x93\xdd\x40\x61\xd2\xa4\xe5\x29\xd1\x3d\xe7\xbb\xb9\xe7\xde\xc7\x3b\x86\x3b\xe8\xe3\xab\xed\x3d\xc2\xce\x04\x98\x53\xd8\xd9\x3e\xb8\xb5\x09\x16\x61\x67\xf1\x36\xb8\x9f\xf1\x7d\x63\x6d\xe7\x71\xdc\xc0\xe0\xe4\xed\xf0\xc9\x63\xb0\xa6\x73\xfd\x16\x7b\xe7\xc3\x03\xaa\x7e\xff\x3b\x19\x76\xc6\xef\x70\xdc\x44\x70\xfc\x0c\x11\x0e\xe7\xe1\xc3\x71\xb0\x1d\xbc\xeb\xd7\xf6\xb6\x62\xf0\xc3\x9a\xc1\x0e\x58\x0f\xb6\x8b\xcd\xcd\x1e\xae\x4f\x9a\xd8\x15\x8d\x2a\x1f\x18\xee\x1e\xd9\x9c\x16\x42\xce\x18\xcb\x14\x71\x4d\xd0\x7c\x5e\x12\x44\x01\x59\x69\xd0\xb3\xa8\x75\x9d\x0c\x6d\x22\x7b\x4c\x18\x00\xb8\x0e\x97\xa7\x26\x25\x78\x89\x95\x12\x4b\xae\x5e\xf0\x95\x5e\x3e\x27\x4d\x9c\xa8\x1d\x85\x42\x6a\x5a\x90\x4a\x50\xd9\x94\xe5\xbb\x22\x21\x47\xca\xfc\x45\x13\x47\x23\xc5\xb7\x86\xfe\x92\xed\x8d\x0f\xed\xc9\xdb\x88\xd2\x62\x49\xb5\xe6\xcb\x95\xfe\x8e\x9c\x0a\xde\x94\xd7\xca\xf5\x60\x4d\x48\xba\x5b\xe5\x99\xf7\x61\xa9\x9e\x26\xd3\x77\xcf\xe1\xd8\xb9\x8d\xb3\xdd\xff\x79\xd8\x74\xc6\x18\x2f\x35\xa9\x31\xa9\xeb\x6c\x78\x9e\x23\xab\x64\xad\x15\x17\x52\x63\xf3\xda\x5e\x55\xe3\x04\x43\xea\x58\x54\x8a\xc4\x42\xc6\xa4\x30\x19\x53\x9a\x42\x51\x41\x8a\x64\x46\x75\xba\x05\x8f\x89\xeb\xa6\x49\x5f\x49\xe4\x54\x92\x26\x64\xbc\xce\x78\x4e\x1f\xdb\x12\x32\xa7\xe7\x7f\x6f\xab\x1d\xe9\xbf\x50\xc9\xdb\x2d\x9e\xdb\xce\x18\xbb\xbf\x47\x11\x2f\x62\x2c\x5d\xf2\x08\xee\x60\x7d\x30\x87\xb7\xcb\x69\x28\xb1\x88\x4b\xf4\x36\xdc\x0c\x76\x76\xb0\x39\xc5\xd1\xd0\xac\xf2\x28\xbf\x6d\xc9\x8a\x4a\x81\x78\xf6\x05\xaa\x7a\x62\xf4\x4c\x59\xa3\x09\x2b\x55\x65\x94\x37\x8a\x10\x06\xb7\xdd\xda\xa1\x8d\xf0\x33\xb0\xbd\xfc\xc2\x24\xc6\x9e\x55\xcb\xa5\xd0\x33\xf6\x67\x00\xff\x53\x94\x62\x61\x03\x00\x00")
+
+func _000012_feed_tokensUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000012_feed_tokensUpSql,
+		"000012_feed_tokens.up.sql",
+	)
+}
+
+func _000012_feed_tokensUpSql() (*asset, error) {
+	bytes, err := _000012_feed_tokensUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000012_feed_tokens.up.sql", size: 865, mode: os.FileMode(0644), modTime: time.Unix(1792291152, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x49, 0xe8, 0x73, 0x85, 0x98, 0x16, 0x55, 0x99, 0xa2, 0xea, 0x7d, 0xeb, 0xc8, 0xb8, 0xc6, 0xe7, 0x97, 0xaf, 0x6d, 0xef, 0x40, 0x77, 0xdd, 0x28, 0x31, 0xb9, 0x27, 0x8a, 0xf6, 0x42, 0x73, 0x28}}
+	return a, nil
+}
+
+var __000013_subscriptionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x5e\x00\xa1\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x75\x62\x73\x63\x72\x69\x70\x74\x69\x6f\x6e\x5f\x69\x74\x65\x6d\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x75\x62\x73\x63\x72\x69\x70\x74\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x4b\x02\x97\x96\x5e\x00\x00\x00")
+
+func _000013_subscriptionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000013_subscriptionsDownSql,
+		"000013_subscriptions.down.sql",
+	)
+}
+
+func _000013_subscriptionsDownSql() (*asset, error) {
+	bytes, err := _000013_subscriptionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000013_subscriptions.down.sql", size: 94, mode: os.FileMode(0644), modTime: time.Unix(1792291554, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x56, 0xfc, 0xd1, 0xef, 0x6a, 0x42, 0x8b, 0x67, 0xc2, 0x7e, 0xe8, 0x17, 0x9b, 0x83, 0x9a, 0x10, 0x2, 0xa7, 0x8b, 0xba, 0xcf, 0x55, 0xff, 0xbe, 0x29, 0x59, 0xb, 0x72, 0xa8, 0xcb, 0xe6, 0xdc}}
+	return a, nil
+}
+
+var __000013_subscriptionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x54\xcd\x72\xe2\x3c\x10\xbc\xfb\x29\xe6\x08\xa9\xfc\x7c\xf7\x9c\x8c\x3d\x10\xd7\x67\x6c\x56\x16\x15\xb2\x17\x97\x82\x06\x50\xc5\xd8\xac\x24\x92\xcd\xdb\x6f\xc9\x76\xbc\x36\xf9\x61\x77\xa1\x8a\x03\xea\xe9\x19\x4d\x77\xeb\xe6\xc2\x83\x0b\xc8\x8e\x8f\x66\xad\xd5\xc1\xaa\xaa\x34\x60\x2b\x60\x59\x06\xa2\x94\xe0\xdb\x6a\x0f\x1b\x22\x69\xe0\x65\x57\x19\x82\x92\x5e\x40\x59\xda\x1b\x10\x9a\xe0\xc7\x91\x8e\x24\x41\x18\xd0\x24\xa4\x2a\xb7\x06\x36\x95\x06\xbb\x23\xc7\x7a\x34\xa4\xaf\x81\xef\xa8\xad\xb0\x3b\x61\x61\x27\x9e\x09\x44\xe1\xf0\xaf\xf0\x48\x54\x82\x71\x3f\x8e\x4d\xd3\xba\xd2\x92\x24\x98\xaa\xc1\x92\x58\xef\xe0\x50\x19\x0b\xca\x40\x55\x16\xaf\x8e\xb5\xed\x59\x95\x6b\x02\x7a\xa6\x12\xd4\xc6\x35\xac\xbb\x41\x21\x2c\x69\x90\x54\x90\x25\x03\x95\x06\xa1\xd7\x3b\xf5\x4c\xa6\x86\xb4\x43\x5e\x7b\x70\x71\xe3\x4d\x70\x16\x25\xb7\x9e\x17\x30\xf4\x39\x02\xf7\x27\x31\x42\x34\x85\x24\xe5\x80\xab\x28\xe3\x19\x98\xc1\x5a\x46\x1e\x00\x80\x92\x30\xf8\x64\xc8\x22\x3f\x86\x05\x8b\xe6\x3e\x7b\x80\xff\xf1\xe1\xb2\xc6\xb9\x71\xf2\x1e\x38\x4a\x38\xce\x90\xd5\xf4\xc9\x32\x8e\x1b\x54\xa1\xca\xa7\x37\x84\xfb\x72\x5c\xf1\x13\x88\x55\xb6\xa0\x53\x48\x88\x53\x7f\x19\xf7\x61\x46\x59\xca\x7b\x74\x9f\xc0\xc8\x8a\x2d\x9c\x67\x2b\x84\xb1\xf9\xbe\x92\x6a\xa3\x48\x7e\xce\x76\x10\x47\x43\xbd\x85\x4c\xd2\x34\x46\x3f\xe9\x6e\xd0\x95\x6c\x44\x61\xa8\xad\xa9\x8a\xa2\x5f\xc3\xa3\x39\x66\xdc\x9f\x2f\xf8\xf7\x0f\x3a\x6c\x84\x2a\x8e\x9a\xcc\x67\x6b\xec\x4a\xfe\x6b\xef\xa7\x75\xa5\xcf\xdf\x6f\xad\x49\x58\x92\x1f\x0e\xf1\x8e\x3a\x49\xef\x47\xe3\xa6\xae\xb7\x92\xbf\xaa\x5b\x26\xd1\xb7\x25\xc2\xa8\x75\xc5\x25\x38\xa5\xc6\xde\xf8\xd6\xf3\xfc\x98\x23\x6b\xed\x37\x34\x9c\x1f\x86\x10\xa4\x49\xc6\x99\x1f\x25\x1c\x36\x4f\xf9\xe0\x3c\x77\x64\x35\xfb\x34\x65\x18\xcd\x12\x67\xbe\xae\xc5\x18\x18\x4e\x91\x61\x12\x60\x56\x67\xc3\xc0\x48\xc9\x71\x8d\x4f\x13\x08\x31\x46\x8e\x10\xf8\x59\xe0\x87\xf8\x3b\x06\x51\x12\xe2\xea\xab\x18\xe4\x8d\x7e\xb9\x92\x3f\x21\x4d\x4e\x23\xd2\x1c\xd6\x6b\xc8\x60\x1a\xb1\x8c\x8f\xe1\xfe\x0e\x19\xd6\xdb\x69\xec\xf2\xe7\x99\xcb\x9b\x77\xa3\x09\xde\xf0\x40\xbe\x73\x42\x23\xd0\xf6\xa8\xe4\x99\x40\xfd\xab\xf6\xbd\x88\xc3\xe8\x64\x9a\xcb\xba\xf1\xd7\x82\xb6\xb7\xf9\x5a\xd5\x06\x34\xf8\xeb\xbd\xc4\xc3\x82\xa1\xd4\xfd\xb3\x33\x92\x5f\x5d\x9d\x3c\xfc\x9d\xbb\xad\xda\x93\xb1\x62\x7f\xe8\x94\x62\xd1\xcc\xc5\xce\x90\x1d\xcc\x66\xba\x47\xc2\x9b\xa0\xb3\x21\x2c\x17\xa1\x2b\x38\xb5\x86\x37\x4d\x19\xa0\x1f\xdc\x01\x4b\xef\x3d\x5c\x61\xb0\xe4\x08\x0b\x96\x06\x18\x2e\x19\x82\xd5\x6a\xbb\x25\x9d\xbb\x06\x6f\x94\x79\x37\xc6\xc8\xed\x35\x48\xe7\xf3\x88\xdf\x7a\xbf\x06\x00\xfc\x97\xfc\x20\xb3\x06\x00\x00")
+
+func _000013_subscriptionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000013_subscriptionsUpSql,
+		"000013_subscriptions.up.sql",
+	)
+}
+
+func _000013_subscriptionsUpSql() (*asset, error) {
+	bytes, err := _000013_subscriptionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000013_subscriptions.up.sql", size: 1715, mode: os.FileMode(0644), modTime: time.Unix(1792291554, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7b, 0xc8, 0xfe, 0x4a, 0xdf, 0x1, 0xc8, 0x86, 0x79, 0x71, 0xc, 0x72, 0x1c, 0xe7, 0x77, 0x18, 0xea, 0xf, 0x92, 0x16, 0x19, 0xeb, 0xd1, 0x99, 0xe6, 0x5, 0x49, 0xd6, 0xd9, 0xcd, 0x52, 0xc6}}
+	return a, nil
+}
+
+var __000014_user_tokensDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4a\x2d\x4e\x2d\x89\x2f\xc9\xcf\x4e\xcd\x2b\xb6\xc6\xae\xa4\x2c\xb5\x28\x33\x2d\x33\x39\xb1\x24\x33\x3f\x0f\xae\xd2\xd1\x27\xc4\x35\x08\xaa\xb4\xb4\x38\xb5\xa8\x58\x01\xac\xd7\xd9\xdf\x27\xd4\xd7\x0f\x49\x73\x6a\x6e\x62\x66\x4e\x3c\xc4\x88\xd4\x14\x6b\x2e\x2e\x67\x7f\x5f\x5f\xcf\x10\x6b\x2e\xc0\x00\xbe\x6b\x7d\xd2\x96\x00\x00\x00")
+
+func _000014_user_tokensDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000014_user_tokensDownSql,
+		"000014_user_tokens.down.sql",
+	)
+}
+
+func _000014_user_tokensDownSql() (*asset, error) {
+	bytes, err := _000014_user_tokensDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000014_user_tokens.down.sql", size: 150, mode: os.FileMode(0644), modTime: time.Unix(1792291780, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x56, 0xe2, 0xa4, 0xe3, 0xc7, 0x10, 0xb9, 0x23, 0xf7, 0x1a, 0x8f, 0x17, 0x4, 0x3a, 0xb9, 0xec, 0x6a, 0x4b, 0x4d, 0x8e, 0xfe, 0x2c, 0xa8, 0x8c, 0xef, 0x3e, 0xed, 0x9d, 0xa3, 0xc, 0x1b, 0xec}}
+	return a, nil
+}
+
+var __000014_user_tokensUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x93\xcd\x6e\xdb\x3a\x10\x85\xf7\x7a\x8a\x59\xda\xc1\xbd\x09\x50\x20\x2b\xa3\x0b\x5a\x1a\xbb\x44\x25\xca\xa5\xa8\x36\xee\x46\x60\xac\xb1\x4d\xc4\x95\x02\x92\x69\xd3\xb7\x2f\x44\xc9\xf1\x4f\xdc\x76\xd5\xa2\x5a\x09\x9a\x8f\x67\xce\xcc\xa1\x6e\xae\x22\xb8\x82\xc2\x34\x9b\x1d\xfd\xff\xe4\x08\x7c\xfb\x40\x8d\x03\xbf\xd5\x1e\xb4\x25\xa0\x2f\xda\xec\xa8\x06\xdf\xc2\x93\x23\xeb\xba\x97\xaf\x64\xcd\xfa\x3b\xf8\x2d\x19\xdb\x03\xa0\xeb\xda\x92\x73\xa0\x9b\x80\x5a\x72\xe4\x3b\x65\x0d\xeb\xd6\x6e\x5a\xef\xa9\x81\x47\xed\xdc\xb7\xd6\xd6\xd7\x90\x37\xbb\x70\x1c\xb6\xda\x6d\xa1\x5d\x03\xe9\xd5\xb6\x6f\x0d\xc6\x81\xf3\xad\xa5\x1a\x9c\x69\x56\x14\xb0\x97\x8a\xee\x34\xef\x49\x5b\xb2\xb0\xb2\x54\x53\xe3\x8d\xde\x5d\x43\xd9\x5b\xeb\x4c\x5b\xda\x18\xe7\xa9\x13\xb8\xa7\x75\xbb\x1f\xa1\x37\x6d\x56\xda\x9b\xb6\x09\x93\xf5\x1f\xa8\xbe\x8e\xe0\xea\x26\x9a\xe2\x9c\x8b\x49\x14\xb1\x54\xa1\x04\xc5\xa6\x29\x0e\x03\xb3\x24\x81\x38\x4f\xcb\x4c\x00\x9f\x81\xc8\x15\xe0\x1d\x2f\x54\xd1\xeb\x56\x7b\x19\x50\x3c\xc3\x42\xb1\x6c\xa1\x3e\x43\x82\x33\x56\xa6\x0a\x44\x99\xa6\x93\xa8\x5c\x24\x4c\xed\xe5\x0a\x54\x67\x27\xdf\xae\x2c\x69\x4f\xf5\x24\x8a\x62\x89\x1d\xd9\xb7\x3f\xed\x76\xec\xbf\x1a\x52\x1a\x45\x00\x00\xa6\x86\x97\xa7\x40\xc9\x59\x0a\x0b\xc9\x33\x26\x97\xf0\x1e\x97\xff\x05\xa6\xeb\x5d\x0d\x20\x17\x0a\xe7\x28\x83\x78\x67\xb0\x27\x82\xa7\x41\xe5\x23\x93\xf1\x3b\x26\x47\x6f\x6e\x6f\xc7\x67\x58\xe8\x3c\x60\xd3\xa5\x42\x06\xa5\xe0\x1f\x4a\x3c\x57\x7b\x7e\x34\x96\x5c\xc0\x8e\x17\x73\x4a\x0d\x83\xff\x94\x3a\xec\x31\xff\x34\x1a\x47\xe3\xb3\x7c\x2e\xad\xa4\x4f\x4b\x14\x4a\x32\x2e\x14\xac\x1f\xaa\x0b\x54\xd5\xad\x23\xf8\x9c\xe5\x12\xf9\x5c\x74\x8b\x82\xd1\xb0\xa4\x31\x48\x9c\xa1\x44\x11\x63\x31\x84\x36\x32\xf5\x38\xf0\xb9\x80\x04\x53\x54\x08\x31\x2b\x62\x96\xe0\x21\x34\x2e\x12\xbc\xfb\x7d\x68\xd5\xd0\xe5\x19\x72\x71\xa9\x7e\xb0\xf1\xeb\xfb\x10\x7e\xb1\x3f\x71\x11\xfe\xa5\x84\x4f\x86\x7c\x1d\xed\x71\xf9\xaf\x65\xfa\xaa\xe9\x3e\xcc\xe3\xc2\x69\x8a\x79\x96\x71\x35\x89\x7e\x0c\x00\x27\xba\x3e\xaa\x6d\x05\x00\x00")
+
+func _000014_user_tokensUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000014_user_tokensUpSql,
+		"000014_user_tokens.up.sql",
+	)
+}
+
+func _000014_user_tokensUpSql() (*asset, error) {
+	bytes, err := _000014_user_tokensUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000014_user_tokens.up.sql", size: 1389, mode: os.FileMode(0644), modTime: time.Unix(1792291780, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x74, 0x53, 0x4f, 0xa2, 0x0, 0x9b, 0x70, 0xae, 0x21, 0x9, 0x49, 0x12, 0x34, 0x14, 0x65, 0x1d, 0x30, 0x24, 0xbc, 0xe7, 0xb2, 0x6f, 0xf2, 0x42, 0x1, 0xe1, 0x26, 0x73, 0x9c, 0xc0, 0x50, 0xa9}}
+	return a, nil
+}
+
+var __000015_user_disabledDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x43\x00\xbc\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x75\x73\x65\x72\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x64\x69\x73\x61\x62\x6c\x65\x64\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xaa\xee\x85\x18\x43\x00\x00\x00")
+
+func _000015_user_disabledDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000015_user_disabledDownSql,
+		"000015_user_disabled.down.sql",
+	)
+}
+
+func _000015_user_disabledDownSql() (*asset, error) {
+	bytes, err := _000015_user_disabledDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000015_user_disabled.down.sql", size: 67, mode: os.FileMode(0644), modTime: time.Unix(1792292301, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x42, 0x5d, 0xf8, 0x84, 0xa, 0x41, 0x81, 0xf2, 0xb4, 0x5f, 0x6f, 0x4f, 0xc4, 0xe0, 0x3a, 0x66, 0xa7, 0x40, 0xdd, 0x7e, 0x1, 0x71, 0x6c, 0x5f, 0xd8, 0xa8, 0x57, 0x35, 0x7b, 0x5, 0x1a, 0x4}}
+	return a, nil
+}
+
+var __000015_user_disabledUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x34\x8e\xc1\x4e\x84\x30\x14\x45\xf7\xfd\x8a\xbb\x26\xc6\xf9\x00\x56\x9d\xa1\x63\x48\x0a\x18\x29\x89\x71\x57\xe1\x01\x8d\xd0\x9a\xbe\xa2\xf1\xef\x4d\x1d\x67\x7b\x73\x72\xee\x39\x15\x02\x05\xe4\xb4\x3b\xef\x38\x45\x9b\x42\x64\x8c\xd6\x63\x72\x6c\xdf\x37\x42\x5a\x09\x76\x1c\xc3\xe1\x13\x23\xcc\x38\x98\x22\x3f\xe0\x33\xd2\x17\xf9\xe4\xfc\x92\x89\x1d\x73\x0c\x3b\xb6\xb0\x2c\x79\x71\x1e\x21\x66\x6f\xa4\x39\x12\xaf\xff\x94\x8b\xd9\x44\xcc\x48\xe1\x83\x3c\xe3\xdb\xa5\x35\x1c\x09\x13\x6d\xf4\xa7\xb2\xfe\x27\x7f\xdc\xd8\xc9\x26\xfb\x28\x50\x9c\xc4\x59\x3d\xd5\x6d\x29\x84\xd4\x46\xbd\xc0\xc8\xb3\x56\xb7\x0e\xc8\xaa\xc2\xa5\xd3\x43\xd3\xa2\xbe\xa2\xed\x0c\xd4\x6b\xdd\x9b\xfe\x9e\x3f\xc1\xd4\x8d\xea\x8d\x6c\x9e\xcd\x1b\x2a\x75\x95\x83\x36\x68\x07\xad\x4b\x21\x2e\x5d\xd3\xd4\xa6\x14\xbf\x03\x00\x30\xb4\x87\xe4\x04\x01\x00\x00")
+
+func _000015_user_disabledUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000015_user_disabledUpSql,
+		"000015_user_disabled.up.sql",
+	)
+}
+
+func _000015_user_disabledUpSql() (*asset, error) {
+	bytes, err := _000015_user_disabledUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000015_user_disabled.up.sql", size: 260, mode: os.FileMode(0644), modTime: time.Unix(1792292301, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x28, 0xe, 0xb7, 0x9c, 0xc2, 0xe9, 0xe4, 0xbd, 0xc6, 0x54, 0x1b, 0x3e, 0x7b, 0x10, 0x16, 0xb3, 0x2e, 0xb4, 0x59, 0x1a, 0xff, 0xd7, 0x75, 0xa0, 0xa9, 0x90, 0x54, 0x43, 0xfa, 0x76, 0x66, 0x8}}
+	return a, nil
+}
+
+var __000016_sessionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x30\x00\xcf\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x65\x73\x73\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x57\xb5\x85\x66\x30\x00\x00\x00")
+
+func _000016_sessionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000016_sessionsDownSql,
+		"000016_sessions.down.sql",
+	)
+}
+
+func _000016_sessionsDownSql() (*asset, error) {
+	bytes, err := _000016_sessionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000016_sessions.down.sql", size: 48, mode: os.FileMode(0644), modTime: time.Unix(1792292530, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4e, 0xa8, 0x72, 0xe1, 0x2, 0x8e, 0x1b, 0x16, 0x17, 0xf6, 0x1b, 0x72, 0x57, 0x9a, 0x1b, 0xf7, 0xe5, 0x9d, 0x84, 0xe6, 0xc0, 0xdb, 0xf1, 0x94, 0x17, 0x1d, 0xe7, 0x5c, 0x18, 0xc4, 0x6a, 0x3d}}
+	return a, nil
+}
+
+var __000016_sessionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x93\x41\x6f\x9b\x30\x18\x86\xef\xfe\x15\xef\x11\xaa\xb5\xd5\xaa\xad\x97\x9c\x28\x7c\x49\xd1\x12\x88\x8c\xb3\xb6\xbb\x20\x0a\x1f\x89\x97\x06\x2a\xdb\xad\xba\x7f\x3f\x39\x0d\x49\x16\xb5\xd2\x38\xc2\xe3\xe7\xf5\xf7\xda\x5c\x9e\x09\x9c\xa1\x60\x6b\x75\xdf\x59\x38\x53\xd5\x6b\xb8\x15\xc3\x70\x6b\xd8\xae\xe0\xfa\x35\x77\x16\xda\xda\x17\x6e\xe0\x7a\x70\x55\xaf\xd0\xf0\xab\xae\x19\x15\x5e\x2c\x1b\x3c\xf5\x4b\x0b\xdd\xa1\x35\xfd\x06\xb6\x87\x5b\x55\xce\x6b\xed\xa0\xad\xab\x0e\x8f\xde\xf9\xda\xaf\xb9\xc1\x23\xb7\xbd\x61\x1f\xa3\xcd\x69\x10\xbf\x3d\x6b\xc3\x17\x50\x2b\x86\x6e\xd0\xb7\xa8\x06\x0f\xb4\xf5\x56\xbf\xbb\x34\x41\xf0\xdb\xe9\x10\x76\x55\x19\x6f\xfc\xe3\x6d\xa8\xea\x9a\xad\x45\xd5\x35\xa7\xda\xbe\xdd\x02\x3b\xd3\x85\xc0\xd9\xa5\xb8\xa1\x49\x9a\x8d\x84\x88\x25\x45\x8a\xa0\xa2\x9b\x29\x21\x1d\x23\xcb\x15\xe8\x3e\x2d\x54\x71\x98\x20\x10\x00\xfc\x86\xf6\xcf\xcf\x48\xc6\xb7\x91\x0c\xae\xae\x43\xcc\x65\x3a\x8b\xe4\x03\x7e\xd0\xc3\x97\x2d\xe8\x6b\x29\x77\x74\x9a\x29\x9a\x90\xdc\x6a\xb3\xc5\x74\x7a\x44\x54\x4b\xee\xdc\x41\xf5\xfd\xeb\x55\x88\x84\xc6\xd1\x62\x7a\x8c\xd6\x4f\x9a\x3b\x57\xea\xe7\xa3\xd4\xeb\x6f\x1f\x91\x4f\x95\x75\xe5\x8b\x65\x1f\xac\xd2\x19\x15\x2a\x9a\xcd\xd5\xaf\x0f\xc8\xf7\x9a\x2d\x70\x42\xfe\xbb\xc9\xda\x70\xe5\xb8\xf9\x94\x3a\x88\xf3\xbb\x20\x7c\x5f\xb3\xe9\x1b\xdd\x6a\x6e\xfe\x6f\x8d\x08\x47\x42\x44\x53\x45\x72\xd7\xff\xbe\xf1\x28\x49\x10\xe7\x59\xa1\x64\x94\x66\x0a\xed\xba\x1c\x3e\xf9\x09\xcd\x36\x6b\x9c\x4b\x4a\x27\x99\xef\x1d\xc1\xae\xf3\x10\x92\xc6\x24\x29\x8b\xa9\x80\x7f\x67\x11\xe8\x26\xdc\xf2\x79\x86\x84\xa6\xa4\x08\x71\x54\xc4\x51\x42\x87\xd3\x4f\xb3\x84\xee\x3f\x39\xfd\x72\xa7\x7e\x43\x9e\x1d\x5d\x89\x21\x70\x24\xc4\xf9\xf9\xe1\x1f\xda\x17\xe0\xf4\x86\xad\xab\x36\xcf\x43\x86\x92\xe9\xc4\xdf\x04\xcb\xee\x30\xcc\x80\x8b\x1b\xf2\xe3\x60\x31\x4f\x3c\x7b\x94\x24\xc6\xb9\x04\x45\xf1\x2d\x64\x7e\x27\xe8\x9e\xe2\x85\x22\xcc\x65\x1e\x53\xb2\x90\x04\x67\xf4\x72\xc9\xa6\xf4\xda\xc1\x56\xee\xc3\x03\xdf\x70\x9c\xcf\x66\xa9\x1a\x89\xbf\x03\x00\xa0\x3d\x5b\xb2\xef\x03\x00\x00")
+
+func _000016_sessionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000016_sessionsUpSql,
+		"000016_sessions.up.sql",
+	)
+}
+
+func _000016_sessionsUpSql() (*asset, error) {
+	bytes, err := _000016_sessionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000016_sessions.up.sql", size: 1007, mode: os.FileMode(0644), modTime: time.Unix(1792292530, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe5, 0x4f, 0x44, 0x9b, 0x42, 0xe2, 0xc2, 0xf7, 0x37, 0xea, 0x15, 0x6c, 0x31, 0x6c, 0x62, 0x6f, 0x77, 0x56, 0x79, 0x5b, 0xbd, 0x14, 0x31, 0xe9, 0xd4, 0x8e, 0x38, 0x8, 0xe3, 0x2e, 0x43, 0xd1}}
+	return a, nil
+}
+
+var __000017_failed_loginsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x7e\x00\x81\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x75\x73\x65\x72\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6c\x6f\x63\x6b\x65\x64\x5f\x75\x6e\x74\x69\x6c\x3b\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x75\x73\x65\x72\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x66\x61\x69\x6c\x65\x64\x5f\x6c\x6f\x67\x69\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x72\x8f\xf1\xbf\x7e\x00\x00\x00")
+
+func _000017_failed_loginsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000017_failed_loginsDownSql,
+		"000017_failed_logins.down.sql",
+	)
+}
+
+func _000017_failed_loginsDownSql() (*asset, error) {
+	bytes, err := _000017_failed_loginsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000017_failed_logins.down.sql", size: 126, mode: os.FileMode(0644), modTime: time.Unix(1792292891, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x18, 0x92, 0x82, 0x50, 0xf5, 0x55, 0xcc, 0xbe, 0x57, 0xa1, 0x5d, 0x18, 0x61, 0x21, 0x89, 0xc9, 0xc0, 0xef, 0x9d, 0x51, 0x4a, 0x5e, 0x60, 0xc8, 0xe3, 0xd0, 0x43, 0x11, 0x5a, 0x2b, 0xfa, 0xb8}}
+	return a, nil
+}
+
+var __000017_failed_loginsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x8f\xb1\x6e\x2a\x31\x14\x44\x7b\x7f\xc5\xd4\x14\xf0\xfa\xad\x16\x30\xc8\x92\x77\x79\x02\x23\x45\x69\x90\x63\xee\x06\x2b\xc6\x8e\xf6\x5e\x47\xe2\xef\xa3\x05\x92\x3e\xfd\xcc\x99\x33\x8b\x99\xc2\x0c\xab\x52\xb3\x40\x2e\x84\x50\x32\x53\xa8\x12\xbf\x08\x83\x8f\x89\xce\x48\xe5\x3d\x66\x46\x19\x40\x3e\x5c\x50\x99\x46\x70\x81\x5c\xbc\xc0\x87\x30\x55\x19\xc1\x67\xbc\x11\x84\xae\x9f\x65\xf4\x63\x4c\xb7\x89\x9b\x4a\xf8\xa0\x33\x4a\x15\xf8\x41\x68\x84\x94\x82\xab\xcf\xb7\x1f\xb6\x97\xa9\x21\x3c\x87\xbb\x8f\x4f\x1a\x91\x31\x12\x93\xa0\x64\x78\x70\x0d\x81\x98\x87\x9a\x1e\x22\x73\x85\xd9\x42\x2d\xf5\xd6\xf4\x8d\x52\xad\x75\x7a\x0f\xd7\x2e\xad\xbe\x8b\x31\xda\xf5\x1a\xab\x9d\x3d\x76\x3d\xcc\x06\xfd\xce\x41\xbf\x98\x83\x3b\x3c\x17\x4f\xcf\x37\xa6\x77\x7a\xab\xf7\xf7\x40\x7f\xb4\x16\x6b\xbd\x69\x8f\xd6\xe1\x5f\xf3\x17\xe8\xe3\xe0\xa9\x66\x89\x09\xce\x74\xfa\xe0\xda\xee\xbf\x7b\xfd\xc5\x4d\xec\x46\xa9\xd5\xae\xeb\x8c\x6b\xd4\xf7\x00\xb3\x1e\xfb\x37\x6f\x01\x00\x00")
+
+func _000017_failed_loginsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000017_failed_loginsUpSql,
+		"000017_failed_logins.up.sql",
+	)
+}
+
+func _000017_failed_loginsUpSql() (*asset, error) {
+	bytes, err := _000017_failed_loginsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000017_failed_logins.up.sql", size: 367, mode: os.FileMode(0644), modTime: time.Unix(1792292891, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x32, 0x64, 0xb2, 0xc3, 0x6d, 0x3e, 0x58, 0x5e, 0x3f, 0x75, 0x6f, 0x7c, 0xd9, 0x30, 0x3f, 0xfc, 0xc4, 0xed, 0xaa, 0xfd, 0x62, 0x14, 0x7f, 0x3e, 0xb0, 0x7f, 0x84, 0xfb, 0x96, 0x9f, 0xdc, 0xa5}}
+	return a, nil
+}
+
+var __000018_api_keysDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x5a\x00\xa5\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x61\x70\x69\x5f\x6b\x65\x79\x5f\x70\x65\x72\x6d\x69\x73\x73\x69\x6f\x6e\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x61\x70\x69\x5f\x6b\x65\x79\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x6c\x68\x72\x65\x5a\x00\x00\x00")
+
+func _000018_api_keysDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000018_api_keysDownSql,
+		"000018_api_keys.down.sql",
+	)
+}
+
+func _000018_api_keysDownSql() (*asset, error) {
+	bytes, err := _000018_api_keysDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000018_api_keys.down.sql", size: 90, mode: os.FileMode(0644), modTime: time.Unix(1792293017, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x5c, 0x21, 0x97, 0x77, 0x8b, 0x41, 0x4f, 0xc6, 0x0, 0x8a, 0xb, 0xf, 0xfd, 0xf9, 0xd, 0xd0, 0x34, 0xcf, 0xe1, 0xae, 0x7d, 0xa4, 0x6f, 0x4e, 0xa3, 0x58, 0x9a, 0x52, 0x9d, 0x56, 0x7, 0x53}}
+	return a, nil
+}
+
+var __000018_api_keysUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x94\x4f\x6f\xe2\x3c\x10\xc6\xef\xf9\x14\x73\x84\xaa\x7f\xa4\x57\xea\xa9\x27\x93\x0c\xd4\x7a\x21\x61\x8d\xd9\xb6\x7b\x89\xdc\x64\x00\xab\x90\x20\xdb\xdd\x6d\xbf\xfd\xca\x21\x21\x81\x16\x15\xad\x56\xcb\x89\xd8\xbf\x99\x79\xe6\x99\x49\x6e\x2e\x02\xb8\x00\x36\xe5\xf0\x42\xef\x16\x94\x21\x58\x97\xc5\xf2\x6a\xad\x7f\x52\x0e\x5b\x32\xb6\x2c\xd4\x1a\x54\x96\x91\xb5\xe0\xca\x17\x2a\x2c\x2c\x4a\x03\x36\x33\x7a\xeb\x2c\xa8\x22\x07\x5d\x38\x5a\x1a\xe5\x74\x59\xd8\x6b\x48\x8a\xf5\x3b\xb8\x15\xf9\xc4\x2b\x65\x57\x50\x2e\xfc\xa3\x2f\x00\xda\x82\x75\xa5\xa1\x1c\xac\x2e\x32\xea\x9e\x2b\x78\x26\x65\xc8\x40\x66\x28\xa7\xc2\x69\xb5\xbe\x06\x54\xd9\xaa\x01\x96\x46\x15\x8e\x72\x9f\x56\x81\x7d\x7d\xb6\xe4\x9a\xd4\x5b\x32\x1b\x6d\xad\xaf\xef\x8f\xb4\xb3\xf0\x6a\xc9\xc0\xaf\x15\x15\xa0\x9d\x8f\xce\x0c\x29\x47\xf9\x75\x00\x17\x37\xc1\x00\x47\x3c\xbe\x0b\x82\x50\x20\x93\x08\x92\x0d\xc6\x08\x7c\x08\x71\x22\x01\x1f\xf9\x4c\xce\x40\x6d\x75\x5a\x59\xd2\x0b\x00\x00\x74\x0e\xfb\xdf\x0c\x05\x67\x63\x98\x0a\x3e\x61\xe2\x09\xfe\xc7\xa7\xcb\x8a\xf1\x25\xd3\x1a\xe4\xb1\xc4\x11\x8a\x2a\x63\x3c\x1f\x8f\x77\x44\xa1\x36\xd4\x64\xf9\xce\x44\x78\xcf\x44\xef\xbf\xdb\xdb\xfe\x11\x56\xd9\x5c\x63\x83\x27\x89\x0c\xe6\x31\xff\x36\xc7\x23\x8c\xde\xb6\xda\x90\xad\x30\xc9\x27\x38\x93\x6c\x32\x95\x3f\x8e\xa8\xb5\xb2\x2e\x7d\xb5\x94\x1f\x51\x11\x0e\xd9\x7c\xdc\x25\x6b\x8b\x4e\xe6\x6b\x43\x92\x87\x5e\x7f\x17\xb3\x29\x73\xbd\xd0\x94\x9f\x17\x13\xf4\xcf\x32\x3d\xed\xce\x73\xe7\x7f\x73\xa3\xf3\xd3\xee\xb6\x51\x7e\x0a\x9f\x33\xdd\x1e\xff\xac\xcb\xf3\xa3\x3a\x0b\x02\xbd\xb6\x81\xcb\x43\xa1\x3b\x53\xd8\x58\xa2\xa8\x3d\xa9\x51\x0b\x2c\x8a\x20\x4c\xe2\x99\x14\x8c\xc7\x12\x16\x2f\x69\x73\xe5\x07\x6a\x2a\x63\x86\x89\x40\x3e\x8a\xfd\x16\x42\xaf\xde\xc0\x3e\x08\x1c\xa2\xc0\x38\xc4\x59\xf5\x22\x58\xe8\xf9\x42\x9e\x4f\x62\x88\x70\x8c\x12\x21\x64\xb3\x90\x45\xf8\x79\xf1\x83\x11\x9c\xd4\xd1\xa5\x9a\xb3\x8f\xaa\x1a\xf8\x48\x58\x7d\xfc\x4f\xb4\xb5\xff\x3f\xca\x6b\xef\x8e\x15\xb6\x37\x5f\x88\xac\x57\x9a\xc7\x11\x3e\x9e\xf8\x8e\xa4\xf5\x6c\xde\x7c\x82\xe6\xb0\x9d\xd8\x5d\x10\x5c\x5d\xb5\xdf\xe1\xfd\xc2\x39\xbd\x21\xeb\xd4\x66\xbb\x7f\x6d\x04\x1f\xf9\xb5\xb6\xe4\x9a\x4e\x6d\xda\xe0\xc1\x00\xfd\x3e\xc0\x7c\x1a\x79\xb6\x53\x29\x18\x26\x02\x90\x85\xf7\x20\x92\x87\x00\x1f\x31\x9c\x4b\x84\xa9\x48\x42\x8c\xe6\x02\xc1\x19\xbd\x5c\x92\x49\x7d\xda\x26\x5b\xba\x2f\xde\x3b\xd4\x77\x60\x4c\x43\x9f\x25\xf5\x60\x28\x5f\xaa\xee\xd2\x7f\xa1\x81\x30\x99\x4c\xb8\xbc\x0b\x7e\x0f\x00\xfd\x51\xa0\x42\xf4\x06\x00\x00")
+
+func _000018_api_keysUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000018_api_keysUpSql,
+		"000018_api_keys.up.sql",
+	)
+}
+
+func _000018_api_keysUpSql() (*asset, error) {
+	bytes, err := _000018_api_keysUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000018_api_keys.up.sql", size: 1780, mode: os.FileMode(0644), modTime: time.Unix(1792293017, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8a, 0x84, 0x1a, 0xfd, 0x27, 0xb8, 0x6c, 0x3e, 0xf7, 0xf1, 0x15, 0xdd, 0x99, 0xa6, 0xe7, 0xf4, 0xf5, 0xaf, 0xa4, 0xa7, 0x15, 0xf6, 0xe1, 0x19, 0x8e, 0x5, 0xa0, 0xaf, 0x38, 0x44, 0x1c, 0x78}}
+	return a, nil
+}
+
+var __000019_oauthDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x57\x00\xa8\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6f\x61\x75\x74\x68\x5f\x63\x6f\x64\x65\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6f\x61\x75\x74\x68\x5f\x63\x6c\x69\x65\x6e\x74\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xbf\xd9\x27\x78\x57\x00\x00\x00")
+
+func _000019_oauthDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000019_oauthDownSql,
+		"000019_oauth.down.sql",
+	)
+}
+
+func _000019_oauthDownSql() (*asset, error) {
+	bytes, err := _000019_oauthDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000019_oauth.down.sql", size: 87, mode: os.FileMode(0644), modTime: time.Unix(1792293240, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xae, 0x4b, 0x53, 0x92, 0x92, 0xed, 0x7d, 0xb7, 0xc3, 0x31, 0x33, 0x39, 0x77, 0xa9, 0x9e, 0x9d, 0xfe, 0x33, 0xb6, 0x27, 0xf6, 0x7f, 0x12, 0xa2, 0x77, 0x95, 0xe, 0x30, 0x66, 0x67, 0xe2, 0xbe}}
+	return a, nil
+}
+
+var __000019_oauthUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x55\x5d\x6f\xe2\x38\x14\x7d\xcf\xaf\xb8\x6f\x25\xd5\xb6\xd5\x56\xea\x6a\x25\x9e\x4c\xb8\xd0\xa8\x90\xb0\xc6\x6c\xdb\x5d\x8d\x22\x37\x31\xc4\x9a\x10\x23\xdb\xf4\x63\x7e\xfd\xc8\x49\x48\x03\x74\xd4\x0f\x0d\x4f\x40\xce\x39\xf7\xf8\xf8\x5c\xe5\xe2\xd4\x83\x53\x88\xc9\xd6\xe6\x97\x90\x16\x52\x94\xd6\x00\xd7\x02\x6c\x2e\x75\x76\xb6\xe1\xda\xbe\x00\xdf\x6c\x0a\x99\x72\x2b\x55\x69\xa0\x67\xb6\x69\x0e\xdc\x80\xcd\x05\x3c\x68\xf5\x64\x84\x06\xf1\x6c\x45\x69\xa4\x2a\x7d\xb0\x39\xb7\x4e\xc1\xe9\x6a\xb1\x92\xc6\x0a\x2d\x32\x78\x78\x01\x5e\x02\xcf\xd6\xb2\x94\xc6\x6a\x6e\x95\x06\xab\x80\x6f\x6d\x2e\x4a\xeb\xd4\x05\x6c\x8d\xd0\x06\x9e\xa4\xcd\x2b\x71\xf7\x4c\x69\xf9\xa3\x1a\x0c\xa9\xca\x2a\xcd\x65\xa1\x9e\xce\x61\xb6\x7d\x28\x64\xda\x3a\xce\xf9\xa3\x80\x52\x81\x11\xa9\x16\x16\x78\x99\xc1\x7a\x6b\xac\x53\x84\xd9\x4d\x80\x7d\x50\x65\xf1\x52\xa9\xe6\xdc\xe4\xa0\x96\xd5\xf7\x06\xae\x96\x4e\x98\x43\xaa\xca\xa5\xcc\x9c\x1d\x5e\x34\xd2\x20\x0d\x18\xab\xb4\xc8\xce\x81\x1c\xf9\xa9\xa3\x32\xb2\x5c\x15\x95\xfb\x6a\xb0\xc9\x95\xb6\x50\xc8\x47\x91\xf5\x9d\xee\x9b\x93\x1d\xbb\xa3\xed\xc1\xe9\x85\x37\xc0\x71\x18\xf5\x3d\x2f\xa0\x48\x18\x02\x23\x83\x09\x42\x38\x82\x28\x66\x80\x77\xe1\x9c\xcd\x41\xb9\x4c\x92\xdd\xa9\x7b\x1e\x00\x80\xcc\xa0\xf3\x99\x23\x0d\xc9\x04\x66\x34\x9c\x12\x7a\x0f\x37\x78\xff\x47\x85\xaa\x39\x49\x03\xfe\x97\xd0\xe0\x9a\xd0\xde\xe5\x5f\x3e\x2c\xa2\xf0\x9f\x05\x56\x53\xa2\xc5\x64\x52\xc3\x4b\xbe\x16\x3b\xc9\x0e\xfc\xea\xca\x3f\x00\x36\x11\x36\xc0\xc1\x3d\x43\x02\x43\x1c\x91\xc5\xa4\x8b\xd2\x22\x93\x5a\xa4\x36\xd9\x6a\x69\x80\xe1\x1d\xfb\xff\xdb\x81\x50\xaa\x05\xb7\x62\x77\x16\x16\x4e\x71\xce\xc8\x74\xc6\xfe\x6b\x71\xaf\xba\xf1\x6d\xcf\xaf\x85\xd7\x2a\x93\x4b\x29\xb2\x8f\xb2\x3c\xff\x43\x01\x57\x77\x5b\xc7\x5b\xdd\x54\xe3\xaa\x73\xc6\x77\x02\x06\x80\x30\x62\x38\x46\xda\x1a\xa9\x0d\xbb\x8e\x77\x40\xbf\x82\x75\x03\x73\xbf\xab\xcc\x0e\x30\x26\x55\x9b\x3d\x6b\x7b\x98\xf6\xdc\x27\x27\xcd\x95\xaa\x32\xfd\x04\x3c\xcd\x79\x51\x88\x72\x25\x0e\x4b\xf0\xe7\xe5\xdf\x87\x25\x68\xb1\xc9\x5a\xd8\x5c\x65\x2d\xf6\x08\x29\x9e\x37\x52\x0b\xb3\xd3\x7c\xfb\xca\xde\x2a\xc4\x97\x3b\xf1\x21\x62\x55\x0b\x32\x61\x48\x9b\x56\x74\x7b\x40\x86\x43\x08\xe2\x68\xce\x28\x09\x23\x06\xcb\xef\x49\xe7\x69\xb3\x8c\xd5\xe0\x51\x4c\x31\x1c\x47\x6e\xe9\xa0\xd7\xf6\xc1\x07\x8a\x23\xa4\x18\x05\x78\xb4\xc0\x32\xf3\x2b\x66\x1c\xc1\x10\x27\xc8\x10\x02\x32\x0f\xc8\x10\xbf\x6c\xc7\xf5\xeb\xd8\x4c\xd3\xba\x3d\x2b\xee\xbf\x77\x2c\x9c\x9d\xd5\xaf\x86\xa6\xdc\xe6\x35\x5a\x2b\xd7\xc2\x58\xbe\xde\xb4\xcb\x44\xc3\xb1\xab\xb1\x11\x76\xe7\xa7\xe6\x24\x3b\x8e\x37\x40\x17\x10\x2c\x66\x43\x47\x88\xa3\xfd\x30\xbc\x51\x4c\x01\x49\x70\x0d\x34\xbe\xf5\xf0\x0e\x83\x05\x43\x98\xd1\x38\xc0\xe1\x82\x22\x58\x2d\x57\x2b\xa1\x13\x37\x60\x27\x99\xb4\x36\x7a\xfe\x9e\x5d\x17\xc5\xe7\xcc\x3a\xc6\xfb\x56\x1d\xea\x37\x18\x0d\xe2\xe9\x34\x64\x7d\xef\xe7\x00\x3e\x49\x6f\xaf\x7e\x07\x00\x00")
+
+func _000019_oauthUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000019_oauthUpSql,
+		"000019_oauth.up.sql",
+	)
+}
+
+func _000019_oauthUpSql() (*asset, error) {
+	bytes, err := _000019_oauthUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000019_oauth.up.sql", size: 1918, mode: os.FileMode(0644), modTime: time.Unix(1792293240, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x92, 0xbb, 0x61, 0x19, 0xc1, 0xc2, 0x47, 0x5, 0x43, 0xc0, 0x71, 0xc7, 0x34, 0x59, 0xaa, 0x50, 0xb6, 0x3e, 0x48, 0xee, 0x56, 0xa1, 0x9, 0x3c, 0x66, 0xa9, 0x32, 0x48, 0x17, 0x91, 0x44, 0x43}}
+	return a, nil
+}
+
+var __000020_user_identitiesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x37\x00\xc8\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x75\x73\x65\x72\x5f\x69\x64\x65\x6e\x74\x69\x74\x69\x65\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x0e\xbe\xc1\xbf\x37\x00\x00\x00")
+
+func _000020_user_identitiesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000020_user_identitiesDownSql,
+		"000020_user_identities.down.sql",
+	)
+}
+
+func _000020_user_identitiesDownSql() (*asset, error) {
+	bytes, err := _000020_user_identitiesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000020_user_identities.down.sql", size: 55, mode: os.FileMode(0644), modTime: time.Unix(1792294037, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x25, 0xdc, 0x3e, 0xe9, 0xed, 0xcc, 0xdc, 0x7f, 0x56, 0x20, 0xaf, 0xeb, 0xe1, 0x19, 0x34, 0xaf, 0xba, 0xfa, 0xf6, 0xbe, 0x5f, 0x56, 0xa, 0xbc, 0x17, 0xfb, 0xc5, 0x8d, 0xaa, 0x13, 0xbd, 0x43}}
+	return a, nil
+}
+
+var __000020_user_identitiesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x92\xcf\x72\xda\x3c\x14\xc5\xf7\x7a\x8a\xb3\x84\x4c\xfe\xcc\xe4\x9b\xac\x58\x29\xf6\x85\x68\x3e\xb0\x19\x21\x9a\xa4\x1b\xc6\xb1\x05\xa8\x01\x99\x5a\x72\xda\xbc\x7d\x47\xc6\x8e\x29\x99\x69\xa7\x3b\x5b\x3a\xf7\x77\x74\xee\xbd\x37\x17\x0c\x17\x10\x85\xb6\xde\x78\xa3\x1d\x76\xc6\xbe\xc2\x6f\x35\xb2\x3c\x2f\x6b\xeb\x1d\xca\x35\x6a\xa7\x2b\x07\x5f\x36\x17\xae\x7e\xf9\xa6\x73\x1f\xce\x33\x8b\xfa\xe0\x7c\xa5\xb3\x3d\xd2\x83\xb6\x22\x46\x54\x5a\xab\x73\x1f\xa8\xe6\x48\x7d\xc7\xa1\x2a\xdf\x4c\xa1\x2b\xf8\x6d\xe6\x1b\x46\x00\x62\x57\x6e\x1c\x8c\xc5\x0f\xe3\xb7\xd7\x50\x27\x68\xe3\x50\x5b\xf3\xbd\xd6\x9d\x67\x47\x08\x58\x57\xf6\x08\xe3\xb0\x2e\x6b\x5b\xe0\xe5\x3d\x1c\x9a\xaa\x37\xd5\x6f\xda\xc2\xac\xdb\x63\xbd\xcf\xcc\x0e\x59\x51\x54\xda\x39\xe4\xdb\xcc\x6e\xb4\xbb\x66\xb8\xb8\x61\xf7\x34\x11\xc9\x88\xb1\x48\x12\x57\x04\xc5\xef\xa7\x04\x31\x46\x92\x2a\xd0\x93\x58\xa8\x45\x93\x7f\xd5\x92\x43\x93\x06\x0c\x40\x1f\x0b\xc0\x17\x2e\xa3\x07\x2e\x07\xff\xdd\x0e\x9b\xc2\x64\x39\x9d\x5e\x36\xaa\x2e\xd3\xa9\xea\xf6\xee\xee\x5c\xd6\x5a\x84\x4f\x88\x44\xd1\x84\xe4\x99\xe2\x18\x01\x7f\x03\xed\x32\xe7\x57\xbb\x72\x63\x2c\xa0\xc4\x8c\x16\x8a\xcf\xe6\xea\x2b\x62\x1a\xf3\xe5\xf4\x54\x99\x57\x3a\xf3\xba\x08\x9f\xbf\x29\x3b\x5e\x5f\x92\x3e\x0e\x86\xc7\x9a\x7d\x59\x98\xb5\xd1\xc5\xbf\xd4\xcc\xa5\x98\x71\xf9\x8c\xff\xe9\x19\x83\xae\x69\x97\xdd\xb0\x87\x6c\x38\x62\x8c\x4f\x15\xc9\xb6\xf7\xe7\xdd\xe6\x71\x8c\x28\x4d\x16\x4a\x72\x91\x28\xac\x5f\x57\x67\x8a\xe6\xbf\x79\xde\x38\x95\x24\x26\xc9\xd1\xaa\x55\x0d\x21\x69\x4c\x92\x92\x88\x16\xed\x26\x0f\x4c\x31\x6c\xf4\x69\x82\x98\xa6\xa4\x08\x11\x5f\x44\x3c\xa6\x7e\x0f\x44\x12\xd3\xd3\x9f\xf7\xa0\x7b\xc7\x4f\xa4\xc9\xf9\x5d\x6f\x3f\x62\xec\xea\x0a\xcb\xb0\xf1\x27\xd7\x1f\x8d\xf4\x66\xaf\x9d\xcf\xf6\x87\x8f\x05\x94\x62\x12\xa6\xef\xb4\xff\x14\xb4\xab\x62\xf7\x14\xa2\x62\x39\x8f\x43\xc9\x67\x7b\x36\x4e\x25\x88\x47\x0f\x90\xe9\x23\xa3\x27\x8a\x96\x8a\x30\x97\x69\x44\xf1\x52\x12\x7c\x65\x36\x1b\x5d\xad\x82\x49\x07\x5d\x7d\x3c\x65\x10\x46\x12\xa5\xb3\x99\x50\x23\xf6\x6b\x00\x33\xda\x83\x42\x23\x04\x00\x00")
+
+func _000020_user_identitiesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000020_user_identitiesUpSql,
+		"000020_user_identities.up.sql",
+	)
+}
+
+func _000020_user_identitiesUpSql() (*asset, error) {
+	bytes, err := _000020_user_identitiesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000020_user_identities.up.sql", size: 1059, mode: os.FileMode(0644), modTime: time.Unix(1792294037, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2, 0xb2, 0xdd, 0xad, 0x63, 0xc8, 0xac, 0x81, 0x6b, 0xb2, 0x64, 0xfe, 0xb0, 0xe8, 0x5d, 0xfe, 0xbb, 0x73, 0xbc, 0x59, 0x61, 0xc6, 0x66, 0x36, 0x5e, 0xf8, 0x9d, 0xea, 0x23, 0xfb, 0xbf, 0xcd}}
+	return a, nil
+}
+
+var __000021_mfaDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x55\x00\xaa\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x63\x6f\x76\x65\x72\x79\x5f\x63\x6f\x64\x65\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x75\x73\x65\x72\x5f\x6d\x66\x61\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x91\xd7\x6c\x91\x55\x00\x00\x00")
+
+func _000021_mfaDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000021_mfaDownSql,
+		"000021_mfa.down.sql",
+	)
+}
+
+func _000021_mfaDownSql() (*asset, error) {
+	bytes, err := _000021_mfaDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000021_mfa.down.sql", size: 85, mode: os.FileMode(0644), modTime: time.Unix(1792294310, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x27, 0x6b, 0x44, 0x6c, 0x40, 0xd, 0x37, 0x33, 0x82, 0xa0, 0x25, 0xfa, 0xd9, 0x45, 0x50, 0x91, 0x40, 0x57, 0xf3, 0x37, 0x58, 0xba, 0xb6, 0xaf, 0xe7, 0x2, 0x2e, 0x3a, 0xf2, 0xfd, 0x1b, 0xd7}}
+	return a, nil
+}
+
+var __000021_mfaUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x54\xc1\x6e\xe2\x3a\x14\xdd\xe7\x2b\xce\x12\xaa\x47\xfb\x16\x4f\x6f\xc3\x2a\x24\x86\x46\x0f\x12\x64\xcc\x6b\x3b\x1b\xe4\x26\x17\x12\x4d\x88\x91\x6d\x86\xe9\xdf\x8f\xec\x24\xb4\x05\x4d\x55\x8d\xca\xea\x5a\x9c\x7b\xee\x39\xe7\x3a\xbe\xbb\x09\x70\x83\xb5\x21\x6d\x90\xcb\x06\xd4\xc8\xe7\x9a\xb0\x3f\xd6\xb6\x1a\x6d\x65\x6e\x95\x86\x3c\xda\x92\x1a\x5b\xe5\xd2\x56\xaa\xc1\xa9\xb2\x25\x24\x6c\xb5\xa7\xd1\xb3\x34\x54\x40\x35\x34\x72\x47\x1c\xa4\x31\x27\xa5\x0b\xc7\x39\x10\x99\x58\x0e\xdf\x36\x3b\xaa\xc3\xe1\x16\xa2\x24\x18\xca\x35\x59\x54\x06\xb9\x26\x69\xa9\xc0\xa9\xa4\x06\xb6\x24\x1c\x0d\x69\x50\xa3\x55\x5d\x1b\xc8\xa6\xc0\x62\x1a\x3a\xa0\x6a\xea\x17\x47\xdc\x4a\x74\x53\x73\x7a\x6d\x28\xa5\x41\xae\x9a\x6d\xa5\xf7\x54\x40\x22\x57\x05\x61\xab\xd5\xde\x43\xde\xa9\x68\x15\xd4\xd2\x58\x6f\x02\xc6\xd2\xc1\x11\xdb\x52\x5a\x9c\xa4\x71\x84\x05\xac\x42\xad\x76\x55\xe3\x46\x1b\xab\x34\x15\x30\xaa\xc5\x38\x6e\x1f\x57\xa3\x2c\x9e\x09\x9a\x0e\xb5\x7c\xa1\xe2\x16\x9c\x72\xf5\x83\xf4\x8b\x1f\x6f\x20\x35\x39\x62\x53\x35\xbb\xda\x1b\x1b\x7b\x17\x5e\x52\x29\x4d\x09\xb5\x05\xc9\xbc\xf4\xf0\xd7\x41\xb7\x01\x6e\xee\x82\x09\x9b\x25\xe9\x38\x08\x22\xce\x42\xc1\x20\xc2\xc9\x9c\x21\x99\x22\xcd\x04\xd8\x63\xb2\x12\x2b\xc7\xa8\x37\xfb\xad\xc4\x20\x00\xd0\x1e\xab\xc2\x95\x40\x92\x0a\x36\x63\x1c\x4b\x9e\x2c\x42\xfe\x84\xff\xd8\xd3\x5f\x1e\xd5\x45\xef\x4a\xe0\xff\x90\x47\xf7\x21\x1f\xfc\xfb\xcf\xd0\x13\xa7\xeb\xf9\xbc\x85\xf5\x31\xbb\x1a\x22\x59\xb0\x95\x08\x17\x4b\xf1\x0d\x31\x9b\x86\xeb\xf9\x5b\xa8\x8b\x72\x93\xab\x63\x63\x49\x63\x92\xcc\x92\x54\x9c\xc9\xce\xf0\xbf\x5b\x6c\xbf\xee\x2b\xda\xab\x86\x34\x7b\x18\x0c\xdb\xa6\xbd\x2a\xaa\x6d\x45\xc5\x27\x9b\x82\xe1\xc7\xb1\xe9\x6e\x4b\x9b\x76\x4b\x6d\x78\xae\x46\xf7\x9b\x3c\x09\x16\x5e\x27\xf7\x36\xdf\x3e\xde\x5e\xc1\xb5\xbb\x3f\x31\xf7\x49\x6f\xe1\x5c\x30\xde\x59\x3b\xdf\x81\x30\x8e\x11\x65\xe9\x4a\xf0\xd0\x2d\x60\xfb\x7d\xd3\xff\xe5\x0b\xaf\x6f\x9a\x71\x96\xcc\x52\x67\x09\x83\xce\xce\x10\x9c\x4d\x19\x67\x69\xc4\xda\x1b\x65\x30\xa8\x8a\xa1\xc7\x67\x29\x62\x36\x67\x82\x21\x0a\x57\x51\x18\xb3\x8b\xe1\x17\x49\x5e\x4b\x78\x0f\xf8\x5a\x21\xdd\x86\x93\x34\x66\x8f\x1f\x6e\x78\xd3\x0d\xf8\x89\x2c\xbd\x5a\x7e\x3f\x7c\x1c\x04\xa3\x91\x7f\x09\xfd\x83\x73\xde\x8a\x7b\x22\x8c\x95\xfb\xc3\xf9\x46\xf1\x64\xe6\x36\x6f\xc8\xbe\x26\xdc\xc3\x83\x09\x73\xd6\xb0\x5e\xc6\x0e\x9b\xa5\xe7\x6f\x34\x98\x66\x1c\x2c\x8c\xee\xc1\xb3\x87\x80\x3d\xb2\x68\x2d\x18\x96\x3c\x8b\x58\xbc\xe6\x0c\x56\x57\xbb\x1d\xe9\x8d\xa3\xed\xd9\x36\xe7\xe1\x83\x4e\xdf\xc5\x03\xd3\x03\x3f\x56\x79\x91\xc6\xef\xb5\xbe\x07\x7e\x81\xe2\x28\x5b\x2c\x12\x31\x0e\x7e\x0d\x00\x08\x20\xaa\x1f\x67\x06\x00\x00")
+
+func _000021_mfaUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000021_mfaUpSql,
+		"000021_mfa.up.sql",
+	)
+}
+
+func _000021_mfaUpSql() (*asset, error) {
+	bytes, err := _000021_mfaUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000021_mfa.up.sql", size: 1639, mode: os.FileMode(0644), modTime: time.Unix(1792294310, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb5, 0xf3, 0xa6, 0xea, 0xce, 0xa4, 0xf7, 0xaf, 0x2e, 0xd8, 0x1c, 0x21, 0x34, 0x8b, 0xa1, 0x5b, 0xdf, 0xd0, 0x8e, 0x5e, 0x1, 0x78, 0xf5, 0xd7, 0xf5, 0x63, 0x9f, 0x32, 0x4a, 0xc9, 0xed, 0x1b}}
+	return a, nil
+}
+
+var __000022_oauth_sessionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4e\x2d\x2e\xce\xcc\xcf\x2b\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x0b\x0e\x09\x72\xf4\xf4\x0b\x51\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xcb\x8e\x87\xa9\x8a\xcf\x4f\x2c\x2d\xc9\x88\x4f\xce\xc9\x4c\xcd\x2b\xb1\xc6\x6b\x90\x4f\xa8\xaf\x1f\x92\x21\x10\x2d\xf1\x99\x29\xd6\x5c\x5c\xce\xfe\xbe\xbe\x9e\x21\xd6\x5c\x80\x01\x00\x57\x0f\x22\x59\x90\x00\x00\x00")
+
+func _000022_oauth_sessionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000022_oauth_sessionsDownSql,
+		"000022_oauth_sessions.down.sql",
+	)
+}
+
+func _000022_oauth_sessionsDownSql() (*asset, error) {
+	bytes, err := _000022_oauth_sessionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000022_oauth_sessions.down.sql", size: 144, mode: os.FileMode(0644), modTime: time.Unix(1792296106, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x65, 0xfe, 0x8c, 0x4a, 0xe4, 0x65, 0xb9, 0x51, 0x33, 0xa7, 0x5f, 0x44, 0x37, 0xaa, 0x99, 0xf7, 0xfd, 0x5b, 0xdf, 0xb0, 0x23, 0x93, 0x2a, 0xf, 0x21, 0x2b, 0xd9, 0x33, 0xb, 0xf6, 0xe0, 0xc8}}
+	return a, nil
+}
+
+var __000022_oauth_sessionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\xd0\x31\x6f\xfa\x30\x10\x05\xf0\xdd\x9f\xe2\x8d\xc0\x00\xd2\x7f\xf8\x2f\x4c\x26\xb9\xd0\xa8\xc1\x91\x1c\x53\xb5\x53\x64\xc8\xa1\x44\xd0\x58\xb2\x4d\x2b\xfa\xe9\xab\x50\x42\x99\xba\xde\xd9\xbf\xf7\x74\x8b\x99\xc0\x0c\x15\x87\xd0\xb9\x3e\x60\xef\xd9\x46\x6e\xf0\xd9\x72\x0f\x8b\x73\x60\x0f\x7b\x8e\xad\xf3\xdd\x17\x07\xd8\x1e\xa5\x3c\xc7\x16\xfb\x53\xc7\x7d\x84\xe7\xbd\xf3\x0d\x62\xcb\xe3\x24\x4f\x11\x1c\x62\x6b\xe3\xe0\x76\x31\xc0\xf3\xc1\x73\x68\x11\xdd\x91\x87\x04\xdb\xc3\xf5\xa7\x0b\x76\x3c\xf0\x0d\x9c\x87\xe7\x0f\x77\xe4\x06\xbb\xcb\x95\x0a\xf6\x7d\xf4\xe6\x30\xc3\x60\xac\xe7\x0e\xb0\x83\x7b\x0b\xb3\x9e\xd1\xf0\x89\xef\x8d\x1f\x8a\x74\x61\x5c\xcd\x05\x66\x0b\xb1\xa2\x75\xae\x96\x42\xc8\xc2\x90\x86\x91\xab\x82\x7e\x5d\x99\xa6\x48\xca\x62\xbb\x51\xc8\x33\xa8\xd2\x80\x5e\xf3\xca\x54\x37\xac\xee\x1a\xbc\x48\x9d\x3c\x49\x3d\xf9\xf7\x7f\x8a\x94\x32\xb9\x2d\x0c\xd4\xb6\x28\xfe\x26\x55\x65\xb4\xcc\x95\xc1\xe1\x58\x8f\xab\xda\x0d\x17\xad\x7f\x68\x01\x00\x59\xa9\x29\x5f\x2b\x3c\xd3\x1b\x26\xf7\xc8\x29\x34\x65\xa4\x49\x25\x54\xe1\xf1\x4f\x78\x7c\x74\x05\x4a\x85\x94\x0a\x32\x84\x44\x56\x89\x4c\x69\x29\x44\x52\x6e\x36\xb9\x59\x8a\xef\x01\x00\x18\xb5\x6e\xed\xe2\x01\x00\x00")
+
+func _000022_oauth_sessionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000022_oauth_sessionsUpSql,
+		"000022_oauth_sessions.up.sql",
+	)
+}
+
+func _000022_oauth_sessionsUpSql() (*asset, error) {
+	bytes, err := _000022_oauth_sessionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000022_oauth_sessions.up.sql", size: 482, mode: os.FileMode(0644), modTime: time.Unix(1792296120, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6e, 0x9, 0x67, 0xb8, 0xc7, 0xd7, 0xd4, 0x36, 0x9b, 0x55, 0x82, 0x38, 0x77, 0xf, 0x3a, 0xb7, 0x86, 0x65, 0x6, 0x8, 0x3c, 0x38, 0xfc, 0x64, 0x68, 0x1b, 0xa3, 0x6f, 0xac, 0x65, 0x9a, 0x46}}
+	return a, nil
+}
+
+var __000023_subscription_succeededDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x4c\x00\xb3\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x73\x75\x62\x73\x63\x72\x69\x70\x74\x69\x6f\x6e\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x75\x63\x63\x65\x65\x64\x65\x64\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xbd\x5d\x69\x37\x4c\x00\x00\x00")
+
+func _000023_subscription_succeededDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000023_subscription_succeededDownSql,
+		"000023_subscription_succeeded.down.sql",
+	)
+}
+
+func _000023_subscription_succeededDownSql() (*asset, error) {
+	bytes, err := _000023_subscription_succeededDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000023_subscription_succeeded.down.sql", size: 76, mode: os.FileMode(0644), modTime: time.Unix(1792297436, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x42, 0xb5, 0x92, 0x70, 0x9c, 0x9a, 0x6b, 0x12, 0x77, 0x5c, 0xf7, 0x4b, 0xf1, 0x18, 0x39, 0x38, 0x87, 0xa0, 0xf4, 0xad, 0xb9, 0x94, 0x2b, 0xc8, 0x29, 0x4, 0xa, 0xa2, 0x47, 0x7d, 0xb3, 0x68}}
+	return a, nil
+}
+
+var __000023_subscription_succeededUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x90\xcd\x6e\xdb\x3a\x10\x85\xf7\x7c\x8a\xb3\x4c\x8c\x1b\xe5\x76\x6d\x78\xa1\x58\x74\x2b\x40\x3f\x81\x45\xa3\x45\x37\x05\x2d\x8e\x22\x02\x34\xe5\x6a\xa8\x1a\x7e\xfb\x82\x72\x0c\xc7\x41\x77\xd2\x0c\xf1\xcd\x77\xce\xf3\x42\x60\x81\x66\xda\x73\x3b\xda\x63\xb0\x83\x67\x8c\xd4\x0e\xa3\xc1\xa9\x27\x8f\xd0\x93\x1d\xd1\x11\x19\x9c\x34\xc3\x69\x0e\xe8\x28\xb4\x3d\x19\xf0\xd4\xb6\xc4\xdc\x4d\xce\x9d\xff\x03\x5b\xdf\x52\x7c\x8f\xe3\xe0\x5c\xa4\x06\x7b\x20\x58\x86\x76\x3c\x60\x3a\x1a\x1d\xe8\x46\xbd\x30\x5b\xed\xfd\x10\xb0\xa7\x2b\x34\x81\xea\x09\x9d\x1d\x39\x7c\xe0\xcf\x48\x0c\xde\x9d\x23\xf7\xe2\xc7\xf3\x2d\x1b\xe8\x10\xbf\x74\x80\x1e\x09\xda\x8d\xa4\xcd\x19\xf6\xc3\x8d\x93\x0d\xfd\x30\x05\xfc\x9e\x68\x22\xeb\xdf\xe2\xe6\x00\x1d\x73\x6a\x63\xfd\x1b\x27\x02\x8b\x67\xf1\x22\xbf\xe6\xd5\x52\x88\xb4\x50\x72\x0b\x95\xbe\x14\x12\x7c\xd7\x4b\x9a\x65\x58\xd7\xc5\xae\xac\x90\x6f\x50\xd5\x0a\xf2\x47\xde\xa8\xe6\x22\x4a\x86\x0c\x54\x5e\xca\x46\xa5\xe5\xab\xfa\x89\x4c\x6e\xd2\x5d\xa1\x50\xed\x8a\x62\x29\xc4\xd3\xd3\xa7\x9a\x67\xe9\x5e\xff\xb9\x59\x33\x91\x7f\x4f\x34\xcf\xf7\xf1\xff\x5f\x6d\x8b\xdd\x6b\x96\xaa\xcf\x7e\x8c\x46\xaa\x9b\xcc\x8a\x93\x58\x1b\x19\x01\x00\xdf\xbf\xc9\xad\xc4\x75\x84\xbc\x99\x03\x44\x37\xa4\x55\x86\x07\x4e\x3a\x6d\xdd\x34\x12\xaf\xfe\x47\xbd\xbd\x46\x7b\x68\x64\x21\xd7\x0a\x5f\xb0\xd9\xd6\xe5\xdd\xc1\x5f\x17\x53\xfb\x8e\xb6\xc9\xfd\xd2\xac\x38\xb1\xe6\xf1\x71\x29\xc4\xba\x2e\xcb\x5c\x2d\xc5\xdf\x01\x00\x07\x6d\x4c\x57\x6d\x02\x00\x00")
+
+func _000023_subscription_succeededUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000023_subscription_succeededUpSql,
+		"000023_subscription_succeeded.up.sql",
+	)
+}
+
+func _000023_subscription_succeededUpSql() (*asset, error) {
+	bytes, err := _000023_subscription_succeededUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000023_subscription_succeeded.up.sql", size: 621, mode: os.FileMode(0644), modTime: time.Unix(1792297436, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x11, 0xea, 0x2d, 0x57, 0x63, 0xa6, 0x8a, 0x4f, 0xd8, 0x4c, 0xbd, 0xc5, 0xb, 0x93, 0xce, 0x2b, 0x28, 0xa0, 0x84, 0xf2, 0xb9, 0x3f, 0x5f, 0x27, 0xd9, 0x29, 0xfe, 0xe6, 0x59, 0x61, 0x1b, 0xc}}
+	return a, nil
+}
+
+var __000024_recovery_code_keysDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x91\xc1\x6e\xb3\x30\x10\x84\xef\x7e\x8a\x3d\x82\xf4\xbf\x01\x27\x63\x2f\xfc\x56\xc1\x46\xcb\xa2\x24\xbd\xa0\x28\x38\x11\xaa\xa2\x54\x40\xab\xf6\xed\x2b\x13\xd2\xb4\x49\x55\x95\x13\xb2\xbe\x99\xf1\x8c\x53\xcc\x8d\x4d\x84\xd0\xe4\x2a\x60\x99\x16\x08\x26\x03\x5c\x9b\x9a\x6b\x18\xfc\xee\xf4\xea\x87\xf7\x76\x77\xea\xfc\x98\x08\xa1\x08\x25\xe3\x95\xb3\x8e\x7f\x66\x21\x12\x00\x00\x41\x07\xcb\x97\x6e\x18\x25\x54\x64\x4a\x49\x1b\x78\xc0\xcd\xbf\x19\x79\x19\xfd\xd0\xf6\x5d\xf8\x05\x63\x19\x73\xa4\xd9\xd6\x36\x45\x71\x26\x76\x83\xdf\x4e\xfe\x4c\xb0\x29\xb1\x66\x59\x56\xfc\xf8\x49\x81\xc6\x4c\x36\x05\x83\x75\xab\x28\x3e\x6b\x8e\xa7\xae\xdf\xf7\xbe\xfb\x9b\x46\xc4\x89\x10\xb2\x60\xa4\xa5\xda\x4d\x19\xa9\x35\x28\x67\x6b\x26\x69\x2c\xc3\xfe\xa9\xfd\x0e\xb4\xa1\xc4\x9c\x9b\x39\x42\x93\xdb\x50\x0f\xa2\xa5\x5a\x0c\x84\x19\x12\x5a\x85\x35\x84\xb3\x11\xa2\xbe\x8b\x67\xde\x59\xd0\x58\x20\x23\x28\x59\x2b\xa9\xf1\x3a\xb2\xb1\x1a\xd7\xbf\x8e\xdc\x2e\x01\x6f\xe0\xec\xdd\xfe\x97\xf0\x2f\xaf\x46\x26\x0f\xeb\x8e\x7e\xba\xbd\xff\x65\x2e\x91\x62\x68\x00\x4d\xa5\x83\xe2\xce\x56\x64\x8e\x00\xa5\xfa\x0f\xe4\x56\x02\xd7\xa8\x1a\x46\xa8\xc8\x29\xd4\x0d\x21\x4c\x43\x7f\x38\xf8\xa1\x0d\x11\x17\xcf\x76\xea\x8f\x7e\x9c\xb6\xc7\xe7\x28\xcc\xac\x5c\x59\x1a\x4e\xc4\xc7\x00\x3d\x7a\x01\x3e\x79\x02\x00\x00")
+
+func _000024_recovery_code_keysDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000024_recovery_code_keysDownSql,
+		"000024_recovery_code_keys.down.sql",
+	)
+}
+
+func _000024_recovery_code_keysDownSql() (*asset, error) {
+	bytes, err := _000024_recovery_code_keysDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000024_recovery_code_keys.down.sql", size: 633, mode: os.FileMode(0644), modTime: time.Unix(1792297701, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6d, 0xee, 0x6, 0x37, 0xed, 0xa3, 0xb5, 0x47, 0xd0, 0x44, 0x8c, 0x62, 0x59, 0x4d, 0x71, 0xa4, 0xdd, 0x5c, 0x4e, 0x64, 0x46, 0xa1, 0x99, 0xe8, 0x9c, 0xfc, 0x47, 0x9, 0xdd, 0xbf, 0x32, 0x6b}}
+	return a, nil
+}
+
+var __000024_recovery_code_keysUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x93\x41\x4f\xe3\x3e\x10\xc5\xef\xfe\x14\xef\xd8\xa2\x3f\x20\xfd\x25\x4e\x3d\x99\x64\x5a\xa2\x4d\x93\xca\x71\x17\xd8\x4b\x64\x9a\x69\x13\x41\xe3\x95\xed\xb2\xf0\xed\x57\x6e\xd3\x22\xca\x6a\xb5\x39\xff\xe6\xbd\x99\xf7\xe2\xeb\x0b\x81\x0b\x28\x5e\xd9\x57\x76\xef\x58\xd9\x86\x3d\x8c\x63\xf8\x60\x1d\x37\x30\x1e\xde\xbc\x04\x6e\xd0\xb0\xeb\x5e\xb9\xc1\x33\xbf\x7b\x38\x13\x5a\x76\x08\xad\xe9\x23\xb2\xeb\x07\xa8\x35\xbe\x65\x0f\xdf\xf5\x2b\x8e\xc2\xa1\xe5\xf7\x83\x5c\x6b\x5d\x00\xf7\x76\xb7\x69\x11\x2c\x9e\x18\x4f\x6e\x17\xf8\x72\x6d\xdd\x8a\x1b\xac\x9d\xdd\xc2\xe0\x85\xcd\x73\xf4\x32\xc1\x3c\x19\xcf\x57\xd0\x2d\x83\xdf\x3a\x1f\xba\x7e\x33\xa8\x47\xdd\x95\xe9\x7b\x1b\xa2\xca\xca\xf6\xaf\xec\xe2\x82\xde\x62\xe7\xd9\x79\x6c\x77\x3e\x60\xc3\x3d\x3b\x13\x18\x3d\xff\x82\xfb\x74\xdf\x95\xc0\xc5\xb5\xb8\xa5\x59\x56\x4c\x84\x48\x55\xb9\x80\x96\xb7\x39\x21\x9b\x82\x1e\xb2\x4a\x57\xa7\x81\x7a\x3f\x30\x11\x22\x51\x24\x35\x7d\x70\x45\xa9\xff\xcc\x62\x24\x00\xa0\x6b\x70\xfa\x2a\x52\x99\xcc\xb1\x50\xd9\x5c\xaa\x47\x7c\xa3\xc7\xff\xf6\x4c\xdc\xb6\x1e\xc0\xac\xd0\x34\x23\xb5\xd7\x2d\x96\x79\x7e\x20\xa2\xfb\x51\xe5\xbb\x54\xc9\x9d\x54\xa3\xff\x6f\x6e\xc6\xe7\x98\x63\x13\x13\x88\x98\xce\xe6\x54\x69\x39\x5f\xe8\x1f\x27\x0a\x29\x4d\xe5\x32\xd7\x28\xca\xfb\xd1\xf8\x20\xbd\xb5\x4d\xb7\xee\xb8\xf9\xb7\x19\x31\x9e\x08\x21\x73\x4d\x6a\x88\xe0\xec\x68\x99\xa6\x48\xca\xa2\xd2\x4a\x66\x85\xc6\xfa\xb9\xfe\x0c\xd4\xf1\xd6\xbd\xef\xb4\x54\x94\xcd\x8a\x98\x02\x46\x43\x02\x63\x28\x9a\x92\xa2\x22\xa1\x6a\xe8\x70\xd4\x35\xe3\x3d\x5f\x16\x48\x29\x27\x4d\x48\x64\x95\xc8\x94\x3e\xca\xc8\x8a\x94\x1e\xfe\x5a\x46\x3d\x18\xbc\xa1\x2c\xbe\xf4\x74\x34\x9f\x08\x71\x79\x79\xfe\x04\x4e\xf1\x84\x6e\xcb\x3e\x98\xed\xcf\xd3\x2f\xa0\xb2\x59\x6c\xca\x73\x38\x3f\xf2\x38\x24\x6e\x29\x9e\x89\xe5\x22\x8d\x13\x5f\xbc\xc5\xb4\x54\x20\x99\xdc\x41\x95\xf7\x82\x1e\x28\x59\x6a\xc2\x42\x95\x09\xa5\x4b\x45\x08\xae\xdb\x6c\xd8\xd5\xd1\xe2\xa8\x59\x9f\x16\x19\xc5\x2e\x92\x72\x3e\xcf\xf4\x44\xfc\x1e\x00\x0c\xb8\xe9\x90\xc0\x03\x00\x00")
+
+func _000024_recovery_code_keysUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000024_recovery_code_keysUpSql,
+		"000024_recovery_code_keys.up.sql",
+	)
+}
+
+func _000024_recovery_code_keysUpSql() (*asset, error) {
+	bytes, err := _000024_recovery_code_keysUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000024_recovery_code_keys.up.sql", size: 960, mode: os.FileMode(0644), modTime: time.Unix(1792297701, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x93, 0x3e, 0xb6, 0xb0, 0x73, 0x14, 0xf, 0xc8, 0xf1, 0xc2, 0x42, 0x95, 0xf7, 0x64, 0x93, 0x2c, 0x92, 0x4a, 0xa5, 0x41, 0xa6, 0x6d, 0xa8, 0xb4, 0x84, 0x50, 0x50, 0xf3, 0x58, 0x83, 0x51, 0x3f}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -242,10 +1166,54 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"000001_initial_schema.down.sql": _000001_initial_schemaDownSql,
-	"000001_initial_schema.up.sql":   _000001_initial_schemaUpSql,
-	"000002_default_roles.down.sql":  _000002_default_rolesDownSql,
-	"000002_default_roles.up.sql":    _000002_default_rolesUpSql,
+	"000001_initial_schema.down.sql":         _000001_initial_schemaDownSql,
+	"000001_initial_schema.up.sql":           _000001_initial_schemaUpSql,
+	"000002_default_roles.down.sql":          _000002_default_rolesDownSql,
+	"000002_default_roles.up.sql":            _000002_default_rolesUpSql,
+	"000003_reading_tombstones.down.sql":     _000003_reading_tombstonesDownSql,
+	"000003_reading_tombstones.up.sql":       _000003_reading_tombstonesUpSql,
+	"000004_epistle_search.down.sql":         _000004_epistle_searchDownSql,
+	"000004_epistle_search.up.sql":           _000004_epistle_searchUpSql,
+	"000005_tags.down.sql":                   _000005_tagsDownSql,
+	"000005_tags.up.sql":                     _000005_tagsUpSql,
+	"000006_notes.down.sql":                  _000006_notesDownSql,
+	"000006_notes.up.sql":                    _000006_notesUpSql,
+	"000007_sync_jobs.down.sql":              _000007_sync_jobsDownSql,
+	"000007_sync_jobs.up.sql":                _000007_sync_jobsUpSql,
+	"000008_epistle_cache_headers.down.sql":  _000008_epistle_cache_headersDownSql,
+	"000008_epistle_cache_headers.up.sql":    _000008_epistle_cache_headersUpSql,
+	"000009_epistle_metadata.down.sql":       _000009_epistle_metadataDownSql,
+	"000009_epistle_metadata.up.sql":         _000009_epistle_metadataUpSql,
+	"000010_epistle_normalized.down.sql":     _000010_epistle_normalizedDownSql,
+	"000010_epistle_normalized.up.sql":       _000010_epistle_normalizedUpSql,
+	"000011_epistle_content.down.sql":        _000011_epistle_contentDownSql,
+	"000011_epistle_content.up.sql":          _000011_epistle_contentUpSql,
+	"000012_feed_tokens.down.sql":            _000012_feed_tokensDownSql,
+	"000012_feed_tokens.up.sql":              _000012_feed_tokensUpSql,
+	"000013_subscriptions.down.sql":          _000013_subscriptionsDownSql,
+	"000013_subscriptions.up.sql":            _000013_subscriptionsUpSql,
+	"000014_user_tokens.down.sql":            _000014_user_tokensDownSql,
+	"000014_user_tokens.up.sql":              _000014_user_tokensUpSql,
+	"000015_user_disabled.down.sql":          _000015_user_disabledDownSql,
+	"000015_user_disabled.up.sql":            _000015_user_disabledUpSql,
+	"000016_sessions.down.sql":               _000016_sessionsDownSql,
+	"000016_sessions.up.sql":                 _000016_sessionsUpSql,
+	"000017_failed_logins.down.sql":          _000017_failed_loginsDownSql,
+	"000017_failed_logins.up.sql":            _000017_failed_loginsUpSql,
+	"000018_api_keys.down.sql":               _000018_api_keysDownSql,
+	"000018_api_keys.up.sql":                 _000018_api_keysUpSql,
+	"000019_oauth.down.sql":                  _000019_oauthDownSql,
+	"000019_oauth.up.sql":                    _000019_oauthUpSql,
+	"000020_user_identities.down.sql":        _000020_user_identitiesDownSql,
+	"000020_user_identities.up.sql":          _000020_user_identitiesUpSql,
+	"000021_mfa.down.sql":                    _000021_mfaDownSql,
+	"000021_mfa.up.sql":                      _000021_mfaUpSql,
+	"000022_oauth_sessions.down.sql":         _000022_oauth_sessionsDownSql,
+	"000022_oauth_sessions.up.sql":           _000022_oauth_sessionsUpSql,
+	"000023_subscription_succeeded.down.sql": _000023_subscription_succeededDownSql,
+	"000023_subscription_succeeded.up.sql":   _000023_subscription_succeededUpSql,
+	"000024_recovery_code_keys.down.sql":     _000024_recovery_code_keysDownSql,
+	"000024_recovery_code_keys.up.sql":       _000024_recovery_code_keysUpSql,
 }
 
 // AssetDebug is true if the assets were built with the debug flag enabled.
@@ -298,6 +1266,50 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"000001_initial_schema.up.sql": {_000001_initial_schemaUpSql, map[string]*bintree{}},
 	"000002_default_roles.down.sql": {_000002_default_rolesDownSql, map[string]*bintree{}},
 	"000002_default_roles.up.sql": {_000002_default_rolesUpSql, map[string]*bintree{}},
+	"000003_reading_tombstones.down.sql": {_000003_reading_tombstonesDownSql, map[string]*bintree{}},
+	"000003_reading_tombstones.up.sql": {_000003_reading_tombstonesUpSql, map[string]*bintree{}},
+	"000004_epistle_search.down.sql": {_000004_epistle_searchDownSql, map[string]*bintree{}},
+	"000004_epistle_search.up.sql": {_000004_epistle_searchUpSql, map[string]*bintree{}},
+	"000005_tags.down.sql": {_000005_tagsDownSql, map[string]*bintree{}},
+	"000005_tags.up.sql": {_000005_tagsUpSql, map[string]*bintree{}},
+	"000006_notes.down.sql": {_000006_notesDownSql, map[string]*bintree{}},
+	"000006_notes.up.sql": {_000006_notesUpSql, map[string]*bintree{}},
+	"000007_sync_jobs.down.sql": {_000007_sync_jobsDownSql, map[string]*bintree{}},
+	"000007_sync_jobs.up.sql": {_000007_sync_jobsUpSql, map[string]*bintree{}},
+	"000008_epistle_cache_headers.down.sql": {_000008_epistle_cache_headersDownSql, map[string]*bintree{}},
+	"000008_epistle_cache_headers.up.sql": {_000008_epistle_cache_headersUpSql, map[string]*bintree{}},
+	"000009_epistle_metadata.down.sql": {_000009_epistle_metadataDownSql, map[string]*bintree{}},
+	"000009_epistle_metadata.up.sql": {_000009_epistle_metadataUpSql, map[string]*bintree{}},
+	"000010_epistle_normalized.down.sql": {_000010_epistle_normalizedDownSql, map[string]*bintree{}},
+	"000010_epistle_normalized.up.sql": {_000010_epistle_normalizedUpSql, map[string]*bintree{}},
+	"000011_epistle_content.down.sql": {_000011_epistle_contentDownSql, map[string]*bintree{}},
+	"000011_epistle_content.up.sql": {_000011_epistle_contentUpSql, map[string]*bintree{}},
+	"000012_feed_tokens.down.sql": {_000012_feed_tokensDownSql, map[string]*bintree{}},
+	"000012_feed_tokens.up.sql": {_000012_feed_tokensUpSql, map[string]*bintree{}},
+	"000013_subscriptions.down.sql": {_000013_subscriptionsDownSql, map[string]*bintree{}},
+	"000013_subscriptions.up.sql": {_000013_subscriptionsUpSql, map[string]*bintree{}},
+	"000014_user_tokens.down.sql": {_000014_user_tokensDownSql, map[string]*bintree{}},
+	"000014_user_tokens.up.sql": {_000014_user_tokensUpSql, map[string]*bintree{}},
+	"000015_user_disabled.down.sql": {_000015_user_disabledDownSql, map[string]*bintree{}},
+	"000015_user_disabled.up.sql": {_000015_user_disabledUpSql, map[string]*bintree{}},
+	"000016_sessions.down.sql": {_000016_sessionsDownSql, map[string]*bintree{}},
+	"000016_sessions.up.sql": {_000016_sessionsUpSql, map[string]*bintree{}},
+	"000017_failed_logins.down.sql": {_000017_failed_loginsDownSql, map[string]*bintree{}},
+	"000017_failed_logins.up.sql": {_000017_failed_loginsUpSql, map[string]*bintree{}},
+	"000018_api_keys.down.sql": {_000018_api_keysDownSql, map[string]*bintree{}},
+	"000018_api_keys.up.sql": {_000018_api_keysUpSql, map[string]*bintree{}},
+	"000019_oauth.down.sql": {_000019_oauthDownSql, map[string]*bintree{}},
+	"000019_oauth.up.sql": {_000019_oauthUpSql, map[string]*bintree{}},
+	"000020_user_identities.down.sql": {_000020_user_identitiesDownSql, map[string]*bintree{}},
+	"000020_user_identities.up.sql": {_000020_user_identitiesUpSql, map[string]*bintree{}},
+	"000021_mfa.down.sql": {_000021_mfaDownSql, map[string]*bintree{}},
+	"000021_mfa.up.sql": {_000021_mfaUpSql, map[string]*bintree{}},
+	"000022_oauth_sessions.down.sql": {_000022_oauth_sessionsDownSql, map[string]*bintree{}},
+	"000022_oauth_sessions.up.sql": {_000022_oauth_sessionsUpSql, map[string]*bintree{}},
+	"000023_subscription_succeeded.down.sql": {_000023_subscription_succeededDownSql, map[string]*bintree{}},
+	"000023_subscription_succeeded.up.sql": {_000023_subscription_succeededUpSql, map[string]*bintree{}},
+	"000024_recovery_code_keys.down.sql": {_000024_recovery_code_keysDownSql, map[string]*bintree{}},
+	"000024_recovery_code_keys.up.sql": {_000024_recovery_code_keysUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
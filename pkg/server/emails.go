@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/users"
+	"github.com/bbengfort/epistolary/pkg/utils/mailer"
+)
+
+const emailTimeout = 30 * time.Second
+
+const verifyEmailText = `Hello %s,
+
+Please confirm that this is your email address by following the link below:
+
+%s
+
+The link expires in %s. If you did not create an Epistolary account, you can safely
+ignore this email.
+`
+
+const resetPasswordText = `Hello %s,
+
+Someone requested a password reset for your Epistolary account. To choose a new
+password, follow the link below:
+
+%s
+
+The link expires in %s. If you did not request a password reset, you can safely ignore
+this email and your password will not be changed.
+`
+
+// Sends an email with the verification token to the user's email address.
+func (s *Server) sendVerificationEmail(ctx context.Context, user *users.User, token string) error {
+	return s.sendEmail(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your Epistolary email address",
+		Text:    fmt.Sprintf(verifyEmailText, greeting(user), s.appURL("/verify", token), users.VerificationTokenTTL),
+	})
+}
+
+// Sends an email with the password reset token to the user's email address.
+func (s *Server) sendResetEmail(ctx context.Context, user *users.User, token string) error {
+	return s.sendEmail(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your Epistolary password",
+		Text:    fmt.Sprintf(resetPasswordText, greeting(user), s.appURL("/reset-password", token), users.ResetTokenTTL),
+	})
+}
+
+func (s *Server) sendEmail(ctx context.Context, msg *mailer.Message) error {
+	ctx, cancel := context.WithTimeout(ctx, emailTimeout)
+	defer cancel()
+	return s.mailer.Send(ctx, msg)
+}
+
+// Returns the url of the path in the front-end app with the token in the query so that
+// the app can submit the token to the API.
+func (s *Server) appURL(path, token string) string {
+	base, err := url.Parse(s.conf.Token.Audience)
+	if err != nil {
+		base = &url.URL{}
+	}
+
+	ref := &url.URL{Path: path, RawQuery: url.Values{"token": []string{token}}.Encode()}
+	return base.ResolveReference(ref).String()
+}
+
+func greeting(user *users.User) string {
+	if user.FullName.Valid && user.FullName.String != "" {
+		return user.FullName.String
+	}
+	return user.Username
+}
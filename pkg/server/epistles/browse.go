@@ -0,0 +1,87 @@
+package epistles
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/utils/pagination"
+)
+
+const (
+	browseEpistlesSQL = "SELECT e.id, e.link, e.normalized, e.title, e.description, e.favicon, e.site_name, e.author, e.published, e.image, e.canonical, e.synced, e.sync_attempts, e.sync_error, e.etag, e.last_modified, e.created, e.modified, (SELECT count(*) FROM reading r WHERE r.epistle_id=e.id) AS readers FROM epistles e"
+)
+
+// Browse all epistles across all users for administration, most recently created
+// first, along with the number of users reading each epistle. If a query is specified
+// then only the epistles that match the full-text query are returned. Epistles are
+// paginated by offset in the same manner as List; if a previous page cursor is
+// specified it must have been created with the same query.
+func Browse(ctx context.Context, query string, prevPage *pagination.Cursor) (e []*Epistle, cursor *pagination.Cursor, err error) {
+	query = strings.TrimSpace(query)
+	fingerprint := pagination.Fingerprint("epistles", query)
+	if prevPage, err = pagination.Bind(prevPage, fingerprint); err != nil {
+		return nil, nil, err
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var sqlq strings.Builder
+	sqlq.WriteString(browseEpistlesSQL)
+
+	params := []any{
+		sql.Named("pageSize", prevPage.Size+1),
+		sql.Named("offset", prevPage.End),
+	}
+
+	if query != "" {
+		sqlq.WriteString(" WHERE e.search @@ (websearch_to_tsquery('english', :query) || websearch_to_tsquery('simple', :query))")
+		params = append(params, sql.Named("query", query))
+	}
+
+	// Add the limit as the page size + 1 to perform a has next page check
+	sqlq.WriteString(" ORDER BY e.created DESC, e.id DESC LIMIT :pageSize OFFSET :offset")
+
+	// Prep the query to convert named arguments into positional arguments
+	qs, args := db.Prep(sqlq.String(), params...)
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(qs, args...); err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	nRows := uint32(0)
+	e = make([]*Epistle, 0, prevPage.Size)
+	for rows.Next() {
+		// The query will request one additional row past the page size to check if
+		// there is a next page. No rows should be processed after the page size.
+		nRows++
+		if nRows > prevPage.Size {
+			continue
+		}
+
+		epistle := &Epistle{}
+		if err = rows.Scan(&epistle.ID, &epistle.Link, &epistle.Normalized, &epistle.Title, &epistle.Description, &epistle.Favicon, &epistle.SiteName, &epistle.Author, &epistle.Published, &epistle.Image, &epistle.Canonical, &epistle.Synced, &epistle.SyncAttempts, &epistle.SyncError, &epistle.ETag, &epistle.LastModified, &epistle.Created, &epistle.Modified, &epistle.Readers); err != nil {
+			return nil, nil, err
+		}
+		e = append(e, epistle)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	tx.Commit()
+
+	if len(e) > 0 && nRows > prevPage.Size {
+		cursor = pagination.New(prevPage.End, prevPage.End+int64(len(e)), prevPage.Size)
+		cursor.Filter = fingerprint
+	}
+	return e, cursor, nil
+}
@@ -0,0 +1,89 @@
+package epistles
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/server/fetch"
+)
+
+// Database model for the reader-mode content of an epistle that is extracted when the
+// epistle is synced so that it can be read offline even if the link goes away.
+type Content struct {
+	EpistleID   int64
+	Link        string
+	Title       sql.NullString
+	HTML        string
+	Text        string
+	WordCount   int64
+	ReadingTime int64
+	Created     time.Time
+	Modified    time.Time
+}
+
+// Create the content model from the content extracted from the epistle's document.
+func newContent(epistleID int64, c *fetch.Content) *Content {
+	return &Content{
+		EpistleID:   epistleID,
+		HTML:        c.HTML,
+		Text:        c.Text,
+		WordCount:   int64(c.WordCount),
+		ReadingTime: int64(c.ReadingTime),
+	}
+}
+
+const (
+	getContentSQL = "SELECT e.link, e.title, c.html, c.text, c.word_count, c.reading_time, c.created, c.modified FROM epistle_content c JOIN epistles e ON e.id=c.epistle_id JOIN reading r ON r.epistle_id=c.epistle_id WHERE c.epistle_id=$1 AND r.user_id=$2 AND r.deleted IS NULL"
+)
+
+// GetContent returns the reader-mode content of the epistle of the user's reading.
+// Returns sql.ErrNoRows if the user does not have the reading and ErrNoContent if the
+// content of the epistle has not been extracted yet.
+func GetContent(ctx context.Context, epistleID, userID int64) (c *Content, err error) {
+	if epistleID == 0 || userID == 0 {
+		return nil, ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err = tx.QueryRow(readingExistsSQL, epistleID, userID).Scan(&exists); err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	c = &Content{EpistleID: epistleID}
+	if err = tx.QueryRow(getContentSQL, epistleID, userID).Scan(&c.Link, &c.Title, &c.HTML, &c.Text, &c.WordCount, &c.ReadingTime, &c.Created, &c.Modified); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNoContent
+		}
+		return nil, err
+	}
+
+	tx.Commit()
+	return c, nil
+}
+
+const (
+	saveContentSQL = "INSERT INTO epistle_content (epistle_id, html, text, word_count, reading_time) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (epistle_id) DO UPDATE SET html=EXCLUDED.html, text=EXCLUDED.text, word_count=EXCLUDED.word_count, reading_time=EXCLUDED.reading_time"
+)
+
+func (c *Content) save(tx *sql.Tx) (err error) {
+	if c.EpistleID == 0 {
+		return ErrIDRequired
+	}
+
+	if _, err = tx.Exec(saveContentSQL, c.EpistleID, c.HTML, c.Text, c.WordCount, c.ReadingTime); err != nil {
+		return err
+	}
+	return nil
+}
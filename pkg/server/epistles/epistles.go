@@ -9,30 +9,56 @@ import (
 
 	"github.com/bbengfort/epistolary/pkg/server/db"
 	"github.com/bbengfort/epistolary/pkg/server/fetch"
+	"github.com/bbengfort/epistolary/pkg/utils/urlnorm"
 )
 
 // Database model for an Epistle object
 type Epistle struct {
-	ID          int64
-	Link        string
-	Title       sql.NullString
-	Description sql.NullString
-	Favicon     sql.NullString
-	Created     time.Time
-	Modified    time.Time
+	ID           int64
+	Link         string
+	Normalized   sql.NullString
+	Title        sql.NullString
+	Description  sql.NullString
+	Favicon      sql.NullString
+	SiteName     sql.NullString
+	Author       sql.NullString
+	Published    sql.NullTime
+	Image        sql.NullString
+	Canonical    sql.NullString
+	Synced       sql.NullTime
+	SyncAttempts int64
+	SyncError    sql.NullString
+	ETag         sql.NullString
+	LastModified sql.NullString
+	Created      time.Time
+	Modified     time.Time
+	Readers      int64 // number of users reading the epistle, only populated by Browse
+	content      *fetch.Content
 }
 
+// IsSynced returns true if the metadata of the epistle has been successfully fetched.
 func (e *Epistle) IsSynced() bool {
-	return e.Title.String != "" || e.Description.String != "" || e.Favicon.String != ""
+	return e.Synced.Valid && !e.Synced.Time.IsZero()
 }
 
+// Sync fetches the metadata of the epistle from its link and saves it, marking the
+// epistle as synced. If the epistle has been synced before, a conditional request is
+// made and if the page has not been modified the metadata is left unchanged. Syncing
+// can take a long time so it should not be called inline with user requests; instead
+// enqueue a sync job for the background workers.
 func (e *Epistle) Sync(ctx context.Context) (err error) {
 	if e.Link == "" {
 		return ErrLinkRequired
 	}
 
 	var doc *fetch.Document
-	if doc, err = fetch.Fetch(ctx, e.Link); err != nil {
+	if doc, err = fetch.FetchConditional(ctx, e.Link, e.ETag.String, e.LastModified.String); err != nil {
+		var herr fetch.HTTPError
+		if errors.As(err, &herr) && herr.NotModified() {
+			e.Synced = sql.NullTime{Valid: true, Time: time.Now()}
+			e.SyncError = sql.NullString{}
+			return e.saveNotModified(ctx)
+		}
 		return err
 	}
 
@@ -44,18 +70,42 @@ func (e *Epistle) Sync(ctx context.Context) (err error) {
 		doc.Favicon = ""
 	}
 
-	e.Title = sql.NullString{Valid: doc.Title != "", String: doc.Title}
-	e.Description = sql.NullString{Valid: doc.Description != "", String: doc.Description}
-	e.Favicon = sql.NullString{Valid: doc.Favicon != "", String: doc.Favicon}
+	e.setMetadata(doc)
+
+	// Content that could not be extracted does not replace previously extracted content
+	e.content = doc.Content
 
-	// Save the epistle after fetching it
-	return e.Save(ctx)
+	// Save the epistle after fetching it, merging it with any epistle that has the
+	// same canonical link.
+	return e.saveCanonical(ctx)
+}
+
+// Sets the metadata of the epistle from the fetched document, truncating any fields
+// that are longer than their database columns allow.
+func (e *Epistle) setMetadata(doc *fetch.Document) {
+	e.Title = sql.NullString{Valid: doc.Title != "", String: truncate(doc.Title, 512)}
+	e.Description = sql.NullString{Valid: doc.Description != "", String: truncate(doc.Description, 4096)}
+	e.Favicon = sql.NullString{Valid: doc.Favicon != "", String: doc.Favicon}
+	e.SiteName = sql.NullString{Valid: doc.SiteName != "", String: truncate(doc.SiteName, 255)}
+	e.Author = sql.NullString{Valid: doc.Author != "", String: truncate(doc.Author, 255)}
+	e.Published = sql.NullTime{Valid: !doc.Published.IsZero(), Time: doc.Published}
+	e.Image = sql.NullString{Valid: doc.Image != "", String: doc.Image}
+	e.Canonical = sql.NullString{Valid: doc.Canonical != "", String: doc.Canonical}
+	e.ETag = sql.NullString{Valid: doc.ETag != "", String: doc.ETag}
+	e.LastModified = sql.NullString{Valid: doc.LastModified != "", String: doc.LastModified}
+	e.Synced = sql.NullTime{Valid: true, Time: time.Now()}
+	e.SyncError = sql.NullString{}
 }
 
 const (
-	saveEpistleSQL = "UPDATE epistles SET link=$2, title=$3, description=$4, favicon=$5, created=$6, modified=$7 WHERE id=$1"
+	saveEpistleSQL     = "UPDATE epistles SET link=$2, normalized=$3, title=COALESCE(NULLIF(title, ''), $4), description=COALESCE(NULLIF(description, ''), $5), favicon=$6, site_name=$7, author=$8, published=$9, image=$10, canonical=$11, synced=$12, sync_error=$13, etag=$14, last_modified=$15, modified=$16 WHERE id=$1 RETURNING title, description"
+	saveNotModifiedSQL = "UPDATE epistles SET synced=$2, sync_error=$3, modified=$4 WHERE id=$1"
 )
 
+// Save the synced metadata of the epistle. Only the columns owned by sync are updated
+// and the title and description are only set if the epistle does not already have
+// one, since they may have been edited by the user; the title and description of the
+// epistle are refreshed from the database once saved.
 func (e *Epistle) Save(ctx context.Context) (err error) {
 	var tx *sql.Tx
 	if tx, err = db.BeginTx(ctx, nil); err != nil {
@@ -76,25 +126,53 @@ func (e *Epistle) save(tx *sql.Tx) (err error) {
 	}
 
 	e.Modified = time.Now()
-	if _, err = tx.Exec(saveEpistleSQL, e.ID, e.Link, e.Title, e.Description, e.Favicon, e.Created, e.Modified); err != nil {
+	if err = tx.QueryRow(saveEpistleSQL, e.ID, e.Link, e.Normalized, e.Title, e.Description, e.Favicon, e.SiteName, e.Author, e.Published, e.Image, e.Canonical, e.Synced, e.SyncError, e.ETag, e.LastModified, e.Modified).Scan(&e.Title, &e.Description); err != nil {
 		return fmt.Errorf("could not save epistle: %w", err)
 	}
 
 	return nil
 }
 
+// Marks the epistle as synced when the page has not been modified since the last sync
+// without updating any of its metadata.
+func (e *Epistle) saveNotModified(ctx context.Context) (err error) {
+	if e.ID == 0 {
+		return ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, nil); err != nil {
+		return fmt.Errorf("could not start write tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	e.Modified = time.Now()
+	if _, err = tx.Exec(saveNotModifiedSQL, e.ID, e.Synced, e.SyncError, e.Modified); err != nil {
+		return fmt.Errorf("could not save epistle: %w", err)
+	}
+
+	return tx.Commit()
+}
+
 const (
-	epistleByLinkSQL = "SELECT id, title, description, favicon, created, modified FROM epistles WHERE link=$1"
-	createEpistleSQL = "INSERT INTO epistles (link) VALUES ($1) RETURNING ID"
+	epistleByLinkSQL = "SELECT id, link, normalized, title, description, favicon, site_name, author, published, image, canonical, synced, sync_attempts, sync_error, etag, last_modified, created, modified FROM epistles WHERE normalized=$1 OR link=$2 ORDER BY normalized=$1 DESC NULLS LAST LIMIT 1"
+	createEpistleSQL = "INSERT INTO epistles (link, normalized) VALUES ($1, $2) RETURNING ID"
+	keyEpistleSQL    = "UPDATE epistles SET normalized=$2 WHERE id=$1 AND normalized IS NULL"
 	epistleTSSQL     = "SELECT created, modified FROM epistles WHERE id=$1"
 )
 
-// Get or create an epistle via a URL, which should be unique.
+// Get or create an epistle via a normalized URL; an existing epistle is returned if
+// its link is a variant of the same URL (e.g. http vs https or a trailing slash).
 func getOrCreateEpistle(tx *sql.Tx, link string) (e *Epistle, err error) {
-	e = &Epistle{Link: link}
-	if err = tx.QueryRow(epistleByLinkSQL, link).Scan(&e.ID, &e.Title, &e.Description, &e.Favicon, &e.Created, &e.Modified); err != nil {
+	var key string
+	if key, err = urlnorm.Key(link); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLink, err)
+	}
+
+	e = &Epistle{Link: link, Normalized: sql.NullString{Valid: true, String: key}}
+	if err = tx.QueryRow(epistleByLinkSQL, key, link).Scan(&e.ID, &e.Link, &e.Normalized, &e.Title, &e.Description, &e.Favicon, &e.SiteName, &e.Author, &e.Published, &e.Image, &e.Canonical, &e.Synced, &e.SyncAttempts, &e.SyncError, &e.ETag, &e.LastModified, &e.Created, &e.Modified); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			if err = tx.QueryRow(createEpistleSQL, link).Scan(&e.ID); err != nil {
+			if err = tx.QueryRow(createEpistleSQL, link, key).Scan(&e.ID); err != nil {
 				return nil, err
 			}
 
@@ -106,20 +184,53 @@ func getOrCreateEpistle(tx *sql.Tx, link string) (e *Epistle, err error) {
 		}
 		return nil, err
 	}
+
+	// Key epistles that were created before links were normalized
+	if !e.Normalized.Valid {
+		if _, err = tx.Exec(keyEpistleSQL, e.ID, key); err != nil {
+			return nil, err
+		}
+		e.Normalized = sql.NullString{Valid: true, String: key}
+	}
 	return e, nil
 }
 
 const (
-	getEpistleSQL = "SELECT link, title, description, favicon, created, modified FROM epistles WHERE id=$1"
+	getEpistleSQL = "SELECT link, normalized, title, description, favicon, site_name, author, published, image, canonical, synced, sync_attempts, sync_error, etag, last_modified, created, modified FROM epistles WHERE id=$1"
 )
 
+// Get an epistle by its id, returns sql.ErrNoRows if the epistle does not exist.
+func Get(ctx context.Context, epistleID int64) (e *Epistle, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	e = &Epistle{ID: epistleID}
+	if err = e.fetch(tx); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return e, nil
+}
+
 func (e *Epistle) fetch(tx *sql.Tx) error {
 	if e.ID < 1 {
 		return ErrIDRequired
 	}
 
-	if err := tx.QueryRow(getEpistleSQL, e.ID).Scan(&e.Link, &e.Title, &e.Description, &e.Favicon, &e.Created, &e.Modified); err != nil {
+	if err := tx.QueryRow(getEpistleSQL, e.ID).Scan(&e.Link, &e.Normalized, &e.Title, &e.Description, &e.Favicon, &e.SiteName, &e.Author, &e.Published, &e.Image, &e.Canonical, &e.Synced, &e.SyncAttempts, &e.SyncError, &e.ETag, &e.LastModified, &e.Created, &e.Modified); err != nil {
 		return err
 	}
 	return nil
 }
+
+// Truncates the string to at most n characters so that it fits in its column.
+func truncate(s string, n int) string {
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
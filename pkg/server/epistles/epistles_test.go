@@ -0,0 +1,61 @@
+package epistles
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/bbengfort/epistolary/pkg/server/config"
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/server/fetch"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveEditedTitle(t *testing.T) {
+	require.NoError(t, db.Connect(config.DatabaseConfig{Testing: true}))
+	t.Cleanup(func() { db.Close() })
+	mock := db.Mock()
+
+	// The title and description edited by the user are kept when the epistle is synced
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(saveEpistleSQL)).
+		WithArgs(int64(42), "https://example.com/article", sqlmock.AnyArg(), "Synced Title", "Synced description", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow("Edited Title", "Edited description"))
+	mock.ExpectCommit()
+
+	e := &Epistle{
+		ID:          42,
+		Link:        "https://example.com/article",
+		Title:       sql.NullString{Valid: true, String: "Synced Title"},
+		Description: sql.NullString{Valid: true, String: "Synced description"},
+	}
+	require.NoError(t, e.Save(context.Background()))
+	require.Equal(t, "Edited Title", e.Title.String)
+	require.Equal(t, "Edited description", e.Description.String)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetMetadataOversized(t *testing.T) {
+	// Metadata longer than the database columns allow is truncated
+	title := strings.Repeat("Hello ", 200)
+	description := strings.Repeat("Hello World ", 500)
+	html := `<html><head><title>` + title + `</title><meta name="description" content="` + description + `"></head></html>`
+
+	tree, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	require.NoError(t, err)
+
+	base, _ := url.Parse("https://example.com/article")
+	meta := fetch.ParseMetadata(tree, base)
+	require.Greater(t, len(meta.Title), 512)
+	require.Greater(t, len(meta.Description), 4096)
+
+	e := &Epistle{}
+	e.setMetadata(&fetch.Document{Title: meta.Title, Description: meta.Description})
+	require.Equal(t, meta.Title[:512], e.Title.String)
+	require.Equal(t, meta.Description[:4096], e.Description.String)
+}
@@ -1,11 +1,25 @@
 package epistles
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/bbengfort/epistolary/pkg/utils/pagination"
+)
 
 var (
 	ErrIDRequired        = errors.New("cannot execute query without an id stored on the model")
 	ErrLinkRequired      = errors.New("cannot fetch epistle information without a link")
-	ErrMissingPageSize   = errors.New("missing page size in paginated query")
+	ErrInvalidLink       = errors.New("invalid link")
+	ErrMergeSelf         = errors.New("cannot merge an epistle into itself")
+	ErrNoContent         = errors.New("epistle content has not been extracted")
+	ErrMissingPageSize   = pagination.ErrMissingPageSize
+	ErrMissingQuery      = errors.New("missing search query")
+	ErrInvalidStatus     = errors.New("status must be one of queued, started, finished, or archived")
+	ErrInvalidSort       = errors.New("readings can only be sorted by created, modified, finished, or title")
+	ErrInvalidDateRange  = errors.New("the start of a date range must be before its end")
+	ErrInvalidDomain     = errors.New("domain must be a hostname such as example.com")
 	ErrAlreadyExists     = errors.New("reading already exists")
 	ErrEpistleIDMismatch = errors.New("cannot update a reading with the wrong epistle id")
+	ErrEmptyNote         = errors.New("a note requires a body or a highlight")
+	ErrNoteTooLong       = errors.New("note body and highlight cannot be longer than 64KiB")
 )
@@ -0,0 +1,77 @@
+package epistles
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/lib/pq"
+)
+
+const (
+	exportReadingsSQL = "SELECT r.epistle_id, r.status, r.started, r.finished, r.archived, r.created, r.modified, e.link, e.title, e.description, e.favicon, e.site_name, e.author, e.published, e.image, e.canonical, e.created, e.modified, ARRAY(SELECT t.name FROM reading_tags rt JOIN tags t ON rt.tag_id=t.id WHERE rt.epistle_id=r.epistle_id AND rt.user_id=r.user_id ORDER BY t.name) FROM reading r JOIN epistles e ON r.epistle_id=e.id WHERE r.user_id=$1 AND r.deleted IS NULL ORDER BY r.created, r.epistle_id"
+)
+
+// Each calls the function with every reading of the user, along with the epistle and
+// tags of the reading, in the order the readings were created so that the user's whole
+// library can be streamed without loading it into memory. If the function returns an
+// error, iteration stops and the error is returned.
+func Each(ctx context.Context, userID int64, fn func(r *Reading, tags []string) error) (err error) {
+	if userID == 0 {
+		return ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(exportReadingsSQL, userID); err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tags []string
+		reading := &Reading{UserID: userID}
+		epistle := &Epistle{}
+
+		if err = rows.Scan(
+			&reading.EpistleID,
+			&reading.Status,
+			&reading.Started,
+			&reading.Finished,
+			&reading.Archived,
+			&reading.Created,
+			&reading.Modified,
+			&epistle.Link,
+			&epistle.Title,
+			&epistle.Description,
+			&epistle.Favicon,
+			&epistle.SiteName,
+			&epistle.Author,
+			&epistle.Published,
+			&epistle.Image,
+			&epistle.Canonical,
+			&epistle.Created,
+			&epistle.Modified,
+			pq.Array(&tags)); err != nil {
+			return err
+		}
+
+		epistle.ID = reading.EpistleID
+		reading.epistle = epistle
+		if err = fn(reading, tags); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	tx.Commit()
+	return nil
+}
@@ -0,0 +1,209 @@
+package epistles
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/tags"
+	"github.com/bbengfort/epistolary/pkg/utils/pagination"
+	"github.com/lib/pq"
+)
+
+// SortField specifies how the list of readings should be ordered.
+type SortField string
+
+const (
+	SortCreated  SortField = "created"
+	SortModified SortField = "modified"
+	SortFinished SortField = "finished"
+	SortTitle    SortField = "title"
+)
+
+// Maps the sort field to the column expression used in the ORDER BY clause.
+var sortColumns = map[SortField]string{
+	SortCreated:  "r.created",
+	SortModified: "r.modified",
+	SortFinished: "r.finished",
+	SortTitle:    "lower(e.title)",
+}
+
+// Extracts the host from the link without the scheme, port, or a leading www.
+const linkDomainSQL = `regexp_replace(lower(substring(e.link from '^[a-zA-Z][a-zA-Z0-9+.-]*://([^/:?#]+)')), '^www\.', '')`
+
+// Filter limits the readings returned by List and specifies their order. The zero
+// value of the filter returns all of the user's readings by most recently created.
+// If tags are specified, readings that have any of the tags are returned.
+type Filter struct {
+	Status         []Status
+	CreatedAfter   time.Time
+	CreatedBefore  time.Time
+	FinishedAfter  time.Time
+	FinishedBefore time.Time
+	Domain         string
+	Tags           []string
+	Sort           SortField
+	Ascending      bool
+}
+
+// Validate the filter, normalizing its values so that they can be fingerprinted.
+func (f *Filter) Validate() error {
+	for i, status := range f.Status {
+		status = Status(strings.ToLower(strings.TrimSpace(string(status))))
+		switch status {
+		case StatusQueued, StatusStarted, StatusFinished, StatusArchived:
+			f.Status[i] = status
+		default:
+			return ErrInvalidStatus
+		}
+	}
+
+	// Sort the statuses so that their order does not change the fingerprint
+	sort.Slice(f.Status, func(i, j int) bool { return f.Status[i] < f.Status[j] })
+
+	if !f.CreatedAfter.IsZero() && !f.CreatedBefore.IsZero() && !f.CreatedAfter.Before(f.CreatedBefore) {
+		return ErrInvalidDateRange
+	}
+
+	if !f.FinishedAfter.IsZero() && !f.FinishedBefore.IsZero() && !f.FinishedAfter.Before(f.FinishedBefore) {
+		return ErrInvalidDateRange
+	}
+
+	f.Domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(f.Domain)), "www.")
+	if strings.ContainsAny(f.Domain, "/:?#%_ ") {
+		return ErrInvalidDomain
+	}
+
+	var err error
+	if f.Tags, err = tags.NormalizeAll(f.Tags); err != nil {
+		return err
+	}
+	sort.Strings(f.Tags)
+
+	if f.Sort == "" {
+		f.Sort = SortCreated
+	}
+
+	if _, ok := sortColumns[f.Sort]; !ok {
+		return ErrInvalidSort
+	}
+
+	return nil
+}
+
+// Fingerprint the filter so that it can be stored on a pagination cursor and a page
+// token cannot be used with a different filter than the one that created it.
+func (f *Filter) Fingerprint() uint64 {
+	params := make([]string, 0, len(f.Status)+len(f.Tags)+8)
+	for _, status := range f.Status {
+		params = append(params, string(status))
+	}
+
+	params = append(params,
+		formatFilterTime(f.CreatedAfter),
+		formatFilterTime(f.CreatedBefore),
+		formatFilterTime(f.FinishedAfter),
+		formatFilterTime(f.FinishedBefore),
+		f.Domain,
+		string(f.Sort),
+	)
+
+	if f.Ascending {
+		params = append(params, "asc")
+	} else {
+		params = append(params, "desc")
+	}
+
+	for _, tag := range f.Tags {
+		params = append(params, "tag:"+tag)
+	}
+
+	return pagination.Fingerprint(params...)
+}
+
+// Returns the conditions and named parameters to add to the WHERE clause of the query.
+func (f *Filter) where() (where []string, params []any) {
+	if len(f.Status) > 0 {
+		statuses := make([]string, 0, len(f.Status))
+		for _, status := range f.Status {
+			statuses = append(statuses, string(status))
+		}
+		params = append(params, sql.Named("status", pq.Array(statuses)))
+		where = append(where, "r.status::text = ANY(:status)")
+	}
+
+	if !f.CreatedAfter.IsZero() {
+		params = append(params, sql.Named("createdAfter", f.CreatedAfter))
+		where = append(where, "r.created >= :createdAfter")
+	}
+
+	if !f.CreatedBefore.IsZero() {
+		params = append(params, sql.Named("createdBefore", f.CreatedBefore))
+		where = append(where, "r.created < :createdBefore")
+	}
+
+	if !f.FinishedAfter.IsZero() {
+		params = append(params, sql.Named("finishedAfter", f.FinishedAfter))
+		where = append(where, "r.finished >= :finishedAfter")
+	}
+
+	if !f.FinishedBefore.IsZero() {
+		params = append(params, sql.Named("finishedBefore", f.FinishedBefore))
+		where = append(where, "r.finished < :finishedBefore")
+	}
+
+	if f.Domain != "" {
+		params = append(params, sql.Named("domain", f.Domain))
+		where = append(where, "("+linkDomainSQL+" = :domain OR "+linkDomainSQL+" LIKE '%.' || :domain)")
+	}
+
+	if len(f.Tags) > 0 {
+		params = append(params, sql.Named("tags", pq.Array(f.Tags)))
+		where = append(where, "EXISTS (SELECT 1 FROM reading_tags rt JOIN tags t ON rt.tag_id=t.id WHERE rt.epistle_id=r.epistle_id AND rt.user_id=r.user_id AND t.name = ANY(:tags))")
+	}
+
+	return where, params
+}
+
+// Returns the ORDER BY clause of the query; ties are broken by the epistle id so that
+// the order of the results is stable across pages.
+func (f *Filter) orderBy() string {
+	column, ok := sortColumns[f.Sort]
+	if !ok {
+		column = sortColumns[SortCreated]
+	}
+
+	direction := " DESC NULLS LAST"
+	if f.Ascending {
+		direction = " ASC NULLS LAST"
+	}
+
+	return " ORDER BY " + column + direction + ", r.epistle_id" + direction
+}
+
+func formatFilterTime(ts time.Time) string {
+	if ts.IsZero() {
+		return ""
+	}
+	return ts.UTC().Format(time.RFC3339Nano)
+}
+
+// PrevPage returns the cursor to fetch the page that precedes the page fetched with the
+// specified cursor, or nil if the cursor fetched the first page. Readings are paginated
+// by offset: the start of the cursor is the offset of the page that was returned with
+// the cursor and the end is the offset of the next page to fetch.
+func PrevPage(c *pagination.Cursor) *pagination.Cursor {
+	if c == nil || c.End == 0 {
+		return nil
+	}
+
+	start := c.Start - int64(c.Size)
+	if start < 0 {
+		start = 0
+	}
+
+	prev := pagination.New(start, c.Start, c.Size)
+	prev.Filter = c.Filter
+	return prev
+}
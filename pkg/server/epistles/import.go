@@ -0,0 +1,68 @@
+package epistles
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/utils/urlnorm"
+	"github.com/lib/pq"
+)
+
+const (
+	importReadingSQL = "INSERT INTO reading (epistle_id, user_id, status, started, finished, archived, created) VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, NOW())) RETURNING created, modified"
+	importTitleSQL   = "UPDATE epistles SET title=$2 WHERE id=$1 AND title IS NULL"
+)
+
+// Import creates a reading for the user from a reading that was exported from another
+// service, keeping the original started, finished, archived, and created timestamps of
+// the reading. The title is used for the epistle until it is synced. Returns
+// ErrAlreadyExists if the user already has a reading of the link.
+func Import(ctx context.Context, r *Reading, link, title string) (err error) {
+	if r.UserID == 0 {
+		return ErrIDRequired
+	}
+
+	if link, err = urlnorm.Normalize(link); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidLink, err)
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if r.epistle, err = getOrCreateEpistle(tx, link); err != nil {
+		return err
+	}
+	r.EpistleID = r.epistle.ID
+
+	if title != "" && !r.epistle.Title.Valid {
+		if _, err = tx.Exec(importTitleSQL, r.EpistleID, truncate(title, 512)); err != nil {
+			return err
+		}
+		r.epistle.Title = sql.NullString{Valid: true, String: truncate(title, 512)}
+	}
+
+	if _, err = tx.Exec(clearTombstoneSQL, r.EpistleID, r.UserID); err != nil {
+		return err
+	}
+
+	created := sql.NullTime{Valid: !r.Created.IsZero(), Time: r.Created}
+	r.Status = r.status()
+	if err = tx.QueryRow(importReadingSQL, r.EpistleID, r.UserID, r.Status, r.Started, r.Finished, r.Archived, created).Scan(&r.Created, &r.Modified); err != nil {
+		if pgerr, ok := err.(*pq.Error); ok && pgerr.Code == "23505" {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+
+	if !r.epistle.IsSynced() {
+		if err = enqueueSync(tx, r.EpistleID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,285 @@
+package epistles
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/utils/urlnorm"
+)
+
+// MergeGroup describes a set of duplicate epistles that were merged into the epistle
+// with the lowest ID, which is kept.
+type MergeGroup struct {
+	Into   int64
+	Link   string
+	Merged []int64
+	Links  []string
+}
+
+const (
+	mergeTombstonesSQL = "DELETE FROM reading r WHERE r.epistle_id=$2 AND r.deleted IS NOT NULL AND EXISTS (SELECT 1 FROM reading f WHERE f.epistle_id=$1 AND f.user_id=r.user_id AND f.deleted IS NULL)"
+	mergeReadingsSQL   = "INSERT INTO reading (epistle_id, user_id, status, started, finished, archived, deleted, created, modified) SELECT $2, user_id, status, started, finished, archived, deleted, created, modified FROM reading WHERE epistle_id=$1 ON CONFLICT (epistle_id, user_id) DO NOTHING"
+	mergeNotesSQL      = "UPDATE notes SET epistle_id=$2 WHERE epistle_id=$1"
+	mergeTagsSQL       = "INSERT INTO reading_tags (epistle_id, user_id, tag_id, created, modified) SELECT $2, user_id, tag_id, created, modified FROM reading_tags WHERE epistle_id=$1 ON CONFLICT DO NOTHING"
+	deleteReadingsSQL  = "DELETE FROM reading WHERE epistle_id=$1"
+	deleteEpistleSQL   = "DELETE FROM epistles WHERE id=$1"
+)
+
+// Merge moves the readings, notes, and tags of the duplicate epistle into the other
+// epistle then deletes the duplicate. If a user has a reading of both epistles, the
+// reading of the epistle that is kept wins unless it has been deleted.
+func Merge(ctx context.Context, fromID, intoID int64) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = merge(tx, fromID, intoID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func merge(tx *sql.Tx, fromID, intoID int64) (err error) {
+	if fromID == 0 || intoID == 0 {
+		return ErrIDRequired
+	}
+
+	if fromID == intoID {
+		return ErrMergeSelf
+	}
+
+	for _, query := range []string{mergeTombstonesSQL, mergeReadingsSQL, mergeNotesSQL, mergeTagsSQL} {
+		if _, err = tx.Exec(query, fromID, intoID); err != nil {
+			return fmt.Errorf("could not merge epistle %d into %d: %w", fromID, intoID, err)
+		}
+	}
+
+	if _, err = tx.Exec(deleteReadingsSQL, fromID); err != nil {
+		return err
+	}
+
+	// Deleting the epistle also deletes its sync job
+	if _, err = tx.Exec(deleteEpistleSQL, fromID); err != nil {
+		return err
+	}
+	return nil
+}
+
+const (
+	dedupeEpistlesSQL = "SELECT id, link, canonical, normalized FROM epistles ORDER BY id"
+	lockEpistlesSQL   = "SELECT id, link, canonical, normalized FROM epistles ORDER BY id FOR UPDATE"
+	clearKeySQL       = "UPDATE epistles SET normalized=NULL WHERE id=$1"
+	setKeySQL         = "UPDATE epistles SET normalized=$2 WHERE id=$1"
+)
+
+// Dedupe finds epistles whose links or canonical links normalize to the same key and
+// merges them into the oldest epistle of the group. Every remaining epistle is keyed by
+// its normalized link so that new duplicates are detected when they are created. If
+// dryRun is true, the groups that would be merged are returned without any changes.
+func Dedupe(ctx context.Context, dryRun bool) (groups []*MergeGroup, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: dryRun}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	type row struct {
+		id         int64
+		link       string
+		key        string
+		normalized sql.NullString
+	}
+
+	var rows *sql.Rows
+	query := lockEpistlesSQL
+	if dryRun {
+		query = dedupeEpistlesSQL
+	}
+
+	if rows, err = tx.Query(query); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Union epistles that share a key from either their link or their canonical link;
+	// the parent of each set is the epistle with the lowest id.
+	var epistles []*row
+	parent := make(map[int64]int64)
+	owner := make(map[string]int64)
+
+	var find func(int64) int64
+	find = func(id int64) int64 {
+		if p := parent[id]; p != id {
+			parent[id] = find(p)
+		}
+		return parent[id]
+	}
+
+	union := func(key string, id int64) {
+		if key == "" {
+			return
+		}
+
+		other, ok := owner[key]
+		if !ok {
+			owner[key] = id
+			return
+		}
+
+		a, b := find(other), find(id)
+		if a > b {
+			a, b = b, a
+		}
+		parent[b] = a
+	}
+
+	for rows.Next() {
+		var canonical sql.NullString
+		r := &row{}
+		if err = rows.Scan(&r.id, &r.link, &canonical, &r.normalized); err != nil {
+			return nil, err
+		}
+
+		// Links that cannot be keyed are left as they are
+		r.key, _ = urlnorm.Key(r.link)
+		parent[r.id] = r.id
+		epistles = append(epistles, r)
+
+		// Canonical links on another site are not trusted to detect duplicates
+		union(r.key, r.id)
+		if canonical.Valid && urlnorm.SameSite(r.link, canonical.String) {
+			if key, _ := urlnorm.Key(canonical.String); key != r.key {
+				union(key, r.id)
+			}
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	keep := make(map[int64]*row)
+	merged := make(map[int64]*MergeGroup)
+	for _, r := range epistles {
+		root := find(r.id)
+		if root == r.id {
+			keep[root] = r
+			continue
+		}
+
+		group, ok := merged[root]
+		if !ok {
+			group = &MergeGroup{Into: root, Link: keep[root].link}
+			merged[root] = group
+			groups = append(groups, group)
+		}
+		group.Merged = append(group.Merged, r.id)
+		group.Links = append(group.Links, r.link)
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Into < groups[j].Into })
+	if dryRun {
+		return groups, nil
+	}
+
+	for _, group := range groups {
+		for _, fromID := range group.Merged {
+			if err = merge(tx, fromID, group.Into); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Clear keys that are not held by the kept epistle before keying so that the unique
+	// constraint is not violated while keys move between epistles.
+	for _, r := range keep {
+		if r.normalized.Valid && r.normalized.String != r.key {
+			if _, err = tx.Exec(clearKeySQL, r.id); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, r := range keep {
+		if r.key != "" && (!r.normalized.Valid || r.normalized.String != r.key) {
+			if _, err = tx.Exec(setKeySQL, r.id, r.key); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+const (
+	epistleIDByKeySQL = "SELECT id FROM epistles WHERE (normalized=$1 OR link=$2) AND id<>$3 ORDER BY id LIMIT 1"
+)
+
+// Saves the synced epistle and its extracted content, using its canonical link to
+// detect duplicates. If another epistle has the same canonical link, this epistle is
+// merged into it and the synced metadata is saved to the other epistle; otherwise the
+// link of the epistle is replaced by its canonical link. Canonical links are only
+// trusted if they are on the same site as the link of the epistle.
+func (e *Epistle) saveCanonical(ctx context.Context) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return fmt.Errorf("could not start write tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if e.Canonical.Valid {
+		// Canonical links that cannot be normalized are ignored, as are canonical links
+		// to another site since any page could otherwise claim to be another page.
+		var link, key string
+		if link, err = urlnorm.Normalize(e.Canonical.String); err == nil {
+			key, err = urlnorm.Key(link)
+		}
+
+		if err == nil && key != e.Normalized.String && urlnorm.SameSite(e.Link, link) {
+			var intoID int64
+			switch err = tx.QueryRow(epistleIDByKeySQL, key, link, e.ID).Scan(&intoID); {
+			case err == nil:
+				into := &Epistle{ID: intoID}
+				if err = into.fetch(tx); err != nil {
+					return err
+				}
+
+				if err = merge(tx, e.ID, into.ID); err != nil {
+					return err
+				}
+
+				// Keep the identity of the epistle being merged into
+				e.ID, e.Link, e.Normalized, e.Created = into.ID, into.Link, into.Normalized, into.Created
+				if !e.Normalized.Valid {
+					e.Normalized = sql.NullString{Valid: true, String: key}
+				}
+			case errors.Is(err, sql.ErrNoRows):
+				e.Link = link
+				e.Normalized = sql.NullString{Valid: true, String: key}
+			default:
+				return err
+			}
+		}
+	}
+
+	if err = e.save(tx); err != nil {
+		return err
+	}
+
+	if e.content != nil {
+		if err = newContent(e.ID, e.content).save(tx); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
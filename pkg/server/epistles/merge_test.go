@@ -0,0 +1,84 @@
+package epistles
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bbengfort/epistolary/pkg/server/config"
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveCanonical(t *testing.T) {
+	require.NoError(t, db.Connect(config.DatabaseConfig{Testing: true}))
+	t.Cleanup(func() { db.Close() })
+	mock := db.Mock()
+
+	// Expects the epistle to be saved with the link and normalized key
+	expectSave := func(link, key string) {
+		args := make([]driver.Value, 0, 16)
+		args = append(args, int64(42), link, key)
+		for i := 0; i < 13; i++ {
+			args = append(args, sqlmock.AnyArg())
+		}
+		mock.ExpectQuery(regexp.QuoteMeta(saveEpistleSQL)).WithArgs(args...).WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow(nil, nil))
+	}
+
+	newEpistle := func(canonical string) *Epistle {
+		return &Epistle{
+			ID:         42,
+			Link:       "https://example.com/article?id=42",
+			Normalized: sql.NullString{Valid: true, String: "example.com/article?id=42"},
+			Canonical:  sql.NullString{Valid: true, String: canonical},
+		}
+	}
+
+	// A canonical link on another host is not trusted to detect duplicates
+	mock.ExpectBegin()
+	expectSave("https://example.com/article?id=42", "example.com/article?id=42")
+	mock.ExpectCommit()
+
+	e := newEpistle("https://attacker.com/article")
+	require.NoError(t, e.saveCanonical(context.Background()))
+	require.Equal(t, "https://example.com/article?id=42", e.Link)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// A canonical link on the same site replaces the link if it is not a duplicate
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(epistleIDByKeySQL)).WithArgs("www.example.com/posts/article", "https://www.example.com/posts/article", int64(42)).WillReturnError(sql.ErrNoRows)
+	expectSave("https://www.example.com/posts/article", "www.example.com/posts/article")
+	mock.ExpectCommit()
+
+	e = newEpistle("https://www.example.com/posts/article")
+	require.NoError(t, e.saveCanonical(context.Background()))
+	require.Equal(t, "https://www.example.com/posts/article", e.Link)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDedupeCanonical(t *testing.T) {
+	require.NoError(t, db.Connect(config.DatabaseConfig{Testing: true}))
+	t.Cleanup(func() { db.Close() })
+	mock := db.Mock()
+
+	// Epistles are grouped by a canonical link on the same site but not by a canonical
+	// link on another site, even if another epistle has that link.
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(dedupeEpistlesSQL)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "link", "canonical", "normalized"}).
+			AddRow(1, "https://attacker.com/article", nil, nil).
+			AddRow(2, "https://example.com/article?id=42", "https://attacker.com/article", nil).
+			AddRow(3, "https://www.example.com/posts/article", nil, nil).
+			AddRow(4, "https://example.com/article?id=7", "https://www.example.com/posts/article", nil))
+	mock.ExpectRollback()
+
+	groups, err := Dedupe(context.Background(), true)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.Equal(t, int64(3), groups[0].Into)
+	require.Equal(t, []int64{4}, groups[0].Merged)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,183 @@
+package epistles
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+)
+
+// MaxNoteLength is the maximum number of bytes in the body or highlight of a note.
+const MaxNoteLength = 64 * 1024
+
+// Database model for a note that a user has made on their reading of an epistle. The
+// body of the note is markdown and the highlight is optional text quoted from the
+// epistle that the note refers to.
+type Note struct {
+	ID        int64
+	EpistleID int64
+	UserID    int64
+	Body      string
+	Highlight sql.NullString
+	Created   time.Time
+	Modified  time.Time
+}
+
+// Validate the note, trimming whitespace from the body and highlight. A note must have
+// either a body or a highlight (e.g. a highlight without any commentary).
+func (n *Note) Validate() error {
+	n.Body = strings.TrimSpace(n.Body)
+	n.Highlight.String = strings.TrimSpace(n.Highlight.String)
+	n.Highlight.Valid = n.Highlight.String != ""
+
+	if n.Body == "" && !n.Highlight.Valid {
+		return ErrEmptyNote
+	}
+
+	if len(n.Body) > MaxNoteLength || len(n.Highlight.String) > MaxNoteLength {
+		return ErrNoteTooLong
+	}
+	return nil
+}
+
+const (
+	readingExistsSQL = "SELECT EXISTS(SELECT 1 FROM reading WHERE epistle_id=$1 AND user_id=$2 AND deleted IS NULL)"
+	listNotesSQL     = "SELECT id, body, highlight, created, modified FROM notes WHERE epistle_id=$1 AND user_id=$2 ORDER BY created, id"
+)
+
+// ListNotes returns all of the notes on the user's reading in the order they were
+// created. Returns sql.ErrNoRows if the user does not have the reading.
+func ListNotes(ctx context.Context, epistleID, userID int64) (notes []*Note, err error) {
+	if epistleID == 0 || userID == 0 {
+		return nil, ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err = tx.QueryRow(readingExistsSQL, epistleID, userID).Scan(&exists); err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(listNotesSQL, epistleID, userID); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes = make([]*Note, 0)
+	for rows.Next() {
+		note := &Note{EpistleID: epistleID, UserID: userID}
+		if err = rows.Scan(&note.ID, &note.Body, &note.Highlight, &note.Created, &note.Modified); err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return notes, nil
+}
+
+const (
+	createNoteSQL = "INSERT INTO notes (epistle_id, user_id, body, highlight) SELECT epistle_id, user_id, $3, $4 FROM reading WHERE epistle_id=$1 AND user_id=$2 AND deleted IS NULL RETURNING id, created, modified"
+)
+
+// Create the note on the user's reading. Returns sql.ErrNoRows if the user does not
+// have the reading.
+func (n *Note) Create(ctx context.Context) (err error) {
+	if n.EpistleID == 0 || n.UserID == 0 {
+		return ErrIDRequired
+	}
+
+	if err = n.Validate(); err != nil {
+		return err
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow(createNoteSQL, n.EpistleID, n.UserID, n.Body, n.Highlight).Scan(&n.ID, &n.Created, &n.Modified); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const (
+	updateNoteSQL = "UPDATE notes n SET body=$4, highlight=$5 FROM reading r WHERE n.id=$1 AND n.epistle_id=$2 AND n.user_id=$3 AND r.epistle_id=n.epistle_id AND r.user_id=n.user_id AND r.deleted IS NULL RETURNING n.created, n.modified"
+)
+
+// Update the body and highlight of the note. Returns sql.ErrNoRows if the note does not
+// exist on the user's reading.
+func (n *Note) Update(ctx context.Context) (err error) {
+	if n.ID == 0 || n.EpistleID == 0 || n.UserID == 0 {
+		return ErrIDRequired
+	}
+
+	if err = n.Validate(); err != nil {
+		return err
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow(updateNoteSQL, n.ID, n.EpistleID, n.UserID, n.Body, n.Highlight).Scan(&n.Created, &n.Modified); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const (
+	deleteNoteSQL = "DELETE FROM notes n USING reading r WHERE n.id=$1 AND n.epistle_id=$2 AND n.user_id=$3 AND r.epistle_id=n.epistle_id AND r.user_id=n.user_id AND r.deleted IS NULL"
+)
+
+// DeleteNote removes the note from the user's reading. Returns sql.ErrNoRows if the
+// note does not exist on the user's reading.
+func DeleteNote(ctx context.Context, noteID, epistleID, userID int64) (err error) {
+	if noteID == 0 || epistleID == 0 || userID == 0 {
+		return ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(deleteNoteSQL, noteID, epistleID, userID); err != nil {
+		return err
+	}
+
+	var nRows int64
+	if nRows, err = result.RowsAffected(); err != nil {
+		return err
+	}
+
+	if nRows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,212 @@
+package epistles
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+)
+
+// Sync jobs that fail are retried with exponential backoff starting at SyncBackoff and
+// doubling with each attempt until MaxSyncBackoff is reached.
+const (
+	SyncBackoff    = 30 * time.Second
+	MaxSyncBackoff = 6 * time.Hour
+)
+
+// SyncJob is a durable request to sync the metadata of an epistle in the background.
+type SyncJob struct {
+	ID        int64
+	EpistleID int64
+	Attempts  int64
+}
+
+// Backoff returns the delay before a job that has failed the specified number of
+// attempts is retried.
+func Backoff(attempts int64) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+
+	delay := SyncBackoff
+	for i := int64(1); i < attempts; i++ {
+		if delay *= 2; delay >= MaxSyncBackoff {
+			return MaxSyncBackoff
+		}
+	}
+	return delay
+}
+
+const (
+	enqueueSyncSQL = "INSERT INTO sync_jobs (epistle_id) VALUES ($1) ON CONFLICT (epistle_id) DO NOTHING"
+)
+
+// EnqueueSync adds a job to sync the epistle to the queue if one is not already pending.
+func EnqueueSync(ctx context.Context, epistleID int64) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = enqueueSync(tx, epistleID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func enqueueSync(tx *sql.Tx, epistleID int64) (err error) {
+	if epistleID == 0 {
+		return ErrIDRequired
+	}
+
+	if _, err = tx.Exec(enqueueSyncSQL, epistleID); err != nil {
+		return err
+	}
+	return nil
+}
+
+const (
+	enqueueStaleSQL = "INSERT INTO sync_jobs (epistle_id) SELECT e.id FROM epistles e WHERE COALESCE(e.synced, e.modified) < $1 AND NOT EXISTS (SELECT 1 FROM sync_jobs j WHERE j.epistle_id=e.id) ORDER BY COALESCE(e.synced, e.modified) LIMIT $2 ON CONFLICT (epistle_id) DO NOTHING"
+)
+
+// EnqueueStale queues sync jobs for up to limit epistles that have not been synced
+// since the stale timestamp, oldest first, including epistles whose sync previously
+// failed. Returns the number of jobs that were queued.
+func EnqueueStale(ctx context.Context, stale time.Time, limit int) (n int64, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(enqueueStaleSQL, stale, limit); err != nil {
+		return 0, err
+	}
+
+	if n, err = result.RowsAffected(); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+const (
+	nextSyncJobSQL  = "UPDATE sync_jobs SET attempts=attempts+1, locked_until=$1 WHERE id=(SELECT id FROM sync_jobs WHERE run_after <= NOW() AND (locked_until IS NULL OR locked_until < NOW()) ORDER BY run_after, id LIMIT 1 FOR UPDATE SKIP LOCKED) RETURNING id, epistle_id, attempts"
+	syncAttemptsSQL = "UPDATE epistles SET sync_attempts=$2 WHERE id=$1"
+)
+
+// NextSyncJob leases the next job that is ready to run for the specified duration and
+// counts the attempt on the job and its epistle. If the job is not completed or failed
+// before the lease expires, it can be leased by another worker. Returns sql.ErrNoRows
+// if there are no jobs ready to run.
+func NextSyncJob(ctx context.Context, lease time.Duration) (job *SyncJob, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	job = &SyncJob{}
+	if err = tx.QueryRow(nextSyncJobSQL, time.Now().Add(lease)).Scan(&job.ID, &job.EpistleID, &job.Attempts); err != nil {
+		return nil, err
+	}
+
+	if _, err = tx.Exec(syncAttemptsSQL, job.EpistleID, job.Attempts); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Run syncs the epistle of the job, completing the job if the sync succeeds or failing
+// it if the sync does not so that it is retried. Returns true if the epistle was
+// synced; an error is only returned if the job could not be updated in the queue.
+func (j *SyncJob) Run(ctx context.Context, maxAttempts int64) (synced bool, err error) {
+	var epistle *Epistle
+	if epistle, err = Get(ctx, j.EpistleID); err == nil {
+		err = epistle.Sync(ctx)
+	}
+
+	if err != nil {
+		if _, err = j.Fail(ctx, err, maxAttempts); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+
+	if err = j.Complete(ctx); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+const (
+	deleteSyncJobSQL = "DELETE FROM sync_jobs WHERE id=$1"
+)
+
+// Complete removes the job from the queue once the epistle has been synced.
+func (j *SyncJob) Complete(ctx context.Context) (err error) {
+	if j.ID == 0 {
+		return ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(deleteSyncJobSQL, j.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const (
+	retrySyncJobSQL = "UPDATE sync_jobs SET run_after=$2, locked_until=NULL, last_error=$3 WHERE id=$1"
+	syncErrorSQL    = "UPDATE epistles SET sync_error=$2 WHERE id=$1"
+)
+
+// Fail records the sync error on the epistle and schedules the job to be retried with
+// exponential backoff. Once the job has been attempted maxAttempts times it is removed
+// from the queue; the error remains on the epistle. Returns true if the job will be
+// retried.
+func (j *SyncJob) Fail(ctx context.Context, cause error, maxAttempts int64) (retry bool, err error) {
+	if j.ID == 0 || j.EpistleID == 0 {
+		return false, ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(syncErrorSQL, j.EpistleID, cause.Error()); err != nil {
+		return false, err
+	}
+
+	if retry = j.Attempts < maxAttempts; retry {
+		if _, err = tx.Exec(retrySyncJobSQL, j.ID, time.Now().Add(Backoff(j.Attempts)), cause.Error()); err != nil {
+			return false, err
+		}
+	} else {
+		if _, err = tx.Exec(deleteSyncJobSQL, j.ID); err != nil {
+			return false, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+	return retry, nil
+}
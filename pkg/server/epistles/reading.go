@@ -3,12 +3,14 @@ package epistles
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/bbengfort/epistolary/pkg/server/db"
 	"github.com/bbengfort/epistolary/pkg/server/users"
 	"github.com/bbengfort/epistolary/pkg/utils/pagination"
+	"github.com/bbengfort/epistolary/pkg/utils/urlnorm"
 	"github.com/lib/pq"
 )
 
@@ -30,6 +32,7 @@ type Reading struct {
 	Started   sql.NullTime
 	Finished  sql.NullTime
 	Archived  sql.NullTime
+	Deleted   sql.NullTime
 	Created   time.Time
 	Modified  time.Time
 	epistle   *Epistle
@@ -50,12 +53,17 @@ func (r Reading) status() Status {
 }
 
 const (
-	createReadingSQL = "INSERT INTO reading (epistle_id, user_id) VALUES ($1, $2)"
-	readingTSSQL     = "SELECT created, modified FROM reading WHERE epistle_id=$1 AND user_id=$2"
+	clearTombstoneSQL = "DELETE FROM reading WHERE epistle_id=$1 AND user_id=$2 AND deleted IS NOT NULL"
+	createReadingSQL  = "INSERT INTO reading (epistle_id, user_id) VALUES ($1, $2)"
+	readingTSSQL      = "SELECT created, modified FROM reading WHERE epistle_id=$1 AND user_id=$2 AND deleted IS NULL"
 )
 
 // Create a reading for a user with a link.
 func Create(ctx context.Context, userID int64, link string) (r *Reading, err error) {
+	if link, err = urlnorm.Normalize(link); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLink, err)
+	}
+
 	r = &Reading{UserID: userID}
 	var tx *sql.Tx
 	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
@@ -69,6 +77,12 @@ func Create(ctx context.Context, userID int64, link string) (r *Reading, err err
 	}
 	r.EpistleID = r.epistle.ID
 
+	// If the user deleted this reading but it has not been purged yet, clear the
+	// tombstone so that the reading is created fresh rather than restored.
+	if _, err = tx.Exec(clearTombstoneSQL, r.EpistleID, r.UserID); err != nil {
+		return nil, err
+	}
+
 	// Insert the reading into the database
 	if _, err = tx.Exec(createReadingSQL, r.EpistleID, r.UserID); err != nil {
 		// Handle unique constraint violated error
@@ -83,23 +97,38 @@ func Create(ctx context.Context, userID int64, link string) (r *Reading, err err
 		return nil, err
 	}
 
+	// Queue the epistle to be synced in the background if it has not been synced yet
+	if !r.epistle.IsSynced() {
+		if err = enqueueSync(tx, r.EpistleID); err != nil {
+			return nil, err
+		}
+	}
+
 	tx.Commit()
 	return r, nil
 }
 
 const (
-	countReadingSQL = "SELECT count(epistle_id) FROM reading WHERE user_id=$1"
-	listReadingSQL  = "SELECT r.epistle_id, r.status, e.id, e.link, e.title, e.favicon, r.created, r.modified FROM reading r JOIN epistles e ON r.epistle_id=e.id"
+	countReadingSQL = "SELECT count(epistle_id) FROM reading WHERE user_id=$1 AND deleted IS NULL"
+	listReadingSQL  = "SELECT r.epistle_id, r.status, r.started, r.finished, r.archived, r.created, r.modified, e.id, e.link, e.title, e.description, e.favicon, e.site_name, e.author, e.published, e.image, e.canonical FROM reading r JOIN epistles e ON r.epistle_id=e.id"
 )
 
-// List readings for the specified user.
-func List(ctx context.Context, userID int64, prevPage *pagination.Cursor) (r []*Reading, cursor *pagination.Cursor, err error) {
-	if prevPage == nil {
-		prevPage = pagination.New(0, 0, 0)
+// List readings for the specified user that match the filter in the order specified by
+// the filter. If filter is nil then all readings are returned by most recently created.
+// If a previous page cursor is specified, it must have been created with the same
+// filter, otherwise pagination.ErrTokenQueryMismatch is returned.
+func List(ctx context.Context, userID int64, filter *Filter, prevPage *pagination.Cursor) (r []*Reading, cursor *pagination.Cursor, err error) {
+	if filter == nil {
+		filter = &Filter{}
+	}
+
+	if err = filter.Validate(); err != nil {
+		return nil, nil, err
 	}
 
-	if prevPage.Size <= 0 {
-		return nil, nil, ErrMissingPageSize
+	fingerprint := filter.Fingerprint()
+	if prevPage, err = pagination.Bind(prevPage, fingerprint); err != nil {
+		return nil, nil, err
 	}
 
 	var tx *sql.Tx
@@ -112,27 +141,26 @@ func List(ctx context.Context, userID int64, prevPage *pagination.Cursor) (r []*
 	var query strings.Builder
 	query.WriteString(listReadingSQL)
 
-	params := make([]any, 0, 3)
-	where := make([]string, 0, 2)
+	params := make([]any, 0, 10)
+	where := make([]string, 0, 8)
 
 	params = append(params, sql.Named("userID", userID))
-	where = append(where, "r.user_id=:userID")
+	where = append(where, "r.user_id=:userID", "r.deleted IS NULL")
 
-	if prevPage.End != 0 {
-		params = append(params, sql.Named("endIndex", prevPage.End))
-		where = append(where, "r.epistle_id < :endIndex")
-	}
+	fwhere, fparams := filter.where()
+	where = append(where, fwhere...)
+	params = append(params, fparams...)
 
 	// Add the where clause to the query
 	query.WriteString(" WHERE ")
 	query.WriteString(strings.Join(where, " AND "))
 
-	// Sort results by descending created timestamp
-	query.WriteString(" ORDER BY created DESC")
+	// Sort results by the filter's sort field
+	query.WriteString(filter.orderBy())
 
 	// Add the limit as the page size + 1 to perform a has next page check
-	params = append(params, sql.Named("pageSize", prevPage.Size+1))
-	query.WriteString(" LIMIT :pageSize")
+	params = append(params, sql.Named("pageSize", prevPage.Size+1), sql.Named("offset", prevPage.End))
+	query.WriteString(" LIMIT :pageSize OFFSET :offset")
 
 	// Prep the query to convert named arguments into positional arguments
 	qs, args := db.Prep(query.String(), params...)
@@ -161,12 +189,21 @@ func List(ctx context.Context, userID int64, prevPage *pagination.Cursor) (r []*
 		if err = rows.Scan(
 			&reading.EpistleID,
 			&reading.Status,
+			&reading.Started,
+			&reading.Finished,
+			&reading.Archived,
+			&reading.Created,
+			&reading.Modified,
 			&epistle.ID,
 			&epistle.Link,
 			&epistle.Title,
+			&epistle.Description,
 			&epistle.Favicon,
-			&reading.Created,
-			&reading.Modified); err != nil {
+			&epistle.SiteName,
+			&epistle.Author,
+			&epistle.Published,
+			&epistle.Image,
+			&epistle.Canonical); err != nil {
 			return nil, nil, err
 		}
 
@@ -180,14 +217,16 @@ func List(ctx context.Context, userID int64, prevPage *pagination.Cursor) (r []*
 
 	tx.Commit()
 
+	// Readings are paginated by offset since they can be sorted by any field.
 	if len(r) > 0 && nRows > prevPage.Size {
-		cursor = pagination.New(r[0].EpistleID, r[len(r)-1].EpistleID, prevPage.Size)
+		cursor = pagination.New(prevPage.End, prevPage.End+int64(len(r)), prevPage.Size)
+		cursor.Filter = fingerprint
 	}
 	return r, cursor, nil
 }
 
 const (
-	fetchReadingSQL = "SELECT r.status, r.started, r.finished, r.archived, r.created, r.modified, e.link, e.title, e.description, e.favicon, e.created, e.modified FROM reading r JOIN epistles e ON r.epistle_id=e.id WHERE r.epistle_id=$1 AND r.user_id=$2"
+	fetchReadingSQL = "SELECT r.status, r.started, r.finished, r.archived, r.created, r.modified, e.link, e.title, e.description, e.favicon, e.site_name, e.author, e.published, e.image, e.canonical, e.created, e.modified FROM reading r JOIN epistles e ON r.epistle_id=e.id WHERE r.epistle_id=$1 AND r.user_id=$2 AND r.deleted IS NULL"
 )
 
 func Fetch(ctx context.Context, epistleID, userID int64) (reading *Reading, err error) {
@@ -215,6 +254,11 @@ func Fetch(ctx context.Context, epistleID, userID int64) (reading *Reading, err
 		&epistle.Title,
 		&epistle.Description,
 		&epistle.Favicon,
+		&epistle.SiteName,
+		&epistle.Author,
+		&epistle.Published,
+		&epistle.Image,
+		&epistle.Canonical,
 		&epistle.Created,
 		&epistle.Modified); err != nil {
 		return nil, err
@@ -226,7 +270,7 @@ func Fetch(ctx context.Context, epistleID, userID int64) (reading *Reading, err
 
 const (
 	updateEpistleSQL = "UPDATE epistles SET title=$2, description=$3 WHERE id=$1"
-	updateReadingSQL = "UPDATE reading SET status=$3, started=$4, finished=$5, archived=$6 WHERE epistle_id=$1 AND user_id=$2"
+	updateReadingSQL = "UPDATE reading SET status=$3, started=$4, finished=$5, archived=$6 WHERE epistle_id=$1 AND user_id=$2 AND deleted IS NULL"
 )
 
 func Update(ctx context.Context, r *Reading, e *Epistle) (err error) {
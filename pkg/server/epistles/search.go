@@ -0,0 +1,116 @@
+package epistles
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/utils/pagination"
+)
+
+const (
+	searchReadingSQL = "SELECT r.epistle_id, r.status, r.started, r.finished, r.archived, r.created, r.modified, e.id, e.link, e.title, e.description, e.favicon, e.site_name, e.author, e.published, e.image, e.canonical, ts_rank(e.search, q.query) AS rank FROM reading r JOIN epistles e ON r.epistle_id=e.id, (SELECT websearch_to_tsquery('english', :query) || websearch_to_tsquery('simple', :query) AS query) q"
+)
+
+// Search the readings of the specified user with a full-text query over the title,
+// description, and link of the epistle. Results are ordered by their rank and are
+// paginated by offset in the same manner as List. If a previous page cursor is
+// specified it must have been created with the same search query.
+func Search(ctx context.Context, userID int64, query string, prevPage *pagination.Cursor) (r []*Reading, cursor *pagination.Cursor, err error) {
+	if query = strings.TrimSpace(query); query == "" {
+		return nil, nil, ErrMissingQuery
+	}
+
+	fingerprint := pagination.Fingerprint(query)
+	if prevPage, err = pagination.Bind(prevPage, fingerprint); err != nil {
+		return nil, nil, err
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	// Build paramaterized query with the full-text match in the WHERE clause
+	var sqlq strings.Builder
+	sqlq.WriteString(searchReadingSQL)
+	sqlq.WriteString(" WHERE r.user_id=:userID AND r.deleted IS NULL AND e.search @@ q.query")
+
+	// Sort by rank then by most recently created to break ties
+	sqlq.WriteString(" ORDER BY rank DESC, r.created DESC, r.epistle_id DESC")
+
+	// Add the limit as the page size + 1 to perform a has next page check
+	sqlq.WriteString(" LIMIT :pageSize OFFSET :offset")
+
+	params := []any{
+		sql.Named("query", query),
+		sql.Named("userID", userID),
+		sql.Named("pageSize", prevPage.Size+1),
+		sql.Named("offset", prevPage.End),
+	}
+
+	// Prep the query to convert named arguments into positional arguments
+	qs, args := db.Prep(sqlq.String(), params...)
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(qs, args...); err != nil {
+		return nil, nil, err
+	}
+
+	nRows := uint32(0)
+	r = make([]*Reading, 0, prevPage.Size)
+	defer rows.Close()
+	for rows.Next() {
+		// The query will request one additional message past the page size to check if
+		// there is a next page. No rows should be processed after the page size.
+		nRows++
+		if nRows > prevPage.Size {
+			continue
+		}
+
+		var rank float64
+		reading := &Reading{
+			UserID: userID,
+		}
+		epistle := &Epistle{}
+
+		if err = rows.Scan(
+			&reading.EpistleID,
+			&reading.Status,
+			&reading.Started,
+			&reading.Finished,
+			&reading.Archived,
+			&reading.Created,
+			&reading.Modified,
+			&epistle.ID,
+			&epistle.Link,
+			&epistle.Title,
+			&epistle.Description,
+			&epistle.Favicon,
+			&epistle.SiteName,
+			&epistle.Author,
+			&epistle.Published,
+			&epistle.Image,
+			&epistle.Canonical,
+			&rank); err != nil {
+			return nil, nil, err
+		}
+
+		reading.epistle = epistle
+		r = append(r, reading)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	tx.Commit()
+
+	if len(r) > 0 && nRows > prevPage.Size {
+		cursor = pagination.New(prevPage.End, prevPage.End+int64(len(r)), prevPage.Size)
+		cursor.Filter = fingerprint
+	}
+	return r, cursor, nil
+}
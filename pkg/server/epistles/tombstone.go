@@ -0,0 +1,154 @@
+package epistles
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+)
+
+// TombstoneDuration is the window after a reading is deleted in which the delete can
+// be undone by restoring the reading. Once the window has passed the reading is purged
+// and any epistle that is no longer read by any user is garbage collected.
+const TombstoneDuration = 30 * time.Minute
+
+const (
+	deleteReadingSQL  = "UPDATE reading SET deleted=$3 WHERE epistle_id=$1 AND user_id=$2 AND deleted IS NULL"
+	restoreReadingSQL = "UPDATE reading SET deleted=NULL WHERE epistle_id=$1 AND user_id=$2 AND deleted IS NOT NULL AND deleted > $3"
+)
+
+// Delete a reading for the specified user by marking it with a tombstone. The reading
+// is no longer returned by List or Fetch but can be restored until the tombstone
+// expires. Returns sql.ErrNoRows if the user has no reading for the epistle.
+func Delete(ctx context.Context, epistleID, userID int64) (deleted time.Time, err error) {
+	if epistleID == 0 || userID == 0 {
+		return deleted, ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return deleted, err
+	}
+	defer tx.Rollback()
+
+	deleted = time.Now()
+	var result sql.Result
+	if result, err = tx.Exec(deleteReadingSQL, epistleID, userID, deleted); err != nil {
+		return deleted, err
+	}
+
+	var nRows int64
+	if nRows, err = result.RowsAffected(); err != nil {
+		return deleted, err
+	}
+
+	if nRows == 0 {
+		return deleted, sql.ErrNoRows
+	}
+
+	if err = tx.Commit(); err != nil {
+		return deleted, err
+	}
+	return deleted, nil
+}
+
+// Restore a tombstoned reading for the specified user so long as the tombstone has not
+// expired. Returns sql.ErrNoRows if there is no reading that can be restored.
+func Restore(ctx context.Context, epistleID, userID int64) (reading *Reading, err error) {
+	if epistleID == 0 || userID == 0 {
+		return nil, ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(restoreReadingSQL, epistleID, userID, time.Now().Add(-TombstoneDuration)); err != nil {
+		return nil, err
+	}
+
+	var nRows int64
+	if nRows, err = result.RowsAffected(); err != nil {
+		return nil, err
+	}
+
+	if nRows == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	reading = &Reading{
+		EpistleID: epistleID,
+		UserID:    userID,
+	}
+	epistle := &Epistle{
+		ID: epistleID,
+	}
+	if err = tx.QueryRow(fetchReadingSQL, epistleID, userID).Scan(
+		&reading.Status,
+		&reading.Started,
+		&reading.Finished,
+		&reading.Archived,
+		&reading.Created,
+		&reading.Modified,
+		&epistle.Link,
+		&epistle.Title,
+		&epistle.Description,
+		&epistle.Favicon,
+		&epistle.SiteName,
+		&epistle.Author,
+		&epistle.Published,
+		&epistle.Image,
+		&epistle.Canonical,
+		&epistle.Created,
+		&epistle.Modified); err != nil {
+		return nil, err
+	}
+	reading.epistle = epistle
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return reading, nil
+}
+
+const (
+	purgeReadingsSQL = "DELETE FROM reading WHERE deleted IS NOT NULL AND deleted <= $1"
+	purgeEpistlesSQL = "DELETE FROM epistles e WHERE NOT EXISTS (SELECT 1 FROM reading r WHERE r.epistle_id=e.id)"
+)
+
+// Purge permanently deletes all readings whose tombstone has expired and then garbage
+// collects any epistles that are no longer being read by any user. The number of
+// readings and epistles that were deleted is returned for logging.
+func Purge(ctx context.Context) (readings, epistles int64, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(purgeReadingsSQL, time.Now().Add(-TombstoneDuration)); err != nil {
+		return 0, 0, err
+	}
+
+	if readings, err = result.RowsAffected(); err != nil {
+		return 0, 0, err
+	}
+
+	if result, err = tx.Exec(purgeEpistlesSQL); err != nil {
+		return 0, 0, err
+	}
+
+	if epistles, err = result.RowsAffected(); err != nil {
+		return 0, 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+	return readings, epistles, nil
+}
@@ -0,0 +1,104 @@
+package epistles
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bbengfort/epistolary/pkg/server/config"
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestore(t *testing.T) {
+	require.NoError(t, db.Connect(config.DatabaseConfig{Testing: true}))
+	t.Cleanup(func() { db.Close() })
+	mock := db.Mock()
+
+	// A reading deleted inside of the tombstone window is restored
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(restoreReadingSQL)).
+		WithArgs(int64(42), int64(7), restorable(now.Add(-20*time.Minute))).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(fetchReadingSQL)).
+		WithArgs(int64(42), int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "started", "finished", "archived", "created", "modified", "link", "title", "description", "favicon", "site_name", "author", "published", "image", "canonical", "created", "modified"}).
+			AddRow("queued", nil, nil, nil, now, now, "https://example.com/article", "An Article", nil, nil, nil, nil, nil, nil, nil, now, now))
+	mock.ExpectCommit()
+
+	reading, err := Restore(context.Background(), 42, 7)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), reading.EpistleID)
+	require.Equal(t, "https://example.com/article", reading.epistle.Link)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// A reading deleted outside of the tombstone window is not restored
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(restoreReadingSQL)).
+		WithArgs(int64(42), int64(7), expired(now.Add(-40*time.Minute))).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err = Restore(context.Background(), 42, 7)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurge(t *testing.T) {
+	require.NoError(t, db.Connect(config.DatabaseConfig{Testing: true}))
+	t.Cleanup(func() { db.Close() })
+	mock := db.Mock()
+
+	// Only readings whose tombstone has expired are purged before the epistles that
+	// are no longer read by any user are garbage collected.
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(purgeReadingsSQL)).
+		WithArgs(expired(now.Add(-40 * time.Minute))).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(purgeEpistlesSQL)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	readings, epistles, err := Purge(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(3), readings)
+	require.Equal(t, int64(2), epistles)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// A reading deleted inside of the tombstone window is not purged
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(purgeReadingsSQL)).
+		WithArgs(restorable(now.Add(-20 * time.Minute))).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(purgeEpistlesSQL)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	_, _, err = Purge(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// Matches the tombstone cutoff if a reading deleted at the given time is still inside
+// of the window, e.g. the reading is restorable and should not be purged.
+type restorable time.Time
+
+func (d restorable) Match(v driver.Value) bool {
+	cutoff, ok := v.(time.Time)
+	return ok && time.Time(d).After(cutoff)
+}
+
+// Matches the tombstone cutoff if a reading deleted at the given time is outside of
+// the window, e.g. the reading can no longer be restored and should be purged.
+type expired time.Time
+
+func (d expired) Match(v driver.Value) bool {
+	cutoff, ok := v.(time.Time)
+	return ok && !time.Time(d).After(cutoff)
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/epistles"
+	"github.com/bbengfort/epistolary/pkg/server/exports"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/gin-gonic/gin"
+)
+
+const exportTimeout = 5 * time.Minute
+
+// Export streams all of the user's readings in the format specified by the format
+// query parameter (JSON Lines by default) as a file attachment. Because the export is
+// streamed, errors that occur after the export has started truncate the file.
+func (s *Server) Export(c *gin.Context) {
+	var (
+		err    error
+		userID int64
+		query  *api.ExportQuery
+		format exports.Format
+	)
+
+	query = &api.ExportQuery{}
+	if err = c.BindQuery(query); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse export query"))
+		return
+	}
+
+	if format, err = exports.ParseFormat(query.Format); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	// Large libraries take longer to stream than the server write timeout allows
+	if err = http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(exportTimeout)); err != nil {
+		sentry.Warn(c).Err(err).Msg("could not extend export deadline")
+	}
+
+	var w exports.Writer
+	if w, err = exports.NewWriter(format, c.Writer); err != nil {
+		sentry.Error(c).Err(err).Msg("could not create export writer")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not export readings"))
+		return
+	}
+
+	filename := fmt.Sprintf("epistolary-%s%s", time.Now().Format("20060102"), format.Extension())
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	var exported int
+	if err = epistles.Each(c.Request.Context(), userID, func(r *epistles.Reading, tags []string) (err error) {
+		var epistle *epistles.Epistle
+		if epistle, err = r.Epistle(c.Request.Context(), false); err != nil {
+			return err
+		}
+
+		exported++
+		return w.Write(exports.NewRecord(r, epistle, tags))
+	}); err != nil {
+		sentry.Error(c).Err(err).Int("exported", exported).Msg("could not export readings")
+		c.Error(err)
+		return
+	}
+
+	if err = w.Close(); err != nil {
+		sentry.Error(c).Err(err).Msg("could not finish export")
+		c.Error(err)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Package exports writes a user's library of readings in formats that can be imported by
+other read-it-later services, browsers, and feed readers so that users can get their
+data out of Epistolary.
+*/
+package exports
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/epistles"
+)
+
+// Format is the file type of an export.
+type Format string
+
+const (
+	JSONLines    Format = "jsonl"
+	CSV          Format = "csv"
+	NetscapeHTML Format = "netscape"
+	OPML         Format = "opml"
+)
+
+// Formats lists all of the formats that a library can be exported in.
+var Formats = []Format{JSONLines, CSV, NetscapeHTML, OPML}
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// ParseFormat returns the format with the specified name; JSON Lines is the default.
+func ParseFormat(s string) (Format, error) {
+	switch s = strings.ToLower(strings.TrimSpace(s)); s {
+	case "":
+		return JSONLines, nil
+	case "json", "ndjson":
+		return JSONLines, nil
+	case "html", "bookmarks":
+		return NetscapeHTML, nil
+	}
+
+	for _, format := range Formats {
+		if s == string(format) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q", ErrUnknownFormat, s)
+}
+
+// ContentType returns the mime type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case JSONLines:
+		return "application/x-ndjson"
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case NetscapeHTML:
+		return "text/html; charset=utf-8"
+	case OPML:
+		return "text/x-opml; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Extension returns the file extension of the format, including the leading dot.
+func (f Format) Extension() string {
+	switch f {
+	case JSONLines:
+		return ".jsonl"
+	case CSV:
+		return ".csv"
+	case NetscapeHTML:
+		return ".html"
+	case OPML:
+		return ".opml"
+	default:
+		return ""
+	}
+}
+
+// Record is a reading in the export with the metadata of its epistle.
+type Record struct {
+	Link        string     `json:"link"`
+	Title       string     `json:"title,omitempty"`
+	Description string     `json:"description,omitempty"`
+	SiteName    string     `json:"site_name,omitempty"`
+	Author      string     `json:"author,omitempty"`
+	Image       string     `json:"image,omitempty"`
+	Published   *time.Time `json:"published,omitempty"`
+	Status      string     `json:"status"`
+	Tags        []string   `json:"tags"`
+	Started     *time.Time `json:"started,omitempty"`
+	Finished    *time.Time `json:"finished,omitempty"`
+	Archived    *time.Time `json:"archived,omitempty"`
+	Created     time.Time  `json:"created"`
+	Modified    time.Time  `json:"modified"`
+}
+
+// NewRecord creates an export record from a reading whose epistle has been fetched.
+func NewRecord(r *epistles.Reading, e *epistles.Epistle, tags []string) *Record {
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return &Record{
+		Link:        e.Link,
+		Title:       e.Title.String,
+		Description: e.Description.String,
+		SiteName:    e.SiteName.String,
+		Author:      e.Author.String,
+		Image:       e.Image.String,
+		Published:   nullTime(e.Published.Valid, e.Published.Time),
+		Status:      string(r.Status),
+		Tags:        tags,
+		Started:     nullTime(r.Started.Valid, r.Started.Time),
+		Finished:    nullTime(r.Finished.Valid, r.Finished.Time),
+		Archived:    nullTime(r.Archived.Valid, r.Archived.Time),
+		Created:     r.Created,
+		Modified:    r.Modified,
+	}
+}
+
+func nullTime(valid bool, ts time.Time) *time.Time {
+	if !valid || ts.IsZero() {
+		return nil
+	}
+	return &ts
+}
+
+// Writer writes records to an export; Close must be called to finish the export.
+type Writer interface {
+	Write(*Record) error
+	Close() error
+}
+
+// NewWriter returns a writer that writes records in the format to w.
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case JSONLines:
+		return &jsonlWriter{enc: json.NewEncoder(w)}, nil
+	case CSV:
+		return newCSVWriter(w), nil
+	case NetscapeHTML:
+		return newNetscapeWriter(w), nil
+	case OPML:
+		return newOPMLWriter(w), nil
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+// JSON Lines exports contain one JSON record per line.
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func (w *jsonlWriter) Write(r *Record) error {
+	return w.enc.Encode(r)
+}
+
+func (w *jsonlWriter) Close() error {
+	return nil
+}
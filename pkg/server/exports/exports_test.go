@@ -0,0 +1,155 @@
+package exports_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/exports"
+	"github.com/stretchr/testify/require"
+)
+
+func records() []*exports.Record {
+	created := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	finished := time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	return []*exports.Record{
+		{
+			Link:        "https://example.com/unread",
+			Title:       "Unread & Unloved",
+			Description: "A <great> article",
+			Status:      "queued",
+			Tags:        []string{"go", "web"},
+			Created:     created,
+			Modified:    created,
+		},
+		{
+			Link:     "https://example.com/read",
+			Status:   "finished",
+			Tags:     []string{},
+			Started:  &finished,
+			Finished: &finished,
+			Created:  created,
+			Modified: finished,
+		},
+	}
+}
+
+func export(t *testing.T, format exports.Format, records []*exports.Record) string {
+	var buf bytes.Buffer
+	w, err := exports.NewWriter(format, &buf)
+	require.NoError(t, err)
+
+	for _, record := range records {
+		require.NoError(t, w.Write(record))
+	}
+	require.NoError(t, w.Close())
+	return buf.String()
+}
+
+func TestParseFormat(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected exports.Format
+	}{
+		{"", exports.JSONLines},
+		{"jsonl", exports.JSONLines},
+		{"JSON", exports.JSONLines},
+		{"csv", exports.CSV},
+		{"netscape", exports.NetscapeHTML},
+		{"html", exports.NetscapeHTML},
+		{" opml ", exports.OPML},
+	}
+
+	for _, tc := range testCases {
+		format, err := exports.ParseFormat(tc.in)
+		require.NoError(t, err, "could not parse %q", tc.in)
+		require.Equal(t, tc.expected, format)
+	}
+
+	_, err := exports.ParseFormat("xlsx")
+	require.ErrorIs(t, err, exports.ErrUnknownFormat)
+}
+
+func TestJSONLines(t *testing.T) {
+	out := export(t, exports.JSONLines, records())
+
+	var lines int
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		record := &exports.Record{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), record))
+		require.Equal(t, records()[lines], record)
+		lines++
+	}
+	require.Equal(t, 2, lines)
+}
+
+func TestCSV(t *testing.T) {
+	rows, err := csv.NewReader(strings.NewReader(export(t, exports.CSV, records()))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	require.Equal(t, "url", rows[0][0])
+	require.Equal(t, []string{"https://example.com/unread", "Unread & Unloved", "A <great> article", "", "", "", "", "queued", "go|web", "", "", "", "2023-01-01T12:00:00Z", "2023-01-01T12:00:00Z"}, rows[1])
+	require.Equal(t, "2023-01-02T12:00:00Z", rows[2][10])
+
+	// Cells that would be evaluated as a formula are escaped
+	rows, err = csv.NewReader(strings.NewReader(export(t, exports.CSV, []*exports.Record{
+		{
+			Link:        "https://example.com/formula",
+			Title:       "=HYPERLINK(\"https://attacker.com\")",
+			Description: "+1 great article",
+			SiteName:    "-Example",
+			Author:      "@jane",
+			Tags:        []string{"=cmd", "web"},
+		},
+	}))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, []string{"https://example.com/formula", "'=HYPERLINK(\"https://attacker.com\")", "'+1 great article", "'-Example", "'@jane", "", "", "", "'=cmd|web"}, rows[1][:9])
+
+	// The header is written even if there are no records
+	rows, err = csv.NewReader(strings.NewReader(export(t, exports.CSV, nil))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+}
+
+func TestNetscapeHTML(t *testing.T) {
+	out := export(t, exports.NetscapeHTML, records())
+	require.True(t, strings.HasPrefix(out, "<!DOCTYPE NETSCAPE-Bookmark-file-1>"))
+	require.Contains(t, out, `<DT><A HREF="https://example.com/unread" ADD_DATE="1672574400" LAST_MODIFIED="1672574400" TAGS="go,web" TOREAD="1">Unread &amp; Unloved</A>`)
+	require.Contains(t, out, `<DD>A &lt;great&gt; article`)
+	require.Contains(t, out, `TOREAD="0">https://example.com/read</A>`)
+	require.True(t, strings.HasSuffix(out, "</DL><p>\n"))
+}
+
+func TestOPML(t *testing.T) {
+	out := export(t, exports.OPML, records())
+
+	doc := struct {
+		XMLName  xml.Name `xml:"opml"`
+		Version  string   `xml:"version,attr"`
+		Title    string   `xml:"head>title"`
+		Outlines []struct {
+			Text     string `xml:"text,attr"`
+			URL      string `xml:"url,attr"`
+			Category string `xml:"category,attr"`
+		} `xml:"body>outline"`
+	}{}
+
+	require.NoError(t, xml.Unmarshal([]byte(out), &doc))
+	require.Equal(t, "2.0", doc.Version)
+	require.Equal(t, "Epistolary Export", doc.Title)
+	require.Len(t, doc.Outlines, 2)
+	require.Equal(t, "Unread & Unloved", doc.Outlines[0].Text)
+	require.Equal(t, "/go,/web", doc.Outlines[0].Category)
+	require.Equal(t, "https://example.com/read", doc.Outlines[1].Text)
+
+	// An empty export is still a valid document
+	require.NoError(t, xml.Unmarshal([]byte(export(t, exports.OPML, nil)), &doc))
+}
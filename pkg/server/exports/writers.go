@@ -0,0 +1,224 @@
+package exports
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+// CSV exports have a header row and tags separated by a pipe like Pocket CSV exports.
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+var csvHeader = []string{"url", "title", "description", "site_name", "author", "image", "published", "status", "tags", "started", "finished", "archived", "created", "modified"}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (w *csvWriter) Write(r *Record) (err error) {
+	if !w.header {
+		if err = w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.header = true
+	}
+
+	return w.w.Write([]string{
+		csvEscape(r.Link),
+		csvEscape(r.Title),
+		csvEscape(r.Description),
+		csvEscape(r.SiteName),
+		csvEscape(r.Author),
+		csvEscape(r.Image),
+		formatTime(r.Published),
+		r.Status,
+		csvEscape(strings.Join(r.Tags, "|")),
+		formatTime(r.Started),
+		formatTime(r.Finished),
+		formatTime(r.Archived),
+		r.Created.Format(time.RFC3339),
+		r.Modified.Format(time.RFC3339),
+	})
+}
+
+func (w *csvWriter) Close() error {
+	// Write the header even if there are no records
+	if !w.header {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// Prefixes cells that spreadsheet applications would evaluate as a formula with a
+// single quote so that an exported title or description cannot run a formula.
+func csvEscape(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func formatTime(ts *time.Time) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.Format(time.RFC3339)
+}
+
+// Netscape bookmark exports can be imported by browsers and most bookmarking services.
+// Readings that are queued or started are marked to read.
+type netscapeWriter struct {
+	w      *bufio.Writer
+	header bool
+}
+
+const netscapeHeader = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file.
+     It will be read and overwritten.
+     DO NOT EDIT! -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+`
+
+func newNetscapeWriter(w io.Writer) *netscapeWriter {
+	return &netscapeWriter{w: bufio.NewWriter(w)}
+}
+
+func (w *netscapeWriter) writeHeader() (err error) {
+	if !w.header {
+		w.header = true
+		_, err = w.w.WriteString(netscapeHeader)
+	}
+	return err
+}
+
+func (w *netscapeWriter) Write(r *Record) (err error) {
+	if err = w.writeHeader(); err != nil {
+		return err
+	}
+
+	toread := "0"
+	if r.Finished == nil && r.Archived == nil {
+		toread = "1"
+	}
+
+	title := r.Title
+	if title == "" {
+		title = r.Link
+	}
+
+	if _, err = fmt.Fprintf(w.w, "    <DT><A HREF=\"%s\" ADD_DATE=\"%d\" LAST_MODIFIED=\"%d\" TAGS=\"%s\" TOREAD=\"%s\">%s</A>\n",
+		html.EscapeString(r.Link), r.Created.Unix(), r.Modified.Unix(), html.EscapeString(strings.Join(r.Tags, ",")), toread, html.EscapeString(title),
+	); err != nil {
+		return err
+	}
+
+	if r.Description != "" {
+		if _, err = fmt.Fprintf(w.w, "    <DD>%s\n", html.EscapeString(r.Description)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *netscapeWriter) Close() (err error) {
+	if err = w.writeHeader(); err != nil {
+		return err
+	}
+
+	if _, err = w.w.WriteString("</DL><p>\n"); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+// OPML exports contain an outline of links with the tags of each reading as categories.
+type opmlWriter struct {
+	w      io.Writer
+	enc    *xml.Encoder
+	header bool
+}
+
+type opmlOutline struct {
+	XMLName     xml.Name `xml:"outline"`
+	Type        string   `xml:"type,attr"`
+	Text        string   `xml:"text,attr"`
+	URL         string   `xml:"url,attr"`
+	Description string   `xml:"description,attr,omitempty"`
+	Status      string   `xml:"status,attr,omitempty"`
+	Category    string   `xml:"category,attr,omitempty"`
+	Created     string   `xml:"created,attr"`
+}
+
+func newOPMLWriter(w io.Writer) *opmlWriter {
+	enc := xml.NewEncoder(w)
+	enc.Indent("    ", "  ")
+	return &opmlWriter{w: w, enc: enc}
+}
+
+func (w *opmlWriter) writeHeader() (err error) {
+	if w.header {
+		return nil
+	}
+	w.header = true
+
+	_, err = fmt.Fprintf(w.w, "%s<opml version=\"2.0\">\n  <head>\n    <title>Epistolary Export</title>\n    <dateCreated>%s</dateCreated>\n  </head>\n  <body>", xml.Header, time.Now().UTC().Format(time.RFC1123Z))
+	return err
+}
+
+func (w *opmlWriter) Write(r *Record) (err error) {
+	if err = w.writeHeader(); err != nil {
+		return err
+	}
+
+	outline := &opmlOutline{
+		Type:        "link",
+		Text:        r.Title,
+		URL:         r.Link,
+		Description: r.Description,
+		Status:      r.Status,
+		Created:     r.Created.UTC().Format(time.RFC1123Z),
+	}
+
+	if outline.Text == "" {
+		outline.Text = r.Link
+	}
+
+	// OPML categories are comma separated slash-delimited paths
+	if len(r.Tags) > 0 {
+		categories := make([]string, 0, len(r.Tags))
+		for _, tag := range r.Tags {
+			categories = append(categories, "/"+tag)
+		}
+		outline.Category = strings.Join(categories, ",")
+	}
+
+	return w.enc.Encode(outline)
+}
+
+func (w *opmlWriter) Close() (err error) {
+	if err = w.writeHeader(); err != nil {
+		return err
+	}
+
+	if err = w.enc.Flush(); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w.w, "\n  </body>\n</opml>\n")
+	return err
+}
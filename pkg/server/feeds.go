@@ -0,0 +1,245 @@
+package server
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/epistles"
+	"github.com/bbengfort/epistolary/pkg/server/feeds"
+	"github.com/bbengfort/epistolary/pkg/server/users"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/gin-gonic/gin"
+)
+
+// ListFeedTokens returns the user's feed tokens; the secrets of the tokens and the URLs
+// of their feeds are only available when the token is issued.
+func (s *Server) ListFeedTokens(c *gin.Context) {
+	var (
+		err    error
+		userID int64
+		tokens []*feeds.Token
+	)
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if tokens, err = feeds.List(c.Request.Context(), userID); err != nil {
+		sentry.Error(c).Err(err).Msg("could not list feed tokens from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch feed tokens"))
+		return
+	}
+
+	out := &api.FeedTokenList{
+		Tokens: make([]*api.FeedToken, 0, len(tokens)),
+	}
+
+	for _, token := range tokens {
+		out.Tokens = append(out.Tokens, &api.FeedToken{
+			ID:       token.ID,
+			LastUsed: api.Timestamp{Time: token.LastUsed.Time},
+			Created:  api.Timestamp{Time: token.Created},
+		})
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateFeedToken issues a new feed token and returns the URLs of the user's feeds.
+func (s *Server) CreateFeedToken(c *gin.Context) {
+	var (
+		err    error
+		userID int64
+		token  *feeds.Token
+	)
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if token, err = feeds.Issue(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, feeds.ErrTooManyTokens) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not issue feed token")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not create feed token"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, &api.FeedToken{
+		ID:      token.ID,
+		Token:   token.Secret,
+		AtomURL: s.feedURL(token.Secret, "atom"),
+		RSSURL:  s.feedURL(token.Secret, "rss"),
+		Created: api.Timestamp{Time: token.Created},
+	})
+}
+
+// RevokeFeedToken deletes the feed token so that its feeds can no longer be read.
+func (s *Server) RevokeFeedToken(c *gin.Context) {
+	var (
+		err     error
+		userID  int64
+		tokenID int64
+	)
+
+	if tokenID, err = strconv.ParseInt(c.Param("tokenID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("feed token not found"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if err = feeds.Revoke(c.Request.Context(), tokenID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("feed token not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not revoke feed token")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not revoke feed token"))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// AtomFeed returns the user's reading list as an Atom feed.
+func (s *Server) AtomFeed(c *gin.Context) {
+	s.readingFeed(c, "atom")
+}
+
+// RSSFeed returns the user's reading list as an RSS 2.0 feed.
+func (s *Server) RSSFeed(c *gin.Context) {
+	s.readingFeed(c, "rss")
+}
+
+// Feeds are authenticated by the feed token in the URL rather than an access token so
+// that they can be followed by feed readers. Readings can be filtered by status using
+// the status query parameter, which can be specified multiple times or comma separated.
+func (s *Server) readingFeed(c *gin.Context, kind string) {
+	var (
+		err     error
+		token   *feeds.Token
+		user    *users.User
+		entries []*feeds.Entry
+	)
+
+	if token, err = feeds.Authenticate(c.Request.Context(), c.Param("token")); err != nil {
+		if errors.Is(err, feeds.ErrInvalidToken) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("feed not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not authenticate feed token")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if user, err = users.UserFromID(c.Request.Context(), token.UserID); err != nil {
+		sentry.Error(c).Err(err).Msg("could not fetch feed user from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	// Feed tokens of disabled users no longer serve their reading list
+	if user.IsDisabled() {
+		c.JSON(http.StatusNotFound, api.ErrorResponse("feed not found"))
+		return
+	}
+
+	var status []epistles.Status
+	for _, param := range c.QueryArray("status") {
+		for _, item := range strings.Split(param, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				status = append(status, epistles.Status(item))
+			}
+		}
+	}
+
+	if entries, err = feeds.Entries(c.Request.Context(), token.UserID, status); err != nil {
+		if errors.Is(err, epistles.ErrInvalidStatus) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not list feed entries from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	author := user.Username
+	if user.FullName.Valid && user.FullName.String != "" {
+		author = user.FullName.String
+	}
+
+	link := s.feedURL(c.Param("token"), kind)
+	if c.Request.URL.RawQuery != "" {
+		link += "?" + c.Request.URL.RawQuery
+	}
+
+	feed := &feeds.Feed{
+		ID:      link,
+		Title:   fmt.Sprintf("%s's Reading List", author),
+		Link:    link,
+		Author:  author,
+		Updated: user.Created,
+		Entries: entries,
+	}
+
+	for _, entry := range entries {
+		if entry.Updated.After(feed.Updated) {
+			feed.Updated = entry.Updated
+		}
+	}
+
+	// Render the feed before writing so that an error can still be returned
+	var (
+		buf         bytes.Buffer
+		contentType string
+	)
+
+	switch kind {
+	case "atom":
+		err, contentType = feed.WriteAtom(&buf), feeds.AtomContentType
+	default:
+		err, contentType = feed.WriteRSS(&buf), feeds.RSSContentType
+	}
+
+	if err != nil {
+		sentry.Error(c).Err(err).Str("kind", kind).Msg("could not render feed")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Header("Last-Modified", feed.Updated.UTC().Format(http.TimeFormat))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// Returns the absolute URL of a feed, which is served by the API at the token issuer.
+func (s *Server) feedURL(token, kind string) string {
+	base, err := url.Parse(s.conf.Token.Issuer)
+	if err != nil {
+		base = &url.URL{}
+	}
+	return base.ResolveReference(&url.URL{Path: "/v1/feed/" + url.PathEscape(token) + "/" + kind}).String()
+}
@@ -0,0 +1,199 @@
+/*
+Package feeds publishes a user's reading list as private Atom and RSS 2.0 feeds that
+are authenticated by a per-user feed token in the URL so that feed readers can follow
+what a user has queued or finished reading.
+*/
+package feeds
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// MaxEntries is the number of most recently modified readings included in a feed.
+const MaxEntries = 50
+
+// Content types of the feeds.
+const (
+	AtomContentType = "application/atom+xml; charset=utf-8"
+	RSSContentType  = "application/rss+xml; charset=utf-8"
+)
+
+// Feed is a format-independent representation of a reading list feed.
+type Feed struct {
+	ID      string
+	Title   string
+	Link    string // the URL of the feed itself
+	Author  string
+	Updated time.Time
+	Entries []*Entry
+}
+
+// Entry is a single reading in a feed.
+type Entry struct {
+	ID         string
+	Title      string
+	Link       string
+	Summary    string
+	Author     string
+	Status     string
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+}
+
+//===========================================================================
+// Atom
+//===========================================================================
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Updated string       `xml:"updated"`
+	Links   []atomLink   `xml:"link"`
+	Author  *atomPerson  `xml:"author,omitempty"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    string         `xml:"summary,omitempty"`
+}
+
+// WriteAtom writes the feed as an Atom 1.0 document.
+func (f *Feed) WriteAtom(w io.Writer) (err error) {
+	feed := &atomFeed{
+		ID:      f.ID,
+		Title:   f.Title,
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Links:   []atomLink{{Href: f.Link, Rel: "self", Type: "application/atom+xml"}},
+		Entries: make([]*atomEntry, 0, len(f.Entries)),
+	}
+
+	if f.Author != "" {
+		feed.Author = &atomPerson{Name: f.Author}
+	}
+
+	for _, e := range f.Entries {
+		entry := &atomEntry{
+			ID:      e.ID,
+			Title:   e.Title,
+			Links:   []atomLink{{Href: e.Link, Rel: "alternate"}},
+			Updated: e.Updated.UTC().Format(time.RFC3339),
+			Summary: e.Summary,
+		}
+
+		if !e.Published.IsZero() {
+			entry.Published = e.Published.UTC().Format(time.RFC3339)
+		}
+
+		if e.Author != "" {
+			entry.Author = &atomPerson{Name: e.Author}
+		}
+
+		for _, term := range e.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: term})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return encode(w, feed)
+}
+
+//===========================================================================
+// RSS 2.0
+//===========================================================================
+
+type rssFeed struct {
+	XMLName xml.Name    `xml:"rss"`
+	Version string      `xml:"version,attr"`
+	Atom    string      `xml:"xmlns:atom,attr"`
+	Channel *rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate"`
+	AtomLink      atomLink   `xml:"atom:link"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description,omitempty"`
+}
+
+// WriteRSS writes the feed as an RSS 2.0 document.
+func (f *Feed) WriteRSS(w io.Writer) (err error) {
+	feed := &rssFeed{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: &rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Title,
+			LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+			AtomLink:      atomLink{Href: f.Link, Rel: "self", Type: "application/rss+xml"},
+			Items:         make([]*rssItem, 0, len(f.Entries)),
+		},
+	}
+
+	for _, e := range f.Entries {
+		feed.Channel.Items = append(feed.Channel.Items, &rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			GUID:        rssGUID{Value: e.ID},
+			PubDate:     e.Updated.UTC().Format(time.RFC1123Z),
+			Categories:  e.Categories,
+			Description: e.Summary,
+		})
+	}
+
+	return encode(w, feed)
+}
+
+func encode(w io.Writer, v interface{}) (err error) {
+	if _, err = io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err = enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
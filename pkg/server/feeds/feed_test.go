@@ -0,0 +1,108 @@
+package feeds_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/feeds"
+	"github.com/stretchr/testify/require"
+)
+
+func feed() *feeds.Feed {
+	updated := time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC)
+	return &feeds.Feed{
+		ID:      "https://api.epistolary.app/v1/feed/secret/atom",
+		Title:   "Jane Doe's Reading List",
+		Link:    "https://api.epistolary.app/v1/feed/secret/atom",
+		Author:  "Jane Doe",
+		Updated: updated,
+		Entries: []*feeds.Entry{
+			{
+				ID:         "urn:epistolary:reading:1:2",
+				Title:      "Hello & Goodbye",
+				Link:       "https://example.com/hello",
+				Summary:    "A <short> greeting",
+				Author:     "John Smith",
+				Status:     "finished",
+				Categories: []string{"go", "web"},
+				Published:  time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+				Updated:    updated,
+			},
+		},
+	}
+}
+
+func TestAtom(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, feed().WriteAtom(&buf))
+
+	doc := struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Title   string   `xml:"title"`
+		Updated string   `xml:"updated"`
+		Link    struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID    string `xml:"id"`
+			Title string `xml:"title"`
+			Link  struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+			Published  string `xml:"published"`
+			Author     string `xml:"author>name"`
+			Summary    string `xml:"summary"`
+			Categories []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+		} `xml:"entry"`
+	}{}
+
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, "Jane Doe's Reading List", doc.Title)
+	require.Equal(t, "2023-01-02T12:00:00Z", doc.Updated)
+	require.Equal(t, "self", doc.Link.Rel)
+	require.Len(t, doc.Entries, 1)
+
+	entry := doc.Entries[0]
+	require.Equal(t, "urn:epistolary:reading:1:2", entry.ID)
+	require.Equal(t, "Hello & Goodbye", entry.Title)
+	require.Equal(t, "https://example.com/hello", entry.Link.Href)
+	require.Equal(t, "2023-01-01T12:00:00Z", entry.Published)
+	require.Equal(t, "John Smith", entry.Author)
+	require.Equal(t, "A <short> greeting", entry.Summary)
+	require.Len(t, entry.Categories, 2)
+}
+
+func TestRSS(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, feed().WriteRSS(&buf))
+	require.Contains(t, buf.String(), `<atom:link href="https://api.epistolary.app/v1/feed/secret/atom" rel="self" type="application/rss+xml"></atom:link>`)
+
+	doc := struct {
+		XMLName xml.Name `xml:"rss"`
+		Version string   `xml:"version,attr"`
+		Channel struct {
+			Title         string `xml:"title"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title      string   `xml:"title"`
+				Link       string   `xml:"link"`
+				GUID       string   `xml:"guid"`
+				PubDate    string   `xml:"pubDate"`
+				Categories []string `xml:"category"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}{}
+
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, "2.0", doc.Version)
+	require.Equal(t, "Mon, 02 Jan 2023 12:00:00 +0000", doc.Channel.LastBuildDate)
+	require.Len(t, doc.Channel.Items, 1)
+	require.Equal(t, "urn:epistolary:reading:1:2", doc.Channel.Items[0].GUID)
+	require.Equal(t, []string{"go", "web"}, doc.Channel.Items[0].Categories)
+}
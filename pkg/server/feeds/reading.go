@@ -0,0 +1,64 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bbengfort/epistolary/pkg/server/epistles"
+	"github.com/bbengfort/epistolary/pkg/server/tags"
+	"github.com/bbengfort/epistolary/pkg/utils/pagination"
+)
+
+// DefaultStatus are the statuses of the readings in a feed if no status is specified.
+var DefaultStatus = []epistles.Status{epistles.StatusQueued, epistles.StatusFinished}
+
+// Entries returns the most recently modified readings of the user with the specified
+// statuses as feed entries, most recent first.
+func Entries(ctx context.Context, userID int64, status []epistles.Status) (entries []*Entry, err error) {
+	if len(status) == 0 {
+		status = DefaultStatus
+	}
+
+	filter := &epistles.Filter{Status: status, Sort: epistles.SortModified}
+
+	var readings []*epistles.Reading
+	if readings, _, err = epistles.List(ctx, userID, filter, pagination.New(0, 0, MaxEntries)); err != nil {
+		return nil, err
+	}
+
+	epistleIDs := make([]int64, 0, len(readings))
+	for _, reading := range readings {
+		epistleIDs = append(epistleIDs, reading.EpistleID)
+	}
+
+	var readingTags map[int64][]string
+	if readingTags, err = tags.ForReadings(ctx, userID, epistleIDs...); err != nil {
+		return nil, err
+	}
+
+	entries = make([]*Entry, 0, len(readings))
+	for _, reading := range readings {
+		var epistle *epistles.Epistle
+		if epistle, err = reading.Epistle(ctx, false); err != nil {
+			return nil, err
+		}
+
+		entry := &Entry{
+			ID:         fmt.Sprintf("urn:epistolary:reading:%d:%d", userID, reading.EpistleID),
+			Title:      epistle.Title.String,
+			Link:       epistle.Link,
+			Summary:    epistle.Description.String,
+			Author:     epistle.Author.String,
+			Status:     string(reading.Status),
+			Categories: readingTags[reading.EpistleID],
+			Published:  reading.Created,
+			Updated:    reading.Modified,
+		}
+
+		if entry.Title == "" {
+			entry.Title = epistle.Link
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,174 @@
+package feeds
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/utils/secrets"
+)
+
+// MaxTokens is the maximum number of feed tokens a user can have at once.
+const MaxTokens = 16
+
+// Number of random bytes in a feed token secret.
+const secretLength = 24
+
+var (
+	ErrIDRequired    = errors.New("a feed token id is required")
+	ErrInvalidToken  = errors.New("invalid feed token")
+	ErrTooManyTokens = errors.New("too many feed tokens, revoke an unused token first")
+)
+
+// Token authenticates requests for the private feeds of a user. The secret is only
+// available when the token is issued; only its hash is stored in the database.
+type Token struct {
+	ID       int64
+	UserID   int64
+	Secret   string
+	LastUsed sql.NullTime
+	Created  time.Time
+	Modified time.Time
+}
+
+const (
+	countTokensSQL = "SELECT count(id) FROM feed_tokens WHERE user_id=$1"
+	issueTokenSQL  = "INSERT INTO feed_tokens (user_id, token) VALUES ($1, $2) RETURNING id, created, modified"
+)
+
+// Issue a new feed token for the user. The secret of the returned token must be given
+// to the user since it cannot be recovered.
+func Issue(ctx context.Context, userID int64) (t *Token, err error) {
+	if userID == 0 {
+		return nil, ErrIDRequired
+	}
+
+	t = &Token{UserID: userID}
+	if t.Secret, err = secrets.New(secretLength); err != nil {
+		return nil, err
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var count int64
+	if err = tx.QueryRow(countTokensSQL, userID).Scan(&count); err != nil {
+		return nil, err
+	}
+
+	if count >= MaxTokens {
+		return nil, ErrTooManyTokens
+	}
+
+	if err = tx.QueryRow(issueTokenSQL, userID, secrets.Hash(t.Secret)).Scan(&t.ID, &t.Created, &t.Modified); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+const (
+	listTokensSQL = "SELECT id, last_used, created, modified FROM feed_tokens WHERE user_id=$1 ORDER BY created, id"
+)
+
+// List the feed tokens of the user, oldest first. The secrets of the tokens are empty.
+func List(ctx context.Context, userID int64) (tokens []*Token, err error) {
+	if userID == 0 {
+		return nil, ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(listTokensSQL, userID); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens = make([]*Token, 0)
+	for rows.Next() {
+		t := &Token{UserID: userID}
+		if err = rows.Scan(&t.ID, &t.LastUsed, &t.Created, &t.Modified); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return tokens, nil
+}
+
+const (
+	revokeTokenSQL = "DELETE FROM feed_tokens WHERE id=$1 AND user_id=$2"
+)
+
+// Revoke the user's feed token so that its feeds can no longer be accessed. Returns
+// sql.ErrNoRows if the user does not have the token.
+func Revoke(ctx context.Context, tokenID, userID int64) (err error) {
+	if tokenID == 0 || userID == 0 {
+		return ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(revokeTokenSQL, tokenID, userID); err != nil {
+		return err
+	}
+
+	if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+const (
+	authenticateSQL = "UPDATE feed_tokens SET last_used=NOW() WHERE token=$1 RETURNING id, user_id, last_used, created, modified"
+)
+
+// Authenticate looks up the feed token with the secret and records that it was used.
+// Returns ErrInvalidToken if the token does not exist or has been revoked.
+func Authenticate(ctx context.Context, secret string) (t *Token, err error) {
+	if secret == "" {
+		return nil, ErrInvalidToken
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	t = &Token{}
+	if err = tx.QueryRow(authenticateSQL, secrets.Hash(secret)).Scan(&t.ID, &t.UserID, &t.LastUsed, &t.Created, &t.Modified); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
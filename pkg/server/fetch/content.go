@@ -0,0 +1,413 @@
+package fetch
+
+import (
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// WordsPerMinute is the average adult reading speed used to estimate reading time.
+const WordsPerMinute = 238
+
+// Content is the main body of an article extracted from a web page in reader mode so
+// that it can be read without the navigation, ads, and comments of the page and so
+// that it can be read offline after the link has gone away. The HTML is sanitized and
+// only contains basic formatting tags with links and images resolved to absolute URLs.
+type Content struct {
+	HTML        string `json:"html"`
+	Text        string `json:"text"`
+	WordCount   int    `json:"word_count"`
+	ReadingTime int    `json:"reading_time"` // estimated reading time in minutes
+}
+
+// Elements that never contain article content and are removed with their children.
+const removeSelector = "script, style, noscript, iframe, object, embed, form, button, input, select, textarea, svg, canvas, template, link, meta, nav, header, footer, aside, [hidden], [aria-hidden=true], [role=navigation], [role=complementary], [role=banner]"
+
+// Elements whose text is scored to find the container of the main content.
+const scoreSelector = "p, pre, td, blockquote"
+
+var (
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cookie|disqus|extra|foot|header|legends|menu|modal|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|ad-break|agegate|pagination|pager|popup|promo|newsletter`)
+	maybeCandidates    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveClass      = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	negativeClass      = regexp.MustCompile(`(?i)hidden|banner|combx|comment|com-|contact|foot|footer|footnote|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget|ad-`)
+	whitespace         = regexp.MustCompile(`[ \t\r\f\v]+`)
+	blankLines         = regexp.MustCompile(`\n\s*\n+`)
+)
+
+// ExtractContent finds the main content of the page using a readability-style scoring
+// of the paragraphs in the document and returns it as sanitized HTML and plain text.
+// Relative links and images are resolved against the base URL. The tree is not
+// modified so metadata can be parsed from it before or after extraction. Returns nil
+// if no content could be found in the document.
+func ExtractContent(tree *goquery.Document, base *url.URL) *Content {
+	doc := goquery.CloneDocument(tree)
+	doc.Find(removeSelector).Remove()
+
+	// Remove elements that are unlikely to be content based on their class and id
+	doc.Find("body *").Each(func(_ int, item *goquery.Selection) {
+		switch goquery.NodeName(item) {
+		case "html", "body", "article", "main", "a":
+			return
+		}
+
+		match := item.AttrOr("class", "") + " " + item.AttrOr("id", "")
+		if unlikelyCandidates.MatchString(match) && !maybeCandidates.MatchString(match) {
+			item.Remove()
+		}
+	})
+
+	article := topCandidate(doc)
+	if article == nil {
+		return nil
+	}
+
+	content := &Content{}
+	sanitizer := &sanitizer{base: base}
+	for _, node := range article {
+		sanitizer.write(node)
+	}
+
+	content.HTML = strings.TrimSpace(sanitizer.html.String())
+	content.Text = cleanText(sanitizer.text.String())
+	if content.Text == "" {
+		return nil
+	}
+
+	content.WordCount = len(strings.Fields(content.Text))
+	content.ReadingTime = ReadingTime(content.WordCount)
+	return content
+}
+
+// ReadingTime estimates the number of minutes it takes to read the number of words,
+// rounded up to the nearest minute.
+func ReadingTime(words int) int {
+	if words <= 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(words) / WordsPerMinute))
+}
+
+// Scores the paragraphs of the document, adding the score to their parent and half
+// the score to their grandparent, then returns the nodes of the candidate with the
+// highest score along with any siblings that look like they are part of the article.
+func topCandidate(doc *goquery.Document) []*html.Node {
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+
+	addScore := func(node *html.Node, score float64) {
+		if node == nil || node.Type != html.ElementNode {
+			return
+		}
+
+		if _, ok := scores[node]; !ok {
+			scores[node] = initialScore(node)
+			candidates = append(candidates, node)
+		}
+		scores[node] += score
+	}
+
+	doc.Find(scoreSelector).Each(func(_ int, item *goquery.Selection) {
+		text := strings.TrimSpace(item.Text())
+		length := utf8.RuneCountInString(text)
+		if length < 25 {
+			return
+		}
+
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(length)/100, 3)
+		node := item.Get(0)
+		addScore(node.Parent, score)
+		if node.Parent != nil {
+			addScore(node.Parent.Parent, score/2)
+		}
+	})
+
+	var top *html.Node
+	var topScore float64
+	for _, node := range candidates {
+		score := scores[node] * (1 - linkDensity(goquery.NewDocumentFromNode(node).Selection))
+		scores[node] = score
+		if top == nil || score > topScore {
+			top, topScore = node, score
+		}
+	}
+
+	// If no paragraphs were found, fall back to the body of the page
+	if top == nil {
+		body := doc.Find("body")
+		if body.Length() == 0 || strings.TrimSpace(body.Text()) == "" {
+			return nil
+		}
+		return []*html.Node{body.Get(0)}
+	}
+
+	// Siblings of the top candidate are part of the article if they scored well or
+	// if they are paragraphs with a lot of text and few links.
+	if top.Parent == nil {
+		return []*html.Node{top}
+	}
+
+	threshold := math.Max(10, topScore*0.2)
+	nodes := make([]*html.Node, 0, 1)
+	for sibling := top.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling == top {
+			nodes = append(nodes, sibling)
+			continue
+		}
+
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+
+		if score, ok := scores[sibling]; ok && score >= threshold {
+			nodes = append(nodes, sibling)
+			continue
+		}
+
+		if sibling.Data == "p" {
+			sel := goquery.NewDocumentFromNode(sibling).Selection
+			text := strings.TrimSpace(sel.Text())
+			if length := utf8.RuneCountInString(text); length > 80 && linkDensity(sel) < 0.25 {
+				nodes = append(nodes, sibling)
+			}
+		}
+	}
+	return nodes
+}
+
+// The initial score of a candidate is based on its tag and its class and id names.
+func initialScore(node *html.Node) (score float64) {
+	switch node.Data {
+	case "article", "main":
+		score += 10
+	case "div":
+		score += 5
+	case "pre", "td", "blockquote":
+		score += 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score -= 5
+	}
+
+	for _, attr := range node.Attr {
+		if attr.Key != "class" && attr.Key != "id" {
+			continue
+		}
+
+		if negativeClass.MatchString(attr.Val) {
+			score -= 25
+		}
+
+		if positiveClass.MatchString(attr.Val) {
+			score += 25
+		}
+	}
+	return score
+}
+
+// The ratio of the text in the selection that is inside of links.
+func linkDensity(sel *goquery.Selection) float64 {
+	length := utf8.RuneCountInString(strings.TrimSpace(sel.Text()))
+	if length == 0 {
+		return 0
+	}
+
+	var links int
+	sel.Find("a").Each(func(_ int, a *goquery.Selection) {
+		links += utf8.RuneCountInString(strings.TrimSpace(a.Text()))
+	})
+	return float64(links) / float64(length)
+}
+
+// Collapse runs of whitespace and blank lines in the extracted text.
+func cleanText(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(whitespace.ReplaceAllString(line, " "))
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// Tags that are kept in the sanitized HTML mapped to the attributes they may keep. Any
+// other element is unwrapped so that only its children are written.
+var allowedTags = map[string][]string{
+	"a": {"href", "title"}, "abbr": {"title"}, "article": nil, "b": nil, "blockquote": {"cite"},
+	"br": nil, "caption": nil, "cite": nil, "code": nil, "dd": nil, "del": nil, "details": nil,
+	"dfn": nil, "div": nil, "dl": nil, "dt": nil, "em": nil, "figcaption": nil, "figure": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil, "hr": nil, "i": nil,
+	"img": {"src", "alt", "title", "width", "height"}, "ins": nil, "kbd": nil, "li": nil,
+	"mark": nil, "ol": {"start"}, "p": nil, "pre": nil, "q": {"cite"}, "s": nil, "samp": nil,
+	"section": nil, "small": nil, "strong": nil, "sub": nil, "summary": nil, "sup": nil,
+	"table": nil, "tbody": nil, "td": {"colspan", "rowspan"}, "tfoot": nil,
+	"th": {"colspan", "rowspan"}, "thead": nil, "time": {"datetime"}, "tr": nil, "u": nil,
+	"ul": nil,
+}
+
+// Elements that are followed by a line break in the plain text of the content.
+var blockTags = map[string]bool{
+	"article": true, "blockquote": true, "br": true, "caption": true, "dd": true, "div": true,
+	"dl": true, "dt": true, "figcaption": true, "figure": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "hr": true, "li": true, "ol": true,
+	"p": true, "pre": true, "section": true, "table": true, "tr": true, "ul": true,
+}
+
+// Elements that have no closing tag.
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// Writes the sanitized HTML and plain text of a node tree.
+type sanitizer struct {
+	base *url.URL
+	html strings.Builder
+	text strings.Builder
+}
+
+func (s *sanitizer) write(node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		s.html.WriteString(html.EscapeString(node.Data))
+		s.text.WriteString(node.Data)
+		return
+	case html.ElementNode:
+	default:
+		// Comments and doctypes are dropped
+		if node.Type == html.DocumentNode {
+			s.children(node)
+		}
+		return
+	}
+
+	allowed, ok := allowedTags[node.Data]
+	if !ok {
+		s.children(node)
+		return
+	}
+
+	var attrs []html.Attribute
+	for _, attr := range node.Attr {
+		if !contains(allowed, attr.Key) {
+			continue
+		}
+
+		switch attr.Key {
+		case "href", "src", "cite":
+			if attr.Val = s.resolve(attr.Val); attr.Val == "" {
+				continue
+			}
+		case "width", "height", "colspan", "rowspan", "start":
+			if _, err := strconv.Atoi(attr.Val); err != nil {
+				continue
+			}
+		}
+		attrs = append(attrs, html.Attribute{Key: attr.Key, Val: attr.Val})
+	}
+
+	// Images are often lazy loaded with the source in a data attribute
+	if node.Data == "img" {
+		if !hasAttr(attrs, "src") {
+			if src := s.resolve(lazySource(node)); src != "" {
+				attrs = append(attrs, html.Attribute{Key: "src", Val: src})
+			}
+		}
+
+		if !hasAttr(attrs, "src") {
+			return
+		}
+	}
+
+	s.html.WriteByte('<')
+	s.html.WriteString(node.Data)
+	for _, attr := range attrs {
+		s.html.WriteByte(' ')
+		s.html.WriteString(attr.Key)
+		s.html.WriteString(`="`)
+		s.html.WriteString(html.EscapeString(attr.Val))
+		s.html.WriteByte('"')
+	}
+	s.html.WriteByte('>')
+
+	if voidTags[node.Data] {
+		if blockTags[node.Data] {
+			s.text.WriteByte('\n')
+		}
+		return
+	}
+
+	s.children(node)
+	s.html.WriteString("</")
+	s.html.WriteString(node.Data)
+	s.html.WriteByte('>')
+
+	if blockTags[node.Data] {
+		s.text.WriteByte('\n')
+	}
+}
+
+func (s *sanitizer) children(node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		s.write(child)
+	}
+}
+
+// Resolves the link against the base URL, returning an empty string if the link is not
+// a web or mail link (e.g. javascript: or data: links are removed).
+func (s *sanitizer) resolve(link string) string {
+	if link = strings.TrimSpace(link); link == "" {
+		return ""
+	}
+
+	if strings.HasPrefix(link, "#") {
+		return link
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	if s.base != nil {
+		u = s.base.ResolveReference(u)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "mailto":
+		return u.String()
+	default:
+		return ""
+	}
+}
+
+func lazySource(node *html.Node) string {
+	for _, key := range []string{"data-src", "data-original", "data-lazy-src"} {
+		for _, attr := range node.Attr {
+			if attr.Key == key && attr.Val != "" {
+				return attr.Val
+			}
+		}
+	}
+	return ""
+}
+
+func hasAttr(attrs []html.Attribute, key string) bool {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package fetch_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/bbengfort/epistolary/pkg/server/fetch"
+	"github.com/stretchr/testify/require"
+)
+
+const article = `<html>
+<head><title>Hello World</title><script>var tracking = true;</script></head>
+<body>
+	<nav><a href="/">Home</a> <a href="/about">About</a></nav>
+	<div class="sidebar"><p>Subscribe to our newsletter, it is great, we promise, really.</p></div>
+	<div id="main">
+		<article class="post-content">
+			<h1>Hello World</h1>
+			<p>This is the first paragraph of the article, it has enough words to be scored as content.</p>
+			<p onclick="alert('hi')">The second paragraph <a href="/posts/other" onmouseover="x()">links to another post</a>, and continues on.</p>
+			<img data-src="/images/hello.png" alt="hello">
+			<img src="javascript:alert(1)">
+			<script>alert("inline");</script>
+			<p>A third paragraph, with more commas, makes this, clearly, the main content of the page.</p>
+		</article>
+	</div>
+	<div class="comments"><p>First! This is a comment on the article that should not be included.</p></div>
+	<footer><p>Copyright 2023, Example Incorporated, all rights reserved worldwide.</p></footer>
+</body>
+</html>`
+
+func TestExtractContent(t *testing.T) {
+	base, _ := url.Parse("https://example.com/posts/hello")
+	tree, err := goquery.NewDocumentFromReader(strings.NewReader(article))
+	require.NoError(t, err)
+
+	content := fetch.ExtractContent(tree, base)
+	require.NotNil(t, content)
+
+	require.Contains(t, content.HTML, "<h1>Hello World</h1>")
+	require.Contains(t, content.HTML, `<a href="https://example.com/posts/other">links to another post</a>`)
+	require.Contains(t, content.HTML, `<img alt="hello" src="https://example.com/images/hello.png">`)
+	require.Contains(t, content.Text, "This is the first paragraph of the article")
+
+	for _, unexpected := range []string{"script", "alert", "onclick", "onmouseover", "javascript", "Subscribe", "First!", "Copyright", "About"} {
+		require.NotContains(t, content.HTML, unexpected)
+		require.NotContains(t, content.Text, unexpected)
+	}
+
+	require.Equal(t, len(strings.Fields(content.Text)), content.WordCount)
+	require.Equal(t, 1, content.ReadingTime)
+
+	// The tree should not be modified by extraction
+	require.Equal(t, 2, tree.Find("script").Length())
+}
+
+func TestExtractContentEmpty(t *testing.T) {
+	tree, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><title>Empty</title></head><body><script>x()</script></body></html>`))
+	require.NoError(t, err)
+	require.Nil(t, fetch.ExtractContent(tree, nil))
+}
+
+func TestReadingTime(t *testing.T) {
+	testCases := []struct {
+		words    int
+		expected int
+	}{
+		{0, 0}, {1, 1}, {238, 1}, {239, 2}, {2380, 10},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, fetch.ReadingTime(tc.words), "unexpected reading time for %d words", tc.words)
+	}
+}
@@ -0,0 +1,277 @@
+package fetch
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// MaxFeedSize is the maximum number of bytes read from the body of a feed.
+const MaxFeedSize = 16 * 1024 * 1024
+
+var ErrNotAFeed = errors.New("document is not an rss or atom feed")
+
+// Ensure the FeedFetcher implements the Fetcher interface.
+var _ Fetcher = &FeedFetcher{}
+
+// FetchFeed fetches and parses the RSS or Atom feed at the specified url.
+func FetchFeed(ctx context.Context, url string) (*Feed, error) {
+	fetcher := NewFeedFetcher(url)
+	return fetcher.FetchFeed(ctx)
+}
+
+// FetchFeedConditional fetches the feed only if it has been modified since the ETag or
+// Last-Modified header values returned by a previous fetch. If the feed has not been
+// modified then an HTTPError is returned where NotModified() is true.
+func FetchFeedConditional(ctx context.Context, url, etag, lastModified string) (*Feed, error) {
+	fetcher := NewFeedFetcher(url)
+	fetcher.etag = etag
+	fetcher.lastModified = lastModified
+	return fetcher.FetchFeed(ctx)
+}
+
+// FeedFetcher fetches the items of an RSS, RDF, or Atom feed.
+type FeedFetcher struct {
+	url          string // the url of the feed
+	etag         string // the etag of a previous fetch for a conditional request
+	lastModified string // the last modified header of a previous fetch for a conditional request
+}
+
+// NewFeedFetcher creates a new feed fetcher that can fetch the feed at the specified URL.
+func NewFeedFetcher(url string) *FeedFetcher {
+	return &FeedFetcher{
+		url: url,
+	}
+}
+
+// Fetch implements the Fetcher interface, returning a *Feed.
+func (f *FeedFetcher) Fetch(ctx context.Context) (any, error) {
+	return f.FetchFeed(ctx)
+}
+
+// FetchFeed uses a GET request to retrieve and parse the feed. After a successful fetch
+// the ETag and Last-Modified headers of the response are used to make subsequent
+// fetches conditional.
+func (f *FeedFetcher) FetchFeed(ctx context.Context) (feed *Feed, err error) {
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil); err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(HeaderUserAgent, userAgent)
+	req.Header.Set(HeaderAccept, acceptRSS)
+	req.Header.Set(HeaderAcceptLang, acceptLang)
+	req.Header.Set(HeaderAcceptEncode, acceptEncode)
+	req.Header.Set(HeaderCacheControl, cacheControl)
+	req.Header.Set(HeaderRFC3229, aimType)
+
+	// Make a conditional request if this feed has been fetched before
+	if f.etag != "" {
+		req.Header.Set(HeaderIfNoneMatch, f.etag)
+	}
+
+	if f.lastModified != "" {
+		req.Header.Set(HeaderIfModifiedSince, f.lastModified)
+	}
+
+	var rep *http.Response
+	if rep, err = client.Do(req); err != nil {
+		return nil, err
+	}
+	defer rep.Body.Close()
+
+	// A 304 is returned as an error to signal that there are no new items.
+	if rep.StatusCode < 200 || rep.StatusCode >= 300 {
+		return nil, HTTPError{
+			Status: rep.Status,
+			Code:   rep.StatusCode,
+		}
+	}
+
+	var reader io.ReadCloser
+	if reader, err = decode(rep); err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	if feed, err = ParseFeed(io.LimitReader(reader, MaxFeedSize), rep.Request.URL); err != nil {
+		return nil, err
+	}
+
+	feed.Link = req.URL.String()
+	feed.ETag = rep.Header.Get(HeaderETag)
+	feed.LastModified = rep.Header.Get(HeaderLastModified)
+
+	f.etag, f.lastModified = feed.ETag, feed.LastModified
+	return feed, nil
+}
+
+// Feed is a format-independent representation of an RSS, RDF, or Atom feed.
+type Feed struct {
+	Link         string      `json:"link"`
+	Title        string      `json:"title"`
+	SiteLink     string      `json:"site_link,omitempty"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Items        []*FeedItem `json:"items"`
+}
+
+// FeedItem is a post in a feed. The ID is the guid of the item if it has one, otherwise
+// it is the link of the item.
+type FeedItem struct {
+	ID        string    `json:"id"`
+	Link      string    `json:"link"`
+	Title     string    `json:"title,omitempty"`
+	Published time.Time `json:"published,omitempty"`
+}
+
+// The elements of RSS 2.0, RDF (RSS 1.0), and Atom feeds are decoded into the same
+// structs since encoding/xml matches elements by local name if no namespace is given.
+type xmlFeed struct {
+	XMLName xml.Name
+	Title   string     `xml:"title"`
+	Links   []xmlLink  `xml:"link"`
+	Entries []*xmlItem `xml:"entry"`
+	Channel *xmlFeed   `xml:"channel"`
+	Items   []*xmlItem `xml:"item"`
+}
+
+type xmlLink struct {
+	Href  string `xml:"href,attr"`
+	Rel   string `xml:"rel,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlItem struct {
+	ID        string    `xml:"id"`
+	GUID      string    `xml:"guid"`
+	About     string    `xml:"about,attr"`
+	Title     string    `xml:"title"`
+	Links     []xmlLink `xml:"link"`
+	PubDate   string    `xml:"pubDate"`
+	Published string    `xml:"published"`
+	Updated   string    `xml:"updated"`
+	Date      string    `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+// ParseFeed parses an RSS 2.0, RDF, or Atom feed, resolving relative links against the
+// base url. Items without a link are skipped.
+func ParseFeed(r io.Reader, base *url.URL) (feed *Feed, err error) {
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = charset.NewReaderLabel
+	dec.Strict = false
+
+	doc := &xmlFeed{}
+	if err = dec.Decode(doc); err != nil {
+		return nil, ErrNotAFeed
+	}
+
+	var items []*xmlItem
+	channel := doc
+	switch strings.ToLower(doc.XMLName.Local) {
+	case "feed":
+		items = doc.Entries
+	case "rss":
+		if doc.Channel == nil {
+			return nil, ErrNotAFeed
+		}
+		channel, items = doc.Channel, doc.Channel.Items
+	case "rdf":
+		// RDF items are siblings rather than children of the channel
+		if doc.Channel != nil {
+			channel = doc.Channel
+		}
+		items = doc.Items
+	default:
+		return nil, ErrNotAFeed
+	}
+
+	feed = &Feed{
+		Title:    strings.TrimSpace(channel.Title),
+		SiteLink: resolve(base, alternate(channel.Links)),
+		Items:    make([]*FeedItem, 0, len(items)),
+	}
+
+	for _, item := range items {
+		entry := &FeedItem{
+			Link:  resolve(base, alternate(item.Links)),
+			Title: strings.TrimSpace(item.Title),
+		}
+
+		// RDF items are identified by their rdf:about attribute
+		if entry.Link == "" && item.About != "" {
+			entry.Link = resolve(base, item.About)
+		}
+
+		if entry.Link == "" {
+			continue
+		}
+
+		switch {
+		case strings.TrimSpace(item.GUID) != "":
+			entry.ID = strings.TrimSpace(item.GUID)
+		case strings.TrimSpace(item.ID) != "":
+			entry.ID = strings.TrimSpace(item.ID)
+		default:
+			entry.ID = entry.Link
+		}
+
+		for _, ts := range []string{item.Published, item.PubDate, item.Date, item.Updated} {
+			if published := parseFeedTime(ts); !published.IsZero() {
+				entry.Published = published.UTC()
+				break
+			}
+		}
+
+		feed.Items = append(feed.Items, entry)
+	}
+
+	return feed, nil
+}
+
+// Returns the alternate link from a list of links: RSS links are the text of the link
+// element while Atom links are the href of the link with an alternate or no rel.
+func alternate(links []xmlLink) string {
+	for _, link := range links {
+		if value := strings.TrimSpace(link.Value); value != "" && link.Href == "" {
+			return value
+		}
+
+		if link.Href != "" && (link.Rel == "" || link.Rel == "alternate") {
+			return strings.TrimSpace(link.Href)
+		}
+	}
+	return ""
+}
+
+// RSS uses RFC 822 dates, which are often written with single digit days or four digit
+// years, in addition to the layouts used by metadata.
+var feedTimeLayouts = []string{
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+}
+
+func parseFeedTime(val string) time.Time {
+	if ts := parseTime(val); !ts.IsZero() {
+		return ts
+	}
+
+	val = strings.TrimSpace(val)
+	for _, layout := range feedTimeLayouts {
+		if ts, err := time.Parse(layout, val); err == nil {
+			return ts
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,143 @@
+package fetch_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/fetch"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFeed(t *testing.T) {
+	base, _ := url.Parse("https://example.com/feed.xml")
+
+	testCases := []struct {
+		name     string
+		xml      string
+		expected *fetch.Feed
+	}{
+		{
+			"rss",
+			`<?xml version="1.0" encoding="UTF-8"?>
+			<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+				<channel>
+					<title> Example Blog </title>
+					<atom:link href="https://example.com/feed.xml" rel="self" type="application/rss+xml"/>
+					<link>https://example.com/</link>
+					<item>
+						<title>Second Post</title>
+						<link>https://example.com/posts/second</link>
+						<guid isPermaLink="false">post-2</guid>
+						<pubDate>Tue, 10 Jan 2023 09:00:00 +0000</pubDate>
+					</item>
+					<item>
+						<title>First Post</title>
+						<link>/posts/first</link>
+						<pubDate>Mon, 2 Jan 2023 09:00:00 GMT</pubDate>
+					</item>
+					<item>
+						<title>No Link</title>
+					</item>
+				</channel>
+			</rss>`,
+			&fetch.Feed{
+				Title:    "Example Blog",
+				SiteLink: "https://example.com/",
+				Items: []*fetch.FeedItem{
+					{ID: "post-2", Link: "https://example.com/posts/second", Title: "Second Post", Published: time.Date(2023, 1, 10, 9, 0, 0, 0, time.UTC)},
+					{ID: "https://example.com/posts/first", Link: "https://example.com/posts/first", Title: "First Post", Published: time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			"atom",
+			`<?xml version="1.0" encoding="utf-8"?>
+			<feed xmlns="http://www.w3.org/2005/Atom">
+				<title>Example Atom</title>
+				<link href="https://example.com/atom.xml" rel="self"/>
+				<link href="https://example.com/"/>
+				<entry>
+					<id>urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a</id>
+					<title>Atom Post</title>
+					<link rel="alternate" href="/posts/atom"/>
+					<updated>2023-02-01T12:00:00Z</updated>
+				</entry>
+			</feed>`,
+			&fetch.Feed{
+				Title:    "Example Atom",
+				SiteLink: "https://example.com/",
+				Items: []*fetch.FeedItem{
+					{ID: "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a", Link: "https://example.com/posts/atom", Title: "Atom Post", Published: time.Date(2023, 2, 1, 12, 0, 0, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			"rdf",
+			`<?xml version="1.0"?>
+			<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+				<channel rdf:about="https://example.com/">
+					<title>Example RDF</title>
+					<link>https://example.com/</link>
+				</channel>
+				<item rdf:about="https://example.com/posts/rdf">
+					<title>RDF Post</title>
+					<dc:date>2023-03-01</dc:date>
+				</item>
+			</rdf:RDF>`,
+			&fetch.Feed{
+				Title:    "Example RDF",
+				SiteLink: "https://example.com/",
+				Items: []*fetch.FeedItem{
+					{ID: "https://example.com/posts/rdf", Link: "https://example.com/posts/rdf", Title: "RDF Post", Published: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			feed, err := fetch.ParseFeed(strings.NewReader(tc.xml), base)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, feed)
+		})
+	}
+
+	_, err := fetch.ParseFeed(strings.NewReader("<html><body>Not a feed</body></html>"), base)
+	require.ErrorIs(t, err, fetch.ErrNotAFeed)
+}
+
+func TestFeedFetcher(t *testing.T) {
+	const etag = `"v1"`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(fetch.HeaderIfNoneMatch) == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set(fetch.HeaderETag, etag)
+		w.Header().Set(fetch.HeaderContentType, "application/rss+xml")
+		w.Write([]byte(`<rss version="2.0"><channel><title>Test</title><item><link>/a</link></item></channel></rss>`))
+	}))
+	defer srv.Close()
+
+	var fetcher fetch.Fetcher = fetch.NewFeedFetcher(srv.URL + "/feed.xml")
+	out, err := fetcher.Fetch(context.Background())
+	require.NoError(t, err)
+
+	feed, ok := out.(*fetch.Feed)
+	require.True(t, ok, "expected the fetcher to return a feed")
+	require.Equal(t, etag, feed.ETag)
+	require.Len(t, feed.Items, 1)
+	require.Equal(t, srv.URL+"/a", feed.Items[0].Link)
+
+	// The second fetch is conditional on the etag of the first
+	_, err = fetcher.Fetch(context.Background())
+	herr, ok := err.(fetch.HTTPError)
+	require.True(t, ok, "expected an http error")
+	require.True(t, herr.NotModified())
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/andybalholm/brotli"
@@ -19,9 +20,21 @@ func Fetch(ctx context.Context, url string) (*Document, error) {
 	return fetcher.Fetch(ctx)
 }
 
+// FetchConditional fetches the document only if it has been modified since the ETag or
+// Last-Modified header values returned by a previous fetch. If the document has not
+// been modified then an HTTPError is returned where NotModified() is true.
+func FetchConditional(ctx context.Context, url, etag, lastModified string) (*Document, error) {
+	fetcher := NewHTMLFetcher(url)
+	fetcher.etag = etag
+	fetcher.lastModified = lastModified
+	return fetcher.Fetch(ctx)
+}
+
 // HTMLFetcher is an interface for fetching the full HTML associated with a feed item
 type HTMLFetcher struct {
-	url string // the url of the article full text
+	url          string // the url of the article full text
+	etag         string // the etag of a previous fetch for a conditional request
+	lastModified string // the last modified header of a previous fetch for a conditional request
 }
 
 // NewHTMLFetcher creates a new HTML fetcher that can fetch the full HTML from the specified URL.
@@ -58,57 +71,33 @@ func (f *HTMLFetcher) Fetch(ctx context.Context) (doc *Document, err error) {
 		}
 	}
 
-	// Handle compression
-	// TODO: handle deflate, br, and other compression schemes
-	var reader io.Reader
-	switch encoding := rep.Header.Get(HeaderContentEncoding); encoding {
-	case gzipEncode:
-		var gzread *gzip.Reader
-		if gzread, err = gzip.NewReader(rep.Body); err != nil {
-			return nil, err
-		}
-		defer gzread.Close()
-		reader = gzread
-	case brotliEncode:
-		reader = brotli.NewReader(rep.Body)
-	case lzwEncode:
-		// TODO: what should the order and litwidth be?
-		lzwreader := lzw.NewReader(rep.Body, lzw.MSB, 8)
-		defer lzwreader.Close()
-		reader = lzwreader
-	case zlibEncode:
-		var zlibreader io.ReadCloser
-		if zlibreader, err = zlib.NewReader(rep.Body); err != nil {
-			return nil, err
-		}
-		defer zlibreader.Close()
-		reader = zlibreader
-	case "":
-		reader = rep.Body
-	default:
-		return nil, fmt.Errorf("unknown content encoding %q", encoding)
+	var reader io.ReadCloser
+	if reader, err = decode(rep); err != nil {
+		return nil, err
 	}
+	defer reader.Close()
 
 	var tree *goquery.Document
 	if tree, err = goquery.NewDocumentFromReader(reader); err != nil {
 		return nil, err
 	}
 
+	// Resolve relative links against the final URL after any redirects
+	meta := ParseMetadata(tree, rep.Request.URL)
 	doc = &Document{
-		Link:  req.URL.String(),
-		Title: tree.Find("title").Contents().Text(),
+		Link:         req.URL.String(),
+		Title:        meta.Title,
+		Description:  meta.Description,
+		SiteName:     meta.SiteName,
+		Author:       meta.Author,
+		Published:    meta.Published,
+		Image:        meta.Image,
+		Canonical:    meta.Canonical,
+		ETag:         rep.Header.Get(HeaderETag),
+		LastModified: rep.Header.Get(HeaderLastModified),
+		Content:      ExtractContent(tree, rep.Request.URL),
 	}
 
-	tree.Find("meta").EachWithBreak(func(index int, item *goquery.Selection) bool {
-		if item.AttrOr("name", "") == "description" {
-			doc.Description = item.AttrOr("content", "")
-			if doc.Description != "" {
-				return false
-			}
-		}
-		return true
-	})
-
 	tree.Find("link").EachWithBreak(func(index int, item *goquery.Selection) bool {
 		if item.AttrOr("rel", "") == "icon" || item.AttrOr("rel", "") == "shortcut icon" {
 			doc.Favicon = item.AttrOr("href", "")
@@ -138,13 +127,54 @@ func (f *HTMLFetcher) newRequest(ctx context.Context) (req *http.Request, err er
 	req.Header.Set(HeaderCacheControl, cacheControl)
 	req.Header.Set(HeaderReferer, referer)
 
+	// Make a conditional request if this resource has been fetched before
+	if f.etag != "" {
+		req.Header.Set(HeaderIfNoneMatch, f.etag)
+	}
+
+	if f.lastModified != "" {
+		req.Header.Set(HeaderIfModifiedSince, f.lastModified)
+	}
+
 	return req, nil
 }
 
+// Returns a reader that decompresses the body of the response according to its content
+// encoding. The caller must close the returned reader but not the response body.
+func decode(rep *http.Response) (_ io.ReadCloser, err error) {
+	switch encoding := rep.Header.Get(HeaderContentEncoding); encoding {
+	case gzipEncode:
+		var gzread *gzip.Reader
+		if gzread, err = gzip.NewReader(rep.Body); err != nil {
+			return nil, err
+		}
+		return gzread, nil
+	case brotliEncode:
+		return io.NopCloser(brotli.NewReader(rep.Body)), nil
+	case lzwEncode:
+		// TODO: what should the order and litwidth be?
+		return lzw.NewReader(rep.Body, lzw.MSB, 8), nil
+	case zlibEncode:
+		return zlib.NewReader(rep.Body)
+	case "":
+		return io.NopCloser(rep.Body), nil
+	default:
+		return nil, fmt.Errorf("unknown content encoding %q", encoding)
+	}
+}
+
 type Document struct {
-	Link         string `json:"link"`
-	Title        string `json:"title"`
-	Description  string `json:"description"`
-	Favicon      string `json:"favicon"`
-	FaviconCheck bool   `json:"favicon_exists,omitempty"`
+	Link         string    `json:"link"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Favicon      string    `json:"favicon"`
+	FaviconCheck bool      `json:"favicon_exists,omitempty"`
+	SiteName     string    `json:"site_name,omitempty"`
+	Author       string    `json:"author,omitempty"`
+	Published    time.Time `json:"published,omitempty"`
+	Image        string    `json:"image,omitempty"`
+	Canonical    string    `json:"canonical,omitempty"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Content      *Content  `json:"content,omitempty"`
 }
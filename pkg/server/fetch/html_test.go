@@ -0,0 +1,63 @@
+package fetch_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bbengfort/epistolary/pkg/server/fetch"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchConditional(t *testing.T) {
+	const (
+		etag         = `"v2"`
+		lastModified = "Wed, 21 Oct 2015 07:28:00 GMT"
+	)
+
+	var requests []http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Header.Clone())
+		if r.Header.Get(fetch.HeaderIfNoneMatch) == etag || r.Header.Get(fetch.HeaderIfModifiedSince) == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set(fetch.HeaderETag, etag)
+		w.Header().Set(fetch.HeaderLastModified, lastModified)
+		w.Header().Set(fetch.HeaderContentType, "text/html; charset=utf-8")
+		w.Write([]byte(`<html><head><title>Conditional Requests</title></head><body><p>Hello world</p></body></html>`))
+	}))
+	defer srv.Close()
+
+	// An unconditional fetch does not send the conditional headers
+	doc, err := fetch.Fetch(context.Background(), srv.URL+"/post")
+	require.NoError(t, err)
+	require.Equal(t, "Conditional Requests", doc.Title)
+	require.Equal(t, etag, doc.ETag)
+	require.Equal(t, lastModified, doc.LastModified)
+
+	require.Len(t, requests, 1)
+	require.Empty(t, requests[0].Get(fetch.HeaderIfNoneMatch))
+	require.Empty(t, requests[0].Get(fetch.HeaderIfModifiedSince))
+
+	// A conditional fetch sends the headers of the previous fetch and returns a 304 error
+	_, err = fetch.FetchConditional(context.Background(), srv.URL+"/post", doc.ETag, doc.LastModified)
+	herr, ok := err.(fetch.HTTPError)
+	require.True(t, ok, "expected an http error")
+	require.True(t, herr.NotModified())
+
+	require.Len(t, requests, 2)
+	require.Equal(t, etag, requests[1].Get(fetch.HeaderIfNoneMatch))
+	require.Equal(t, lastModified, requests[1].Get(fetch.HeaderIfModifiedSince))
+
+	// If the document has changed since the previous fetch it is returned
+	doc, err = fetch.FetchConditional(context.Background(), srv.URL+"/post", `"v1"`, "")
+	require.NoError(t, err)
+	require.Equal(t, etag, doc.ETag)
+
+	require.Len(t, requests, 3)
+	require.Equal(t, `"v1"`, requests[2].Get(fetch.HeaderIfNoneMatch))
+	require.Empty(t, requests[2].Get(fetch.HeaderIfModifiedSince))
+}
@@ -0,0 +1,242 @@
+package fetch
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Metadata is the structured data that a page exposes about itself using OpenGraph,
+// Twitter Card, and standard meta tags and schema.org JSON-LD.
+type Metadata struct {
+	Title       string
+	Description string
+	SiteName    string
+	Author      string
+	Published   time.Time
+	Image       string
+	Canonical   string
+}
+
+// Layouts of the published timestamps that are used in the wild; most sites use some
+// form of ISO 8601 but some omit the seconds or the timezone.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// ParseMetadata extracts the metadata from the parsed HTML of the page at the base URL,
+// resolving any relative links against it. When multiple sources describe the same
+// field, OpenGraph is preferred, then standard meta tags, Twitter Cards, and JSON-LD.
+func ParseMetadata(tree *goquery.Document, base *url.URL) *Metadata {
+	meta := make(map[string]string)
+	tree.Find("meta").Each(func(index int, item *goquery.Selection) {
+		key := strings.ToLower(strings.TrimSpace(item.AttrOr("property", item.AttrOr("name", ""))))
+		val := strings.TrimSpace(item.AttrOr("content", ""))
+		if key == "" || val == "" {
+			return
+		}
+
+		// Use the first value for a key since some properties such as og:image can be
+		// repeated with the most important value first.
+		if _, ok := meta[key]; !ok {
+			meta[key] = val
+		}
+	})
+
+	var canonical string
+	tree.Find("link").EachWithBreak(func(index int, item *goquery.Selection) bool {
+		if strings.EqualFold(strings.TrimSpace(item.AttrOr("rel", "")), "canonical") {
+			canonical = strings.TrimSpace(item.AttrOr("href", ""))
+		}
+		return canonical == ""
+	})
+
+	ld := parseJSONLD(tree)
+
+	md := &Metadata{
+		Title:       first(meta["og:title"], meta["twitter:title"], ld.Headline, ld.Name, strings.TrimSpace(tree.Find("title").First().Text())),
+		Description: first(meta["og:description"], meta["description"], meta["twitter:description"], ld.Description),
+		SiteName:    first(meta["og:site_name"], ld.Publisher.Name, meta["application-name"]),
+		Author:      first(notURL(meta["article:author"]), meta["author"], ld.Author.Name, strings.TrimPrefix(meta["twitter:creator"], "@")),
+		Image:       resolve(base, first(meta["og:image:secure_url"], meta["og:image"], meta["og:image:url"], meta["twitter:image"], meta["twitter:image:src"], ld.Image.URL)),
+		Canonical:   resolve(base, first(canonical, meta["og:url"], ld.URL)),
+	}
+
+	for _, ts := range []string{meta["article:published_time"], ld.DatePublished, meta["og:published_time"], meta["date"]} {
+		if md.Published = parseTime(ts); !md.Published.IsZero() {
+			break
+		}
+	}
+
+	return md
+}
+
+// The subset of the schema.org Article and WebPage types that is extracted.
+type jsonLD struct {
+	Type          ldStrings `json:"@type"`
+	Graph         []jsonLD  `json:"@graph"`
+	Headline      string    `json:"headline"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	URL           string    `json:"url"`
+	DatePublished string    `json:"datePublished"`
+	Author        ldThing   `json:"author"`
+	Publisher     ldThing   `json:"publisher"`
+	Image         ldThing   `json:"image"`
+}
+
+// Finds the JSON-LD object that best describes the page, preferring article types.
+func parseJSONLD(tree *goquery.Document) (best jsonLD) {
+	var candidates []jsonLD
+	tree.Find(`script[type="application/ld+json"]`).Each(func(index int, item *goquery.Selection) {
+		data := []byte(strings.TrimSpace(item.Text()))
+		if len(data) == 0 {
+			return
+		}
+
+		// JSON-LD may be a single object, a list of objects, or an object with a graph
+		var objs []jsonLD
+		if data[0] == '[' {
+			if err := json.Unmarshal(data, &objs); err != nil {
+				return
+			}
+		} else {
+			var obj jsonLD
+			if err := json.Unmarshal(data, &obj); err != nil {
+				return
+			}
+			objs = append(objs, obj)
+		}
+
+		for _, obj := range objs {
+			candidates = append(candidates, obj)
+			candidates = append(candidates, obj.Graph...)
+		}
+	})
+
+	for _, obj := range candidates {
+		if obj.isArticle() {
+			return obj
+		}
+	}
+
+	for _, obj := range candidates {
+		if obj.Headline != "" || obj.DatePublished != "" {
+			return obj
+		}
+	}
+	return best
+}
+
+func (ld jsonLD) isArticle() bool {
+	for _, t := range ld.Type {
+		if strings.HasSuffix(t, "Article") || t == "BlogPosting" || t == "Report" {
+			return true
+		}
+	}
+	return false
+}
+
+// JSON-LD values can be specified as a single string or as a list of strings.
+type ldStrings []string
+
+func (s *ldStrings) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*s = ldStrings{one}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return nil
+	}
+	*s = many
+	return nil
+}
+
+// A JSON-LD thing such as a person, organization or image that may be specified as a
+// string, an object, or a list of either; only the first item of a list is used.
+type ldThing struct {
+	Name string
+	URL  string
+}
+
+func (t *ldThing) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		t.Name, t.URL = str, str
+		return nil
+	}
+
+	var obj struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	}
+	if err := json.Unmarshal(data, &obj); err == nil {
+		t.Name, t.URL = obj.Name, obj.URL
+		return nil
+	}
+
+	var list []ldThing
+	if err := json.Unmarshal(data, &list); err == nil && len(list) > 0 {
+		*t = list[0]
+	}
+	return nil
+}
+
+func first(values ...string) string {
+	for _, val := range values {
+		if val = strings.TrimSpace(val); val != "" {
+			return val
+		}
+	}
+	return ""
+}
+
+func notURL(val string) string {
+	if strings.HasPrefix(val, "http://") || strings.HasPrefix(val, "https://") {
+		return ""
+	}
+	return val
+}
+
+func resolve(base *url.URL, link string) string {
+	if link == "" {
+		return ""
+	}
+
+	ref, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	if base == nil {
+		return ref.String()
+	}
+	return base.ResolveReference(ref).String()
+}
+
+func parseTime(val string) time.Time {
+	if val = strings.TrimSpace(val); val == "" {
+		return time.Time{}
+	}
+
+	for _, layout := range timeLayouts {
+		if ts, err := time.Parse(layout, val); err == nil {
+			return ts
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,111 @@
+package fetch_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/bbengfort/epistolary/pkg/server/fetch"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetadata(t *testing.T) {
+	base, _ := url.Parse("https://example.com/posts/hello?utm_source=feed")
+
+	testCases := []struct {
+		name     string
+		html     string
+		expected *fetch.Metadata
+	}{
+		{
+			"title only",
+			`<html><head><title> Hello World </title><meta name="description" content="A greeting"></head></html>`,
+			&fetch.Metadata{Title: "Hello World", Description: "A greeting"},
+		},
+		{
+			"opengraph preferred",
+			`<html><head>
+				<title>Hello World | Example</title>
+				<meta name="description" content="A greeting">
+				<meta property="og:title" content="Hello World">
+				<meta property="og:description" content="The best greeting">
+				<meta property="og:site_name" content="Example">
+				<meta property="og:image" content="/images/hello.png">
+				<meta property="og:image" content="/images/other.png">
+				<meta property="article:author" content="https://example.com/authors/jane">
+				<meta name="author" content="Jane Doe">
+				<meta property="article:published_time" content="2023-01-15T10:30:00Z">
+				<link rel="canonical" href="/posts/hello">
+			</head></html>`,
+			&fetch.Metadata{
+				Title:       "Hello World",
+				Description: "The best greeting",
+				SiteName:    "Example",
+				Author:      "Jane Doe",
+				Published:   time.Date(2023, 1, 15, 10, 30, 0, 0, time.UTC),
+				Image:       "https://example.com/images/hello.png",
+				Canonical:   "https://example.com/posts/hello",
+			},
+		},
+		{
+			"twitter card",
+			`<html><head>
+				<meta name="twitter:title" content="Hello Twitter">
+				<meta name="twitter:description" content="A tweet">
+				<meta name="twitter:image" content="https://cdn.example.com/hello.jpg">
+				<meta name="twitter:creator" content="@jane">
+			</head></html>`,
+			&fetch.Metadata{
+				Title:       "Hello Twitter",
+				Description: "A tweet",
+				Author:      "jane",
+				Image:       "https://cdn.example.com/hello.jpg",
+			},
+		},
+		{
+			"json-ld graph",
+			`<html><head><title>Fallback</title>
+				<script type="application/ld+json">{
+					"@context": "https://schema.org",
+					"@graph": [
+						{"@type": "WebSite", "name": "Example"},
+						{
+							"@type": ["NewsArticle"],
+							"headline": "Hello JSON-LD",
+							"description": "Linked data",
+							"url": "https://example.com/posts/hello",
+							"datePublished": "2023-02-01",
+							"author": [{"@type": "Person", "name": "Jane Doe"}, {"name": "John Doe"}],
+							"publisher": {"@type": "Organization", "name": "Example News"},
+							"image": {"@type": "ImageObject", "url": "https://example.com/hello.png"}
+						}
+					]
+				}</script>
+			</head></html>`,
+			&fetch.Metadata{
+				Title:       "Hello JSON-LD",
+				Description: "Linked data",
+				SiteName:    "Example News",
+				Author:      "Jane Doe",
+				Published:   time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+				Image:       "https://example.com/hello.png",
+				Canonical:   "https://example.com/posts/hello",
+			},
+		},
+		{
+			"invalid json-ld",
+			`<html><head><title>Hello</title><script type="application/ld+json">{"headline": </script></head></html>`,
+			&fetch.Metadata{Title: "Hello"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tree, err := goquery.NewDocumentFromReader(strings.NewReader(tc.html))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, fetch.ParseMetadata(tree, base))
+		})
+	}
+}
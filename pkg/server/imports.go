@@ -0,0 +1,116 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/imports"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/gin-gonic/gin"
+)
+
+const importTimeout = 5 * time.Minute
+
+// Import readings from an export of another service that is uploaded as a multipart
+// form with the export in the file field and an optional format field. The import is
+// run before responding and a summary of the imported readings is returned.
+func (s *Server) Import(c *gin.Context) {
+	var (
+		err    error
+		userID int64
+		format imports.Format
+		header *multipart.FileHeader
+		items  []*imports.Item
+	)
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	// Large exports take longer to upload and import than the server timeouts allow
+	rc := http.NewResponseController(c.Writer)
+	if err = rc.SetReadDeadline(time.Now().Add(importTimeout)); err == nil {
+		err = rc.SetWriteDeadline(time.Now().Add(importTimeout))
+	}
+
+	if err != nil {
+		sentry.Warn(c).Err(err).Msg("could not extend import deadlines")
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, imports.MaxSize)
+	if format, err = imports.ParseFormat(c.PostForm("format")); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+		return
+	}
+
+	if header, err = c.FormFile("file"); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("an export file is required"))
+		return
+	}
+
+	var data []byte
+	if data, err = readFormFile(header); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not read export file"))
+		return
+	}
+
+	if format == imports.Auto {
+		if format, err = imports.Detect(header.Filename, data); err != nil {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+	}
+
+	if items, err = imports.Parse(format, bytes.NewReader(data)); err != nil {
+		c.Error(err)
+		switch {
+		case errors.Is(err, imports.ErrNoItems), errors.Is(err, imports.ErrTooManyItems):
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+		default:
+			c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse "+string(format)+" export"))
+		}
+		return
+	}
+
+	var summary *imports.Summary
+	if summary, err = imports.Run(c.Request.Context(), userID, format, items); err != nil {
+		sentry.Warn(c).Err(err).Int("imported", summary.Imported).Msg("import was interrupted")
+	}
+
+	// Sync the metadata of the imported epistles in the background
+	if summary.Imported > 0 {
+		s.NotifySync()
+	}
+
+	out := &api.ImportSummary{
+		Format:   string(summary.Format),
+		Total:    summary.Total,
+		Imported: summary.Imported,
+		Skipped:  summary.Skipped,
+		Failed:   summary.Failed,
+	}
+
+	for _, ierr := range summary.Errors {
+		out.Errors = append(out.Errors, &api.ImportError{Link: ierr.Link, Error: ierr.Error})
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func readFormFile(header *multipart.FileHeader) (_ []byte, err error) {
+	var f multipart.File
+	if f, err = header.Open(); err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
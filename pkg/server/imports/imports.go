@@ -0,0 +1,238 @@
+/*
+Package imports parses the exports of other read-it-later and bookmarking services so
+that a user's backlog can be imported into Epistolary as readings, keeping the original
+timestamps, tags, and read state of each item.
+*/
+package imports
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/epistles"
+	"github.com/bbengfort/epistolary/pkg/server/tags"
+)
+
+// Format is the service and file type of an export.
+type Format string
+
+const (
+	Auto          Format = ""
+	PocketHTML    Format = "pocket-html"
+	PocketCSV     Format = "pocket-csv"
+	InstapaperCSV Format = "instapaper-csv"
+	PinboardJSON  Format = "pinboard-json"
+	NetscapeHTML  Format = "netscape-html"
+)
+
+// Formats lists all of the export formats that can be imported.
+var Formats = []Format{PocketHTML, PocketCSV, InstapaperCSV, PinboardJSON, NetscapeHTML}
+
+// Limits on the size of an import to prevent a single import from overwhelming the
+// database and the sync workers.
+const (
+	MaxSize   = 32 * 1024 * 1024
+	MaxItems  = 10000
+	MaxErrors = 100
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown import format")
+	ErrDetectFormat  = errors.New("could not detect the format of the import")
+	ErrNoItems       = errors.New("no items found in import")
+	ErrTooManyItems  = fmt.Errorf("import has more than %d items", MaxItems)
+)
+
+// ParseFormat returns the format with the specified name; an empty name returns Auto.
+func ParseFormat(s string) (Format, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || s == "auto" {
+		return Auto, nil
+	}
+
+	for _, format := range Formats {
+		if s == string(format) {
+			return format, nil
+		}
+	}
+	return Auto, fmt.Errorf("%w %q", ErrUnknownFormat, s)
+}
+
+// Item is a single link from an export along with the state of its reading.
+type Item struct {
+	Link     string
+	Title    string
+	Tags     []string
+	Added    time.Time
+	Read     time.Time
+	Archived time.Time
+}
+
+// Reading returns the reading of the item for the specified user. Items that were read
+// or archived are marked as started and finished at the time they were read.
+func (i *Item) Reading(userID int64) *epistles.Reading {
+	r := &epistles.Reading{UserID: userID, Created: i.Added}
+
+	if !i.Read.IsZero() {
+		r.Started = sql.NullTime{Valid: true, Time: i.Read}
+		r.Finished = sql.NullTime{Valid: true, Time: i.Read}
+	}
+
+	if !i.Archived.IsZero() {
+		r.Archived = sql.NullTime{Valid: true, Time: i.Archived}
+	}
+	return r
+}
+
+// Mark the item as read or archived, defaulting to the time it was added.
+func (i *Item) markRead(archived bool) {
+	ts := i.Added
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	i.Read = ts
+	if archived {
+		i.Archived = ts
+	}
+}
+
+// Detect the format of the export from its filename and contents.
+func Detect(filename string, data []byte) (Format, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	head := bytes.ToLower(bytes.TrimSpace(data[:min(len(data), 4096)]))
+
+	switch {
+	case ext == ".json" || bytes.HasPrefix(head, []byte("[")):
+		return PinboardJSON, nil
+	case bytes.Contains(head, []byte("<title>pocket export</title>")) || bytes.Contains(head, []byte("time_added=")):
+		return PocketHTML, nil
+	case bytes.Contains(head, []byte("netscape-bookmark-file")) || bytes.Contains(head, []byte("<dt>")):
+		return NetscapeHTML, nil
+	case ext == ".csv" || ext == "":
+		line, _, _ := bytes.Cut(head, []byte("\n"))
+		switch {
+		case bytes.Contains(line, []byte("time_added")):
+			return PocketCSV, nil
+		case bytes.Contains(line, []byte("folder")) && bytes.Contains(line, []byte("url")):
+			return InstapaperCSV, nil
+		}
+	}
+	return Auto, ErrDetectFormat
+}
+
+// Parse the items from an export in the specified format.
+func Parse(format Format, r io.Reader) (items []*Item, err error) {
+	switch format {
+	case PocketHTML:
+		items, err = parsePocketHTML(r)
+	case PocketCSV:
+		items, err = parsePocketCSV(r)
+	case InstapaperCSV:
+		items, err = parseInstapaperCSV(r)
+	case PinboardJSON:
+		items, err = parsePinboardJSON(r)
+	case NetscapeHTML:
+		items, err = parseNetscapeHTML(r)
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(items) == 0:
+		return nil, ErrNoItems
+	case len(items) > MaxItems:
+		return nil, ErrTooManyItems
+	}
+	return items, nil
+}
+
+// Summary reports the results of an import.
+type Summary struct {
+	Format   Format
+	Total    int
+	Imported int
+	Skipped  int
+	Failed   int
+	Errors   []*ItemError
+}
+
+// ItemError describes why a link could not be imported.
+type ItemError struct {
+	Link  string
+	Error string
+}
+
+// Run imports the items as readings for the user. Links that the user already has a
+// reading for are skipped rather than updated. Items that cannot be imported are
+// reported in the summary; an error is only returned if the context is done.
+func Run(ctx context.Context, userID int64, format Format, items []*Item) (summary *Summary, err error) {
+	summary = &Summary{Format: format, Total: len(items)}
+	for _, item := range items {
+		if err = ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		reading := item.Reading(userID)
+		if err = epistles.Import(ctx, reading, item.Link, item.Title); err != nil {
+			if errors.Is(err, epistles.ErrAlreadyExists) {
+				summary.Skipped++
+				continue
+			}
+
+			summary.fail(item, err)
+			continue
+		}
+
+		if names := normalizeTags(item.Tags); len(names) > 0 {
+			if _, err = tags.SetReadingTags(ctx, reading.EpistleID, userID, names); err != nil {
+				summary.fail(item, err)
+				continue
+			}
+		}
+
+		summary.Imported++
+	}
+	return summary, nil
+}
+
+func (s *Summary) fail(item *Item, err error) {
+	s.Failed++
+	if len(s.Errors) < MaxErrors {
+		s.Errors = append(s.Errors, &ItemError{Link: item.Link, Error: err.Error()})
+	}
+}
+
+// Tags from other services that are not valid Epistolary tags are dropped rather than
+// failing the import of the item.
+func normalizeTags(names []string) []string {
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if name, err := tags.Normalize(name); err == nil {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// Splits a list of tags on the separator, removing empty tags.
+func splitTags(s, sep string) []string {
+	var names []string
+	for _, name := range strings.Split(s, sep) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
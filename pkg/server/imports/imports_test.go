@@ -0,0 +1,178 @@
+package imports_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/imports"
+	"github.com/stretchr/testify/require"
+)
+
+const pocketHTML = `<!DOCTYPE html>
+<html>
+	<head>
+		<meta http-equiv="Content-Type" content="text/html; charset=UTF-8" />
+		<title>Pocket Export</title>
+	</head>
+	<body>
+		<h1>Unread</h1>
+		<ul>
+			<li><a href="https://example.com/unread" time_added="1672531200" tags="go,web">Unread Article</a></li>
+		</ul>
+
+		<h1>Read Archive</h1>
+		<ul>
+			<li><a href="https://example.com/read" time_added="1672617600" tags="">Read Article</a></li>
+		</ul>
+	</body>
+</html>`
+
+const pocketCSV = `title,url,time_added,tags,status
+Unread Article,https://example.com/unread,1672531200,go|web,unread
+Read Article,https://example.com/read,1672617600,,archive
+`
+
+const instapaperCSV = `URL,Title,Selection,Folder,Timestamp
+https://example.com/unread,Unread Article,,Unread,1672531200
+https://example.com/read,Read Article,,Archive,1672617600
+https://example.com/starred,Starred Article,A selection,Research,1672704000
+`
+
+const pinboardJSON = `[
+	{"href":"https://example.com/unread","description":"Unread Article","extended":"","meta":"abc","hash":"def","time":"2023-01-01T00:00:00Z","shared":"no","toread":"yes","tags":"go web"},
+	{"href":"https://example.com/read","description":"Read Article","extended":"","meta":"abc","hash":"def","time":"2023-01-02T00:00:00Z","shared":"yes","toread":"no","tags":""}
+]`
+
+const netscapeHTML = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+	<DT><H3 ADD_DATE="1672531200" PERSONAL_TOOLBAR_FOLDER="true">Bookmarks bar</H3>
+	<DL><p>
+		<DT><A HREF="https://example.com/toolbar" ADD_DATE="1672531200">Toolbar Article</A>
+		<DT><H3 ADD_DATE="1672531200">Research</H3>
+		<DL><p>
+			<DT><A HREF="https://example.com/research" ADD_DATE="1672617600" TAGS="go,web">Research Article</A>
+		</DL><p>
+	</DL><p>
+	<DT><A HREF="https://example.com/other" ADD_DATE="1672704000">Other Article</A>
+</DL><p>`
+
+func TestDetect(t *testing.T) {
+	testCases := []struct {
+		filename string
+		data     string
+		expected imports.Format
+	}{
+		{"ril_export.html", pocketHTML, imports.PocketHTML},
+		{"part_000000.csv", pocketCSV, imports.PocketCSV},
+		{"instapaper-export.csv", instapaperCSV, imports.InstapaperCSV},
+		{"pinboard_export.json", pinboardJSON, imports.PinboardJSON},
+		{"export", pinboardJSON, imports.PinboardJSON},
+		{"bookmarks.html", netscapeHTML, imports.NetscapeHTML},
+	}
+
+	for _, tc := range testCases {
+		format, err := imports.Detect(tc.filename, []byte(tc.data))
+		require.NoError(t, err, "could not detect format of %s", tc.filename)
+		require.Equal(t, tc.expected, format, "unexpected format for %s", tc.filename)
+	}
+
+	_, err := imports.Detect("notes.txt", []byte("hello world"))
+	require.ErrorIs(t, err, imports.ErrDetectFormat)
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := imports.ParseFormat(" Pocket-HTML ")
+	require.NoError(t, err)
+	require.Equal(t, imports.PocketHTML, format)
+
+	format, err = imports.ParseFormat("auto")
+	require.NoError(t, err)
+	require.Equal(t, imports.Auto, format)
+
+	_, err = imports.ParseFormat("delicious")
+	require.ErrorIs(t, err, imports.ErrUnknownFormat)
+}
+
+func TestParse(t *testing.T) {
+	jan1 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	jan2 := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	jan3 := time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		format   imports.Format
+		data     string
+		expected []*imports.Item
+	}{
+		{
+			imports.PocketHTML, pocketHTML, []*imports.Item{
+				{Link: "https://example.com/unread", Title: "Unread Article", Tags: []string{"go", "web"}, Added: jan1},
+				{Link: "https://example.com/read", Title: "Read Article", Added: jan2, Read: jan2, Archived: jan2},
+			},
+		},
+		{
+			imports.PocketCSV, pocketCSV, []*imports.Item{
+				{Link: "https://example.com/unread", Title: "Unread Article", Tags: []string{"go", "web"}, Added: jan1},
+				{Link: "https://example.com/read", Title: "Read Article", Added: jan2, Read: jan2, Archived: jan2},
+			},
+		},
+		{
+			imports.InstapaperCSV, instapaperCSV, []*imports.Item{
+				{Link: "https://example.com/unread", Title: "Unread Article", Added: jan1},
+				{Link: "https://example.com/read", Title: "Read Article", Added: jan2, Read: jan2, Archived: jan2},
+				{Link: "https://example.com/starred", Title: "Starred Article", Tags: []string{"Research"}, Added: jan3},
+			},
+		},
+		{
+			imports.PinboardJSON, pinboardJSON, []*imports.Item{
+				{Link: "https://example.com/unread", Title: "Unread Article", Tags: []string{"go", "web"}, Added: jan1},
+				{Link: "https://example.com/read", Title: "Read Article", Tags: []string{}, Added: jan2, Read: jan2},
+			},
+		},
+		{
+			imports.NetscapeHTML, netscapeHTML, []*imports.Item{
+				{Link: "https://example.com/toolbar", Title: "Toolbar Article", Added: jan1},
+				{Link: "https://example.com/research", Title: "Research Article", Tags: []string{"go", "web", "Research"}, Added: jan2},
+				{Link: "https://example.com/other", Title: "Other Article", Added: jan3},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		items, err := imports.Parse(tc.format, strings.NewReader(tc.data))
+		require.NoError(t, err, "could not parse %s", tc.format)
+		require.Equal(t, tc.expected, items, "unexpected items parsed from %s", tc.format)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := imports.Parse(imports.PinboardJSON, strings.NewReader("[]"))
+	require.ErrorIs(t, err, imports.ErrNoItems)
+
+	_, err = imports.Parse(imports.Auto, strings.NewReader(pinboardJSON))
+	require.ErrorIs(t, err, imports.ErrUnknownFormat)
+
+	_, err = imports.Parse(imports.PinboardJSON, strings.NewReader("{"))
+	require.Error(t, err)
+}
+
+func TestItemReading(t *testing.T) {
+	added := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	item := &imports.Item{Link: "https://example.com", Added: added}
+
+	reading := item.Reading(42)
+	require.Equal(t, int64(42), reading.UserID)
+	require.Equal(t, added, reading.Created)
+	require.False(t, reading.Started.Valid)
+	require.False(t, reading.Finished.Valid)
+	require.False(t, reading.Archived.Valid)
+
+	item.Read, item.Archived = added, added
+	reading = item.Reading(42)
+	require.True(t, reading.Started.Valid)
+	require.True(t, reading.Finished.Valid)
+	require.True(t, reading.Archived.Valid)
+}
@@ -0,0 +1,44 @@
+package imports
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// Instapaper CSV exports have URL, Title, Selection, Folder, and Timestamp columns and
+// newer exports include a Tags column that is a JSON list of tag names. Items in the
+// Archive folder are archived and any folder other than Unread is imported as a tag.
+func parseInstapaperCSV(r io.Reader) (items []*Item, err error) {
+	var rows []map[string]string
+	if rows, err = readCSV(r); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		item := &Item{
+			Link:  row["url"],
+			Title: row["title"],
+			Added: parseUnix(row["timestamp"]),
+		}
+
+		if row["tags"] != "" {
+			var names []string
+			if err := json.Unmarshal([]byte(row["tags"]), &names); err == nil {
+				item.Tags = names
+			} else {
+				item.Tags = splitTags(row["tags"], ",")
+			}
+		}
+
+		switch folder := row["folder"]; strings.ToLower(folder) {
+		case "", "unread":
+		case "archive":
+			item.markRead(true)
+		default:
+			item.Tags = append(item.Tags, folder)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,51 @@
+package imports
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Netscape bookmark files are exported by browsers and many bookmarking services. Links
+// may have add_date, tags, and toread attributes; the names of the folders that contain
+// a link are imported as tags except for the browser's toolbar folder.
+func parseNetscapeHTML(r io.Reader) (items []*Item, err error) {
+	var doc *goquery.Document
+	if doc, err = goquery.NewDocumentFromReader(r); err != nil {
+		return nil, err
+	}
+
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		item := &Item{
+			Link:  strings.TrimSpace(a.AttrOr("href", "")),
+			Title: strings.TrimSpace(a.Text()),
+			Tags:  splitTags(a.AttrOr("tags", ""), ","),
+			Added: parseUnix(a.AttrOr("add_date", "")),
+		}
+
+		a.ParentsFiltered("dl").Each(func(_ int, dl *goquery.Selection) {
+			folder := dl.PrevAllFiltered("h3").First()
+			if folder.Length() == 0 {
+				folder = dl.Parent().ChildrenFiltered("h3").First()
+			}
+
+			if _, toolbar := folder.Attr("personal_toolbar_folder"); folder.Length() > 0 && !toolbar {
+				item.Tags = append(item.Tags, strings.TrimSpace(folder.Text()))
+			}
+		})
+		items = append(items, item)
+	})
+	return items, nil
+}
+
+// Parses a unix timestamp in seconds, returning a zero time if it cannot be parsed.
+func parseUnix(s string) time.Time {
+	secs, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || secs <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(secs, 0).UTC()
+}
@@ -0,0 +1,45 @@
+package imports
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+// A bookmark in a Pinboard JSON export.
+type pinboardBookmark struct {
+	Href        string `json:"href"`
+	Description string `json:"description"`
+	Time        string `json:"time"`
+	ToRead      string `json:"toread"`
+	Tags        string `json:"tags"`
+}
+
+// Pinboard JSON exports are a list of bookmarks with space separated tags. Bookmarks
+// that are not marked to read are imported as read since they were saved for reference.
+func parsePinboardJSON(r io.Reader) (items []*Item, err error) {
+	var bookmarks []*pinboardBookmark
+	if err = json.NewDecoder(r).Decode(&bookmarks); err != nil {
+		return nil, err
+	}
+
+	items = make([]*Item, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		item := &Item{
+			Link:  strings.TrimSpace(bookmark.Href),
+			Title: strings.TrimSpace(bookmark.Description),
+			Tags:  strings.Fields(bookmark.Tags),
+		}
+
+		if ts, err := time.Parse(time.RFC3339, bookmark.Time); err == nil {
+			item.Added = ts
+		}
+
+		if bookmark.ToRead != "yes" {
+			item.markRead(false)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
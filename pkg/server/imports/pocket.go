@@ -0,0 +1,92 @@
+package imports
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Pocket HTML exports list unread items under an "Unread" heading and archived items
+// under a "Read Archive" heading; each link has time_added and tags attributes.
+func parsePocketHTML(r io.Reader) (items []*Item, err error) {
+	var doc *goquery.Document
+	if doc, err = goquery.NewDocumentFromReader(r); err != nil {
+		return nil, err
+	}
+
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		item := &Item{
+			Link:  strings.TrimSpace(a.AttrOr("href", "")),
+			Title: strings.TrimSpace(a.Text()),
+			Tags:  splitTags(a.AttrOr("tags", ""), ","),
+			Added: parseUnix(a.AttrOr("time_added", "")),
+		}
+
+		heading := a.Closest("ul").PrevAllFiltered("h1").First()
+		if strings.Contains(strings.ToLower(heading.Text()), "archive") {
+			item.markRead(true)
+		}
+		items = append(items, item)
+	})
+	return items, nil
+}
+
+// Pocket CSV exports have title, url, time_added, tags, and status columns where the
+// tags are separated by a pipe and the status is either unread or archive.
+func parsePocketCSV(r io.Reader) (items []*Item, err error) {
+	var rows []map[string]string
+	if rows, err = readCSV(r); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		item := &Item{
+			Link:  row["url"],
+			Title: row["title"],
+			Tags:  splitTags(row["tags"], "|"),
+			Added: parseUnix(row["time_added"]),
+		}
+
+		if strings.EqualFold(row["status"], "archive") {
+			item.markRead(true)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Reads the rows of a CSV file with a header into maps keyed by lowercase column name.
+func readCSV(r io.Reader) (rows []map[string]string, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var header []string
+	if header, err = reader.Read(); err != nil {
+		return nil, err
+	}
+
+	for i, name := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+	}
+
+	for {
+		var record []string
+		if record, err = reader.Read(); err != nil {
+			if err == io.EOF {
+				return rows, nil
+			}
+			return nil, err
+		}
+
+		row := make(map[string]string, len(header))
+		for i, value := range record {
+			if i < len(header) {
+				row[header[i]] = strings.TrimSpace(value)
+			}
+		}
+		rows = append(rows, row)
+	}
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/epistles"
+	"github.com/bbengfort/epistolary/pkg/server/oauth"
+	"github.com/bbengfort/epistolary/pkg/server/users"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/rs/zerolog/log"
+)
+
+const janitorInterval = 10 * time.Minute
+
+// Janitor runs in its own go routine and periodically purges readings whose tombstone
+// has expired, garbage collecting any epistles that are no longer read by any user, and
+// deletes expired email verification and password reset tokens, sessions, and
+// authorization codes.
+// The janitor is stopped when the server is shutdown.
+func (s *Server) Janitor() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(janitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+		nReadings, nEpistles, err := epistles.Purge(ctx)
+		cancel()
+
+		if err != nil {
+			sentry.Error(ctx).Err(err).Msg("could not purge deleted readings")
+			continue
+		}
+
+		log.Debug().Int64("readings", nReadings).Int64("epistles", nEpistles).Msg("purged deleted readings")
+
+		ctx, cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+		nTokens, err := users.PurgeExpiredTokens(ctx)
+		cancel()
+
+		if err != nil {
+			sentry.Error(ctx).Err(err).Msg("could not purge expired user tokens")
+			continue
+		}
+
+		log.Debug().Int64("tokens", nTokens).Msg("purged expired user tokens")
+
+		ctx, cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+		nCodes, err := oauth.PurgeExpiredCodes(ctx)
+		cancel()
+
+		if err != nil {
+			sentry.Error(ctx).Err(err).Msg("could not purge expired authorization codes")
+			continue
+		}
+
+		log.Debug().Int64("codes", nCodes).Msg("purged expired authorization codes")
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/config"
+	"github.com/bbengfort/epistolary/pkg/utils/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// loginLimiter locks out the IP addresses and usernames that have too many failed
+// logins within a sliding window. Lockouts are checked before the user is fetched so
+// that locked out clients cannot force the server to derive password keys.
+type loginLimiter struct {
+	ips   *ratelimit.Limiter
+	users *ratelimit.Limiter
+}
+
+func newLoginLimiter(conf config.LoginConfig) *loginLimiter {
+	return &loginLimiter{
+		ips:   ratelimit.New(conf.MaxIPFailures, conf.Window, conf.Lockout),
+		users: ratelimit.New(conf.MaxUserFailures, conf.Window, conf.Lockout),
+	}
+}
+
+// Check returns how long logins from the IP address or for the username are locked
+// out or zero if the login is allowed.
+func (l *loginLimiter) Check(ip, username string) time.Duration {
+	return maxDuration(l.ips.Check(ip), l.users.Check(loginKey(username)))
+}
+
+// Fail records a failed login, returning how long the IP address or username is now
+// locked out or zero if neither has reached its limit.
+func (l *loginLimiter) Fail(ip, username string) time.Duration {
+	return maxDuration(l.ips.Fail(ip), l.users.Fail(loginKey(username)))
+}
+
+// Succeed forgets the failed logins for the username. Failures from the IP address are
+// kept so that a successful login cannot be used to reset a credential stuffing attack.
+func (l *loginLimiter) Succeed(username string) {
+	l.users.Reset(loginKey(username))
+}
+
+// Usernames are case-insensitive for the purpose of limiting logins so that the limit
+// cannot be bypassed by changing the case of the username.
+func loginKey(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Responds that the client must wait before it can attempt to login again, setting the
+// Retry-After header to the number of seconds until the lockout expires.
+func tooManyLogins(c *gin.Context, retryAfter time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, api.ErrorResponse("too many failed login attempts, try again later"))
+}
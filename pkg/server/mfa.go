@@ -0,0 +1,364 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/mfa"
+	"github.com/bbengfort/epistolary/pkg/server/users"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/gin-gonic/gin"
+)
+
+// The issuer that labels the account of the user in their authenticator app.
+const mfaIssuer = "Epistolary"
+
+// LoginMFA completes the login of a user that has enabled multi-factor authentication
+// using the challenge token returned by Login and a code from the authenticator of the
+// user or one of their recovery codes. Invalid codes count as failed logins so that the
+// code cannot be brute-forced with the challenge token.
+func (s *Server) LoginMFA(c *gin.Context) {
+	var (
+		err    error
+		in     *api.MFALoginRequest
+		userID int64
+		user   *users.User
+		factor *users.MFA
+	)
+
+	if err = c.BindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse mfa login request"))
+		return
+	}
+
+	if in.MFAToken == "" || in.Code == "" {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("mfa token and code are required"))
+		return
+	}
+
+	if userID, err = s.tokens.VerifyMFAToken(in.MFAToken); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnauthorized, api.ErrorResponse("mfa challenge is invalid or has expired, please login again"))
+		return
+	}
+
+	if user, err = users.UserFromID(c.Request.Context(), userID); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnauthorized, api.ErrorResponse("authentication failed"))
+		return
+	}
+
+	ip := c.ClientIP()
+	if retryAfter := s.logins.Check(ip, user.Username); retryAfter > 0 {
+		tooManyLogins(c, retryAfter)
+		return
+	}
+
+	if user.IsLocked() {
+		tooManyLogins(c, time.Until(user.LockedUntil.Time))
+		return
+	}
+
+	if user.IsDisabled() {
+		c.JSON(http.StatusForbidden, api.ErrorResponse("account has been disabled"))
+		return
+	}
+
+	// MFA may have been disabled since the challenge token was issued.
+	if factor, err = users.GetMFA(c.Request.Context(), user.ID); err != nil || !factor.IsEnabled() {
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			sentry.Error(c).Err(err).Msg("could not fetch mfa of user")
+		}
+		c.JSON(http.StatusUnauthorized, api.ErrorResponse("mfa challenge is invalid or has expired, please login again"))
+		return
+	}
+
+	if err = verifySecondFactor(c.Request.Context(), factor, in.Code); err != nil {
+		if !isInvalidCode(err) {
+			sentry.Error(c).Err(err).Msg("could not verify second factor")
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+			return
+		}
+
+		c.Error(err)
+		s.failedLogin(c, ip, user.Username)
+		if err = user.FailedLogin(c.Request.Context(), s.conf.Login.MaxConsecutive, s.conf.Login.Lockout); err != nil {
+			sentry.Warn(c).Err(err).Msg("could not record failed login")
+		}
+
+		c.JSON(http.StatusUnauthorized, api.ErrorResponse("invalid authentication code"))
+		return
+	}
+
+	s.logins.Succeed(user.Username)
+	if err = user.ResetFailedLogins(c.Request.Context()); err != nil {
+		sentry.Warn(c).Err(err).Msg("could not reset failed logins")
+	}
+
+	s.completeLogin(c, user)
+}
+
+// MFAStatus returns whether the authenticated user has enabled multi-factor
+// authentication and how many of their recovery codes have not been used.
+func (s *Server) MFAStatus(c *gin.Context) {
+	var (
+		err    error
+		userID int64
+		factor *users.MFA
+	)
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if factor, err = users.GetMFA(c.Request.Context(), userID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		sentry.Error(c).Err(err).Msg("could not fetch mfa of user")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	out := &api.MFAStatus{Enabled: factor.IsEnabled()}
+	if out.Enabled {
+		if out.RecoveryCodes, err = users.CountRecoveryCodes(c.Request.Context(), userID); err != nil {
+			sentry.Error(c).Err(err).Msg("could not count recovery codes of user")
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+			return
+		}
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// EnrollMFA creates a new TOTP secret for the authenticated user after verifying their
+// password. Multi-factor authentication is not enabled until the user confirms a code
+// from their authenticator with ConfirmMFA.
+func (s *Server) EnrollMFA(c *gin.Context) {
+	var (
+		err    error
+		user   *users.User
+		secret string
+	)
+
+	if user, err = s.confirmPassword(c); err != nil {
+		return
+	}
+
+	if secret, err = mfa.NewSecret(); err != nil {
+		sentry.Error(c).Err(err).Msg("could not create totp secret")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not enroll authenticator"))
+		return
+	}
+
+	if _, err = user.EnrollMFA(c.Request.Context(), secret); err != nil {
+		if errors.Is(err, users.ErrMFAEnabled) {
+			c.JSON(http.StatusConflict, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not enroll mfa in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not enroll authenticator"))
+		return
+	}
+
+	noStore(c)
+	c.JSON(http.StatusOK, &api.MFAEnrollment{
+		Secret: secret,
+		URI:    mfa.URI(mfaIssuer, user.Email, secret),
+	})
+}
+
+// ConfirmMFA enables multi-factor authentication for the authenticated user once they
+// have entered a valid code from the authenticator they enrolled, returning the recovery
+// codes that the user can login with if they lose their authenticator.
+func (s *Server) ConfirmMFA(c *gin.Context) {
+	var (
+		err     error
+		in      *api.MFAConfirmRequest
+		userID  int64
+		factor  *users.MFA
+		counter int64
+	)
+
+	if err = c.BindJSON(&in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse confirm mfa request"))
+		return
+	}
+
+	if in.Code == "" {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("code is required"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if factor, err = users.GetMFA(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(users.ErrMFANotEnrolled))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not fetch mfa of user")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if factor.IsEnabled() {
+		c.JSON(http.StatusConflict, api.ErrorResponse(users.ErrMFAEnabled))
+		return
+	}
+
+	if counter, err = mfa.Validate(factor.Secret, in.Code, time.Now()); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+		return
+	}
+
+	var (
+		codes  []string
+		hashes []string
+	)
+	if codes, hashes, err = mfa.NewRecoveryCodes(); err != nil {
+		sentry.Error(c).Err(err).Msg("could not create recovery codes")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not enable multi-factor authentication"))
+		return
+	}
+
+	if err = factor.Enable(c.Request.Context(), counter, hashes); err != nil {
+		if errors.Is(err, users.ErrMFAEnabled) {
+			c.JSON(http.StatusConflict, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not enable mfa in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not enable multi-factor authentication"))
+		return
+	}
+
+	noStore(c)
+	c.JSON(http.StatusOK, &api.RecoveryCodes{Codes: codes})
+}
+
+// DisableMFA removes the authenticator and the recovery codes of the authenticated user
+// after verifying their password.
+func (s *Server) DisableMFA(c *gin.Context) {
+	var (
+		err  error
+		user *users.User
+	)
+
+	if user, err = s.confirmPassword(c); err != nil {
+		return
+	}
+
+	if err = users.DisableMFA(c.Request.Context(), user.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse("multi-factor authentication is not enabled"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not disable mfa in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not disable multi-factor authentication"))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the authenticated user after
+// verifying their password so that codes that have been lost can no longer be used.
+func (s *Server) RegenerateRecoveryCodes(c *gin.Context) {
+	var (
+		err    error
+		user   *users.User
+		factor *users.MFA
+	)
+
+	if user, err = s.confirmPassword(c); err != nil {
+		return
+	}
+
+	if factor, err = users.GetMFA(c.Request.Context(), user.ID); err != nil || !factor.IsEnabled() {
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			sentry.Error(c).Err(err).Msg("could not fetch mfa of user")
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("multi-factor authentication is not enabled"))
+		return
+	}
+
+	var (
+		codes  []string
+		hashes []string
+	)
+	if codes, hashes, err = mfa.NewRecoveryCodes(); err != nil {
+		sentry.Error(c).Err(err).Msg("could not create recovery codes")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not generate recovery codes"))
+		return
+	}
+
+	if err = users.ReplaceRecoveryCodes(c.Request.Context(), user.ID, hashes); err != nil {
+		sentry.Error(c).Err(err).Msg("could not replace recovery codes in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not generate recovery codes"))
+		return
+	}
+
+	noStore(c)
+	c.JSON(http.StatusOK, &api.RecoveryCodes{Codes: codes})
+}
+
+// Fetches the authenticated user and verifies the password in the request so that the
+// second factor of the user cannot be changed with a stolen or impersonated session,
+// writing an error response to the request and returning an error if it fails.
+func (s *Server) confirmPassword(c *gin.Context) (user *users.User, err error) {
+	var in *api.MFARequest
+	if err = c.BindJSON(&in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse mfa request"))
+		return nil, err
+	}
+
+	if in.Password == "" {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("password is required"))
+		return nil, errors.New("missing required field")
+	}
+
+	if user, err = s.currentUser(c); err != nil {
+		return nil, err
+	}
+
+	if err = verifyPassword(c, user, in.Password); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Verifies the TOTP code or recovery code of the user, ensuring that neither can be
+// used more than once.
+func verifySecondFactor(ctx context.Context, factor *users.MFA, code string) (err error) {
+	if mfa.IsRecoveryCode(code) {
+		return users.UseRecoveryCode(ctx, factor.UserID, mfa.NormalizeRecoveryCode(code))
+	}
+
+	var counter int64
+	if counter, err = mfa.Validate(factor.Secret, code, time.Now()); err != nil {
+		return err
+	}
+	return factor.UseCode(ctx, counter)
+}
+
+func isInvalidCode(err error) bool {
+	return errors.Is(err, mfa.ErrInvalidCode) ||
+		errors.Is(err, users.ErrCodeReused) ||
+		errors.Is(err, users.ErrInvalidRecoveryCode)
+}
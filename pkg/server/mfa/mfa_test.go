@@ -0,0 +1,116 @@
+package mfa_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/mfa"
+	"github.com/bbengfort/epistolary/pkg/server/passwd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCode(t *testing.T) {
+	// Test vectors from RFC 6238 appendix B for SHA1, truncated to six digits.
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		ts   int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tc := range tests {
+		code, err := mfa.Code(secret, mfa.Counter(time.Unix(tc.ts, 0)))
+		require.NoError(t, err)
+		require.Equal(t, tc.code, code, "unexpected code at %d", tc.ts)
+	}
+
+	_, err := mfa.Code("not base32!", 1)
+	require.ErrorIs(t, err, mfa.ErrInvalidSecret)
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := mfa.NewSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	now := time.Now()
+	counter := mfa.Counter(now)
+
+	// Codes from the adjacent time steps are accepted to allow for clock skew
+	for _, step := range []int64{counter - 1, counter, counter + 1} {
+		code, err := mfa.Code(secret, step)
+		require.NoError(t, err)
+
+		actual, err := mfa.Validate(secret, code, now)
+		require.NoError(t, err)
+		require.Equal(t, step, actual)
+	}
+
+	for _, step := range []int64{counter - 2, counter + 2} {
+		code, err := mfa.Code(secret, step)
+		require.NoError(t, err)
+
+		_, err = mfa.Validate(secret, code, now)
+		require.ErrorIs(t, err, mfa.ErrInvalidCode)
+	}
+
+	for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+		_, err = mfa.Validate(secret, code, now)
+		require.ErrorIs(t, err, mfa.ErrInvalidCode, code)
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := mfa.URI("Epistolary", "jane@example.com", "JBSWY3DPEHPK3PXP")
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", u.Scheme)
+	require.Equal(t, "totp", u.Host)
+	require.Equal(t, "/Epistolary:jane@example.com", u.Path)
+
+	query := u.Query()
+	require.Equal(t, "JBSWY3DPEHPK3PXP", query.Get("secret"))
+	require.Equal(t, "Epistolary", query.Get("issuer"))
+	require.Equal(t, "SHA1", query.Get("algorithm"))
+	require.Equal(t, "6", query.Get("digits"))
+	require.Equal(t, "30", query.Get("period"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := mfa.NewRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, mfa.RecoveryCodes)
+	require.Len(t, hashes, mfa.RecoveryCodes)
+
+	seen := make(map[string]struct{})
+	for i, code := range codes {
+		require.Regexp(t, `^[a-z2-9]{5}-[a-z2-9]{5}$`, code)
+		require.True(t, mfa.IsRecoveryCode(code))
+
+		// The derived key of the code is salted so it can only be verified
+		verified, err := passwd.VerifyDerivedKey(hashes[i], mfa.NormalizeRecoveryCode(code))
+		require.NoError(t, err)
+		require.True(t, verified)
+
+		_, ok := seen[code]
+		require.False(t, ok, "duplicate recovery code")
+		seen[code] = struct{}{}
+	}
+
+	hash, err := mfa.HashRecoveryCode(codes[0])
+	require.NoError(t, err)
+	require.NotEqual(t, hashes[0], hash, "expected the derived key to be salted")
+
+	// Recovery codes are normalized before they are hashed
+	require.Equal(t, "abcdefghjk", mfa.NormalizeRecoveryCode("ABCDE FGHJK"))
+	require.Equal(t, "abcdefghjk", mfa.NormalizeRecoveryCode("abcde-fghjk"))
+	require.False(t, mfa.IsRecoveryCode("123456"))
+}
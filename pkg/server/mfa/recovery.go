@@ -0,0 +1,83 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"strings"
+
+	"github.com/bbengfort/epistolary/pkg/server/passwd"
+)
+
+const (
+	// Number of recovery codes that are generated when MFA is enabled.
+	RecoveryCodes = 10
+
+	// Recovery codes are two groups of five characters from the alphabet, which omits
+	// characters that are easily confused when the codes are written down.
+	recoveryGroup    = 5
+	recoveryAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// NewRecoveryCodes creates random recovery codes and the derived keys that are stored.
+// The codes must be shown to the user since they cannot be recovered.
+func NewRecoveryCodes() (codes, hashes []string, err error) {
+	codes = make([]string, 0, RecoveryCodes)
+	hashes = make([]string, 0, RecoveryCodes)
+
+	for i := 0; i < RecoveryCodes; i++ {
+		var code, hash string
+		if code, err = newRecoveryCode(); err != nil {
+			return nil, nil, err
+		}
+
+		if hash, err = HashRecoveryCode(code); err != nil {
+			return nil, nil, err
+		}
+
+		codes = append(codes, code)
+		hashes = append(hashes, hash)
+	}
+	return codes, hashes, nil
+}
+
+func newRecoveryCode() (_ string, err error) {
+	// Random bytes at or above the largest multiple of the length of the alphabet are
+	// rejected so that every character of the alphabet is equally likely.
+	limit := 256 - 256%len(recoveryAlphabet)
+	chars := make([]byte, 0, 2*recoveryGroup)
+	buf := make([]byte, 2*recoveryGroup)
+
+	for len(chars) < cap(chars) {
+		if _, err = rand.Read(buf); err != nil {
+			return "", err
+		}
+
+		for _, b := range buf {
+			if int(b) < limit && len(chars) < cap(chars) {
+				chars = append(chars, recoveryAlphabet[int(b)%len(recoveryAlphabet)])
+			}
+		}
+	}
+	return string(chars[:recoveryGroup]) + "-" + string(chars[recoveryGroup:]), nil
+}
+
+// HashRecoveryCode returns a salted derived key of the normalized recovery code for
+// storage. Recovery codes are short enough to be brute-forced from a fast hash, so
+// they are verified against the derived keys of the user with passwd.VerifyDerivedKey.
+func HashRecoveryCode(code string) (string, error) {
+	return passwd.CreateDerivedKey(NormalizeRecoveryCode(code))
+}
+
+// NormalizeRecoveryCode normalizes the recovery code entered by the user, ignoring case,
+// spaces, and hyphens.
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// IsRecoveryCode returns true if the code looks like a recovery code rather than a
+// TOTP code so that the code entered by the user can be checked against the right
+// factor.
+func IsRecoveryCode(code string) bool {
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	return len(code) == 2*recoveryGroup
+}
@@ -0,0 +1,129 @@
+/*
+Package mfa implements the second factor that users can enable for their accounts:
+time-based one-time passwords (TOTP, RFC 6238) generated by an authenticator app and
+single-use recovery codes in case the authenticator is lost. The secrets are stored by
+the users package; this package only generates and verifies them.
+*/
+package mfa
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP parameters; these are the defaults of most authenticator apps, which ignore the
+// parameters in the otpauth URI.
+const (
+	Digits    = 6
+	Period    = 30 * time.Second
+	Skew      = 1
+	Algorithm = "SHA1"
+
+	// Length of the secret in bytes (RFC 4226 section 4 recommends 160 bits).
+	secretLength = 20
+)
+
+var (
+	ErrInvalidSecret = errors.New("totp secret must be base32 encoded")
+	ErrInvalidCode   = errors.New("invalid authentication code")
+)
+
+// Secrets are base32 encoded without padding as expected by authenticator apps.
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewSecret creates a random base32 encoded TOTP secret.
+func NewSecret() (string, error) {
+	buf := make([]byte, secretLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(buf), nil
+}
+
+// URI returns the otpauth URI of the secret that authenticator apps scan as a QR code
+// to add the account, labeled with the issuer and the account name of the user.
+func URI(issuer, account, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", Algorithm)
+	params.Set("digits", fmt.Sprintf("%d", Digits))
+	params.Set("period", fmt.Sprintf("%d", int(Period.Seconds())))
+
+	u := &url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: params.Encode(),
+	}
+	return u.String()
+}
+
+// Counter returns the TOTP time step of the timestamp.
+func Counter(ts time.Time) int64 {
+	return ts.Unix() / int64(Period.Seconds())
+}
+
+// Code returns the TOTP code of the secret for the time step (RFC 4226 section 5.3).
+func Code(secret string, counter int64) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", Digits, value%mod), nil
+}
+
+// Validate the code against the secret at the timestamp, allowing for the clock of the
+// authenticator to be off by up to Skew time steps. The time step of the code is
+// returned so that the caller can prevent the code from being used again.
+func Validate(secret, code string, ts time.Time) (counter int64, err error) {
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != Digits {
+		return 0, ErrInvalidCode
+	}
+
+	now := Counter(ts)
+	for step := now - Skew; step <= now+Skew; step++ {
+		var expected string
+		if expected, err = Code(secret, step); err != nil {
+			return 0, err
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, nil
+		}
+	}
+	return 0, ErrInvalidCode
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
@@ -0,0 +1,244 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/epistles"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) ListNotes(c *gin.Context) {
+	var (
+		err       error
+		readingID int64
+		userID    int64
+		notes     []*epistles.Note
+	)
+
+	if readingID, err = strconv.ParseInt(c.Param("readingID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("reading not found"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if notes, err = epistles.ListNotes(c.Request.Context(), readingID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("reading not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not list notes from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch notes"))
+		return
+	}
+
+	out := &api.NoteList{
+		Notes: make([]*api.Note, 0, len(notes)),
+	}
+
+	for _, note := range notes {
+		out.Notes = append(out.Notes, apiNote(note))
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (s *Server) CreateNote(c *gin.Context) {
+	var (
+		err       error
+		readingID int64
+		userID    int64
+	)
+
+	note := &api.Note{}
+	if err = c.BindJSON(note); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse note input"))
+		return
+	}
+
+	if readingID, err = strconv.ParseInt(c.Param("readingID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("reading not found"))
+		return
+	}
+
+	if note.ID != 0 {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("note id cannot be specified on create"))
+		return
+	}
+
+	// Ensure the endpoint matches the reading specified by the note
+	if note.ReadingID != 0 && note.ReadingID != readingID {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("reading id must match endpoint"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	model := &epistles.Note{
+		EpistleID: readingID,
+		UserID:    userID,
+		Body:      note.Body,
+		Highlight: sql.NullString{String: note.Highlight, Valid: note.Highlight != ""},
+	}
+
+	if err = model.Create(c.Request.Context()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("reading not found"))
+			return
+		}
+
+		if errors.Is(err, epistles.ErrEmptyNote) || errors.Is(err, epistles.ErrNoteTooLong) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not create note in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not create note"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiNote(model))
+}
+
+func (s *Server) UpdateNote(c *gin.Context) {
+	var (
+		err       error
+		readingID int64
+		noteID    int64
+		userID    int64
+	)
+
+	note := &api.Note{}
+	if err = c.BindJSON(note); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse note input"))
+		return
+	}
+
+	if readingID, err = strconv.ParseInt(c.Param("readingID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("reading not found"))
+		return
+	}
+
+	if noteID, err = strconv.ParseInt(c.Param("noteID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("note not found"))
+		return
+	}
+
+	// Populate the IDs from the endpoint if they were not submitted
+	if note.ID == 0 {
+		note.ID = noteID
+	}
+
+	if note.ReadingID == 0 {
+		note.ReadingID = readingID
+	}
+
+	// Ensure the endpoint matches the IDs specified by the note
+	if note.ID != noteID || note.ReadingID != readingID {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("id must match endpoint"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	model := &epistles.Note{
+		ID:        noteID,
+		EpistleID: readingID,
+		UserID:    userID,
+		Body:      note.Body,
+		Highlight: sql.NullString{String: note.Highlight, Valid: note.Highlight != ""},
+	}
+
+	if err = model.Update(c.Request.Context()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("note not found"))
+			return
+		}
+
+		if errors.Is(err, epistles.ErrEmptyNote) || errors.Is(err, epistles.ErrNoteTooLong) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not update note in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not update note"))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiNote(model))
+}
+
+func (s *Server) DeleteNote(c *gin.Context) {
+	var (
+		err       error
+		readingID int64
+		noteID    int64
+		userID    int64
+	)
+
+	if readingID, err = strconv.ParseInt(c.Param("readingID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("reading not found"))
+		return
+	}
+
+	if noteID, err = strconv.ParseInt(c.Param("noteID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("note not found"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if err = epistles.DeleteNote(c.Request.Context(), noteID, readingID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("note not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not delete note from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func apiNote(model *epistles.Note) *api.Note {
+	return &api.Note{
+		ID:        model.ID,
+		ReadingID: model.EpistleID,
+		Body:      model.Body,
+		Highlight: model.Highlight.String,
+		Created:   api.Timestamp{Time: model.Created},
+		Modified:  api.Timestamp{Time: model.Modified},
+	}
+}
@@ -0,0 +1,513 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/oauth"
+	"github.com/bbengfort/epistolary/pkg/server/tokens"
+	"github.com/bbengfort/epistolary/pkg/server/users"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/gin-gonic/gin"
+)
+
+// OAuth2 error codes (RFC 6749 sections 4.1.2.1 and 5.2 and OpenID Connect Core 3.1.2.6).
+const (
+	errInvalidRequest          = "invalid_request"
+	errInvalidClient           = "invalid_client"
+	errInvalidGrant            = "invalid_grant"
+	errUnsupportedGrantType    = "unsupported_grant_type"
+	errUnsupportedResponseType = "unsupported_response_type"
+	errAccessDenied            = "access_denied"
+	errLoginRequired           = "login_required"
+	errServerError             = "server_error"
+)
+
+// OAuthAuthorize is the authorization endpoint of the authorization code flow. If the
+// user is logged in to Epistolary an authorization code is issued to the client and the
+// user is redirected back to the client, otherwise the user is redirected to login in
+// the front-end app, which returns to this endpoint once the user has logged in.
+// Clients are registered by administrators, so the user is not asked for consent.
+func (s *Server) OAuthAuthorize(c *gin.Context) {
+	var (
+		err      error
+		client   *oauth.Client
+		redirect string
+		claims   *tokens.Claims
+	)
+
+	// Errors with the client or the redirect URI must not redirect the user since the
+	// redirect URI cannot be trusted (RFC 6749 section 4.1.2.1).
+	if client, err = oauth.GetClient(c.Request.Context(), c.Query("client_id")); err != nil {
+		if errors.Is(err, oauth.ErrUnknownClient) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not fetch oauth client")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if redirect, err = client.RedirectURI(c.Query("redirect_uri")); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+		return
+	}
+
+	state := c.Query("state")
+	if c.Query("response_type") != "code" {
+		authorizeError(c, redirect, state, errUnsupportedResponseType, "only the authorization code flow is supported")
+		return
+	}
+
+	// The redirect URI is stored as requested since the token request must match it.
+	code := &oauth.Code{
+		ClientID:        client.ID,
+		RedirectURI:     c.Query("redirect_uri"),
+		Scope:           oauth.ParseScope(c.Query("scope")),
+		Nonce:           c.Query("nonce"),
+		Challenge:       c.Query("code_challenge"),
+		ChallengeMethod: c.Query("code_challenge_method"),
+	}
+
+	if err = code.ValidateChallenge(); err != nil {
+		authorizeError(c, redirect, state, errInvalidRequest, err.Error())
+		return
+	}
+
+	if claims, err = s.authenticate(c); err != nil {
+		if c.Query("prompt") == "none" {
+			authorizeError(c, redirect, state, errLoginRequired, "the user is not logged in")
+			return
+		}
+
+		next := s.issuerURL(&url.URL{Path: c.Request.URL.Path, RawQuery: c.Request.URL.RawQuery})
+		c.Redirect(http.StatusFound, s.loginURL(next))
+		return
+	}
+
+	// API keys, impersonated users, and other clients cannot authorize clients on
+	// behalf of the user.
+	if claims.APIKeyID != 0 || claims.Impersonator != "" || claims.ClientID != "" {
+		authorizeError(c, redirect, state, errAccessDenied, "clients can only be authorized by the user")
+		return
+	}
+
+	if code.UserID, err = claims.SubjectID(); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		authorizeError(c, redirect, state, errServerError, "")
+		return
+	}
+
+	if err = code.Issue(c.Request.Context()); err != nil {
+		sentry.Error(c).Err(err).Msg("could not issue authorization code")
+		authorizeError(c, redirect, state, errServerError, "")
+		return
+	}
+
+	params := url.Values{"code": []string{code.Code}}
+	if state != "" {
+		params.Set("state", state)
+	}
+	c.Redirect(http.StatusFound, withQuery(redirect, params))
+}
+
+// OAuthToken is the token endpoint that exchanges an authorization code or a refresh
+// token for access and refresh tokens. Exchanging an authorization code creates a new
+// session for the user, which the user can revoke like any other session. An ID token
+// is also issued if the openid scope was authorized. The tokens are limited to the
+// authorized scope and only have the permissions of the authorized permission scopes
+// that the user also has, so they cannot be used for first-party endpoints.
+func (s *Server) OAuthToken(c *gin.Context) {
+	var (
+		err    error
+		client *oauth.Client
+		claims *tokens.Claims
+		out    *api.OAuthToken
+	)
+
+	if client, err = s.oauthClient(c); err != nil {
+		return
+	}
+
+	out = &api.OAuthToken{TokenType: "Bearer"}
+	switch c.PostForm("grant_type") {
+	case "authorization_code":
+		var code *oauth.Code
+		if code, err = oauth.Exchange(c.Request.Context(), c.PostForm("code")); err != nil {
+			if errors.Is(err, oauth.ErrInvalidCode) {
+				oauthError(c, http.StatusBadRequest, errInvalidGrant, err.Error())
+				return
+			}
+
+			sentry.Error(c).Err(err).Msg("could not exchange authorization code")
+			oauthError(c, http.StatusInternalServerError, errServerError, "")
+			return
+		}
+
+		if code.ClientID != client.ID || code.RedirectURI != c.PostForm("redirect_uri") {
+			oauthError(c, http.StatusBadRequest, errInvalidGrant, "authorization code was not issued to the client or redirect uri")
+			return
+		}
+
+		if err = code.Verify(c.PostForm("code_verifier")); err != nil {
+			oauthError(c, http.StatusBadRequest, errInvalidGrant, err.Error())
+			return
+		}
+
+		var user *users.User
+		if user, err = users.UserFromID(c.Request.Context(), code.UserID); err != nil {
+			sentry.Error(c).Err(err).Msg("could not retreive user of authorization code")
+			oauthError(c, http.StatusBadRequest, errInvalidGrant, "could not authorize user")
+			return
+		}
+
+		if user.IsDisabled() {
+			oauthError(c, http.StatusBadRequest, errInvalidGrant, ErrUserDisabled.Error())
+			return
+		}
+
+		claims = oauthClaims(c.Request.Context(), user, client.ClientID, code.Scope)
+		if out.AccessToken, out.RefreshToken, err = s.issueTokens(c, user.ID, claims); err != nil {
+			sentry.Error(c).Err(err).Msg("could not create access and refresh tokens")
+			oauthError(c, http.StatusInternalServerError, errServerError, "")
+			return
+		}
+
+		out.Scope = claims.Scope
+		if code.HasScope(oauth.ScopeOpenID) {
+			if out.IDToken, err = s.idToken(user, claims, code, client); err != nil {
+				sentry.Error(c).Err(err).Msg("could not create id token")
+				oauthError(c, http.StatusInternalServerError, errServerError, "")
+				return
+			}
+		}
+
+	case "refresh_token":
+		if claims, out.AccessToken, out.RefreshToken, err = s.refreshTokens(c, c.PostForm("refresh_token"), client.ClientID); err != nil {
+			c.Error(err)
+			oauthError(c, http.StatusBadRequest, errInvalidGrant, "invalid, expired, or revoked refresh token")
+			return
+		}
+		out.Scope = claims.Scope
+
+	default:
+		oauthError(c, http.StatusBadRequest, errUnsupportedGrantType, "grant type must be authorization_code or refresh_token")
+		return
+	}
+
+	var expires time.Time
+	if expires, err = tokens.ExpiresAt(out.AccessToken); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse access token expiration")
+		oauthError(c, http.StatusInternalServerError, errServerError, "")
+		return
+	}
+	out.ExpiresIn = int64(time.Until(expires).Seconds())
+
+	noStore(c)
+	c.JSON(http.StatusOK, out)
+}
+
+// OAuthUserInfo is the OpenID Connect userinfo endpoint that returns the profile of the
+// user of the access token; tokens issued to clients only return the profile and email
+// of the user if the corresponding scopes were authorized.
+func (s *Server) OAuthUserInfo(c *gin.Context) {
+	var (
+		err    error
+		userID int64
+		claims *tokens.Claims
+		user   *users.User
+	)
+
+	if claims, err = GetUserClaims(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not fetch user claims")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if userID, err = claims.SubjectID(); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if user, err = users.UserFromID(c.Request.Context(), userID); err != nil {
+		sentry.Error(c).Err(err).Msg("could not retreive user from id on claims")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	out := &api.UserInfo{Subject: claims.Subject}
+	if claims.ClientID == "" || oauth.HasScope(claims.Scope, oauth.ScopeProfile) {
+		out.Name = user.FullName.String
+		out.PreferredUsername = user.Username
+	}
+
+	if claims.ClientID == "" || oauth.HasScope(claims.Scope, oauth.ScopeEmail) {
+		out.Email = user.Email
+		out.EmailVerified = user.EmailVerified.Valid
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// OAuthRevoke revokes the session of an access or refresh token (RFC 7009) so that the
+// refresh token can no longer be used; access tokens remain valid until they expire.
+// Clients can only revoke the sessions that were created for them. The response is the
+// same whether or not the token was valid.
+func (s *Server) OAuthRevoke(c *gin.Context) {
+	client, err := s.oauthClient(c)
+	if err != nil {
+		return
+	}
+
+	if claims, err := s.tokens.Parse(c.PostForm("token")); err == nil && claims.ID != "" && claims.ClientID == client.ClientID {
+		if userID, err := claims.SubjectID(); err == nil {
+			if err = users.RevokeClientSession(c.Request.Context(), claims.ID, userID, client.ClientID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+				sentry.Error(c).Err(err).Msg("could not revoke session")
+				oauthError(c, http.StatusServiceUnavailable, errServerError, "")
+				return
+			}
+		}
+	}
+
+	noStore(c)
+	c.Status(http.StatusOK)
+}
+
+// AdminListOAuthClients lists the clients that are registered to authenticate users.
+func (s *Server) AdminListOAuthClients(c *gin.Context) {
+	clients, err := oauth.ListClients(c.Request.Context())
+	if err != nil {
+		sentry.Error(c).Err(err).Msg("could not list oauth clients from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch oauth clients"))
+		return
+	}
+
+	out := &api.OAuthClientList{
+		Clients: make([]*api.OAuthClient, 0, len(clients)),
+	}
+
+	for _, client := range clients {
+		out.Clients = append(out.Clients, apiOAuthClient(client))
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// AdminCreateOAuthClient registers a new client, returning the secret of confidential
+// clients, which cannot be retrieved again.
+func (s *Server) AdminCreateOAuthClient(c *gin.Context) {
+	var (
+		err error
+		in  *api.OAuthClient
+	)
+
+	if err = c.BindJSON(&in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse oauth client"))
+		return
+	}
+
+	if in.ClientID != "" || in.Secret != "" {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("cannot specify the id or secret of a new oauth client"))
+		return
+	}
+
+	client := &oauth.Client{
+		Name:         in.Name,
+		RedirectURIs: in.RedirectURIs,
+	}
+
+	if err = client.Create(c.Request.Context(), !in.Public); err != nil {
+		if isOAuthClientValidationError(err) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not create oauth client")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not create oauth client"))
+		return
+	}
+
+	out := apiOAuthClient(client)
+	out.Secret = client.Secret
+	c.JSON(http.StatusCreated, out)
+}
+
+// AdminDeleteOAuthClient removes the client so that it can no longer authenticate users.
+func (s *Server) AdminDeleteOAuthClient(c *gin.Context) {
+	if err := oauth.DeleteClient(c.Request.Context(), c.Param("clientID")); err != nil {
+		if errors.Is(err, oauth.ErrUnknownClient) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("oauth client not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not delete oauth client")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not delete oauth client"))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Authenticates the client of a token or revocation request using HTTP basic auth or
+// the client credentials in the form (RFC 6749 section 2.3.1). Public clients only
+// identify themselves with their client ID. If the client cannot be authenticated an
+// error response is written and an error returned.
+func (s *Server) oauthClient(c *gin.Context) (client *oauth.Client, err error) {
+	clientID, secret, basic := c.Request.BasicAuth()
+	if basic {
+		// Credentials are form encoded before they are base64 encoded in the header.
+		clientID, _ = url.QueryUnescape(clientID)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		clientID, secret = c.PostForm("client_id"), c.PostForm("client_secret")
+	}
+
+	if client, err = oauth.GetClient(c.Request.Context(), clientID); err == nil && !client.VerifySecret(secret) {
+		err = oauth.ErrUnknownClient
+	}
+
+	if err != nil {
+		if !errors.Is(err, oauth.ErrUnknownClient) {
+			sentry.Error(c).Err(err).Msg("could not fetch oauth client")
+			oauthError(c, http.StatusInternalServerError, errServerError, "")
+			return nil, err
+		}
+
+		if basic {
+			c.Header("WWW-Authenticate", `Basic realm="epistolary"`)
+		}
+		oauthError(c, http.StatusUnauthorized, errInvalidClient, "client authentication failed")
+		return nil, err
+	}
+	return client, nil
+}
+
+// Creates the OpenID Connect ID token of the user for the client, adding the profile
+// and email claims if those scopes were authorized.
+func (s *Server) idToken(user *users.User, claims *tokens.Claims, code *oauth.Code, client *oauth.Client) (string, error) {
+	id := &tokens.IDClaims{Nonce: code.Nonce}
+	id.Subject = claims.Subject
+
+	if code.HasScope(oauth.ScopeProfile) {
+		id.Name = user.FullName.String
+		id.PreferredUsername = user.Username
+	}
+
+	if code.HasScope(oauth.ScopeEmail) {
+		verified := user.EmailVerified.Valid
+		id.Email = user.Email
+		id.EmailVerified = &verified
+	}
+
+	return s.tokens.CreateIDToken(id, s.issuerURL(&url.URL{Path: "/"}), client.ClientID)
+}
+
+// Creates the claims for the access and refresh tokens issued to a client, which only
+// identify the user within the authorized scope. Like API keys, the permission scopes
+// are limited to the permissions that the user currently has.
+func oauthClaims(ctx context.Context, user *users.User, clientID, scope string) *tokens.Claims {
+	claims := &tokens.Claims{ClientID: clientID, Scope: scope}
+	claims.SetSubjectID(user.ID)
+
+	if oauth.HasPermissionScope(scope) {
+		permissions, _ := user.Permissions(ctx, false)
+		claims.Scope, claims.Permissions = oauth.LimitScope(scope, permissions)
+	}
+
+	if oauth.HasScope(scope, oauth.ScopeProfile) {
+		claims.Name = user.FullName.String
+		claims.Username = user.Username
+	}
+
+	if oauth.HasScope(scope, oauth.ScopeEmail) {
+		claims.Email = user.Email
+	}
+	return claims
+}
+
+// Returns the url of the reference on the API, e.g. the endpoints of the OpenID
+// configuration; the issuer of the OpenID configuration is the root of the API.
+func (s *Server) issuerURL(ref *url.URL) string {
+	base, err := url.Parse(s.conf.Token.Issuer)
+	if err != nil {
+		base = &url.URL{}
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// Returns the url of the login page of the front-end app, which redirects the user to
+// the next url once they have logged in.
+func (s *Server) loginURL(next string) string {
+	base, err := url.Parse(s.conf.Token.Audience)
+	if err != nil {
+		base = &url.URL{}
+	}
+
+	ref := &url.URL{Path: "/login", RawQuery: url.Values{"next": []string{next}}.Encode()}
+	return base.ResolveReference(ref).String()
+}
+
+// Redirects the user back to the client with the error (RFC 6749 section 4.1.2.1).
+func authorizeError(c *gin.Context, redirect, state, code, description string) {
+	params := url.Values{"error": []string{code}}
+	if description != "" {
+		params.Set("error_description", description)
+	}
+
+	if state != "" {
+		params.Set("state", state)
+	}
+	c.Redirect(http.StatusFound, withQuery(redirect, params))
+}
+
+// Responds with an OAuth2 error from the token or revocation endpoints.
+func oauthError(c *gin.Context, status int, code, description string) {
+	noStore(c)
+	c.JSON(status, &api.OAuthError{Error: code, Description: description})
+}
+
+// Responses with tokens or credentials must not be cached (RFC 6749 section 5.1).
+func noStore(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+}
+
+// Adds the params to the query of the redirect URI, keeping its existing query.
+func withQuery(redirect string, params url.Values) string {
+	u, err := url.Parse(redirect)
+	if err != nil {
+		return redirect
+	}
+
+	query := u.Query()
+	for key, vals := range params {
+		query[key] = vals
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func isOAuthClientValidationError(err error) bool {
+	return errors.Is(err, oauth.ErrNameRequired) ||
+		errors.Is(err, oauth.ErrNameLength) ||
+		errors.Is(err, oauth.ErrNoRedirectURIs) ||
+		errors.Is(err, oauth.ErrTooManyRedirectURIs) ||
+		errors.Is(err, oauth.ErrInvalidRedirectURI)
+}
+
+func apiOAuthClient(client *oauth.Client) *api.OAuthClient {
+	return &api.OAuthClient{
+		ClientID:     client.ClientID,
+		Name:         client.Name,
+		RedirectURIs: client.RedirectURIs,
+		Public:       client.IsPublic(),
+		Created:      api.Timestamp{Time: client.Created},
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Package oauth stores the third-party clients and the authorization codes that allow
+Epistolary to act as an OAuth2 and OpenID Connect provider using the authorization code
+flow with PKCE (RFC 7636). Clients are registered by administrators and are trusted, so
+users are not asked to consent to the scopes that a client requests.
+*/
+package oauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/utils/secrets"
+	"github.com/lib/pq"
+	ulid "github.com/oklog/ulid/v2"
+)
+
+// Limits on the clients that can be registered.
+const (
+	MaxNameLength   = 255
+	MaxRedirectURIs = 8
+)
+
+var (
+	ErrUnknownClient       = errors.New("unknown oauth client")
+	ErrNameRequired        = errors.New("oauth client name is required")
+	ErrNameLength          = errors.New("oauth client name must be no more than 255 characters")
+	ErrNoRedirectURIs      = errors.New("oauth client must have at least one redirect uri")
+	ErrTooManyRedirectURIs = errors.New("oauth client can have no more than 8 redirect uris")
+	ErrInvalidRedirectURI  = errors.New("redirect uri must be an absolute uri without a fragment that uses https unless it is a loopback address")
+	ErrRedirectMismatch    = errors.New("redirect uri is not registered for the oauth client")
+)
+
+// Client is a third-party application that authenticates users with the authorization
+// code flow. Public clients (such as the browser extension) cannot keep a secret and
+// must use PKCE; confidential clients must also authenticate with their secret, which
+// is only available when the client is created.
+type Client struct {
+	ID           int64
+	ClientID     string
+	Name         string
+	Secret       string
+	RedirectURIs []string
+	Created      time.Time
+	Modified     time.Time
+	secret       []byte
+}
+
+// IsPublic returns true if the client does not have a secret.
+func (c *Client) IsPublic() bool {
+	return len(c.secret) == 0
+}
+
+// Validate the name and redirect URIs of the client.
+func (c *Client) Validate() error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return ErrNameRequired
+	}
+
+	if utf8.RuneCountInString(c.Name) > MaxNameLength {
+		return ErrNameLength
+	}
+
+	if len(c.RedirectURIs) == 0 {
+		return ErrNoRedirectURIs
+	}
+
+	if len(c.RedirectURIs) > MaxRedirectURIs {
+		return ErrTooManyRedirectURIs
+	}
+
+	for _, uri := range c.RedirectURIs {
+		if err := ValidateRedirectURI(uri); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateRedirectURI ensures that the redirect URI is absolute and has no fragment
+// (RFC 6749 section 3.1.2). Redirects over plain http are only allowed to loopback
+// addresses for native applications; custom schemes are allowed for extensions.
+func ValidateRedirectURI(uri string) error {
+	u, err := url.Parse(uri)
+	if err != nil || !u.IsAbs() || u.Fragment != "" {
+		return ErrInvalidRedirectURI
+	}
+
+	if u.Scheme == "http" {
+		switch u.Hostname() {
+		case "localhost", "127.0.0.1", "::1":
+		default:
+			return ErrInvalidRedirectURI
+		}
+	}
+	return nil
+}
+
+// RedirectURI returns the registered redirect URI that exactly matches the requested
+// URI. If no URI is requested and the client has only one redirect URI it is returned.
+func (c *Client) RedirectURI(requested string) (string, error) {
+	if requested == "" {
+		if len(c.RedirectURIs) == 1 {
+			return c.RedirectURIs[0], nil
+		}
+		return "", ErrRedirectMismatch
+	}
+
+	for _, uri := range c.RedirectURIs {
+		if uri == requested {
+			return uri, nil
+		}
+	}
+	return "", ErrRedirectMismatch
+}
+
+// VerifySecret returns true if the secret authenticates the client. Public clients are
+// only verified if no secret is given.
+func (c *Client) VerifySecret(secret string) bool {
+	if len(c.secret) == 0 {
+		return secret == ""
+	}
+	return subtle.ConstantTimeCompare(c.secret, secrets.Hash(secret)) == 1
+}
+
+const (
+	createClientSQL = "INSERT INTO oauth_clients (client_id, name, secret, redirect_uris) VALUES ($1, $2, $3, $4) RETURNING id, created, modified"
+)
+
+// Create a new client with a random client ID. If the client is confidential a random
+// secret is created, which must be given to the developer of the client since it
+// cannot be recovered.
+func (c *Client) Create(ctx context.Context, confidential bool) (err error) {
+	if err = c.Validate(); err != nil {
+		return err
+	}
+
+	c.ClientID = strings.ToLower(ulid.Make().String())
+	c.Secret, c.secret = "", nil
+	if confidential {
+		if c.Secret, err = secrets.New(secrets.DefaultLength); err != nil {
+			return err
+		}
+		c.secret = secrets.Hash(c.Secret)
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow(createClientSQL, c.ClientID, c.Name, c.secret, pq.Array(c.RedirectURIs)).Scan(&c.ID, &c.Created, &c.Modified); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const (
+	listClientsSQL = "SELECT id, client_id, name, secret, redirect_uris, created, modified FROM oauth_clients ORDER BY created, id"
+	getClientSQL   = "SELECT id, client_id, name, secret, redirect_uris, created, modified FROM oauth_clients WHERE client_id=$1"
+)
+
+// ListClients returns all registered clients, oldest first.
+func ListClients(ctx context.Context) (clients []*Client, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(listClientsSQL); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clients = make([]*Client, 0)
+	for rows.Next() {
+		client := &Client{}
+		if err = rows.Scan(&client.ID, &client.ClientID, &client.Name, &client.secret, pq.Array(&client.RedirectURIs), &client.Created, &client.Modified); err != nil {
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return clients, nil
+}
+
+// GetClient returns the client with the client ID or ErrUnknownClient if it does not
+// exist.
+func GetClient(ctx context.Context, clientID string) (client *Client, err error) {
+	if clientID == "" {
+		return nil, ErrUnknownClient
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	client = &Client{}
+	if err = tx.QueryRow(getClientSQL, clientID).Scan(&client.ID, &client.ClientID, &client.Name, &client.secret, pq.Array(&client.RedirectURIs), &client.Created, &client.Modified); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUnknownClient
+		}
+		return nil, err
+	}
+
+	tx.Commit()
+	return client, nil
+}
+
+const (
+	deleteClientSQL = "DELETE FROM oauth_clients WHERE client_id=$1"
+)
+
+// DeleteClient removes the client and its outstanding authorization codes. Tokens that
+// have already been issued to the client are not revoked. Returns ErrUnknownClient if
+// the client does not exist.
+func DeleteClient(ctx context.Context, clientID string) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(deleteClientSQL, clientID); err != nil {
+		return err
+	}
+
+	if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return ErrUnknownClient
+	}
+	return tx.Commit()
+}
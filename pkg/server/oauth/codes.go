@@ -0,0 +1,255 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/utils/secrets"
+)
+
+// CodeTTL is how long an authorization code can be exchanged for tokens.
+const CodeTTL = 10 * time.Minute
+
+// PKCE code challenge method (RFC 7636 section 4.2); the plain method is not supported
+// since it does not protect the code if the authorization request is intercepted.
+const ChallengeS256 = "S256"
+
+// Scopes that can be requested by clients; unsupported scopes are ignored. Permission
+// scopes authorize the client to use the API with the permission of the same name if
+// the user has the permission; administrative permissions cannot be authorized.
+const (
+	ScopeOpenID         = "openid"
+	ScopeProfile        = "profile"
+	ScopeEmail          = "email"
+	ScopeEpistlesRead   = "epistles:read"
+	ScopeEpistlesUpdate = "epistles:update"
+	ScopeEpistlesDelete = "epistles:delete"
+)
+
+// Scopes are the supported scopes in the order they are granted.
+var Scopes = []string{ScopeOpenID, ScopeProfile, ScopeEmail, ScopeEpistlesRead, ScopeEpistlesUpdate, ScopeEpistlesDelete}
+
+var permissionScopes = map[string]struct{}{
+	ScopeEpistlesRead:   {},
+	ScopeEpistlesUpdate: {},
+	ScopeEpistlesDelete: {},
+}
+
+var (
+	ErrInvalidCode      = errors.New("invalid, expired, or already used authorization code")
+	ErrChallenge        = errors.New("a code challenge is required")
+	ErrChallengeMethod  = errors.New("code challenge method must be S256")
+	ErrInvalidChallenge = errors.New("code challenge must be 43-128 unreserved characters")
+	ErrInvalidVerifier  = errors.New("code verifier does not match the code challenge")
+)
+
+// Code challenges and verifiers are 43-128 characters from the unreserved set.
+var pkce = regexp.MustCompile(`^[A-Za-z0-9\-._~]{43,128}$`)
+
+// Code is an authorization code issued to a client on behalf of a user. The code is
+// only available when it is issued; only its hash is stored in the database.
+type Code struct {
+	Code            string
+	ClientID        int64
+	UserID          int64
+	RedirectURI     string
+	Scope           string
+	Nonce           string
+	Challenge       string
+	ChallengeMethod string
+	Expires         time.Time
+	Created         time.Time
+}
+
+// ValidateChallenge ensures that the code has an S256 PKCE challenge, which is required
+// for all clients.
+func (c *Code) ValidateChallenge() error {
+	if c.Challenge == "" {
+		return ErrChallenge
+	}
+
+	if c.ChallengeMethod != ChallengeS256 {
+		return ErrChallengeMethod
+	}
+
+	if !pkce.MatchString(c.Challenge) {
+		return ErrInvalidChallenge
+	}
+	return nil
+}
+
+// Verify the PKCE code verifier against the challenge of the code.
+func (c *Code) Verify(verifier string) error {
+	if !pkce.MatchString(verifier) {
+		return ErrInvalidVerifier
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(c.Challenge)) != 1 {
+		return ErrInvalidVerifier
+	}
+	return nil
+}
+
+// HasScope returns true if the scope was granted to the code.
+func (c *Code) HasScope(scope string) bool {
+	return HasScope(c.Scope, scope)
+}
+
+// HasScope returns true if the space delimited granted scopes include the scope.
+func HasScope(granted, scope string) bool {
+	for _, s := range strings.Fields(granted) {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseScope returns the supported scopes of the space delimited scope in a stable
+// order, ignoring unsupported and duplicate scopes (RFC 6749 section 3.3).
+func ParseScope(scope string) string {
+	requested := make(map[string]struct{})
+	for _, s := range strings.Fields(scope) {
+		requested[s] = struct{}{}
+	}
+
+	scopes := make([]string, 0, len(Scopes))
+	for _, s := range Scopes {
+		if _, ok := requested[s]; ok {
+			scopes = append(scopes, s)
+		}
+	}
+	return strings.Join(scopes, " ")
+}
+
+// HasPermissionScope returns true if the space delimited scope includes any permission
+// scopes, which must be limited to the permissions of the user with LimitScope.
+func HasPermissionScope(scope string) bool {
+	for _, s := range strings.Fields(scope) {
+		if _, ok := permissionScopes[s]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// LimitScope removes the permission scopes that are not in the permissions of the user
+// from the space delimited scope, returning the limited scope and the permissions that
+// it grants to the client.
+func LimitScope(scope string, permissions []string) (_ string, granted []string) {
+	has := make(map[string]struct{}, len(permissions))
+	for _, permission := range permissions {
+		has[permission] = struct{}{}
+	}
+
+	scopes := make([]string, 0, len(Scopes))
+	for _, s := range strings.Fields(scope) {
+		if _, ok := permissionScopes[s]; ok {
+			if _, ok := has[s]; !ok {
+				continue
+			}
+			granted = append(granted, s)
+		}
+		scopes = append(scopes, s)
+	}
+	return strings.Join(scopes, " "), granted
+}
+
+const (
+	issueCodeSQL = "INSERT INTO oauth_codes (code, client_id, user_id, redirect_uri, scope, nonce, challenge, challenge_method, expires) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING created"
+)
+
+// Issue a new authorization code that expires after the CodeTTL. The code must be sent
+// to the redirect URI of the client.
+func (c *Code) Issue(ctx context.Context) (err error) {
+	if err = c.ValidateChallenge(); err != nil {
+		return err
+	}
+
+	if c.Code, err = secrets.New(secrets.DefaultLength); err != nil {
+		return err
+	}
+	c.Expires = time.Now().Add(CodeTTL)
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow(issueCodeSQL, secrets.Hash(c.Code), c.ClientID, c.UserID, c.RedirectURI, c.Scope, c.Nonce, c.Challenge, c.ChallengeMethod, c.Expires).Scan(&c.Created); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const (
+	exchangeCodeSQL = "DELETE FROM oauth_codes WHERE code=$1 RETURNING client_id, user_id, redirect_uri, scope, nonce, challenge, challenge_method, expires, created"
+)
+
+// Exchange deletes the authorization code so that it can only be used once and returns
+// it so that the client, redirect URI and code verifier can be checked. Returns
+// ErrInvalidCode if the code does not exist or has expired.
+func Exchange(ctx context.Context, code string) (c *Code, err error) {
+	if code == "" {
+		return nil, ErrInvalidCode
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	c = &Code{Code: code}
+	if err = tx.QueryRow(exchangeCodeSQL, secrets.Hash(code)).Scan(&c.ClientID, &c.UserID, &c.RedirectURI, &c.Scope, &c.Nonce, &c.Challenge, &c.ChallengeMethod, &c.Expires, &c.Created); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidCode
+		}
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if !c.Expires.After(time.Now()) {
+		return nil, ErrInvalidCode
+	}
+	return c, nil
+}
+
+const (
+	purgeCodesSQL = "DELETE FROM oauth_codes WHERE expires <= NOW()"
+)
+
+// PurgeExpiredCodes deletes authorization codes that were never exchanged, returning
+// the number of codes deleted.
+func PurgeExpiredCodes(ctx context.Context) (nRows int64, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(purgeCodesSQL); err != nil {
+		return 0, err
+	}
+
+	if nRows, err = result.RowsAffected(); err != nil {
+		return 0, err
+	}
+	return nRows, tx.Commit()
+}
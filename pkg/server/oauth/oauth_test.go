@@ -0,0 +1,112 @@
+package oauth_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/bbengfort/epistolary/pkg/server/oauth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPKCE(t *testing.T) {
+	verifier := "dBjftJeZ4CVP-mJ92K1jl_Rk2mWqJXJG_hg1IaXmQk2"
+	sum := sha256.Sum256([]byte(verifier))
+
+	code := &oauth.Code{Challenge: base64.RawURLEncoding.EncodeToString(sum[:]), ChallengeMethod: oauth.ChallengeS256}
+	require.NoError(t, code.ValidateChallenge())
+	require.NoError(t, code.Verify(verifier))
+	require.ErrorIs(t, code.Verify(code.Challenge), oauth.ErrInvalidVerifier)
+	require.ErrorIs(t, code.Verify("short"), oauth.ErrInvalidVerifier)
+
+	require.ErrorIs(t, code.Verify(strings.ToUpper(verifier)), oauth.ErrInvalidVerifier)
+
+	// Only the S256 challenge method is supported
+	require.ErrorIs(t, (&oauth.Code{Challenge: verifier}).ValidateChallenge(), oauth.ErrChallengeMethod)
+	require.ErrorIs(t, (&oauth.Code{Challenge: verifier, ChallengeMethod: "plain"}).ValidateChallenge(), oauth.ErrChallengeMethod)
+	require.ErrorIs(t, (&oauth.Code{Challenge: verifier, ChallengeMethod: "S512"}).ValidateChallenge(), oauth.ErrChallengeMethod)
+
+	require.ErrorIs(t, (&oauth.Code{}).ValidateChallenge(), oauth.ErrChallenge)
+	require.ErrorIs(t, (&oauth.Code{Challenge: "tooshort", ChallengeMethod: oauth.ChallengeS256}).ValidateChallenge(), oauth.ErrInvalidChallenge)
+	require.ErrorIs(t, (&oauth.Code{Challenge: verifier + "!", ChallengeMethod: oauth.ChallengeS256}).ValidateChallenge(), oauth.ErrInvalidChallenge)
+}
+
+func TestScope(t *testing.T) {
+	require.Equal(t, "openid profile email", oauth.ParseScope("email profile openid"))
+	require.Equal(t, "openid email", oauth.ParseScope("email offline_access  openid email"))
+	require.Equal(t, "", oauth.ParseScope("admin"))
+	require.Equal(t, "openid epistles:read epistles:update", oauth.ParseScope("epistles:update admin:cms epistles:read openid"))
+
+	// Permission scopes are limited to the permissions of the user
+	require.False(t, oauth.HasPermissionScope("openid profile email"))
+	require.True(t, oauth.HasPermissionScope("openid epistles:read"))
+
+	scope, granted := oauth.LimitScope("openid epistles:read epistles:delete", []string{"epistles:read", "epistles:update", "admin:cms"})
+	require.Equal(t, "openid epistles:read", scope)
+	require.Equal(t, []string{"epistles:read"}, granted)
+
+	scope, granted = oauth.LimitScope("openid epistles:read", nil)
+	require.Equal(t, "openid", scope)
+	require.Empty(t, granted)
+
+	code := &oauth.Code{Scope: "openid email"}
+	require.True(t, code.HasScope(oauth.ScopeOpenID))
+	require.True(t, code.HasScope(oauth.ScopeEmail))
+	require.False(t, code.HasScope(oauth.ScopeProfile))
+}
+
+func TestRedirectURI(t *testing.T) {
+	valid := []string{
+		"https://example.com/callback",
+		"https://example.com/callback?source=oauth",
+		"http://localhost:8080/callback",
+		"http://127.0.0.1/callback",
+		"http://[::1]:3000/",
+		"chrome-extension://abcdefghijklmnop/callback.html",
+	}
+
+	for _, uri := range valid {
+		require.NoError(t, oauth.ValidateRedirectURI(uri), uri)
+	}
+
+	invalid := []string{
+		"",
+		"/callback",
+		"http://example.com/callback",
+		"https://example.com/callback#fragment",
+		"example.com/callback",
+	}
+
+	for _, uri := range invalid {
+		require.ErrorIs(t, oauth.ValidateRedirectURI(uri), oauth.ErrInvalidRedirectURI, uri)
+	}
+
+	client := &oauth.Client{Name: "Extension", RedirectURIs: []string{"https://example.com/callback"}}
+	require.NoError(t, client.Validate())
+	require.True(t, client.IsPublic())
+	require.True(t, client.VerifySecret(""))
+	require.False(t, client.VerifySecret("secret"))
+
+	// A client with only one redirect uri uses it by default
+	uri, err := client.RedirectURI("")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/callback", uri)
+
+	uri, err = client.RedirectURI("https://example.com/callback")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/callback", uri)
+
+	// Redirect uris must match exactly
+	_, err = client.RedirectURI("https://example.com/callback/")
+	require.ErrorIs(t, err, oauth.ErrRedirectMismatch)
+
+	client.RedirectURIs = append(client.RedirectURIs, "http://localhost:8080/callback")
+	_, err = client.RedirectURI("")
+	require.ErrorIs(t, err, oauth.ErrRedirectMismatch)
+
+	require.ErrorIs(t, (&oauth.Client{RedirectURIs: client.RedirectURIs}).Validate(), oauth.ErrNameRequired)
+	require.ErrorIs(t, (&oauth.Client{Name: "Extension"}).Validate(), oauth.ErrNoRedirectURIs)
+	require.ErrorIs(t, (&oauth.Client{Name: "Extension", RedirectURIs: []string{"http://example.com"}}).Validate(), oauth.ErrInvalidRedirectURI)
+	require.ErrorIs(t, (&oauth.Client{Name: "Extension", RedirectURIs: make([]string, oauth.MaxRedirectURIs+1)}).Validate(), oauth.ErrTooManyRedirectURIs)
+}
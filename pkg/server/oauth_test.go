@@ -0,0 +1,137 @@
+package server_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/server/tokens"
+)
+
+func (suite *epistolaryTestSuite) TestOAuthClientTokens() {
+	require := suite.Require()
+
+	claims := &tokens.Claims{Username: "jane", ClientID: "01GE6191AQTGMCJ9BN0QC3CCVG", Scope: "openid profile"}
+	claims.SetSubjectID(42)
+	tks := suite.signClaims(claims)
+
+	// Tokens issued to clients cannot be used for first-party endpoints
+	require.Equal(http.StatusForbidden, suite.doRequest(http.MethodGet, "/v1/users/me", tks, nil, nil))
+	require.Equal(http.StatusForbidden, suite.doRequest(http.MethodGet, "/v1/sessions", tks, nil, nil))
+	require.Equal(http.StatusUnauthorized, suite.doRequest(http.MethodGet, "/v1/reading", tks, nil, nil))
+
+	// The userinfo is limited to the scope authorized by the user
+	out := &api.UserInfo{}
+	suite.expectUser(42, "jane@example.com")
+	require.Equal(http.StatusOK, suite.doRequest(http.MethodGet, "/oauth/userinfo", tks, nil, out))
+	require.Equal("2a", out.Subject)
+	require.Equal("jane", out.PreferredUsername)
+	require.Empty(out.Email, "email should not be returned without the email scope")
+	require.NoError(db.Mock().ExpectationsWereMet())
+}
+
+func (suite *epistolaryTestSuite) TestOAuthRevokeClient() {
+	require := suite.Require()
+	tm, err := tokens.New(suite.conf.Token)
+	require.NoError(err, "could not create token manager")
+
+	claims := &tokens.Claims{ClientID: "01GE62EXXR0X0561XD53RDFBQJ", Scope: "openid"}
+	claims.SetSubjectID(42)
+
+	_, refresh, err := tm.CreateTokens(claims)
+	require.NoError(err, "could not create tokens")
+
+	// A client cannot revoke the sessions of another client
+	suite.expectClient("01GE6191AQTGMCJ9BN0QC3CCVG")
+	require.Equal(http.StatusOK, suite.postForm("/oauth/revoke", url.Values{"token": {refresh}, "client_id": {"01GE6191AQTGMCJ9BN0QC3CCVG"}}))
+	require.NoError(db.Mock().ExpectationsWereMet())
+
+	// The client the token was issued to can revoke its session
+	mock := db.Mock()
+	suite.expectClient("01GE62EXXR0X0561XD53RDFBQJ")
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM sessions WHERE id=").
+		WithArgs(sqlmock.AnyArg(), int64(42), "01GE62EXXR0X0561XD53RDFBQJ").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.Equal(http.StatusOK, suite.postForm("/oauth/revoke", url.Values{"token": {refresh}, "client_id": {"01GE62EXXR0X0561XD53RDFBQJ"}}))
+	require.NoError(db.Mock().ExpectationsWereMet())
+}
+
+func (suite *epistolaryTestSuite) TestOAuthPermissionScopes() {
+	require := suite.Require()
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	sum := sha256.Sum256([]byte(verifier))
+
+	// The permission scopes are limited to the permissions of the user
+	now := time.Now()
+	mock := db.Mock()
+	suite.expectClient("01GE6191AQTGMCJ9BN0QC3CCVG")
+	mock.ExpectBegin()
+	mock.ExpectQuery("DELETE FROM oauth_codes WHERE code=").
+		WillReturnRows(sqlmock.NewRows([]string{"client_id", "user_id", "redirect_uri", "scope", "nonce", "challenge", "challenge_method", "expires", "created"}).
+			AddRow(1, 42, "https://example.com/callback", "profile epistles:read epistles:delete", "", base64.RawURLEncoding.EncodeToString(sum[:]), "S256", now.Add(5*time.Minute), now))
+	mock.ExpectCommit()
+	suite.expectUser(42, "jane@example.com")
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT permission FROM user_permissions WHERE user_id=").
+		WithArgs(int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"permission"}).AddRow("epistles:read").AddRow("epistles:update"))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO sessions").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {"authcode"},
+		"redirect_uri":  {"https://example.com/callback"},
+		"code_verifier": {verifier},
+		"client_id":     {"01GE6191AQTGMCJ9BN0QC3CCVG"},
+	}
+
+	rep, err := http.PostForm(suite.srv.URL()+"/oauth/token", form)
+	require.NoError(err, "could not make request")
+	defer rep.Body.Close()
+	require.Equal(http.StatusOK, rep.StatusCode)
+	require.NoError(mock.ExpectationsWereMet())
+
+	out := &api.OAuthToken{}
+	require.NoError(json.NewDecoder(rep.Body).Decode(out), "could not decode response")
+	require.Equal("profile epistles:read", out.Scope)
+
+	tm, err := tokens.New(suite.conf.Token)
+	require.NoError(err, "could not create token manager")
+
+	claims, err := tm.Verify(out.AccessToken)
+	require.NoError(err, "could not verify access token")
+	require.Equal("profile epistles:read", claims.Scope)
+	require.Equal([]string{"epistles:read"}, claims.Permissions)
+}
+
+// Expects the public OAuth client to be fetched from the database.
+func (suite *epistolaryTestSuite) expectClient(clientID string) {
+	now := time.Now()
+	mock := db.Mock()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, client_id, name, secret, redirect_uris, created, modified FROM oauth_clients WHERE client_id=").
+		WithArgs(clientID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "client_id", "name", "secret", "redirect_uris", "created", "modified"}).
+			AddRow(1, clientID, "Browser Extension", nil, "{https://example.com/callback}", now, now))
+	mock.ExpectCommit()
+}
+
+// Posts the form to the test server and returns the status code.
+func (suite *epistolaryTestSuite) postForm(path string, form url.Values) int {
+	rep, err := http.PostForm(suite.srv.URL()+path, form)
+	suite.Require().NoError(err, "could not make request")
+	rep.Body.Close()
+	return rep.StatusCode
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/subscriptions"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	pollTimeout   = 2 * time.Minute
+	pollEvery     = 1 * time.Hour
+	pollBatchSize = 100
+)
+
+// Poller runs in its own go routine and periodically polls the feeds of subscriptions
+// that are due, creating readings for any new items so that the sync workers can fetch
+// their metadata. The poller is stopped when the server is shutdown.
+func (s *Server) Poller() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.conf.Subscriptions.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		s.pollSubscriptions()
+	}
+}
+
+// Polls a batch of subscriptions that are due, stopping early if the server is shutdown.
+func (s *Server) pollSubscriptions() {
+	every := s.conf.Subscriptions.PollEvery
+	if every <= 0 {
+		every = pollEvery
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	subs, err := subscriptions.Due(ctx, every, pollBatchSize)
+	cancel()
+
+	if err != nil {
+		sentry.Error(ctx).Err(err).Msg("could not fetch subscriptions to poll")
+		return
+	}
+
+	var total int
+	for _, sub := range subs {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), pollTimeout)
+		queued, err := sub.Poll(ctx)
+		cancel()
+
+		if err != nil {
+			sentry.Error(ctx).Err(err).Int64("subscription", sub.ID).Msg("could not poll subscription")
+		}
+
+		if sub.Error.Valid {
+			log.Debug().Int64("subscription", sub.ID).Int64("failures", sub.Failures).Str("error", sub.Error.String).Msg("could not fetch subscription feed")
+		}
+
+		if queued > 0 {
+			total += queued
+			s.NotifySync()
+		}
+	}
+
+	log.Debug().Int("subscriptions", len(subs)).Int("readings", total).Msg("polled subscriptions")
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/bbengfort/epistolary/pkg/api/v1"
 	"github.com/bbengfort/epistolary/pkg/server/epistles"
+	"github.com/bbengfort/epistolary/pkg/server/tags"
 	"github.com/bbengfort/epistolary/pkg/utils/pagination"
 	"github.com/bbengfort/epistolary/pkg/utils/sentry"
 	"github.com/gin-gonic/gin"
@@ -20,15 +22,22 @@ func (s *Server) ListReadings(c *gin.Context) {
 		out      *api.ReadingPage
 		curPage  *pagination.Cursor
 		nextPage *pagination.Cursor
+		filter   *epistles.Filter
 	)
 
-	query := &api.PageQuery{}
-	if err = c.BindQuery(&query); err != nil {
+	query := &api.ReadingQuery{}
+	if err = c.BindQuery(query); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse page query"))
 		return
 	}
 
+	if filter, err = ReadingFilter(query); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+		return
+	}
+
 	var userID int64
 	if userID, err = GetUserID(c); err != nil {
 		sentry.Error(c).Err(err).Msg("could not parse userID from request")
@@ -36,7 +45,8 @@ func (s *Server) ListReadings(c *gin.Context) {
 		return
 	}
 
-	// Parse the previous page token if one was supplied
+	// Parse the previous page token if one was supplied, otherwise start at the first
+	// page of results with the requested page size.
 	if query.PageToken != "" {
 		if curPage, err = pagination.Parse(query.PageToken); err != nil {
 			sentry.Warn(c).Err(err).Msg("invalid next page token")
@@ -45,9 +55,19 @@ func (s *Server) ListReadings(c *gin.Context) {
 		}
 	}
 
+	firstPage := curPage
+	if firstPage == nil {
+		firstPage = pagination.New(0, 0, uint32(query.PageSize))
+	}
+
 	// Fetch the readings for the user
 	var reads []*epistles.Reading
-	if reads, nextPage, err = epistles.List(c.Request.Context(), userID, curPage); err != nil {
+	if reads, nextPage, err = epistles.List(c.Request.Context(), userID, filter, firstPage); err != nil {
+		if errors.Is(err, pagination.ErrTokenQueryMismatch) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
 		sentry.Error(c).Err(err).Msg("could not fetch readings from database")
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch readings"))
 		return
@@ -65,8 +85,7 @@ func (s *Server) ListReadings(c *gin.Context) {
 		}
 	}
 
-	if curPage != nil {
-		prevPage := curPage.PrevPage()
+	if prevPage := epistles.PrevPage(curPage); prevPage != nil {
 		if out.PrevPageToken, err = prevPage.PageToken(); err != nil {
 			sentry.Error(c).Err(err).Msg("could not create prev page token")
 			c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch readings"))
@@ -74,23 +93,116 @@ func (s *Server) ListReadings(c *gin.Context) {
 		}
 	}
 
+	// Fetch the tags for all of the readings on the page
+	ids := make([]int64, 0, len(reads))
 	for _, r := range reads {
-		epistle, _ := r.Epistle(c.Request.Context(), false)
-		item := &api.Reading{
-			ID:          r.EpistleID,
-			Status:      string(r.Status),
-			Link:        epistle.Link,
-			Title:       epistle.Title.String,
-			Description: epistle.Description.String,
-			Favicon:     epistle.Favicon.String,
-			Started:     api.Timestamp{Time: r.Started.Time},
-			Finished:    api.Timestamp{Time: r.Finished.Time},
-			Archived:    api.Timestamp{Time: r.Archived.Time},
-			Created:     api.Timestamp{Time: r.Created},
-			Modified:    api.Timestamp{Time: r.Modified},
+		ids = append(ids, r.EpistleID)
+	}
+
+	var readingTags map[int64][]string
+	if readingTags, err = tags.ForReadings(c.Request.Context(), userID, ids...); err != nil {
+		sentry.Error(c).Err(err).Msg("could not fetch reading tags from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch readings"))
+		return
+	}
+
+	for _, r := range reads {
+		out.Readings = append(out.Readings, readingResponse(c.Request.Context(), r, readingTags[r.EpistleID]))
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (s *Server) SearchReadings(c *gin.Context) {
+	var (
+		err      error
+		out      *api.ReadingPage
+		curPage  *pagination.Cursor
+		nextPage *pagination.Cursor
+	)
+
+	query := &api.SearchQuery{}
+	if err = c.BindQuery(query); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse search query"))
+		return
+	}
+
+	if query.Query = strings.TrimSpace(query.Query); query.Query == "" {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("a search query is required"))
+		return
+	}
+
+	var userID int64
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse userID from request")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	// Parse the previous page token if one was supplied, otherwise start at the first
+	// page of results with the requested page size.
+	if query.PageToken != "" {
+		if curPage, err = pagination.Parse(query.PageToken); err != nil {
+			sentry.Warn(c).Err(err).Msg("invalid next page token")
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+	}
+
+	firstPage := curPage
+	if firstPage == nil {
+		firstPage = pagination.New(0, 0, uint32(query.PageSize))
+	}
+
+	// Search the readings for the user
+	var reads []*epistles.Reading
+	if reads, nextPage, err = epistles.Search(c.Request.Context(), userID, query.Query, firstPage); err != nil {
+		if errors.Is(err, pagination.ErrTokenQueryMismatch) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
 		}
 
-		out.Readings = append(out.Readings, item)
+		sentry.Error(c).Err(err).Msg("could not search readings in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not search readings"))
+		return
+	}
+
+	out = &api.ReadingPage{
+		Readings: make([]*api.Reading, 0, len(reads)),
+	}
+
+	if nextPage != nil {
+		if out.NextPageToken, err = nextPage.PageToken(); err != nil {
+			sentry.Error(c).Err(err).Msg("could not create next page token")
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not search readings"))
+			return
+		}
+	}
+
+	if prevPage := epistles.PrevPage(curPage); prevPage != nil {
+		if out.PrevPageToken, err = prevPage.PageToken(); err != nil {
+			sentry.Error(c).Err(err).Msg("could not create prev page token")
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not search readings"))
+			return
+		}
+	}
+
+	// Fetch the tags for all of the readings on the page
+	ids := make([]int64, 0, len(reads))
+	for _, r := range reads {
+		ids = append(ids, r.EpistleID)
+	}
+
+	var readingTags map[int64][]string
+	if readingTags, err = tags.ForReadings(c.Request.Context(), userID, ids...); err != nil {
+		sentry.Error(c).Err(err).Msg("could not fetch reading tags from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not search readings"))
+		return
+	}
+
+	for _, r := range reads {
+		out.Readings = append(out.Readings, readingResponse(c.Request.Context(), r, readingTags[r.EpistleID]))
 	}
 
 	c.JSON(http.StatusOK, out)
@@ -116,8 +228,13 @@ func (s *Server) CreateReading(c *gin.Context) {
 		return
 	}
 
-	if reading.ID != 0 || reading.Title != "" || reading.Description != "" || reading.Favicon != "" {
-		c.JSON(http.StatusBadRequest, api.ErrorResponse("reading can only be created with a link"))
+	if reading.ID != 0 || reading.Title != "" || reading.Description != "" || reading.Favicon != "" || reading.SiteName != "" || reading.Author != "" || reading.Image != "" || reading.Canonical != "" || !reading.Published.IsZero() {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("reading can only be created with a link and tags"))
+		return
+	}
+
+	if reading.Tags, err = tags.NormalizeAll(reading.Tags); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
 		return
 	}
 
@@ -129,7 +246,7 @@ func (s *Server) CreateReading(c *gin.Context) {
 	}
 
 	if read, err = epistles.Create(c.Request.Context(), userID, reading.Link); err != nil {
-		if errors.Is(err, epistles.ErrAlreadyExists) {
+		if errors.Is(err, epistles.ErrAlreadyExists) || errors.Is(err, epistles.ErrInvalidLink) {
 			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
 			return
 		}
@@ -139,24 +256,21 @@ func (s *Server) CreateReading(c *gin.Context) {
 		return
 	}
 
+	if len(reading.Tags) > 0 {
+		if reading.Tags, err = tags.SetReadingTags(c.Request.Context(), read.EpistleID, userID, reading.Tags); err != nil {
+			sentry.Error(c).Err(err).Msg("could not tag reading in database")
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not tag reading"))
+			return
+		}
+	}
+
+	// The epistle metadata is synced in the background if it has not been synced yet
 	epistle, _ = read.Epistle(c.Request.Context(), false)
-	if err = epistle.Sync(c.Request.Context()); err != nil {
-		sentry.Error(c).Err(err).Msg("could not sync epistle")
-	}
-
-	reading.ID = read.EpistleID
-	reading.Status = string(read.Status)
-	reading.Link = epistle.Link
-	reading.Title = epistle.Title.String
-	reading.Description = epistle.Description.String
-	reading.Favicon = epistle.Favicon.String
-	reading.Started = api.Timestamp{Time: read.Started.Time}
-	reading.Finished = api.Timestamp{Time: read.Finished.Time}
-	reading.Archived = api.Timestamp{Time: read.Archived.Time}
-	reading.Created = api.Timestamp{Time: read.Created}
-	reading.Modified = api.Timestamp{Time: read.Modified}
-
-	c.JSON(http.StatusCreated, reading)
+	if !epistle.IsSynced() {
+		s.NotifySync()
+	}
+
+	c.JSON(http.StatusCreated, readingResponse(c.Request.Context(), read, reading.Tags))
 }
 
 func (s *Server) FetchReading(c *gin.Context) {
@@ -189,19 +303,14 @@ func (s *Server) FetchReading(c *gin.Context) {
 		return
 	}
 
-	epistle, _ := item.Epistle(c.Request.Context(), false)
-	reading.Status = string(item.Status)
-	reading.Link = epistle.Link
-	reading.Title = epistle.Title.String
-	reading.Description = epistle.Description.String
-	reading.Favicon = epistle.Favicon.String
-	reading.Started = api.Timestamp{Time: item.Started.Time}
-	reading.Finished = api.Timestamp{Time: item.Finished.Time}
-	reading.Archived = api.Timestamp{Time: item.Archived.Time}
-	reading.Created = api.Timestamp{Time: item.Created}
-	reading.Modified = api.Timestamp{Time: item.Modified}
+	var readingTags map[int64][]string
+	if readingTags, err = tags.ForReadings(c.Request.Context(), userID, item.EpistleID); err != nil {
+		sentry.Error(c).Err(err).Msg("could not fetch reading tags from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
 
-	c.JSON(http.StatusOK, reading)
+	c.JSON(http.StatusOK, readingResponse(c.Request.Context(), item, readingTags[item.EpistleID]))
 }
 
 func (s *Server) UpdateReading(c *gin.Context) {
@@ -252,6 +361,16 @@ func (s *Server) UpdateReading(c *gin.Context) {
 		return
 	}
 
+	// If tags are not specified the tags on the reading are left unchanged, an empty
+	// list of tags removes all of the tags from the reading.
+	updateTags := reading.Tags != nil
+	if updateTags {
+		if reading.Tags, err = tags.NormalizeAll(reading.Tags); err != nil {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+	}
+
 	// Convert the reading to the model with the fields that are updateable.
 	model := &epistles.Reading{
 		EpistleID: reading.ID,
@@ -275,18 +394,182 @@ func (s *Server) UpdateReading(c *gin.Context) {
 		return
 	}
 
-	// Convert the model back to the API for the response
-	reading.Status = string(model.Status)
-	reading.Started = api.Timestamp{Time: model.Started.Time}
-	reading.Finished = api.Timestamp{Time: model.Finished.Time}
-	reading.Archived = api.Timestamp{Time: model.Archived.Time}
-	reading.Created = api.Timestamp{Time: model.Created}
-	reading.Modified = api.Timestamp{Time: model.Modified}
+	if updateTags {
+		if reading.Tags, err = tags.SetReadingTags(c.Request.Context(), model.EpistleID, userID, reading.Tags); err != nil {
+			sentry.Error(c).Err(err).Msg("could not tag reading in database")
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not tag reading"))
+			return
+		}
+	} else {
+		var readingTags map[int64][]string
+		if readingTags, err = tags.ForReadings(c.Request.Context(), userID, model.EpistleID); err != nil {
+			sentry.Error(c).Err(err).Msg("could not fetch reading tags from database")
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+			return
+		}
+		reading.Tags = readingTags[model.EpistleID]
+	}
 
-	// Return the reading object back to the user
-	c.JSON(http.StatusOK, reading)
+	// Return the updated reading back to the user
+	c.JSON(http.StatusOK, readingResponse(c.Request.Context(), model, reading.Tags))
 }
 
 func (s *Server) DeleteReading(c *gin.Context) {
-	c.Status(http.StatusNotImplemented)
+	var (
+		err       error
+		readingID int64
+		userID    int64
+	)
+
+	if readingID, err = strconv.ParseInt(c.Param("readingID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("reading not found"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	// Tombstone the reading; it can be restored until the tombstone expires.
+	if _, err = epistles.Delete(c.Request.Context(), readingID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("reading not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not delete reading from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (s *Server) RestoreReading(c *gin.Context) {
+	var (
+		err     error
+		userID  int64
+		item    *epistles.Reading
+		reading *api.Reading
+	)
+
+	reading = &api.Reading{}
+	if reading.ID, err = strconv.ParseInt(c.Param("readingID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("reading not found"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	// Only readings whose tombstone has not yet expired can be restored.
+	if item, err = epistles.Restore(c.Request.Context(), reading.ID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("no deleted reading to restore"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not restore reading in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	var readingTags map[int64][]string
+	if readingTags, err = tags.ForReadings(c.Request.Context(), userID, item.EpistleID); err != nil {
+		sentry.Error(c).Err(err).Msg("could not fetch reading tags from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, readingResponse(c.Request.Context(), item, readingTags[item.EpistleID]))
+}
+
+// Converts a reading and its epistle into the API representation of the reading with
+// the specified tags. The epistle is fetched if it was not loaded with the reading.
+func readingResponse(ctx context.Context, r *epistles.Reading, names []string) *api.Reading {
+	epistle, _ := r.Epistle(ctx, false)
+	return &api.Reading{
+		ID:          r.EpistleID,
+		Status:      string(r.Status),
+		Link:        epistle.Link,
+		Title:       epistle.Title.String,
+		Description: epistle.Description.String,
+		Favicon:     epistle.Favicon.String,
+		SiteName:    epistle.SiteName.String,
+		Author:      epistle.Author.String,
+		Image:       epistle.Image.String,
+		Canonical:   epistle.Canonical.String,
+		Published:   api.Timestamp{Time: epistle.Published.Time},
+		Tags:        tagNames(names),
+		Started:     api.Timestamp{Time: r.Started.Time},
+		Finished:    api.Timestamp{Time: r.Finished.Time},
+		Archived:    api.Timestamp{Time: r.Archived.Time},
+		Created:     api.Timestamp{Time: r.Created},
+		Modified:    api.Timestamp{Time: r.Modified},
+	}
+}
+
+// Ensures that readings without tags are serialized with an empty list of tags.
+func tagNames(names []string) []string {
+	if names == nil {
+		return []string{}
+	}
+	return names
+}
+
+// ReadingFilter converts the query parameters of a list readings request into a
+// validated filter or returns an error that can be returned to the user.
+func ReadingFilter(query *api.ReadingQuery) (filter *epistles.Filter, err error) {
+	filter = &epistles.Filter{
+		Status:         make([]epistles.Status, 0, len(query.Status)),
+		CreatedAfter:   query.CreatedAfter,
+		CreatedBefore:  query.CreatedBefore,
+		FinishedAfter:  query.FinishedAfter,
+		FinishedBefore: query.FinishedBefore,
+		Domain:         query.Domain,
+		Tags:           make([]string, 0, len(query.Tags)),
+		Sort:           epistles.SortField(strings.ToLower(strings.TrimSpace(query.Sort))),
+	}
+
+	// Statuses can be specified multiple times or as a comma separated list
+	for _, status := range query.Status {
+		for _, item := range strings.Split(status, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				filter.Status = append(filter.Status, epistles.Status(item))
+			}
+		}
+	}
+
+	// Tags can also be specified multiple times or as a comma separated list
+	for _, tag := range query.Tags {
+		for _, item := range strings.Split(tag, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				filter.Tags = append(filter.Tags, item)
+			}
+		}
+	}
+
+	// Titles are sorted alphabetically by default, timestamps by most recent first
+	switch strings.ToLower(strings.TrimSpace(query.Order)) {
+	case "":
+		filter.Ascending = filter.Sort == epistles.SortTitle
+	case "asc":
+		filter.Ascending = true
+	case "desc":
+		filter.Ascending = false
+	default:
+		return nil, errors.New("order must be either asc or desc")
+	}
+
+	if err = filter.Validate(); err != nil {
+		return nil, err
+	}
+	return filter, nil
 }
@@ -0,0 +1,38 @@
+package server_test
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/server/tokens"
+)
+
+func (suite *epistolaryTestSuite) TestSearchReadingsDeleted() {
+	require := suite.Require()
+	claims := &tokens.Claims{Name: "Jane Doe", Email: "jane@example.com", Permissions: []string{"epistles:read"}}
+	claims.SetSubjectID(42)
+	tks := suite.signClaims(claims)
+
+	// Readings that have been deleted are excluded from the search results
+	now := time.Now()
+	mock := db.Mock()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM reading r JOIN epistles e ON r\.epistle_id=e\.id, .+ WHERE r\.user_id=\$2 AND r\.deleted IS NULL AND e\.search @@ q\.query`).
+		WithArgs("article", int64(42), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"epistle_id", "status", "started", "finished", "archived", "created", "modified", "id", "link", "title", "description", "favicon", "site_name", "author", "published", "image", "canonical", "rank"}).
+			AddRow(7, "queued", nil, nil, nil, now, now, 7, "https://example.com/article", "An Article", nil, nil, nil, nil, nil, nil, nil, 0.5))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT rt.epistle_id, t.name FROM reading_tags rt").
+		WillReturnRows(sqlmock.NewRows([]string{"epistle_id", "name"}))
+	mock.ExpectCommit()
+
+	out := &api.ReadingPage{}
+	require.Equal(http.StatusOK, suite.doRequest(http.MethodGet, "/v1/reading/search?q=article", tks, nil, out))
+	require.Len(out.Readings, 1)
+	require.Equal(int64(7), out.Readings[0].ID)
+	require.NoError(db.Mock().ExpectationsWereMet())
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/rs/zerolog/log"
+)
+
+// The key rotator should check the key directory much more frequently than the publish
+// delay so that keys generated by other replicas are published well before they are used.
+const keyRotatorInterval = 5 * time.Minute
+
+// KeyRotator runs in its own go routine and periodically rotates the JWT signing keys,
+// generating a new key when the current key is older than the rotation interval,
+// switching to new keys once they have been published, and deleting keys that can no
+// longer have signed an unexpired token. The key rotator is stopped when the server is
+// shutdown.
+func (s *Server) KeyRotator() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(keyRotatorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.tokens.Rotate(time.Now()); err != nil {
+			sentry.Error(context.Background()).Err(err).Msg("could not rotate token signing keys")
+			continue
+		}
+
+		log.Debug().Str("kid", s.tokens.CurrentKey().String()).Msg("rotated token signing keys")
+	}
+}
@@ -22,8 +22,10 @@ import (
 	"github.com/bbengfort/epistolary/pkg/server/config"
 	"github.com/bbengfort/epistolary/pkg/server/db"
 	"github.com/bbengfort/epistolary/pkg/server/db/schema"
+	"github.com/bbengfort/epistolary/pkg/server/sso"
 	"github.com/bbengfort/epistolary/pkg/server/tokens"
 	"github.com/bbengfort/epistolary/pkg/utils/logger"
+	"github.com/bbengfort/epistolary/pkg/utils/mailer"
 	"github.com/bbengfort/epistolary/pkg/utils/sentry"
 )
 
@@ -44,10 +46,16 @@ type Server struct {
 	srv     *http.Server
 	router  *gin.Engine
 	tokens  *tokens.TokenManager
+	mailer  mailer.Mailer
+	logins  *loginLimiter
+	sso     map[string]*sso.Provider
 	started time.Time
 	healthy bool
 	url     string
 	errc    chan error
+	done    chan struct{}
+	syncq   chan struct{}
+	wg      sync.WaitGroup
 }
 
 // New creates a new Epistolary server from the specified configuration.
@@ -76,15 +84,23 @@ func New(conf config.Config) (s *Server, err error) {
 
 	// Create the server and prepare to serve
 	s = &Server{
-		conf: conf,
-		errc: make(chan error, 1),
+		conf:  conf,
+		errc:  make(chan error, 1),
+		done:  make(chan struct{}),
+		syncq: make(chan struct{}, 1),
 	}
 
+	// Limit failed logins to protect against brute-force attacks
+	s.logins = newLoginLimiter(conf.Login)
+
+	// Users can login with the configured upstream identity providers
+	s.setupSSO()
+
 	// Connect to the TestNet and MainNet directory services and database if we're not
 	// in maintenance or testing mode (in testing mode, the connection will be manual).
 	if !s.conf.Maintenance {
 		log.Debug().Msg("setting up production mode")
-		if len(s.conf.Token.Keys) == 0 {
+		if len(s.conf.Token.Keys) == 0 && s.conf.Token.KeyDir == "" {
 			return nil, errors.New("invalid configuration: no token keys specified")
 		}
 
@@ -95,7 +111,13 @@ func New(conf config.Config) (s *Server, err error) {
 
 	// Create the router
 	gin.SetMode(string(conf.Mode))
+	// Only trust the forwarded headers of the configured proxies so that the client IP
+	// used to rate limit logins and record sessions cannot be spoofed by the client.
 	s.router = gin.New()
+	if err = s.router.SetTrustedProxies(s.conf.TrustedProxies); err != nil {
+		return nil, err
+	}
+
 	if err = s.setupRoutes(); err != nil {
 		return nil, err
 	}
@@ -141,6 +163,34 @@ func (s *Server) Serve() (err error) {
 			return err
 		}
 		log.Debug().Bool("read-only", s.conf.Database.ReadOnly).Str("dsn", s.conf.Database.URL).Msg("connected to database")
+
+		// Start background routines that maintain the database
+		s.wg.Add(1)
+		go s.Janitor()
+
+		// Start the workers that sync epistle metadata in the background
+		for i := 0; i < s.conf.Sync.Workers; i++ {
+			s.wg.Add(1)
+			go s.SyncWorker()
+		}
+
+		// Periodically resync epistles whose metadata is stale
+		if s.conf.Sync.StaleAfter > 0 {
+			s.wg.Add(1)
+			go s.Resyncer()
+		}
+
+		// Periodically poll the feeds that users are subscribed to for new items
+		if s.conf.Subscriptions.Interval > 0 {
+			s.wg.Add(1)
+			go s.Poller()
+		}
+
+		// Periodically rotate the token signing keys in the key directory
+		if s.conf.Token.KeyDir != "" {
+			s.wg.Add(1)
+			go s.KeyRotator()
+		}
 	}
 
 	// Set the health of the service to true unless we're in maintenance mode.
@@ -197,6 +247,10 @@ func (s *Server) Shutdown() (err error) {
 	}
 
 	if !s.conf.Maintenance {
+		// Stop the background routines before closing the database
+		close(s.done)
+		s.wg.Wait()
+
 		if serr := db.Close(); serr != nil {
 			err = multierror.Append(err, serr)
 		}
@@ -296,22 +350,134 @@ func (s *Server) setupRoutes() (err error) {
 
 		// Login route (no authentication required)
 		v1.POST("/login", s.Login)
+		v1.POST("/login/mfa", s.LoginMFA)
 		v1.POST("/logout", s.Logout)
 
+		// Login with upstream identity providers (no authentication required)
+		v1.GET("/sso", s.SSOProviders)
+		v1.GET("/sso/:provider/login", s.SSOLogin)
+		v1.GET("/sso/:provider/callback", s.SSOCallback)
+
+		// Email verification and password reset (no authentication required)
+		v1.POST("/verify", s.VerifyEmail)
+		v1.POST("/forgot-password", s.ForgotPassword)
+		v1.POST("/reset-password", s.ResetPassword)
+
+		// Account self-service for the authenticated user (requires login)
+		me := v1.Group("/users/me", s.Authenticate, s.RequireLogin)
+		{
+			me.GET("", s.Profile)
+			me.PUT("", s.UpdateProfile)
+			me.POST("/password", s.ChangePassword)
+			me.DELETE("", s.DeleteAccount)
+			me.GET("/mfa", s.MFAStatus)
+			me.POST("/mfa", s.EnrollMFA)
+			me.POST("/mfa/confirm", s.ConfirmMFA)
+			me.POST("/mfa/disable", s.DisableMFA)
+			me.POST("/mfa/recovery-codes", s.RegenerateRecoveryCodes)
+		}
+
+		// Device sessions of the authenticated user (requires login)
+		sess := v1.Group("/sessions", s.Authenticate, s.RequireLogin)
+		{
+			sess.GET("", s.ListSessions)
+			sess.DELETE("/:sessionID", s.RevokeSession)
+		}
+
+		// Personal API keys for scripts and integrations (requires login)
+		keys := v1.Group("/apikeys", s.Authenticate, s.RequireLogin)
+		{
+			keys.GET("", s.ListAPIKeys)
+			keys.POST("", s.CreateAPIKey)
+			keys.GET("/:keyID", s.FetchAPIKey)
+			keys.PUT("/:keyID", s.UpdateAPIKey)
+			keys.DELETE("/:keyID", s.DeleteAPIKey)
+		}
+
 		// Reading REST Resource (requires authentication)
 		r := v1.Group("/reading", s.Authenticate)
 		{
 			r.GET("", s.Authorize("epistles:read"), s.ListReadings)
 			r.POST("", s.Authorize("epistles:update"), s.CreateReading)
+			r.GET("/search", s.Authorize("epistles:read"), s.SearchReadings)
 			r.GET("/:readingID", s.Authorize("epistles:read"), s.FetchReading)
 			r.PUT("/:readingID", s.Authorize("epistles:update"), s.UpdateReading)
 			r.DELETE("/:readingID", s.Authorize("epistles:delete"), s.DeleteReading)
+			r.POST("/:readingID/restore", s.Authorize("epistles:delete"), s.RestoreReading)
+			r.GET("/:readingID/content", s.Authorize("epistles:read"), s.FetchContent)
+			r.GET("/:readingID/notes", s.Authorize("epistles:read"), s.ListNotes)
+			r.POST("/:readingID/notes", s.Authorize("epistles:update"), s.CreateNote)
+			r.PUT("/:readingID/notes/:noteID", s.Authorize("epistles:update"), s.UpdateNote)
+			r.DELETE("/:readingID/notes/:noteID", s.Authorize("epistles:delete"), s.DeleteNote)
+		}
+
+		// Tags REST Resource (requires authentication)
+		t := v1.Group("/tags", s.Authenticate)
+		{
+			t.GET("", s.Authorize("epistles:read"), s.ListTags)
+			t.POST("", s.Authorize("epistles:update"), s.CreateTag)
+			t.GET("/:tagID", s.Authorize("epistles:read"), s.FetchTag)
+			t.PUT("/:tagID", s.Authorize("epistles:update"), s.UpdateTag)
+			t.DELETE("/:tagID", s.Authorize("epistles:delete"), s.DeleteTag)
+		}
+
+		// Subscriptions REST Resource (requires authentication)
+		sub := v1.Group("/subscriptions", s.Authenticate)
+		{
+			sub.GET("", s.Authorize("epistles:read"), s.ListSubscriptions)
+			sub.POST("", s.Authorize("epistles:update"), s.CreateSubscription)
+			sub.GET("/:subscriptionID", s.Authorize("epistles:read"), s.FetchSubscription)
+			sub.PUT("/:subscriptionID", s.Authorize("epistles:update"), s.UpdateSubscription)
+			sub.DELETE("/:subscriptionID", s.Authorize("epistles:delete"), s.DeleteSubscription)
+		}
+
+		// Import and export the user's library (requires authentication)
+		v1.POST("/import", s.Authenticate, s.Authorize("epistles:update"), s.Import)
+		v1.GET("/export", s.Authenticate, s.Authorize("epistles:read"), s.Export)
+
+		// Feed tokens REST Resource (requires authentication)
+		f := v1.Group("/feeds", s.Authenticate)
+		{
+			f.GET("", s.Authorize("epistles:read"), s.ListFeedTokens)
+			f.POST("", s.Authorize("epistles:update"), s.CreateFeedToken)
+			f.DELETE("/:tokenID", s.Authorize("epistles:delete"), s.RevokeFeedToken)
+		}
+
+		// Reading list feeds (authenticated by the feed token in the URL)
+		v1.GET("/feed/:token/atom", s.AtomFeed)
+		v1.GET("/feed/:token/rss", s.RSSFeed)
+
+		// Administration of users and epistles (requires the admin:cms permission)
+		admin := v1.Group("/admin", s.Authenticate, s.Authorize("admin:cms"))
+		{
+			admin.GET("/users", s.AdminListUsers)
+			admin.GET("/users/:userID", s.AdminFetchUser)
+			admin.PUT("/users/:userID/role", s.AdminSetRole)
+			admin.POST("/users/:userID/disable", s.AdminSetDisabled(true))
+			admin.POST("/users/:userID/enable", s.AdminSetDisabled(false))
+			admin.POST("/users/:userID/impersonate", s.RequireLogin, s.AdminImpersonate)
+			admin.GET("/roles", s.AdminListRoles)
+			admin.GET("/epistles", s.AdminListEpistles)
+			admin.GET("/oauth/clients", s.AdminListOAuthClients)
+			admin.POST("/oauth/clients", s.RequireLogin, s.AdminCreateOAuthClient)
+			admin.DELETE("/oauth/clients/:clientID", s.RequireLogin, s.AdminDeleteOAuthClient)
 		}
 
 		// Heartbeat route (no authentication required)
 		v1.GET("/status", s.Status)
 	}
 
+	// OAuth2 and OpenID Connect provider endpoints for third-party clients; these
+	// endpoints are advertised by the OpenID configuration.
+	oa := s.router.Group("/oauth")
+	{
+		oa.GET("/authorize", s.OAuthAuthorize)
+		oa.POST("/token", s.OAuthToken)
+		oa.GET("/userinfo", s.Authenticate, s.OAuthUserInfo)
+		oa.POST("/userinfo", s.Authenticate, s.OAuthUserInfo)
+		oa.POST("/revoke", s.OAuthRevoke)
+	}
+
 	// The "well known" routes expose client security information and credentials.
 	wk := s.router.Group("/.well-known")
 	{
@@ -22,6 +22,7 @@ type epistolaryTestSuite struct {
 	srv    *server.Server
 	conf   config.Config
 	client api.EpistolaryClient
+	idp    *idp
 	dbPath string
 	stop   chan bool
 }
@@ -36,6 +37,10 @@ func (suite *epistolaryTestSuite) SetupSuite() {
 	suite.dbPath, err = os.MkdirTemp("", "epistolary-*")
 	require.NoError(err, "could not create temporary directory for database")
 
+	// Start a local identity provider to login with SSO
+	suite.idp, err = newIdP()
+	require.NoError(err, "could not start the identity provider")
+
 	// Create a test configuration to run the Epistolary API server as a fully
 	// functional server on an open port using the local-loopback for networking.
 	suite.conf, err = config.Config{
@@ -57,6 +62,18 @@ func (suite *epistolaryTestSuite) SetupSuite() {
 			Audience: "http://localhost:3000",
 			Issuer:   "http://localhost:8000",
 		},
+		Login: config.LoginConfig{
+			Window:          15 * time.Minute,
+			Lockout:         15 * time.Minute,
+			MaxIPFailures:   20,
+			MaxUserFailures: 5,
+			MaxConsecutive:  10,
+		},
+		SSO: config.SSOConfig{
+			Providers: config.SSOProviders{
+				{Name: "acme", Title: "Acme", Issuer: suite.idp.srv.URL, ClientID: idpClientID, ClientSecret: idpSecret},
+			},
+		},
 	}.Mark()
 	require.NoError(err, "test configuration is invalid")
 
@@ -92,6 +109,7 @@ func (suite *epistolaryTestSuite) TearDownSuite() {
 
 	// Wait for server to stop to prevent race conditions
 	<-suite.stop
+	suite.idp.srv.Close()
 
 	// Cleanup temporary test directory
 	err = os.RemoveAll(suite.dbPath)
@@ -101,6 +119,24 @@ func (suite *epistolaryTestSuite) TearDownSuite() {
 func (suite *epistolaryTestSuite) ResetDatabase() (err error) {
 	// Truncate all database tables except roles, permissions, and role_permissions
 	stmts := []string{
+		"TRUNCATE recovery_codes",
+		"TRUNCATE user_mfa",
+		"TRUNCATE user_identities",
+		"TRUNCATE sessions",
+		"TRUNCATE oauth_codes",
+		"TRUNCATE oauth_clients",
+		"TRUNCATE api_key_permissions",
+		"TRUNCATE api_keys",
+		"TRUNCATE verification_tokens",
+		"TRUNCATE reset_tokens",
+		"TRUNCATE subscription_items",
+		"TRUNCATE subscriptions",
+		"TRUNCATE feed_tokens",
+		"TRUNCATE sync_jobs",
+		"TRUNCATE epistle_content",
+		"TRUNCATE notes",
+		"TRUNCATE reading_tags",
+		"TRUNCATE tags",
 		"TRUNCATE reading",
 		"TRUNCATE users",
 		"TRUNCATE epistles",
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/tokens"
+	"github.com/bbengfort/epistolary/pkg/server/users"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/gin-gonic/gin"
+)
+
+// ListSessions returns the devices the authenticated user is logged in from, marking
+// the session of the current request.
+func (s *Server) ListSessions(c *gin.Context) {
+	var (
+		err      error
+		claims   *tokens.Claims
+		userID   int64
+		sessions []*users.Session
+	)
+
+	if claims, err = GetUserClaims(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not get user claims")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if userID, err = claims.SubjectID(); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if sessions, err = users.ListSessions(c.Request.Context(), userID); err != nil {
+		sentry.Error(c).Err(err).Msg("could not list sessions")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not list sessions"))
+		return
+	}
+
+	out := &api.SessionList{Sessions: make([]*api.Session, 0, len(sessions))}
+	for _, session := range sessions {
+		out.Sessions = append(out.Sessions, &api.Session{
+			ID:        session.ID,
+			UserAgent: session.UserAgent.String,
+			ClientIP:  session.ClientIP.String,
+			Current:   session.ID == claims.ID,
+			LastUsed:  api.Timestamp{Time: session.LastUsed.Time},
+			Expires:   api.Timestamp{Time: session.Expires},
+			Created:   api.Timestamp{Time: session.Created},
+		})
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// RevokeSession logs the authenticated user out of the device of the session so that
+// its refresh token can no longer be used. Revoking the current session also clears
+// the authentication cookies.
+func (s *Server) RevokeSession(c *gin.Context) {
+	var (
+		err    error
+		claims *tokens.Claims
+		userID int64
+	)
+
+	if claims, err = GetUserClaims(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not get user claims")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if userID, err = claims.SubjectID(); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	sessionID := c.Param("sessionID")
+	if err = users.RevokeSession(c.Request.Context(), sessionID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("session not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not revoke session")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not revoke session"))
+		return
+	}
+
+	if sessionID == claims.ID {
+		c.SetCookie(AccessTokenCookie, "", -1, "/", s.conf.Token.CookieDomain, true, true)
+		c.SetCookie(RefreshTokenCookie, "", -1, "/", s.conf.Token.CookieDomain, true, true)
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,333 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/passwd"
+	"github.com/bbengfort/epistolary/pkg/server/sso"
+	"github.com/bbengfort/epistolary/pkg/server/users"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// The state of a login with an identity provider is kept in a cookie that is only
+	// sent to the login and callback endpoints of the provider.
+	ssoStateCookie = "sso_state"
+	ssoStateMaxAge = 600
+
+	// Number of usernames that are tried when a user is provisioned before giving up.
+	maxUsernameAttempts = 10
+)
+
+// Errors returned when a user cannot login with an identity provider.
+var (
+	ErrEmailNotVerified = errors.New("identity provider has not verified the email address")
+	ErrNoAccount        = errors.New("no account is registered with the email address")
+)
+
+// Creates the upstream identity providers from the configuration; the provider
+// redirects the user back to the callback endpoint of the provider after login.
+func (s *Server) setupSSO() {
+	s.sso = make(map[string]*sso.Provider, len(s.conf.SSO.Providers))
+	for _, conf := range s.conf.SSO.Providers {
+		callback := s.issuerURL(&url.URL{Path: "/v1/sso/" + conf.Name + "/callback"})
+		s.sso[conf.Name] = sso.New(conf, callback)
+	}
+}
+
+// SSOProviders lists the identity providers that users can login with so that the
+// front-end can display a login button for each of them.
+func (s *Server) SSOProviders(c *gin.Context) {
+	out := &api.SSOProviderList{Providers: make([]*api.SSOProvider, 0, len(s.conf.SSO.Providers))}
+	for _, conf := range s.conf.SSO.Providers {
+		provider := s.sso[conf.Name]
+		out.Providers = append(out.Providers, &api.SSOProvider{
+			Name:     provider.Name(),
+			Title:    provider.Title(),
+			LoginURL: s.issuerURL(&url.URL{Path: "/v1/sso/" + provider.Name() + "/login"}),
+		})
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// SSOLogin redirects the user to login with the identity provider, storing the state of
+// the login in a cookie so that the callback can verify it.
+func (s *Server) SSOLogin(c *gin.Context) {
+	provider, ok := s.sso[c.Param("provider")]
+	if !ok {
+		c.JSON(http.StatusNotFound, api.ErrorResponse("unknown identity provider"))
+		return
+	}
+
+	state, err := sso.NewState()
+	if err != nil {
+		sentry.Error(c).Err(err).Msg("could not create sso login state")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	var redirect string
+	if redirect, err = provider.AuthCodeURL(c.Request.Context(), state); err != nil {
+		sentry.Error(c).Err(err).Str("provider", provider.Name()).Msg("could not create identity provider login url")
+		c.JSON(http.StatusBadGateway, api.ErrorResponse("could not connect to identity provider"))
+		return
+	}
+
+	c.SetCookie(ssoStateCookie, state.Encode(), ssoStateMaxAge, ssoCookiePath(provider), "", true, true)
+	c.Redirect(http.StatusFound, redirect)
+}
+
+// SSOCallback completes the login with the identity provider by exchanging the
+// authorization code for the ID token of the user. The user that is linked to the
+// identity is logged in; otherwise the identity is linked to the user with the verified
+// email address or, if the provider allows it, a new user is registered. The user is
+// redirected to the front-end with the access and refresh token cookies set, to the
+// login page of the front-end with an MFA challenge token if the user has enabled MFA,
+// or to the login page with the sso_error query parameter if the login failed.
+func (s *Server) SSOCallback(c *gin.Context) {
+	var (
+		err      error
+		state    *sso.State
+		identity *sso.Identity
+		user     *users.User
+	)
+
+	provider, ok := s.sso[c.Param("provider")]
+	if !ok {
+		c.JSON(http.StatusNotFound, api.ErrorResponse("unknown identity provider"))
+		return
+	}
+
+	// The state can only be used once so the cookie is cleared whether or not the
+	// login succeeds.
+	cookie, _ := c.Cookie(ssoStateCookie)
+	c.SetCookie(ssoStateCookie, "", -1, ssoCookiePath(provider), "", true, true)
+
+	if state, err = sso.ParseState(cookie); err != nil || subtle.ConstantTimeCompare([]byte(state.State), []byte(c.Query("state"))) != 1 {
+		s.ssoError(c, "invalid_state")
+		return
+	}
+
+	if c.Query("error") != "" {
+		s.ssoError(c, errAccessDenied)
+		return
+	}
+
+	if identity, err = provider.Exchange(c.Request.Context(), c.Query("code"), state); err != nil {
+		sentry.Warn(c).Err(err).Str("provider", provider.Name()).Msg("could not exchange code with identity provider")
+		s.ssoError(c, "login_failed")
+		return
+	}
+
+	if user, err = s.ssoUser(c, provider, identity); err != nil {
+		switch {
+		case errors.Is(err, ErrEmailNotVerified):
+			s.ssoError(c, "email_not_verified")
+		case errors.Is(err, ErrNoAccount):
+			s.ssoError(c, "no_account")
+		default:
+			sentry.Error(c).Err(err).Str("provider", provider.Name()).Msg("could not find or create user for identity")
+			s.ssoError(c, "login_failed")
+		}
+		return
+	}
+
+	// Disabled users cannot login until their account is enabled by an administrator.
+	if user.IsDisabled() {
+		s.ssoError(c, "account_disabled")
+		return
+	}
+
+	// Users that have enabled multi-factor authentication must complete the login with
+	// a code from their authenticator, so the front-end is given the same MFA challenge
+	// token as Login instead of the access and refresh tokens. The challenge is in the
+	// fragment so that it is not sent to the front-end server or in the referer header.
+	var factor *users.MFA
+	if factor, err = users.GetMFA(c.Request.Context(), user.ID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		sentry.Error(c).Err(err).Msg("could not fetch mfa of user")
+		s.ssoError(c, "login_failed")
+		return
+	}
+
+	if factor.IsEnabled() {
+		var mfaToken string
+		if mfaToken, err = s.tokens.CreateMFAToken(user.ID); err != nil {
+			sentry.Error(c).Err(err).Msg("could not create mfa challenge token")
+			s.ssoError(c, "login_failed")
+			return
+		}
+
+		challenge := url.Values{"mfa_required": []string{"true"}, "mfa_token": []string{mfaToken}}
+		c.Redirect(http.StatusFound, s.frontendURL("/login", nil)+"#"+challenge.Encode())
+		return
+	}
+
+	// Create the access and refresh tokens from the claims for a new session
+	var accessToken, refreshToken string
+	claims := userClaims(c.Request.Context(), user)
+	if accessToken, refreshToken, err = s.issueTokens(c, user.ID, claims); err != nil {
+		sentry.Error(c).Err(err).Msg("could not create access and refresh tokens")
+		s.ssoError(c, "login_failed")
+		return
+	}
+
+	// Set the access and refresh tokens as cookies for the front-end
+	if err = SetAuthCookies(c, accessToken, refreshToken, s.conf.Token.CookieDomain); err != nil {
+		sentry.Error(c).Err(err).Msg("could not set access and refresh token cookies")
+		s.ssoError(c, "login_failed")
+		return
+	}
+
+	if err = user.UpdateLastSeen(c.Request.Context()); err != nil {
+		sentry.Warn(c).Err(err).Msg("could not update user last seen")
+	}
+	c.Redirect(http.StatusFound, s.frontendURL("/", nil))
+}
+
+// Returns the user that is linked to the identity, linking the identity to the user
+// with the same email address if the provider has verified it. If there is no such user
+// and the provider is allowed to provision users then a new user is created. Existing
+// users whose email address has not been verified are not linked since the account may
+// have been registered by someone else before the owner of the email address.
+func (s *Server) ssoUser(c *gin.Context, provider *sso.Provider, identity *sso.Identity) (user *users.User, err error) {
+	ctx := c.Request.Context()
+	if user, err = users.UserFromIdentity(ctx, provider.Name(), identity.Subject); err == nil {
+		if err = user.LinkIdentity(ctx, provider.Name(), identity.Subject, identity.Email, identity.EmailVerified); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
+	if user, err = users.UserFromEmail(ctx, identity.Email, true); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		if !provider.AutoProvision() {
+			return nil, ErrNoAccount
+		}
+
+		if user, err = provisionUser(c, identity); err != nil {
+			return nil, err
+		}
+	} else if !user.EmailVerified.Valid {
+		return nil, ErrEmailNotVerified
+	}
+
+	if err = user.LinkIdentity(ctx, provider.Name(), identity.Subject, identity.Email, identity.EmailVerified); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Registers a new user for the identity with a username derived from the identity and a
+// random password; the user can set a password by resetting it. If the username is taken
+// a number is appended to it until a username that is not taken is found.
+func provisionUser(c *gin.Context, identity *sso.Identity) (user *users.User, err error) {
+	user = &users.User{
+		FullName: sql.NullString{Valid: identity.Name != "", String: identity.Name},
+		Email:    identity.Email,
+	}
+
+	var password string
+	if password, err = randomPassword(); err != nil {
+		return nil, err
+	}
+
+	if user.Password, err = passwd.CreateDerivedKey(password); err != nil {
+		return nil, err
+	}
+
+	username := ssoUsername(identity)
+	for i := 1; i <= maxUsernameAttempts; i++ {
+		user.Username = username
+		if i > 1 {
+			user.Username += strconv.Itoa(i)
+		}
+
+		if err = user.Create(c.Request.Context()); !errors.Is(err, users.ErrUsernameTaken) {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Returns a valid username from the preferred username of the identity or the local
+// part of its email address, leaving room for a number to make it unique.
+func ssoUsername(identity *sso.Identity) string {
+	name := identity.PreferredUsername
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	var sb strings.Builder
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+		case sb.Len() > 0 && (r == '.' || r == '-' || r == '_'):
+			sb.WriteRune(r)
+		}
+	}
+
+	username := []rune(sb.String())
+	if len(username) > users.MaxUsernameLength-2 {
+		username = username[:users.MaxUsernameLength-2]
+	}
+
+	if len(username) < users.MinUsernameLength {
+		return "user" + string(username)
+	}
+	return string(username)
+}
+
+// Creates a random password for users that are provisioned by an identity provider.
+func randomPassword() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Redirects the user to the login page of the front-end with the reason the login with
+// the identity provider failed.
+func (s *Server) ssoError(c *gin.Context, code string) {
+	c.Redirect(http.StatusFound, s.frontendURL("/login", url.Values{"sso_error": []string{code}}))
+}
+
+// Returns the url of the path of the front-end app with the query.
+func (s *Server) frontendURL(path string, query url.Values) string {
+	base, err := url.Parse(s.conf.Token.Audience)
+	if err != nil {
+		base = &url.URL{}
+	}
+	return base.ResolveReference(&url.URL{Path: path, RawQuery: query.Encode()}).String()
+}
+
+func ssoCookiePath(provider *sso.Provider) string {
+	return "/v1/sso/" + provider.Name()
+}
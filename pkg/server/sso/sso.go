@@ -0,0 +1,373 @@
+/*
+Package sso implements the relying party of the OpenID Connect authorization code flow
+so that users can login to Epistolary with an upstream identity provider. The provider
+metadata is discovered from the issuer when it is first needed and the ID tokens that
+are returned from the provider are verified with the keys from its JWKS.
+*/
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/config"
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+const (
+	// Timeout for requests to the identity provider.
+	timeout = 10 * time.Second
+
+	// Minimum time between refreshes of the JWKS when a token is signed by an unknown
+	// key so that tokens with bogus key IDs cannot be used to flood the provider.
+	refreshInterval = 1 * time.Minute
+
+	// Number of random bytes in the state, nonce, and PKCE code verifier.
+	randomLength = 32
+)
+
+var (
+	ErrDiscovery         = errors.New("could not discover openid configuration of identity provider")
+	ErrExchange          = errors.New("could not exchange authorization code with identity provider")
+	ErrInvalidIDToken    = errors.New("identity provider returned an invalid id token")
+	ErrNonceMismatch     = errors.New("id token nonce does not match the login request")
+	ErrInvalidState      = errors.New("login state is invalid or has expired")
+	ErrIssuerMismatch    = errors.New("identity provider issuer does not match its configuration")
+	ErrUnknownSigningKey = errors.New("id token is signed by an unknown key")
+)
+
+// Provider is an upstream OpenID Connect identity provider. Providers are safe for
+// concurrent use.
+type Provider struct {
+	conf     config.SSOProvider
+	redirect string
+	client   *http.Client
+
+	mu        sync.Mutex
+	meta      *metadata
+	keys      jwk.Set
+	refreshed time.Time
+}
+
+// The subset of the OpenID configuration of the provider that is used for login.
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Identity is the verified identity of the user from the ID token of the provider.
+type Identity struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// Claims of the ID token of the provider.
+type idClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+// New creates a provider that redirects users back to the redirect URL after they login.
+func New(conf config.SSOProvider, redirectURL string) *Provider {
+	return &Provider{
+		conf:     conf,
+		redirect: redirectURL,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Name returns the name of the provider that identifies it in URLs.
+func (p *Provider) Name() string {
+	return p.conf.Name
+}
+
+// Title returns the display name of the provider, defaulting to its name.
+func (p *Provider) Title() string {
+	if p.conf.Title != "" {
+		return p.conf.Title
+	}
+	return p.conf.Name
+}
+
+// AutoProvision returns true if users without an account should be registered.
+func (p *Provider) AutoProvision() bool {
+	return p.conf.AutoProvision
+}
+
+// AuthCodeURL returns the URL of the provider that the user is redirected to in order
+// to login, using the state, nonce, and code challenge of the login.
+func (p *Provider) AuthCodeURL(ctx context.Context, state *State) (_ string, err error) {
+	var meta *metadata
+	if meta, err = p.discover(ctx); err != nil {
+		return "", err
+	}
+
+	var u *url.URL
+	if u, err = url.Parse(meta.AuthorizationEndpoint); err != nil {
+		return "", ErrDiscovery
+	}
+
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.conf.ClientID)
+	query.Set("redirect_uri", p.redirect)
+	query.Set("scope", "openid profile email")
+	query.Set("state", state.State)
+	query.Set("nonce", state.Nonce)
+	query.Set("code_challenge", state.Challenge())
+	query.Set("code_challenge_method", "S256")
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Exchange the authorization code from the callback of the provider for an ID token and
+// return the identity of the user once the ID token has been verified.
+func (p *Provider) Exchange(ctx context.Context, code string, state *State) (_ *Identity, err error) {
+	var meta *metadata
+	if meta, err = p.discover(ctx); err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    []string{"authorization_code"},
+		"code":          []string{code},
+		"redirect_uri":  []string{p.redirect},
+		"code_verifier": []string{state.Verifier},
+	}
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode())); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.conf.ClientID), url.QueryEscape(p.conf.ClientSecret))
+
+	var rep *http.Response
+	if rep, err = p.client.Do(req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrExchange, err)
+	}
+	defer rep.Body.Close()
+
+	if rep.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: [%d] %s", ErrExchange, rep.StatusCode, rep.Status)
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err = json.NewDecoder(rep.Body).Decode(&tokens); err != nil || tokens.IDToken == "" {
+		return nil, fmt.Errorf("%w: no id token in response", ErrExchange)
+	}
+
+	return p.Verify(ctx, tokens.IDToken, state.Nonce)
+}
+
+// Verify the signature and claims of the ID token from the provider, ensuring that it
+// was issued by the provider to this client for the login with the nonce.
+func (p *Provider) Verify(ctx context.Context, idToken, nonce string) (_ *Identity, err error) {
+	var meta *metadata
+	if meta, err = p.discover(ctx); err != nil {
+		return nil, err
+	}
+
+	claims := &idClaims{}
+	parser := &jwt.Parser{ValidMethods: []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}}
+	if _, err = parser.ParseWithClaims(idToken, claims, p.keyFunc(ctx)); err != nil {
+		if errors.Is(err, ErrUnknownSigningKey) {
+			return nil, ErrUnknownSigningKey
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIDToken, err)
+	}
+
+	if !claims.VerifyIssuer(meta.Issuer, true) {
+		return nil, fmt.Errorf("%w: invalid issuer %q", ErrInvalidIDToken, claims.Issuer)
+	}
+
+	if !claims.VerifyAudience(p.conf.ClientID, true) {
+		return nil, fmt.Errorf("%w: invalid audience %q", ErrInvalidIDToken, claims.Audience)
+	}
+
+	if claims.ExpiresAt == nil || claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing exp or sub claim", ErrInvalidIDToken)
+	}
+
+	if claims.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	return &Identity{
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified,
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
+
+// Returns the key that signed the token from the JWKS of the provider, refreshing the
+// JWKS if the key is not found in case the provider has rotated its keys.
+func (p *Provider) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (_ interface{}, err error) {
+		kid, _ := token.Header["kid"].(string)
+
+		var key jwk.Key
+		if key, err = p.lookupKey(ctx, kid); err != nil {
+			return nil, err
+		}
+
+		var raw interface{}
+		if err = key.Raw(&raw); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	}
+}
+
+func (p *Provider) lookupKey(ctx context.Context, kid string) (_ jwk.Key, err error) {
+	var meta *metadata
+	if meta, err = p.discover(ctx); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if p.keys != nil {
+			if key, ok := findKey(p.keys, kid); ok {
+				return key, nil
+			}
+		}
+
+		if time.Since(p.refreshed) < refreshInterval {
+			break
+		}
+
+		var keys jwk.Set
+		if keys, err = jwk.Fetch(ctx, meta.JWKSURI, jwk.WithHTTPClient(p.client)); err != nil {
+			return nil, fmt.Errorf("could not fetch jwks of identity provider: %w", err)
+		}
+		p.keys, p.refreshed = keys, time.Now()
+	}
+	return nil, ErrUnknownSigningKey
+}
+
+// Finds the signing key with the key ID; if the token has no key ID the provider must
+// have exactly one key.
+func findKey(keys jwk.Set, kid string) (jwk.Key, bool) {
+	if kid == "" {
+		if keys.Len() == 1 {
+			return keys.Key(0)
+		}
+		return nil, false
+	}
+	return keys.LookupKeyID(kid)
+}
+
+// Fetches and caches the OpenID configuration of the provider, which must have the
+// issuer that the provider is configured with (OpenID Connect Discovery 4.3).
+func (p *Provider) discover(ctx context.Context) (_ *metadata, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.meta != nil {
+		return p.meta, nil
+	}
+
+	endpoint := strings.TrimSuffix(p.conf.Issuer, "/") + "/.well-known/openid-configuration"
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var rep *http.Response
+	if rep, err = p.client.Do(req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDiscovery, err)
+	}
+	defer rep.Body.Close()
+
+	if rep.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: [%d] %s", ErrDiscovery, rep.StatusCode, rep.Status)
+	}
+
+	meta := &metadata{}
+	if err = json.NewDecoder(rep.Body).Decode(meta); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDiscovery, err)
+	}
+
+	if meta.Issuer != p.conf.Issuer {
+		return nil, ErrIssuerMismatch
+	}
+
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: missing endpoints", ErrDiscovery)
+	}
+
+	p.meta = meta
+	return p.meta, nil
+}
+
+// State is stored in a cookie while the user logs in with the provider to protect the
+// login from cross-site request forgery (state), replay of the ID token (nonce), and
+// interception of the authorization code (PKCE code verifier).
+type State struct {
+	State    string
+	Nonce    string
+	Verifier string
+}
+
+// NewState creates random values for the login.
+func NewState() (state *State, err error) {
+	state = &State{}
+	for _, val := range []*string{&state.State, &state.Nonce, &state.Verifier} {
+		buf := make([]byte, randomLength)
+		if _, err = rand.Read(buf); err != nil {
+			return nil, err
+		}
+		*val = base64.RawURLEncoding.EncodeToString(buf)
+	}
+	return state, nil
+}
+
+// ParseState parses the encoded state from the cookie.
+func ParseState(value string) (*State, error) {
+	parts := strings.Split(value, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, ErrInvalidState
+	}
+	return &State{State: parts[0], Nonce: parts[1], Verifier: parts[2]}, nil
+}
+
+// Encode the state so that it can be stored in a cookie.
+func (s *State) Encode() string {
+	return strings.Join([]string{s.State, s.Nonce, s.Verifier}, ".")
+}
+
+// Challenge returns the S256 PKCE code challenge of the code verifier.
+func (s *State) Challenge() string {
+	sum := sha256.Sum256([]byte(s.Verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
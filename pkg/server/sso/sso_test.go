@@ -0,0 +1,231 @@
+package sso_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/config"
+	"github.com/bbengfort/epistolary/pkg/server/sso"
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	clientID    = "epistolary"
+	secret      = "supersecret"
+	redirectURL = "http://localhost:8000/v1/sso/acme/callback"
+)
+
+// idp is a local stand-in for an OpenID Connect identity provider that issues an ID
+// token with the claims of the next login for any authorization code.
+type idp struct {
+	srv    *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+	claims jwt.MapClaims
+	state  *sso.State
+}
+
+func newIdP(t *testing.T) *idp {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "could not generate idp signing key")
+
+	p := &idp{key: key, kid: "idp-key-1"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.srv.URL,
+			"authorization_endpoint": p.srv.URL + "/authorize",
+			"token_endpoint":         p.srv.URL + "/token",
+			"jwks_uri":               p.srv.URL + "/jwks.json",
+		})
+	})
+
+	mux.HandleFunc("/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		pub, _ := jwk.FromRaw(&p.key.PublicKey)
+		pub.Set(jwk.KeyIDKey, p.kid)
+		set := jwk.NewSet()
+		set.AddKey(pub)
+		json.NewEncoder(w).Encode(set)
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, pass, ok := r.BasicAuth()
+		if !ok || id != clientID || pass != secret {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.PostFormValue("code") != "authcode" || r.PostFormValue("redirect_uri") != redirectURL || r.PostFormValue("code_verifier") != p.state.Verifier {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "upstream",
+			"token_type":   "Bearer",
+			"id_token":     p.sign(t, p.claims),
+		})
+	})
+
+	p.srv = httptest.NewServer(mux)
+	t.Cleanup(p.srv.Close)
+	return p
+}
+
+func (p *idp) sign(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = p.kid
+	tks, err := token.SignedString(p.key)
+	require.NoError(t, err, "could not sign id token")
+	return tks
+}
+
+func (p *idp) login(nonce string) jwt.MapClaims {
+	p.claims = jwt.MapClaims{
+		"iss":                p.srv.URL,
+		"sub":                "248289761001",
+		"aud":                clientID,
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"iat":                time.Now().Unix(),
+		"nonce":              nonce,
+		"email":              "jane@acme.test",
+		"email_verified":     true,
+		"name":               "Jane Doe",
+		"preferred_username": "jane",
+	}
+	return p.claims
+}
+
+func (p *idp) provider() *sso.Provider {
+	return sso.New(config.SSOProvider{Name: "acme", Issuer: p.srv.URL, ClientID: clientID, ClientSecret: secret}, redirectURL)
+}
+
+func TestLogin(t *testing.T) {
+	idp := newIdP(t)
+	provider := idp.provider()
+	ctx := context.Background()
+
+	state, err := sso.NewState()
+	require.NoError(t, err)
+	idp.state = state
+
+	authURL, err := provider.AuthCodeURL(ctx, state)
+	require.NoError(t, err)
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	require.Equal(t, idp.srv.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+
+	query := u.Query()
+	require.Equal(t, "code", query.Get("response_type"))
+	require.Equal(t, clientID, query.Get("client_id"))
+	require.Equal(t, redirectURL, query.Get("redirect_uri"))
+	require.Equal(t, "openid profile email", query.Get("scope"))
+	require.Equal(t, state.State, query.Get("state"))
+	require.Equal(t, state.Nonce, query.Get("nonce"))
+	require.Equal(t, state.Challenge(), query.Get("code_challenge"))
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+
+	idp.login(state.Nonce)
+	identity, err := provider.Exchange(ctx, "authcode", state)
+	require.NoError(t, err)
+	require.Equal(t, &sso.Identity{
+		Subject:           "248289761001",
+		Email:             "jane@acme.test",
+		EmailVerified:     true,
+		Name:              "Jane Doe",
+		PreferredUsername: "jane",
+	}, identity)
+
+	// The code verifier must match the challenge of the login
+	other, err := sso.NewState()
+	require.NoError(t, err)
+	_, err = provider.Exchange(ctx, "authcode", other)
+	require.ErrorIs(t, err, sso.ErrExchange)
+}
+
+func TestVerify(t *testing.T) {
+	idp := newIdP(t)
+	provider := idp.provider()
+	ctx := context.Background()
+
+	claims := idp.login("nonce")
+	_, err := provider.Verify(ctx, idp.sign(t, claims), "nonce")
+	require.NoError(t, err)
+
+	_, err = provider.Verify(ctx, idp.sign(t, claims), "other")
+	require.ErrorIs(t, err, sso.ErrNonceMismatch)
+
+	tests := map[string]func(jwt.MapClaims){
+		"issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.test" },
+		"audience": func(c jwt.MapClaims) { c["aud"] = "another-client" },
+		"expired":  func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"subject":  func(c jwt.MapClaims) { delete(c, "sub") },
+	}
+
+	for name, modify := range tests {
+		claims := idp.login("nonce")
+		modify(claims)
+		_, err = provider.Verify(ctx, idp.sign(t, claims), "nonce")
+		require.ErrorIs(t, err, sso.ErrInvalidIDToken, name)
+	}
+
+	// Tokens signed by another key are rejected
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.login("nonce"))
+	token.Header["kid"] = idp.kid
+	tks, err := token.SignedString(key)
+	require.NoError(t, err)
+	_, err = provider.Verify(ctx, tks, "nonce")
+	require.ErrorIs(t, err, sso.ErrInvalidIDToken)
+
+	token.Header["kid"] = "unknown"
+	tks, err = token.SignedString(key)
+	require.NoError(t, err)
+	_, err = provider.Verify(ctx, tks, "nonce")
+	require.ErrorIs(t, err, sso.ErrUnknownSigningKey)
+
+	// Unsigned tokens are rejected
+	tks, err = jwt.NewWithClaims(jwt.SigningMethodNone, idp.login("nonce")).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = provider.Verify(ctx, tks, "nonce")
+	require.ErrorIs(t, err, sso.ErrInvalidIDToken)
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	idp := newIdP(t)
+	provider := sso.New(config.SSOProvider{Name: "acme", Issuer: idp.srv.URL + "/", ClientID: clientID}, redirectURL)
+
+	state, err := sso.NewState()
+	require.NoError(t, err)
+
+	_, err = provider.AuthCodeURL(context.Background(), state)
+	require.ErrorIs(t, err, sso.ErrIssuerMismatch)
+}
+
+func TestState(t *testing.T) {
+	state, err := sso.NewState()
+	require.NoError(t, err)
+	require.Len(t, state.State, 43)
+	require.NotEqual(t, state.State, state.Nonce)
+	require.NotEqual(t, state.Nonce, state.Verifier)
+
+	parsed, err := sso.ParseState(state.Encode())
+	require.NoError(t, err)
+	require.Equal(t, state, parsed)
+
+	for _, value := range []string{"", "abc", "a.b", "a..c", "a.b.c.d"} {
+		_, err = sso.ParseState(value)
+		require.ErrorIs(t, err, sso.ErrInvalidState, value)
+	}
+}
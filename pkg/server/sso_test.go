@@ -0,0 +1,159 @@
+package server_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bbengfort/epistolary/pkg/server"
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/server/tokens"
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+const (
+	idpClientID = "epistolary"
+	idpSecret   = "supersecret"
+	idpSubject  = "248289761001"
+)
+
+// idp is a local stand-in for an OpenID Connect identity provider that issues an ID
+// token for the nonce of the current login in exchange for any authorization code.
+type idp struct {
+	srv   *httptest.Server
+	key   *rsa.PrivateKey
+	nonce string
+}
+
+func newIdP() (p *idp, err error) {
+	p = &idp{}
+	if p.key, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.srv.URL,
+			"authorization_endpoint": p.srv.URL + "/authorize",
+			"token_endpoint":         p.srv.URL + "/token",
+			"jwks_uri":               p.srv.URL + "/jwks.json",
+		})
+	})
+
+	mux.HandleFunc("/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		pub, _ := jwk.FromRaw(&p.key.PublicKey)
+		pub.Set(jwk.KeyIDKey, "idp-key-1")
+		set := jwk.NewSet()
+		set.AddKey(pub)
+		json.NewEncoder(w).Encode(set)
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":            p.srv.URL,
+			"sub":            idpSubject,
+			"aud":            idpClientID,
+			"exp":            time.Now().Add(time.Hour).Unix(),
+			"iat":            time.Now().Unix(),
+			"nonce":          p.nonce,
+			"email":          "jane@example.com",
+			"email_verified": true,
+		})
+		token.Header["kid"] = "idp-key-1"
+
+		tks, err := token.SignedString(p.key)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "upstream", "token_type": "Bearer", "id_token": tks})
+	})
+
+	p.srv = httptest.NewServer(mux)
+	return p, nil
+}
+
+func (suite *epistolaryTestSuite) TestSSOCallbackMFA() {
+	require := suite.Require()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	// Start the login with the identity provider
+	rep, err := client.Get(suite.srv.URL() + "/v1/sso/acme/login")
+	require.NoError(err, "could not start sso login")
+	rep.Body.Close()
+	require.Equal(http.StatusFound, rep.StatusCode)
+
+	authorize, err := url.Parse(rep.Header.Get("Location"))
+	require.NoError(err, "could not parse identity provider redirect")
+	suite.idp.nonce = authorize.Query().Get("nonce")
+
+	// The user has linked the identity and enabled MFA
+	now := time.Now()
+	mock := db.Mock()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT u.id, u.full_name, u.email, u.email_verified, u.disabled, u.username, u.role_id, u.last_seen, u.pwchanged, u.failed_logins, u.locked_until, u.created, u.modified FROM user_identities").
+		WithArgs("acme", idpSubject).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "full_name", "email", "email_verified", "disabled", "username", "role_id", "last_seen", "pwchanged", "failed_logins", "locked_until", "created", "modified"}).
+			AddRow(42, "Jane Doe", "jane@example.com", now, nil, "jane", 2, now, now, 0, nil, now, now))
+	mock.ExpectQuery("SELECT id, title, description, created, modified FROM roles WHERE id=").
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "created", "modified"}).AddRow(2, "Reader", nil, now, now))
+	mock.ExpectQuery("SELECT permission FROM user_permissions WHERE user_id=").
+		WithArgs(int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"permission"}).AddRow("epistles:read"))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO user_identities").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT secret, enabled, last_counter, created, modified FROM user_mfa WHERE user_id=").
+		WithArgs(int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"secret", "enabled", "last_counter", "created", "modified"}).AddRow("JBSWY3DPEHPK3PXP", now, 0, now, now))
+	mock.ExpectCommit()
+
+	// Complete the login with the state cookie of the login
+	query := url.Values{"code": {"authcode"}, "state": {authorize.Query().Get("state")}}
+	req, err := http.NewRequest(http.MethodGet, suite.srv.URL()+"/v1/sso/acme/callback?"+query.Encode(), nil)
+	require.NoError(err, "could not create callback request")
+	for _, cookie := range rep.Cookies() {
+		req.AddCookie(cookie)
+	}
+
+	rep, err = client.Do(req)
+	require.NoError(err, "could not complete sso login")
+	rep.Body.Close()
+	require.Equal(http.StatusFound, rep.StatusCode)
+	require.NoError(mock.ExpectationsWereMet())
+
+	// The user must complete the login with the MFA challenge token
+	redirect, err := url.Parse(rep.Header.Get("Location"))
+	require.NoError(err, "could not parse front-end redirect")
+	require.Equal("/login", redirect.Path)
+	require.Empty(redirect.Query().Get("sso_error"))
+
+	challenge, err := url.ParseQuery(redirect.Fragment)
+	require.NoError(err, "could not parse mfa challenge")
+	require.Equal("true", challenge.Get("mfa_required"))
+
+	tm, err := tokens.New(suite.conf.Token)
+	require.NoError(err, "could not create token manager")
+
+	userID, err := tm.VerifyMFAToken(challenge.Get("mfa_token"))
+	require.NoError(err, "could not verify mfa challenge token")
+	require.Equal(int64(42), userID)
+
+	// No session is created until the MFA challenge is completed
+	for _, cookie := range rep.Cookies() {
+		require.NotEqual(server.AccessTokenCookie, cookie.Name)
+		require.NotEqual(server.RefreshTokenCookie, cookie.Name)
+	}
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/subscriptions"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) ListSubscriptions(c *gin.Context) {
+	var (
+		err    error
+		userID int64
+		models []*subscriptions.Subscription
+	)
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if models, err = subscriptions.List(c.Request.Context(), userID); err != nil {
+		sentry.Error(c).Err(err).Msg("could not list subscriptions from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch subscriptions"))
+		return
+	}
+
+	out := &api.SubscriptionList{
+		Subscriptions: make([]*api.Subscription, 0, len(models)),
+	}
+
+	for _, model := range models {
+		out.Subscriptions = append(out.Subscriptions, apiSubscription(model))
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (s *Server) CreateSubscription(c *gin.Context) {
+	var (
+		err    error
+		userID int64
+		model  *subscriptions.Subscription
+	)
+
+	sub := &api.Subscription{}
+	if err = c.BindJSON(sub); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse subscription input"))
+		return
+	}
+
+	if sub.ID != 0 {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("subscription can only be created with a link"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if model, err = subscriptions.Create(c.Request.Context(), userID, sub.Link); err != nil {
+		if isSubscriptionValidationError(err) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not create subscription in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not create subscription"))
+		return
+	}
+
+	// Subscriptions can be created paused so that they are not polled until resumed
+	if sub.Paused {
+		if err = model.SetPaused(c.Request.Context(), true); err != nil {
+			sentry.Error(c).Err(err).Msg("could not pause subscription in database")
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not create subscription"))
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, apiSubscription(model))
+}
+
+func (s *Server) FetchSubscription(c *gin.Context) {
+	var (
+		err    error
+		subID  int64
+		userID int64
+		model  *subscriptions.Subscription
+	)
+
+	if subID, err = strconv.ParseInt(c.Param("subscriptionID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("subscription not found"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if model, err = subscriptions.Get(c.Request.Context(), subID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("subscription not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not fetch subscription from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiSubscription(model))
+}
+
+// UpdateSubscription pauses or resumes the subscription; the link of a subscription
+// cannot be changed, instead the subscription should be deleted and recreated.
+func (s *Server) UpdateSubscription(c *gin.Context) {
+	var (
+		err    error
+		subID  int64
+		userID int64
+	)
+
+	sub := &api.Subscription{}
+	if err = c.BindJSON(sub); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse subscription input"))
+		return
+	}
+
+	if subID, err = strconv.ParseInt(c.Param("subscriptionID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("subscription not found"))
+		return
+	}
+
+	// Populate the subscription ID from the endpoint if it was not submitted
+	if sub.ID == 0 {
+		sub.ID = subID
+	}
+
+	// Ensure the endpoint matches the ID specified by the subscription
+	if sub.ID != subID {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("id must match endpoint"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	model := &subscriptions.Subscription{ID: subID, UserID: userID}
+	if err = model.SetPaused(c.Request.Context(), sub.Paused); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("subscription not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not update subscription in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not update subscription"))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiSubscription(model))
+}
+
+func (s *Server) DeleteSubscription(c *gin.Context) {
+	var (
+		err    error
+		subID  int64
+		userID int64
+	)
+
+	if subID, err = strconv.ParseInt(c.Param("subscriptionID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("subscription not found"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if err = subscriptions.Delete(c.Request.Context(), subID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("subscription not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not delete subscription from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not delete subscription"))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func apiSubscription(model *subscriptions.Subscription) *api.Subscription {
+	return &api.Subscription{
+		ID:       model.ID,
+		Link:     model.Link,
+		Title:    model.Title.String,
+		SiteLink: model.SiteLink.String,
+		Paused:   model.Paused,
+		Polled:   api.Timestamp{Time: model.Polled.Time},
+		Failures: model.Failures,
+		Error:    model.Error.String,
+		Created:  api.Timestamp{Time: model.Created},
+		Modified: api.Timestamp{Time: model.Modified},
+	}
+}
+
+func isSubscriptionValidationError(err error) bool {
+	return errors.Is(err, subscriptions.ErrLinkRequired) ||
+		errors.Is(err, subscriptions.ErrInvalidLink) ||
+		errors.Is(err, subscriptions.ErrAlreadyExists)
+}
@@ -0,0 +1,10 @@
+package subscriptions
+
+import "errors"
+
+var (
+	ErrIDRequired    = errors.New("cannot execute query without an id stored on the model")
+	ErrLinkRequired  = errors.New("a feed link is required")
+	ErrInvalidLink   = errors.New("invalid feed link")
+	ErrAlreadyExists = errors.New("already subscribed to feed")
+)
@@ -0,0 +1,213 @@
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/server/epistles"
+	"github.com/bbengfort/epistolary/pkg/server/fetch"
+	"github.com/lib/pq"
+)
+
+// MaxBackoff limits how many times the poll interval is doubled for a subscription
+// whose feed cannot be fetched.
+const MaxBackoff = 6
+
+// MaxPollItems limits how many readings are created by a single poll of a subscription.
+const MaxPollItems = 50
+
+const (
+	dueSubscriptionsSQL = "SELECT " + subscriptionColumns + " FROM subscriptions s WHERE NOT paused AND (polled IS NULL OR polled + make_interval(secs => $1 * power(2, LEAST(failures, $2))) <= NOW()) AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id=s.user_id AND u.disabled IS NOT NULL) ORDER BY polled NULLS FIRST, id LIMIT $3"
+)
+
+// Due returns the subscriptions that have not been polled within the interval, with the
+// interval doubled for each consecutive failure to fetch the feed of the subscription.
+// Subscriptions that have never been polled are returned first and the subscriptions of
+// disabled users are not returned.
+func Due(ctx context.Context, every time.Duration, limit int) (subs []*Subscription, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(dueSubscriptionsSQL, every.Seconds(), MaxBackoff, limit); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs = make([]*Subscription, 0)
+	for rows.Next() {
+		sub := &Subscription{}
+		if err = sub.scan(rows); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return subs, nil
+}
+
+const (
+	seenItemsSQL   = "SELECT guid FROM subscription_items WHERE subscription_id=$1 AND guid=ANY($2)"
+	markItemSQL    = "INSERT INTO subscription_items (subscription_id, guid) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+	pollSuccessSQL = "UPDATE subscriptions SET title=COALESCE($2, title), site_link=COALESCE($3, site_link), etag=$4, last_modified=$5, polled=NOW(), succeeded=NOW(), failures=0, error=NULL WHERE id=$1 RETURNING polled, succeeded, modified"
+	pollFailureSQL = "UPDATE subscriptions SET polled=NOW(), failures=failures+1, error=$2 WHERE id=$1 RETURNING polled, failures, modified"
+)
+
+// Poll fetches the feed of the subscription and creates a reading for each item in the
+// feed that has not been seen before, returning the number of readings created. The
+// first successful poll of a subscription only records the items that are already in
+// the feed so that subscribing to a feed does not queue its entire backlog, and at most
+// MaxPollItems of the newest items are queued by any later poll. Failures to fetch the
+// feed are stored on the subscription rather than returned; an error is only returned
+// if the database could not be updated.
+func (s *Subscription) Poll(ctx context.Context) (queued int, err error) {
+	if s.ID == 0 || s.UserID == 0 {
+		return 0, ErrIDRequired
+	}
+
+	var feed *fetch.Feed
+	if feed, err = fetch.FetchFeedConditional(ctx, s.Link, s.ETag.String, s.LastModified.String); err != nil {
+		var herr fetch.HTTPError
+		if errors.As(err, &herr) && herr.NotModified() {
+			return 0, s.polled(ctx, nil)
+		}
+		return 0, s.failed(ctx, err)
+	}
+
+	var items []*fetch.FeedItem
+	if items, err = s.unseen(ctx, feed.Items); err != nil {
+		return 0, err
+	}
+
+	// Feeds list the newest items first, so create the readings of the oldest items
+	// first to preserve the order of the feed in the user's reading list. Older items
+	// past the limit are marked as seen without being queued.
+	if s.Succeeded.Valid {
+		queue := items
+		if len(queue) > MaxPollItems {
+			queue = queue[:MaxPollItems]
+		}
+
+		for i := len(queue) - 1; i >= 0; i-- {
+			if _, err = epistles.Create(ctx, s.UserID, queue[i].Link); err != nil {
+				if errors.Is(err, epistles.ErrAlreadyExists) || errors.Is(err, epistles.ErrInvalidLink) {
+					continue
+				}
+				return queued, err
+			}
+			queued++
+		}
+	}
+
+	if err = s.polled(ctx, feed, items...); err != nil {
+		return queued, err
+	}
+	return queued, nil
+}
+
+// Returns the items of the feed that have not been seen by the subscription.
+func (s *Subscription) unseen(ctx context.Context, items []*fetch.FeedItem) (_ []*fetch.FeedItem, err error) {
+	guids := make([]string, 0, len(items))
+	for _, item := range items {
+		guids = append(guids, item.ID)
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(seenItemsSQL, s.ID, pq.Array(guids)); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := make(map[string]struct{}, len(items))
+	for rows.Next() {
+		var guid string
+		if err = rows.Scan(&guid); err != nil {
+			return nil, err
+		}
+		seen[guid] = struct{}{}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	unseen := make([]*fetch.FeedItem, 0, len(items))
+	for _, item := range items {
+		if _, ok := seen[item.ID]; !ok {
+			seen[item.ID] = struct{}{}
+			unseen = append(unseen, item)
+		}
+	}
+
+	tx.Commit()
+	return unseen, nil
+}
+
+// Records a successful poll, marking the items as seen. If the feed is nil then it was
+// not modified since the last poll and only the poll time is updated.
+func (s *Subscription) polled(ctx context.Context, feed *fetch.Feed, items ...*fetch.FeedItem) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if feed != nil {
+		s.Title = nullString(feed.Title, s.Title)
+		s.SiteLink = nullString(feed.SiteLink, s.SiteLink)
+		s.ETag = sql.NullString{Valid: feed.ETag != "", String: feed.ETag}
+		s.LastModified = sql.NullString{Valid: feed.LastModified != "", String: feed.LastModified}
+	}
+
+	if err = tx.QueryRow(pollSuccessSQL, s.ID, s.Title, s.SiteLink, s.ETag, s.LastModified).Scan(&s.Polled, &s.Succeeded, &s.Modified); err != nil {
+		return err
+	}
+	s.Failures, s.Error = 0, sql.NullString{}
+
+	for _, item := range items {
+		if _, err = tx.Exec(markItemSQL, s.ID, item.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Records a failure to fetch the feed so that the poll is retried with backoff.
+func (s *Subscription) failed(ctx context.Context, perr error) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	s.Error = sql.NullString{Valid: true, String: perr.Error()}
+	if err = tx.QueryRow(pollFailureSQL, s.ID, s.Error).Scan(&s.Polled, &s.Failures, &s.Modified); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func nullString(s string, prev sql.NullString) sql.NullString {
+	if s == "" {
+		return prev
+	}
+	return sql.NullString{Valid: true, String: s}
+}
@@ -0,0 +1,196 @@
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bbengfort/epistolary/pkg/server/config"
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/stretchr/testify/require"
+)
+
+const etag = `"v1"`
+
+func TestPollFirst(t *testing.T) {
+	mock := setupDB(t)
+	srv := feedServer(t, 3)
+	now := time.Now()
+
+	// The first poll only marks the items in the feed as seen
+	sub := &Subscription{ID: 7, UserID: 42, Link: srv.URL + "/feed.xml"}
+	expectUnseen(mock, 7)
+	expectPolled(mock, 7, now, "post-3", "post-2", "post-1")
+
+	queued, err := sub.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, queued)
+	require.True(t, sub.Succeeded.Valid)
+	require.Equal(t, etag, sub.ETag.String)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPollAfterFailure(t *testing.T) {
+	mock := setupDB(t)
+	srv := feedServer(t, 3)
+	now := time.Now()
+
+	// A failure to fetch the feed updates the poll time but not the success time
+	sub := &Subscription{ID: 7, UserID: 42, Link: srv.URL + "/missing.xml"}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(pollFailureSQL)).
+		WithArgs(int64(7), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"polled", "failures", "modified"}).AddRow(now, 1, now))
+	mock.ExpectCommit()
+
+	queued, err := sub.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, queued)
+	require.True(t, sub.Polled.Valid)
+	require.False(t, sub.Succeeded.Valid)
+	require.Equal(t, int64(1), sub.Failures)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// The first successful poll after a failure still only marks the items as seen
+	sub.Link = srv.URL + "/feed.xml"
+	expectUnseen(mock, 7)
+	expectPolled(mock, 7, now, "post-3", "post-2", "post-1")
+
+	queued, err = sub.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, queued)
+	require.True(t, sub.Succeeded.Valid)
+	require.Equal(t, int64(0), sub.Failures)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPollNotModified(t *testing.T) {
+	mock := setupDB(t)
+	srv := feedServer(t, 3)
+	now := time.Now()
+
+	// A feed that has not been modified only updates the poll time
+	sub := &Subscription{
+		ID:        7,
+		UserID:    42,
+		Link:      srv.URL + "/feed.xml",
+		ETag:      sql.NullString{Valid: true, String: etag},
+		Polled:    sql.NullTime{Valid: true, Time: now.Add(-1 * time.Hour)},
+		Succeeded: sql.NullTime{Valid: true, Time: now.Add(-1 * time.Hour)},
+	}
+	expectPolled(mock, 7, now)
+
+	queued, err := sub.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, queued)
+	require.Equal(t, etag, sub.ETag.String)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPollMaxItems(t *testing.T) {
+	mock := setupDB(t)
+	srv := feedServer(t, MaxPollItems+2)
+	now := time.Now()
+
+	// Only the newest items are queued, oldest first, but every item is marked as seen
+	sub := &Subscription{
+		ID:        7,
+		UserID:    42,
+		Link:      srv.URL + "/feed.xml",
+		Polled:    sql.NullTime{Valid: true, Time: now.Add(-1 * time.Hour)},
+		Succeeded: sql.NullTime{Valid: true, Time: now.Add(-1 * time.Hour)},
+	}
+	expectUnseen(mock, 7)
+
+	guids := make([]string, 0, MaxPollItems+2)
+	for i := MaxPollItems + 2; i > 0; i-- {
+		guids = append(guids, fmt.Sprintf("post-%d", i))
+	}
+
+	for i := 3; i <= MaxPollItems+2; i++ {
+		link := fmt.Sprintf("%s/posts/%d", srv.URL, i)
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, link, normalized, title, description, favicon, site_name, author, published, image, canonical, synced, sync_attempts, sync_error, etag, last_modified, created, modified FROM epistles WHERE normalized=$1 OR link=$2")).
+			WithArgs(sqlmock.AnyArg(), link).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "link", "normalized", "title", "description", "favicon", "site_name", "author", "published", "image", "canonical", "synced", "sync_attempts", "sync_error", "etag", "last_modified", "created", "modified"}).
+				AddRow(int64(i), link, strings.TrimPrefix(link, "http://"), nil, nil, nil, nil, nil, nil, nil, nil, now, 1, nil, nil, nil, now, now))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM reading WHERE epistle_id=$1 AND user_id=$2 AND deleted IS NOT NULL")).
+			WithArgs(int64(i), int64(42)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO reading (epistle_id, user_id) VALUES ($1, $2)")).
+			WithArgs(int64(i), int64(42)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT created, modified FROM reading WHERE epistle_id=$1 AND user_id=$2 AND deleted IS NULL")).
+			WithArgs(int64(i), int64(42)).
+			WillReturnRows(sqlmock.NewRows([]string{"created", "modified"}).AddRow(now, now))
+		mock.ExpectCommit()
+	}
+	expectPolled(mock, 7, now, guids...)
+
+	queued, err := sub.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, MaxPollItems, queued)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func setupDB(t *testing.T) sqlmock.Sqlmock {
+	require.NoError(t, db.Connect(config.DatabaseConfig{Testing: true}))
+	t.Cleanup(func() { db.Close() })
+	return db.Mock()
+}
+
+// Serves a feed with the number of items, newest first, at /feed.xml that is not
+// modified if requested with the etag; any other path is not found.
+func feedServer(t *testing.T, items int) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feed.xml" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, `<rss version="2.0"><channel><title>Test</title>`)
+		for i := items; i > 0; i-- {
+			fmt.Fprintf(w, `<item><guid>post-%d</guid><link>/posts/%d</link></item>`, i, i)
+		}
+		fmt.Fprint(w, `</channel></rss>`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// Expects none of the items of the feed to have been seen before.
+func expectUnseen(mock sqlmock.Sqlmock, subID int64) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(seenItemsSQL)).
+		WithArgs(subID, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"guid"}))
+	mock.ExpectCommit()
+}
+
+// Expects a successful poll that marks the items as seen.
+func expectPolled(mock sqlmock.Sqlmock, subID int64, now time.Time, guids ...string) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(pollSuccessSQL)).
+		WithArgs(subID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"polled", "succeeded", "modified"}).AddRow(now, now, now))
+	for _, guid := range guids {
+		mock.ExpectExec(regexp.QuoteMeta(markItemSQL)).
+			WithArgs(subID, guid).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+}
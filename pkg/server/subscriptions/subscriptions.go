@@ -0,0 +1,202 @@
+/*
+Package subscriptions watches the RSS and Atom feeds that users follow and queues the
+new posts of each feed as readings so that users do not have to add them by hand.
+*/
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/utils/urlnorm"
+	"github.com/lib/pq"
+)
+
+// Subscription is a user's subscription to the feed at the link. The ETag and
+// Last-Modified headers of the last fetch are stored to make conditional requests.
+// Polled is the time of the last attempt to fetch the feed, whereas Succeeded is the
+// time the feed was last fetched successfully.
+type Subscription struct {
+	ID           int64
+	UserID       int64
+	Link         string
+	Title        sql.NullString
+	SiteLink     sql.NullString
+	ETag         sql.NullString
+	LastModified sql.NullString
+	Paused       bool
+	Polled       sql.NullTime
+	Succeeded    sql.NullTime
+	Failures     int64
+	Error        sql.NullString
+	Created      time.Time
+	Modified     time.Time
+}
+
+const subscriptionColumns = "id, user_id, link, title, site_link, etag, last_modified, paused, polled, succeeded, failures, error, created, modified"
+
+func (s *Subscription) scan(row interface{ Scan(...any) error }) error {
+	return row.Scan(&s.ID, &s.UserID, &s.Link, &s.Title, &s.SiteLink, &s.ETag, &s.LastModified, &s.Paused, &s.Polled, &s.Succeeded, &s.Failures, &s.Error, &s.Created, &s.Modified)
+}
+
+const (
+	listSubscriptionsSQL = "SELECT " + subscriptionColumns + " FROM subscriptions WHERE user_id=$1 ORDER BY created, id"
+	getSubscriptionSQL   = "SELECT " + subscriptionColumns + " FROM subscriptions WHERE id=$1 AND user_id=$2"
+)
+
+// List the subscriptions of the user, oldest first.
+func List(ctx context.Context, userID int64) (subs []*Subscription, err error) {
+	if userID == 0 {
+		return nil, ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(listSubscriptionsSQL, userID); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs = make([]*Subscription, 0)
+	for rows.Next() {
+		sub := &Subscription{}
+		if err = sub.scan(rows); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return subs, nil
+}
+
+// Get a subscription by its id for the specified user. Returns sql.ErrNoRows if the user
+// does not have a subscription with the id.
+func Get(ctx context.Context, subID, userID int64) (sub *Subscription, err error) {
+	if subID == 0 || userID == 0 {
+		return nil, ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	sub = &Subscription{}
+	if err = sub.scan(tx.QueryRow(getSubscriptionSQL, subID, userID)); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return sub, nil
+}
+
+const (
+	createSubscriptionSQL = "INSERT INTO subscriptions (user_id, link) VALUES ($1, $2) RETURNING " + subscriptionColumns
+)
+
+// Create a subscription for the user to the feed at the link. The feed is not fetched
+// until it is polled; any problems fetching the feed are stored on the subscription.
+func Create(ctx context.Context, userID int64, link string) (sub *Subscription, err error) {
+	if userID == 0 {
+		return nil, ErrIDRequired
+	}
+
+	if link == "" {
+		return nil, ErrLinkRequired
+	}
+
+	if link, err = urlnorm.Normalize(link); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLink, err)
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	sub = &Subscription{}
+	if err = sub.scan(tx.QueryRow(createSubscriptionSQL, userID, link)); err != nil {
+		if pgerr, ok := err.(*pq.Error); ok && pgerr.Code == "23505" {
+			return nil, ErrAlreadyExists
+		}
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+const (
+	pauseSubscriptionSQL  = "UPDATE subscriptions SET paused=true WHERE id=$1 AND user_id=$2 RETURNING " + subscriptionColumns
+	resumeSubscriptionSQL = "UPDATE subscriptions SET paused=false, failures=0, error=NULL WHERE id=$1 AND user_id=$2 RETURNING " + subscriptionColumns
+)
+
+// SetPaused pauses or resumes polling the feed of the subscription. Resuming a
+// subscription clears any previous failures so that it is polled again immediately.
+// Returns sql.ErrNoRows if the user does not have the subscription.
+func (s *Subscription) SetPaused(ctx context.Context, paused bool) (err error) {
+	if s.ID == 0 || s.UserID == 0 {
+		return ErrIDRequired
+	}
+
+	query := resumeSubscriptionSQL
+	if paused {
+		query = pauseSubscriptionSQL
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = s.scan(tx.QueryRow(query, s.ID, s.UserID)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const (
+	deleteSubscriptionSQL = "DELETE FROM subscriptions WHERE id=$1 AND user_id=$2"
+)
+
+// Delete the subscription; readings that were queued from the feed are not deleted.
+// Returns sql.ErrNoRows if the user does not have the subscription.
+func Delete(ctx context.Context, subID, userID int64) (err error) {
+	if subID == 0 || userID == 0 {
+		return ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(deleteSubscriptionSQL, subID, userID); err != nil {
+		return err
+	}
+
+	if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/epistles"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	syncLease    = 5 * time.Minute
+	syncTimeout  = 3 * time.Minute
+	syncInterval = 30 * time.Second
+
+	resyncInterval  = 1 * time.Hour
+	resyncBatchSize = 500
+)
+
+// SyncWorker runs in its own go routine and syncs the metadata of epistles from the
+// sync job queue. Workers process jobs until the queue has no jobs that are ready to
+// run, then wait to be notified of new jobs or for the retry interval to elapse. The
+// worker is stopped when the server is shutdown.
+func (s *Server) SyncWorker() {
+	defer s.wg.Done()
+
+	interval := s.conf.Sync.Interval
+	if interval <= 0 {
+		interval = syncInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for s.syncNext() {
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-s.syncq:
+		case <-ticker.C:
+		}
+	}
+}
+
+// Syncs the next epistle in the queue, returning false if there are no jobs that are
+// ready to run, the queue could not be read, or the server is shutting down.
+func (s *Server) syncNext() bool {
+	select {
+	case <-s.done:
+		return false
+	default:
+	}
+
+	ctx, cancel := s.withShutdown(syncTimeout)
+	defer cancel()
+
+	job, err := epistles.NextSyncJob(ctx, syncLease)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			sentry.Error(ctx).Err(err).Msg("could not fetch next sync job")
+		}
+		return false
+	}
+
+	var synced bool
+	if synced, err = job.Run(ctx, s.conf.Sync.MaxAttempts); err != nil {
+		sentry.Error(ctx).Err(err).Int64("epistle", job.EpistleID).Msg("could not update sync job")
+		return true
+	}
+
+	log.Debug().Int64("epistle", job.EpistleID).Int64("attempts", job.Attempts).Bool("synced", synced).Msg("sync job processed")
+	return true
+}
+
+// Returns a context with the timeout that is also cancelled when the server is shutdown
+// so that shutdown does not wait for a long running sync to complete. Jobs interrupted
+// by shutdown are retried once their lease expires.
+func (s *Server) withShutdown(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// Resyncer runs in its own go routine and periodically queues epistles whose metadata
+// has not been synced within the stale duration so that the sync workers can refresh
+// them. The resyncer is stopped when the server is shutdown.
+func (s *Server) Resyncer() {
+	defer s.wg.Done()
+
+	interval := s.conf.Sync.ResyncInterval
+	if interval <= 0 {
+		interval = resyncInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+		n, err := epistles.EnqueueStale(ctx, time.Now().Add(-s.conf.Sync.StaleAfter), resyncBatchSize)
+		cancel()
+
+		if err != nil {
+			sentry.Error(ctx).Err(err).Msg("could not queue stale epistles for resync")
+			continue
+		}
+
+		log.Debug().Int64("epistles", n).Msg("queued stale epistles for resync")
+		if n > 0 {
+			s.NotifySync()
+		}
+	}
+}
+
+// NotifySync wakes an idle sync worker to process a newly queued sync job. If all of
+// the workers are busy the notification is dropped since the workers will process the
+// job once they have finished with their current jobs.
+func (s *Server) NotifySync() {
+	select {
+	case s.syncq <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/tags"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) ListTags(c *gin.Context) {
+	var (
+		err    error
+		userID int64
+		models []*tags.Tag
+	)
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if models, err = tags.List(c.Request.Context(), userID); err != nil {
+		sentry.Error(c).Err(err).Msg("could not list tags from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not fetch tags"))
+		return
+	}
+
+	out := &api.TagList{
+		Tags: make([]*api.Tag, 0, len(models)),
+	}
+
+	for _, model := range models {
+		out.Tags = append(out.Tags, apiTag(model))
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (s *Server) CreateTag(c *gin.Context) {
+	var (
+		err    error
+		userID int64
+	)
+
+	tag := &api.Tag{}
+	if err = c.BindJSON(tag); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse tag input"))
+		return
+	}
+
+	if tag.ID != 0 {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("tag can only be created with a name"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	model := &tags.Tag{UserID: userID, Name: tag.Name}
+	if err = model.Create(c.Request.Context()); err != nil {
+		if isTagValidationError(err) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not create tag in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not create tag"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiTag(model))
+}
+
+func (s *Server) FetchTag(c *gin.Context) {
+	var (
+		err    error
+		tagID  int64
+		userID int64
+		model  *tags.Tag
+	)
+
+	if tagID, err = strconv.ParseInt(c.Param("tagID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("tag not found"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	if model, err = tags.Get(c.Request.Context(), tagID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("tag not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not fetch tag from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiTag(model))
+}
+
+func (s *Server) UpdateTag(c *gin.Context) {
+	var (
+		err    error
+		tagID  int64
+		userID int64
+		model  *tags.Tag
+	)
+
+	tag := &api.Tag{}
+	if err = c.BindJSON(tag); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse tag input"))
+		return
+	}
+
+	if tagID, err = strconv.ParseInt(c.Param("tagID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("tag not found"))
+		return
+	}
+
+	// Populate the tag ID from the endpoint if it was not submitted
+	if tag.ID == 0 {
+		tag.ID = tagID
+	}
+
+	// Ensure the endpoint matches the ID specified by the tag
+	if tag.ID != tagID {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("id must match endpoint"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	// Only the name of the tag can be updated
+	model = &tags.Tag{ID: tagID, UserID: userID}
+	if err = model.Rename(c.Request.Context(), tag.Name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("tag not found"))
+			return
+		}
+
+		if isTagValidationError(err) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not update tag in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not update tag"))
+		return
+	}
+
+	// Refetch the tag to return the number of readings with the tag
+	if model, err = tags.Get(c.Request.Context(), tagID, userID); err != nil {
+		sentry.Error(c).Err(err).Msg("could not fetch tag from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiTag(model))
+}
+
+func (s *Server) DeleteTag(c *gin.Context) {
+	var (
+		err    error
+		tagID  int64
+		userID int64
+	)
+
+	if tagID, err = strconv.ParseInt(c.Param("tagID"), 10, 64); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, api.ErrorResponse("tag not found"))
+		return
+	}
+
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	// Deleting a tag removes it from all readings but does not delete the readings.
+	if err = tags.Delete(c.Request.Context(), tagID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("tag not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not delete tag from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func apiTag(model *tags.Tag) *api.Tag {
+	return &api.Tag{
+		ID:       model.ID,
+		Name:     model.Name,
+		Readings: model.Readings,
+		Created:  api.Timestamp{Time: model.Created},
+		Modified: api.Timestamp{Time: model.Modified},
+	}
+}
+
+func isTagValidationError(err error) bool {
+	return errors.Is(err, tags.ErrNameRequired) ||
+		errors.Is(err, tags.ErrNameTooLong) ||
+		errors.Is(err, tags.ErrInvalidName) ||
+		errors.Is(err, tags.ErrAlreadyExists)
+}
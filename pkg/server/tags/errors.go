@@ -0,0 +1,11 @@
+package tags
+
+import "errors"
+
+var (
+	ErrIDRequired    = errors.New("cannot execute query without an id stored on the model")
+	ErrNameRequired  = errors.New("tag name is required")
+	ErrNameTooLong   = errors.New("tag name cannot be longer than 64 characters")
+	ErrInvalidName   = errors.New("tag name cannot contain commas")
+	ErrAlreadyExists = errors.New("tag already exists")
+)
@@ -0,0 +1,101 @@
+package tags
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/lib/pq"
+)
+
+const (
+	readingTagsSQL = "SELECT rt.epistle_id, t.name FROM reading_tags rt JOIN tags t ON rt.tag_id=t.id WHERE rt.user_id=$1 AND rt.epistle_id = ANY($2) ORDER BY t.name"
+)
+
+// ForReadings returns the names of the tags on each of the specified readings of the
+// user, mapped by epistle id. Readings without tags are not included in the map.
+func ForReadings(ctx context.Context, userID int64, epistleIDs ...int64) (tags map[int64][]string, err error) {
+	tags = make(map[int64][]string)
+	if len(epistleIDs) == 0 {
+		return tags, nil
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(readingTagsSQL, userID, pq.Array(epistleIDs)); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			epistleID int64
+			name      string
+		)
+
+		if err = rows.Scan(&epistleID, &name); err != nil {
+			return nil, err
+		}
+		tags[epistleID] = append(tags[epistleID], name)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return tags, nil
+}
+
+const (
+	ensureTagsSQL       = "INSERT INTO tags (user_id, name) SELECT $1, unnest($2::text[]) ON CONFLICT (user_id, name) DO NOTHING"
+	clearReadingTagsSQL = "DELETE FROM reading_tags WHERE epistle_id=$1 AND user_id=$2 AND tag_id NOT IN (SELECT id FROM tags WHERE user_id=$2 AND name = ANY($3))"
+	addReadingTagsSQL   = "INSERT INTO reading_tags (epistle_id, user_id, tag_id) SELECT $1, $2, id FROM tags WHERE user_id=$2 AND name = ANY($3) ON CONFLICT DO NOTHING"
+)
+
+// SetReadingTags replaces the tags on the user's reading with the specified tag names,
+// creating any tags that the user does not have yet. The normalized tag names are
+// returned. An empty list of names removes all of the tags from the reading.
+func SetReadingTags(ctx context.Context, epistleID, userID int64, names []string) (_ []string, err error) {
+	if epistleID == 0 || userID == 0 {
+		return nil, ErrIDRequired
+	}
+
+	if names, err = NormalizeAll(names); err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if len(names) > 0 {
+		if _, err = tx.Exec(ensureTagsSQL, userID, pq.Array(names)); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err = tx.Exec(clearReadingTagsSQL, epistleID, userID, pq.Array(names)); err != nil {
+		return nil, err
+	}
+
+	if len(names) > 0 {
+		if _, err = tx.Exec(addReadingTagsSQL, epistleID, userID, pq.Array(names)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
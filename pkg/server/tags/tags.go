@@ -0,0 +1,218 @@
+package tags
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/lib/pq"
+)
+
+// MaxNameLength is the maximum number of characters in a tag name.
+const MaxNameLength = 64
+
+// Database model for a tag object. Tags are owned by a single user and are used to
+// group that user's readings into collections.
+type Tag struct {
+	ID       int64
+	UserID   int64
+	Name     string
+	Readings int64
+	Created  time.Time
+	Modified time.Time
+}
+
+// Normalize a tag name so that tags are case-insensitive and free of extra whitespace.
+// Commas are not allowed since tags are specified as comma separated lists in queries.
+func Normalize(name string) (string, error) {
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+	switch {
+	case name == "":
+		return "", ErrNameRequired
+	case utf8.RuneCountInString(name) > MaxNameLength:
+		return "", ErrNameTooLong
+	case strings.Contains(name, ","):
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
+
+// NormalizeAll normalizes the tag names, removing any duplicates.
+func NormalizeAll(names []string) (_ []string, err error) {
+	seen := make(map[string]struct{}, len(names))
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if name, err = Normalize(name); err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		out = append(out, name)
+	}
+	return out, nil
+}
+
+const (
+	listTagsSQL = "SELECT t.id, t.name, count(r.epistle_id), t.created, t.modified FROM tags t LEFT JOIN reading_tags rt ON rt.tag_id=t.id LEFT JOIN reading r ON r.epistle_id=rt.epistle_id AND r.user_id=rt.user_id AND r.deleted IS NULL WHERE t.user_id=$1 GROUP BY t.id ORDER BY t.name"
+	getTagSQL   = "SELECT t.name, count(r.epistle_id), t.created, t.modified FROM tags t LEFT JOIN reading_tags rt ON rt.tag_id=t.id LEFT JOIN reading r ON r.epistle_id=rt.epistle_id AND r.user_id=rt.user_id AND r.deleted IS NULL WHERE t.id=$1 AND t.user_id=$2 GROUP BY t.id"
+)
+
+// List all of the tags for the specified user in alphabetical order along with the
+// number of readings that have been tagged with each tag.
+func List(ctx context.Context, userID int64) (tags []*Tag, err error) {
+	if userID == 0 {
+		return nil, ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(listTagsSQL, userID); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags = make([]*Tag, 0)
+	for rows.Next() {
+		tag := &Tag{UserID: userID}
+		if err = rows.Scan(&tag.ID, &tag.Name, &tag.Readings, &tag.Created, &tag.Modified); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return tags, nil
+}
+
+// Get a tag by its id for the specified user. Returns sql.ErrNoRows if the user does
+// not have a tag with the id.
+func Get(ctx context.Context, tagID, userID int64) (tag *Tag, err error) {
+	if tagID == 0 || userID == 0 {
+		return nil, ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tag = &Tag{ID: tagID, UserID: userID}
+	if err = tx.QueryRow(getTagSQL, tagID, userID).Scan(&tag.Name, &tag.Readings, &tag.Created, &tag.Modified); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return tag, nil
+}
+
+const (
+	createTagSQL = "INSERT INTO tags (user_id, name) VALUES ($1, $2) RETURNING id, created, modified"
+)
+
+// Create a new tag for the user with the normalized name of the tag.
+func (t *Tag) Create(ctx context.Context) (err error) {
+	if t.UserID == 0 {
+		return ErrIDRequired
+	}
+
+	if t.Name, err = Normalize(t.Name); err != nil {
+		return err
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow(createTagSQL, t.UserID, t.Name).Scan(&t.ID, &t.Created, &t.Modified); err != nil {
+		if pgerr, ok := err.(*pq.Error); ok && pgerr.Code == "23505" {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+
+	t.Readings = 0
+	return tx.Commit()
+}
+
+const (
+	renameTagSQL = "UPDATE tags SET name=$3 WHERE id=$1 AND user_id=$2 RETURNING created, modified"
+)
+
+// Rename the tag, returning sql.ErrNoRows if the user does not have a tag with the id.
+func (t *Tag) Rename(ctx context.Context, name string) (err error) {
+	if t.ID == 0 || t.UserID == 0 {
+		return ErrIDRequired
+	}
+
+	if name, err = Normalize(name); err != nil {
+		return err
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow(renameTagSQL, t.ID, t.UserID, name).Scan(&t.Created, &t.Modified); err != nil {
+		if pgerr, ok := err.(*pq.Error); ok && pgerr.Code == "23505" {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+
+	t.Name = name
+	return tx.Commit()
+}
+
+const (
+	deleteTagSQL = "DELETE FROM tags WHERE id=$1 AND user_id=$2"
+)
+
+// Delete the tag from the user, removing it from all of the user's readings. Returns
+// sql.ErrNoRows if the user does not have a tag with the id.
+func Delete(ctx context.Context, tagID, userID int64) (err error) {
+	if tagID == 0 || userID == 0 {
+		return ErrIDRequired
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(deleteTagSQL, tagID, userID); err != nil {
+		return err
+	}
+
+	var nRows int64
+	if nRows, err = result.RowsAffected(); err != nil {
+		return err
+	}
+
+	if nRows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,67 @@
+package tags_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bbengfort/epistolary/pkg/server/tags"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		err      error
+	}{
+		{"golang", "golang", nil},
+		{"GoLang", "golang", nil},
+		{"  Machine   Learning\t", "machine learning", nil},
+		{"to\nread", "to read", nil},
+		{"ÉCRITURE", "écriture", nil},
+		{"", "", tags.ErrNameRequired},
+		{" \t\n ", "", tags.ErrNameRequired},
+		{"a,b", "", tags.ErrInvalidName},
+		{strings.Repeat("a", tags.MaxNameLength), strings.Repeat("a", tags.MaxNameLength), nil},
+		{strings.Repeat("é", tags.MaxNameLength), strings.Repeat("é", tags.MaxNameLength), nil},
+		{strings.Repeat("a", tags.MaxNameLength+1), "", tags.ErrNameTooLong},
+	}
+
+	for _, tc := range tests {
+		actual, err := tags.Normalize(tc.name)
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err, "expected an error normalizing %q", tc.name)
+			continue
+		}
+
+		require.NoError(t, err, "could not normalize %q", tc.name)
+		require.Equal(t, tc.expected, actual)
+	}
+}
+
+func TestNormalizeAll(t *testing.T) {
+	tests := []struct {
+		names    []string
+		expected []string
+		err      error
+	}{
+		{nil, []string{}, nil},
+		{[]string{"Go", "Rust"}, []string{"go", "rust"}, nil},
+		{[]string{"Go", "go", " GO ", "rust"}, []string{"go", "rust"}, nil},
+		{[]string{"machine  learning", "Machine Learning"}, []string{"machine learning"}, nil},
+		{[]string{"go", ""}, nil, tags.ErrNameRequired},
+		{[]string{"go", "a,b"}, nil, tags.ErrInvalidName},
+	}
+
+	for _, tc := range tests {
+		actual, err := tags.NormalizeAll(tc.names)
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err, "expected an error normalizing %v", tc.names)
+			require.Nil(t, actual)
+			continue
+		}
+
+		require.NoError(t, err, "could not normalize %v", tc.names)
+		require.Equal(t, tc.expected, actual)
+	}
+}
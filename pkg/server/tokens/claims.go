@@ -14,6 +14,30 @@ type Claims struct {
 	Email       string   `json:"email,omitempty"`
 	Role        string   `json:"role,omitempty"`
 	Permissions []string `json:"permissions,omitempty"`
+
+	// The username of the administrator that is impersonating the user for support.
+	Impersonator string `json:"impersonator,omitempty"`
+
+	// The client ID of the third-party OAuth client the token was issued to and the
+	// scope that the user authorized; tokens issued to clients have no permissions.
+	ClientID string `json:"client_id,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+
+	// The ID of the API key that authenticated the request; API keys are not JWTs so
+	// this is never serialized and cannot be set by a token.
+	APIKeyID int64 `json:"-"`
+}
+
+// IDClaims are the claims of an OpenID Connect ID token that identifies the user to a
+// third-party client. The profile and email claims are only set if the client requested
+// the corresponding scopes.
+type IDClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce,omitempty"`
+	Name              string `json:"name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     *bool  `json:"email_verified,omitempty"`
 }
 
 func (c *Claims) SetSubjectID(uid int64) {
@@ -0,0 +1,128 @@
+package tokens
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	ulid "github.com/oklog/ulid/v2"
+)
+
+const keyExt = ".pem"
+
+// KeyStore persists the RSA signing keys of the token manager in a directory as PEM
+// encoded files named by the ulid of the key. Because the ulid of a key is created from
+// the time the key was generated, the store does not need any other metadata to know
+// when a key was published or whether it should be rotated.
+type KeyStore struct {
+	dir  string
+	size int
+}
+
+// NewKeyStore creates a key store that loads keys from and generates keys of the
+// specified number of bits in the directory. The directory is created when the first
+// key is generated if it does not already exist.
+func NewKeyStore(dir string, size int) *KeyStore {
+	return &KeyStore{dir: dir, size: size}
+}
+
+// Load all of the keys in the directory. Files that are not named by a ulid with the
+// pem extension are ignored so that other files can be stored alongside the keys.
+func (ks *KeyStore) Load() (keys map[ulid.ULID]*rsa.PrivateKey, err error) {
+	keys = make(map[ulid.ULID]*rsa.PrivateKey)
+
+	var entries []os.DirEntry
+	if entries, err = os.ReadDir(ks.dir); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return keys, nil
+		}
+		return nil, fmt.Errorf("could not read key directory %s: %w", ks.dir, err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != keyExt {
+			continue
+		}
+
+		var keyID ulid.ULID
+		if keyID, err = ulid.Parse(strings.TrimSuffix(name, keyExt)); err != nil {
+			continue
+		}
+
+		path := filepath.Join(ks.dir, name)
+
+		var data []byte
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("could not read kid %s from %s: %s", keyID, path, err)
+		}
+
+		var key *rsa.PrivateKey
+		if key, err = jwt.ParseRSAPrivateKeyFromPEM(data); err != nil {
+			return nil, fmt.Errorf("could not parse RSA private key kid %s from %s: %s", keyID, path, err)
+		}
+		keys[keyID] = key
+	}
+
+	return keys, nil
+}
+
+// Generate a new RSA key whose ulid has the timestamp of the specified time and save it
+// in the directory. The key is written to a temporary file that is renamed once it is
+// complete so that a partially written key is never loaded.
+func (ks *KeyStore) Generate(now time.Time) (keyID ulid.ULID, key *rsa.PrivateKey, err error) {
+	if keyID, err = ulid.New(ulid.Timestamp(now), rand.Reader); err != nil {
+		return keyID, nil, err
+	}
+
+	if key, err = rsa.GenerateKey(rand.Reader, ks.size); err != nil {
+		return keyID, nil, err
+	}
+
+	if err = os.MkdirAll(ks.dir, 0700); err != nil {
+		return keyID, nil, fmt.Errorf("could not create key directory %s: %w", ks.dir, err)
+	}
+
+	var f *os.File
+	if f, err = os.CreateTemp(ks.dir, ".*.tmp"); err != nil {
+		return keyID, nil, err
+	}
+	defer os.Remove(f.Name())
+
+	if err = pem.Encode(f, &pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}); err != nil {
+		f.Close()
+		return keyID, nil, err
+	}
+
+	if err = f.Close(); err != nil {
+		return keyID, nil, err
+	}
+
+	if err = os.Rename(f.Name(), ks.path(keyID)); err != nil {
+		return keyID, nil, err
+	}
+	return keyID, key, nil
+}
+
+// Delete the key from the directory once no unexpired token can have been signed by it.
+func (ks *KeyStore) Delete(keyID ulid.ULID) (err error) {
+	if err = os.Remove(ks.path(keyID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (ks *KeyStore) path(keyID ulid.ULID) string {
+	return filepath.Join(ks.dir, keyID.String()+keyExt)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bbengfort/epistolary/pkg/server/config"
@@ -19,8 +20,13 @@ const (
 	accessTokenDuration  = 1 * time.Hour
 	refreshTokenDuration = 2 * time.Hour
 	accessRefreshOverlap = -15 * time.Minute
+	mfaTokenDuration     = 5 * time.Minute
 )
 
+// MFAAudience is the audience of MFA challenge tokens, which ensures that they cannot
+// be used as access or refresh tokens.
+const MFAAudience = "urn:epistolary:mfa"
+
 // Global variables that should really not be changed except between major versions.
 // NOTE: the signing method should match the value returned by the JWKS
 var (
@@ -32,30 +38,45 @@ var (
 // facilitate signing key rollover, TokenManager can accept multiple keys identified by
 // a ulid. JWT tokens generated by token managers include a kid in the header that
 // allows the token manager to verify the key with the specified signature. To sign keys
-// the token manager will always use the latest private key by ulid.
+// the token manager will always use the latest published private key by ulid.
+//
+// If the token manager has a key store, Rotate periodically generates a new key in the
+// store. New keys are published for verification immediately but are only used to sign
+// tokens after the publish delay so that clients that cache the JWKS can fetch the key
+// before they receive a token signed by it. Old keys are deleted from the store once
+// every token that they signed has expired.
 //
 // When the TokenManager creates tokens it will use JWT standard claims as well as
 // extended claims based on Oauth credentials. The standard claims included are exp, nbf
 // aud, and sub. The iss claim is optional and would duplicate aud, so it is omitted.
 // On token verification, the exp, nbf, and aud claims are validated.
-// TODO: Create automatic key rotation mechanism rather than loading keys.
 type TokenManager struct {
-	audience     string
-	issuer       string
-	currentKeyID ulid.ULID
-	currentKey   *rsa.PrivateKey
-	keys         map[ulid.ULID]*rsa.PublicKey
+	sync.RWMutex
+	audience         string
+	issuer           string
+	store            *KeyStore
+	rotationInterval time.Duration
+	publishDelay     time.Duration
+	static           map[ulid.ULID]*rsa.PrivateKey
+	currentKeyID     ulid.ULID
+	currentKey       *rsa.PrivateKey
+	keys             map[ulid.ULID]*rsa.PublicKey
 }
 
 // New creates a TokenManager with the specified keys which should be a mapping of ULID
 // strings to paths to files that contain PEM encoded RSA private keys. This input is
 // specifically designed for the config environment variable so that keys can be loaded
-// from k8s or vault secrets that are mounted as files on disk.
+// from k8s or vault secrets that are mounted as files on disk. If a key directory is
+// configured, the keys in the directory are loaded and a key is generated if the
+// directory does not have a current key.
 func New(conf config.TokenConfig) (tm *TokenManager, err error) {
 	tm = &TokenManager{
-		keys:     make(map[ulid.ULID]*rsa.PublicKey, len(conf.Keys)),
-		audience: conf.Audience,
-		issuer:   conf.Issuer,
+		static:           make(map[ulid.ULID]*rsa.PrivateKey, len(conf.Keys)),
+		keys:             make(map[ulid.ULID]*rsa.PublicKey, len(conf.Keys)),
+		audience:         conf.Audience,
+		issuer:           conf.Issuer,
+		rotationInterval: conf.RotationInterval,
+		publishDelay:     conf.PublishDelay,
 	}
 
 	for kid, path := range conf.Keys {
@@ -77,6 +98,7 @@ func New(conf config.TokenConfig) (tm *TokenManager, err error) {
 		}
 
 		// Add the key to the key map
+		tm.static[keyID] = key
 		tm.keys[keyID] = &key.PublicKey
 
 		// Set the current key if it is the latest key
@@ -86,9 +108,101 @@ func New(conf config.TokenConfig) (tm *TokenManager, err error) {
 		}
 	}
 
+	if conf.KeyDir != "" {
+		tm.store = NewKeyStore(conf.KeyDir, conf.KeySize)
+		if err = tm.Rotate(time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
 	return tm, nil
 }
 
+// Rotate reloads the keys in the key store, generating a new key if the latest key in
+// the store is older than the rotation interval, then signs tokens with the latest key
+// that has been published for the publish delay. Keys in the store that are older than
+// the current key are deleted once the tokens they signed have expired; keys that were
+// loaded from the configuration are never deleted. Rotate does nothing if the token
+// manager does not have a key store.
+func (tm *TokenManager) Rotate(now time.Time) (err error) {
+	if tm.store == nil {
+		return nil
+	}
+
+	var stored map[ulid.ULID]*rsa.PrivateKey
+	if stored, err = tm.store.Load(); err != nil {
+		return err
+	}
+
+	// Generate a new key if the store is empty or its latest key has expired
+	if latest := latestKey(stored, now, 0); latest.Compare(nilID) == 0 || now.Sub(ulid.Time(latest.Time())) >= tm.rotationInterval {
+		var (
+			keyID ulid.ULID
+			key   *rsa.PrivateKey
+		)
+
+		if keyID, key, err = tm.store.Generate(now); err != nil {
+			return fmt.Errorf("could not generate signing key: %w", err)
+		}
+		stored[keyID] = key
+	}
+
+	// Merge the static and stored keys to select the current signing key
+	keys := make(map[ulid.ULID]*rsa.PrivateKey, len(tm.static)+len(stored))
+	for keyID, key := range tm.static {
+		keys[keyID] = key
+	}
+	for keyID, key := range stored {
+		keys[keyID] = key
+	}
+
+	// If no key has been published for long enough then there is no previous key that
+	// clients could be using, so the latest key is used immediately.
+	currentKeyID := latestKey(keys, now, tm.publishDelay)
+	if currentKeyID.Compare(nilID) == 0 {
+		currentKeyID = latestKey(keys, now, 0)
+	}
+
+	// Delete stored keys that stopped signing before the longest lived token expired
+	if activated := ulid.Time(currentKeyID.Time()).Add(tm.publishDelay); now.Sub(activated) > refreshTokenDuration {
+		for keyID := range stored {
+			if keyID.Compare(currentKeyID) < 0 {
+				if err = tm.store.Delete(keyID); err != nil {
+					return fmt.Errorf("could not delete expired signing key: %w", err)
+				}
+				delete(keys, keyID)
+			}
+		}
+	}
+
+	public := make(map[ulid.ULID]*rsa.PublicKey, len(keys))
+	for keyID, key := range keys {
+		public[keyID] = &key.PublicKey
+	}
+
+	tm.Lock()
+	tm.keys = public
+	tm.currentKeyID = currentKeyID
+	tm.currentKey = keys[currentKeyID]
+	tm.Unlock()
+	return nil
+}
+
+// Returns the latest key that was created at least delay before now, or the nil ID if
+// there is no such key.
+func latestKey(keys map[ulid.ULID]*rsa.PrivateKey, now time.Time, delay time.Duration) (latest ulid.ULID) {
+	for keyID := range keys {
+		if ulid.Time(keyID.Time()).Add(delay).After(now) {
+			continue
+		}
+
+		if keyID.Compare(latest) > 0 {
+			latest = keyID
+		}
+	}
+	return latest
+}
+
 // Verify an access or a refresh token after parsing and return its claims.
 func (tm *TokenManager) Verify(tks string) (claims *Claims, err error) {
 	var token *jwt.Token
@@ -127,6 +241,9 @@ func (tm *TokenManager) Parse(tks string) (claims *Claims, err error) {
 
 // Sign an access or refresh token and return the token string.
 func (tm *TokenManager) Sign(token *jwt.Token) (tks string, err error) {
+	tm.RLock()
+	defer tm.RUnlock()
+
 	// Sanity check to prevent nil panics.
 	if tm.currentKey == nil || tm.currentKeyID.Compare(nilID) == 0 {
 		return "", errors.New("token manager not initialized with signing keys")
@@ -140,15 +257,23 @@ func (tm *TokenManager) Sign(token *jwt.Token) (tks string, err error) {
 }
 
 // CreateAccessToken from the credential payload or from an previous token if the
-// access token is being reauthorized from previous credentials. Note that the returned
-// token only contains the claims and is unsigned.
+// access token is being reauthorized from previous credentials. If the claims already
+// have an ID then it is kept so that the session identified by the ID is preserved when
+// reauthorizing, otherwise a new ID is generated. Note that the returned token only
+// contains the claims and is unsigned.
 func (tm *TokenManager) CreateAccessToken(claims *Claims) (_ *jwt.Token, err error) {
 	// Create the claims for the access token, using access token defaults.
 	now := time.Now()
 	sub := claims.RegisteredClaims.Subject
 
+	// ID is randomly generated and shared between access and refresh tokens.
+	id := claims.RegisteredClaims.ID
+	if id == "" {
+		id = strings.ToLower(ulid.Make().String())
+	}
+
 	claims.RegisteredClaims = jwt.RegisteredClaims{
-		ID:        strings.ToLower(ulid.Make().String()), // ID is randomly generated and shared between access and refresh tokens.
+		ID:        id,
 		Subject:   sub,
 		Audience:  jwt.ClaimStrings{tm.audience},
 		Issuer:    tm.issuer,
@@ -181,11 +306,63 @@ func (tm *TokenManager) CreateRefreshToken(accessToken *jwt.Token) (refreshToken
 			NotBefore: jwt.NewNumericDate(accessClaims.ExpiresAt.Add(accessRefreshOverlap)),
 			ExpiresAt: jwt.NewNumericDate(accessClaims.IssuedAt.Add(refreshTokenDuration)),
 		},
+		// Tokens issued to OAuth clients are refreshed with the same client and scope.
+		ClientID: accessClaims.ClientID,
+		Scope:    accessClaims.Scope,
 	}
 
 	return jwt.NewWithClaims(signingMethod, claims), nil
 }
 
+// CreateIDToken creates and signs an OpenID Connect ID token for the client. The issuer
+// of ID tokens must exactly match the issuer of the OpenID configuration, which may
+// differ from the issuer of access tokens, and the audience is the client ID. The ID
+// token expires with the access token that it is issued with.
+func (tm *TokenManager) CreateIDToken(claims *IDClaims, issuer, clientID string) (_ string, err error) {
+	now := time.Now()
+	claims.Issuer = issuer
+	claims.Audience = jwt.ClaimStrings{clientID}
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(accessTokenDuration))
+	return tm.Sign(jwt.NewWithClaims(signingMethod, claims))
+}
+
+// CreateMFAToken creates and signs a short-lived challenge token for a user that has
+// verified their password but must still verify a second factor to login. The token
+// only identifies the user and has the MFA audience, so it does not grant access.
+func (tm *TokenManager) CreateMFAToken(userID int64) (_ string, err error) {
+	now := time.Now()
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        strings.ToLower(ulid.Make().String()),
+			Audience:  jwt.ClaimStrings{MFAAudience},
+			Issuer:    tm.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(mfaTokenDuration)),
+		},
+	}
+	claims.SetSubjectID(userID)
+	return tm.Sign(jwt.NewWithClaims(signingMethod, claims))
+}
+
+// VerifyMFAToken verifies an MFA challenge token and returns the ID of its user.
+func (tm *TokenManager) VerifyMFAToken(tks string) (userID int64, err error) {
+	claims := &Claims{}
+	if _, err = jwt.ParseWithClaims(tks, claims, tm.keyFunc); err != nil {
+		return 0, err
+	}
+
+	if !claims.VerifyAudience(MFAAudience, true) {
+		return 0, fmt.Errorf("invalid audience %q", claims.Audience)
+	}
+
+	if !claims.VerifyIssuer(tm.issuer, true) {
+		return 0, fmt.Errorf("invalid issuer %q", claims.Issuer)
+	}
+	return claims.SubjectID()
+}
+
 // CreateTokens creates and signs an access and refresh token in one step.
 func (tm *TokenManager) CreateTokens(claims *Claims) (signedAccessToken, signedRefreshToken string, err error) {
 	var accessToken, refreshToken *jwt.Token
@@ -209,13 +386,22 @@ func (tm *TokenManager) CreateTokens(claims *Claims) (signedAccessToken, signedR
 	return signedAccessToken, signedRefreshToken, nil
 }
 
-// Keys returns the map of ulid to public key for use externally.
+// Keys returns a copy of the map of ulid to public key for use externally.
 func (tm *TokenManager) Keys() map[ulid.ULID]*rsa.PublicKey {
-	return tm.keys
+	tm.RLock()
+	defer tm.RUnlock()
+
+	keys := make(map[ulid.ULID]*rsa.PublicKey, len(tm.keys))
+	for keyID, key := range tm.keys {
+		keys[keyID] = key
+	}
+	return keys
 }
 
 // CurrentKey returns the ulid of the current key being used to sign tokens.
 func (tm *TokenManager) CurrentKey() ulid.ULID {
+	tm.RLock()
+	defer tm.RUnlock()
 	return tm.currentKeyID
 }
 
@@ -241,6 +427,8 @@ func (tm *TokenManager) keyFunc(token *jwt.Token) (key interface{}, err error) {
 	}
 
 	// Fetch the key from the list of managed keys
+	tm.RLock()
+	defer tm.RUnlock()
 	if key, ok = tm.keys[keyID]; !ok {
 		return nil, errors.New("unknown signing key")
 	}
@@ -3,6 +3,8 @@ package tokens_test
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -11,6 +13,7 @@ import (
 	"github.com/bbengfort/epistolary/pkg/server/tokens"
 	jwt "github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -89,6 +92,11 @@ func (s *TokenTestSuite) TestTokenManager() {
 	require.Equal(15*time.Minute, ac.ExpiresAt.Sub(rc.NotBefore.Time), "refresh token active does not overlap active token active by 15 minutes")
 	require.Equal(60*time.Minute, rc.ExpiresAt.Sub(ac.ExpiresAt.Time), "refresh token does not expire 1 hour after access token")
 
+	// Reauthorizing with the ID of a previous token should keep the ID of the session
+	reauth, err := tm.CreateAccessToken(&tokens.Claims{RegisteredClaims: jwt.RegisteredClaims{ID: ac.ID}, Email: creds.Email})
+	require.NoError(err, "could not create access token from previous claims")
+	require.Equal(ac.ID, reauth.Claims.(*tokens.Claims).ID, "reauthorized token must keep the jid")
+
 	// Sign the access token
 	atks, err := tm.Sign(accessToken)
 	require.NoError(err, "could not sign access token")
@@ -316,7 +324,191 @@ func (s *TokenTestSuite) TestParseExpiredToken() {
 	require.Empty(claims, "bad signature token returned non-empty claims")
 }
 
+func (s *TokenTestSuite) TestIDToken() {
+	require := s.Require()
+	conf := config.TokenConfig{
+		Keys:     s.testdata,
+		Audience: "http://localhost:3000",
+		Issuer:   "http://localhost:3001",
+	}
+
+	tm, err := tokens.New(conf)
+	require.NoError(err, "could not initialize token manager")
+
+	claims := &tokens.IDClaims{Nonce: "abc123", Email: "kate@rotational.io"}
+	claims.Subject = "2a"
+
+	tks, err := tm.CreateIDToken(claims, "http://localhost:3001/", "01hxyzclient")
+	require.NoError(err, "could not create id token")
+
+	// The ID token must be verifiable with the public keys from the JWKS
+	parsed := &tokens.IDClaims{}
+	token, err := jwt.ParseWithClaims(tks, parsed, func(token *jwt.Token) (interface{}, error) {
+		return tm.Keys()[tm.CurrentKey()], nil
+	})
+	require.NoError(err, "could not verify id token")
+	require.Equal(tm.CurrentKey().String(), token.Header["kid"])
+
+	require.Equal("2a", parsed.Subject)
+	require.Equal("http://localhost:3001/", parsed.Issuer)
+	require.Equal(jwt.ClaimStrings{"01hxyzclient"}, parsed.Audience)
+	require.Equal("abc123", parsed.Nonce)
+	require.Equal("kate@rotational.io", parsed.Email)
+	require.True(parsed.ExpiresAt.After(time.Now()))
+
+	// ID tokens cannot be used as access tokens
+	_, err = tm.Verify(tks)
+	require.Error(err, "id token was verified as an access token")
+}
+
+func (s *TokenTestSuite) TestClientRefreshToken() {
+	require := s.Require()
+	conf := config.TokenConfig{
+		Keys:     s.testdata,
+		Audience: "http://localhost:3000",
+		Issuer:   "http://localhost:3001",
+	}
+
+	tm, err := tokens.New(conf)
+	require.NoError(err, "could not initialize token manager")
+
+	accessToken, err := tm.CreateAccessToken(&tokens.Claims{Email: "kate@rotational.io", ClientID: "01hxyzclient", Scope: "openid email"})
+	require.NoError(err, "could not create access token")
+
+	// Refresh tokens of OAuth clients keep the client and scope but not the profile
+	refreshToken, err := tm.CreateRefreshToken(accessToken)
+	require.NoError(err, "could not create refresh token")
+
+	rc := refreshToken.Claims.(*tokens.Claims)
+	require.Equal("01hxyzclient", rc.ClientID)
+	require.Equal("openid email", rc.Scope)
+	require.Empty(rc.Email)
+}
+
+func (s *TokenTestSuite) TestMFAToken() {
+	require := s.Require()
+	conf := config.TokenConfig{
+		Keys:     s.testdata,
+		Audience: "http://localhost:3000",
+		Issuer:   "http://localhost:3001",
+	}
+
+	tm, err := tokens.New(conf)
+	require.NoError(err, "could not initialize token manager")
+
+	tks, err := tm.CreateMFAToken(42)
+	require.NoError(err, "could not create mfa token")
+
+	userID, err := tm.VerifyMFAToken(tks)
+	require.NoError(err, "could not verify mfa token")
+	require.Equal(int64(42), userID)
+
+	// MFA tokens cannot be used as access tokens and access tokens cannot be used as
+	// MFA tokens.
+	_, err = tm.Verify(tks)
+	require.Error(err, "mfa token was verified as an access token")
+
+	claims := &tokens.Claims{}
+	claims.SetSubjectID(42)
+	atks, _, err := tm.CreateTokens(claims)
+	require.NoError(err, "could not create tokens")
+
+	_, err = tm.VerifyMFAToken(atks)
+	require.Error(err, "access token was verified as an mfa token")
+}
+
 // Execute suite as a go test.
+// Test that keys in the key directory are generated, published, used, and deleted as
+// the key manager is rotated over the lifetime of the keys.
+func (s *TokenTestSuite) TestAutomaticKeyRotation() {
+	require := s.Require()
+	conf := config.TokenConfig{
+		KeyDir:           s.T().TempDir(),
+		KeySize:          2048,
+		RotationInterval: 24 * time.Hour,
+		PublishDelay:     1 * time.Hour,
+		Audience:         "http://localhost:3000",
+		Issuer:           "http://localhost:3001",
+	}
+
+	// Without any published keys a new key is used immediately
+	tm, err := tokens.New(conf)
+	require.NoError(err, "could not initialize token manager from key directory")
+	require.Len(tm.Keys(), 1)
+	require.Len(keyFiles(s.T(), conf.KeyDir), 1)
+	require.Contains(tm.Keys(), tm.CurrentKey())
+
+	// With static keys a new key is published but not used until the publish delay
+	conf.Keys = s.testdata
+	conf.KeyDir = s.T().TempDir()
+
+	start := time.Now()
+	tm, err = tokens.New(conf)
+	require.NoError(err, "could not initialize token manager from key directory")
+	require.Len(tm.Keys(), 3)
+	require.Equal("01GE62EXXR0X0561XD53RDFBQJ", tm.CurrentKey().String())
+
+	files := keyFiles(s.T(), conf.KeyDir)
+	require.Len(files, 1)
+	first := files[0]
+
+	require.NoError(tm.Rotate(start.Add(30 * time.Minute)))
+	require.Equal("01GE62EXXR0X0561XD53RDFBQJ", tm.CurrentKey().String())
+
+	// After the publish delay the new key is used to sign tokens
+	require.NoError(tm.Rotate(start.Add(1*time.Hour + time.Second)))
+	require.Equal(first, tm.CurrentKey().String())
+
+	claims := &tokens.Claims{Email: "kate@rotational.io", Name: "Kate Holland"}
+	atks, _, err := tm.CreateTokens(claims)
+	require.NoError(err, "could not create tokens")
+
+	// After the rotation interval a new key is generated and published
+	require.NoError(tm.Rotate(start.Add(24*time.Hour + time.Second)))
+	require.Equal(first, tm.CurrentKey().String())
+	require.Len(tm.Keys(), 4)
+
+	files = keyFiles(s.T(), conf.KeyDir)
+	require.Len(files, 2)
+	second := files[1]
+
+	require.NoError(tm.Rotate(start.Add(25*time.Hour + time.Minute)))
+	require.Equal(second, tm.CurrentKey().String())
+
+	_, err = tm.Verify(atks)
+	require.NoError(err, "tokens signed by the previous key should still be verified")
+
+	// Once every token signed by the previous key has expired it is deleted
+	require.NoError(tm.Rotate(start.Add(27*time.Hour + time.Minute)))
+	require.Equal(second, tm.CurrentKey().String())
+	require.Len(tm.Keys(), 3)
+	require.Equal([]string{second}, keyFiles(s.T(), conf.KeyDir))
+
+	_, err = tm.Verify(atks)
+	require.Error(err, "the previous key should no longer be published")
+
+	// Forcing a rotation by generating a key in the directory publishes the key
+	keyID, _, err := tokens.NewKeyStore(conf.KeyDir, conf.KeySize).Generate(start.Add(27 * time.Hour))
+	require.NoError(err, "could not generate key")
+
+	require.NoError(tm.Rotate(start.Add(27*time.Hour + time.Minute)))
+	require.Contains(tm.Keys(), keyID)
+	require.Equal(second, tm.CurrentKey().String())
+}
+
+// Returns the sorted key ids of the keys in the directory.
+func keyFiles(t *testing.T, dir string) []string {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	require.NoError(t, err)
+
+	keys := make([]string, 0, len(paths))
+	for _, path := range paths {
+		keys = append(keys, strings.TrimSuffix(filepath.Base(path), ".pem"))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func TestTokenTestSuite(t *testing.T) {
 	suite.Run(t, new(TokenTestSuite))
 }
@@ -0,0 +1,292 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/passwd"
+	"github.com/bbengfort/epistolary/pkg/server/users"
+	"github.com/bbengfort/epistolary/pkg/utils/sentry"
+	"github.com/gin-gonic/gin"
+)
+
+// Profile returns the account of the authenticated user.
+func (s *Server) Profile(c *gin.Context) {
+	var (
+		err  error
+		user *users.User
+	)
+
+	if user, err = s.currentUser(c); err != nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, apiUser(user, roleTitle(c, user)))
+}
+
+// UpdateProfile changes the full name, email, and username of the authenticated user.
+// If the email address is changed then the user must confirm their current password and
+// the new address must be verified again before the user can login, so a verification
+// email is sent to the new address.
+func (s *Server) UpdateProfile(c *gin.Context) {
+	var (
+		err  error
+		in   *api.User
+		user *users.User
+	)
+
+	if err = c.BindJSON(&in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse user input"))
+		return
+	}
+
+	if user, err = s.currentUser(c); err != nil {
+		return
+	}
+
+	if in.ID != 0 && in.ID != user.ID {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("id must match the authenticated user"))
+		return
+	}
+
+	// Changing the email address allows the password to be reset from the new address,
+	// so the current password is required to prevent takeover with a stolen token.
+	if in.Email != user.Email {
+		if in.Password == "" {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse("current password is required to change the email address"))
+			return
+		}
+
+		if err = verifyPassword(c, user, in.Password); err != nil {
+			return
+		}
+	}
+
+	// Only validate the fields that have changed so that users whose usernames predate
+	// the validation rules can still update their full name.
+	var verrs users.ValidationErrors
+	if in.Email != user.Email {
+		if err = users.ValidateEmail(in.Email); err != nil {
+			verrs = append(verrs, &users.FieldError{Field: "email", Err: err})
+		}
+	}
+
+	if in.Username != user.Username {
+		if err = users.ValidateUsername(in.Username); err != nil {
+			verrs = append(verrs, &users.FieldError{Field: "username", Err: err})
+		}
+	}
+
+	if len(verrs) > 0 {
+		c.JSON(http.StatusBadRequest, validationErrorResponse(verrs))
+		return
+	}
+
+	emailChanged := in.Email != user.Email
+	user.FullName = sql.NullString{Valid: in.FullName != "", String: in.FullName}
+	user.Email = in.Email
+	user.Username = in.Username
+
+	if err = user.Update(c.Request.Context()); err != nil {
+		if errors.Is(err, users.ErrUsernameTaken) || errors.Is(err, users.ErrEmailTaken) {
+			c.JSON(http.StatusConflict, validationErrorResponse(err))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not update user in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not update user"))
+		return
+	}
+
+	if emailChanged {
+		s.sendVerification(c, user)
+	}
+
+	c.JSON(http.StatusOK, apiUser(user, roleTitle(c, user)))
+}
+
+// ChangePassword sets a new password for the authenticated user after verifying their
+// current password. Changing the password revokes all sessions of the user, so new
+// tokens are issued for a new session on the current device.
+func (s *Server) ChangePassword(c *gin.Context) {
+	var (
+		err  error
+		in   *api.ChangePasswordRequest
+		out  *api.LoginReply
+		user *users.User
+	)
+
+	if err = c.BindJSON(&in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse change password request"))
+		return
+	}
+
+	if in.Password == "" || in.NewPassword == "" {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("password and new password are required"))
+		return
+	}
+
+	if user, err = s.currentUser(c); err != nil {
+		return
+	}
+
+	if err = verifyPassword(c, user, in.Password); err != nil {
+		return
+	}
+
+	if err = users.ValidateNewPassword(in.NewPassword, user.Username); err != nil {
+		c.JSON(http.StatusBadRequest, validationErrorResponse(users.ValidationErrors{{Field: "password", Err: err}}))
+		return
+	}
+
+	if user.Password, err = passwd.CreateDerivedKey(in.NewPassword); err != nil {
+		sentry.Error(c).Err(err).Msg("could not create derived key")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not change password"))
+		return
+	}
+
+	if err = user.UpdatePassword(c.Request.Context()); err != nil {
+		sentry.Error(c).Err(err).Msg("could not update password in database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not change password"))
+		return
+	}
+
+	// Revoke all sessions of the user (including the current session) so that other
+	// devices have to login with the new password.
+	if _, err = users.RevokeSessions(c.Request.Context(), user.ID, ""); err != nil {
+		sentry.Error(c).Err(err).Msg("could not revoke sessions of user")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("password changed, please login again"))
+		return
+	}
+
+	out = &api.LoginReply{}
+	claims := userClaims(c.Request.Context(), user)
+	if out.AccessToken, out.RefreshToken, err = s.issueTokens(c, user.ID, claims); err != nil {
+		sentry.Error(c).Err(err).Msg("could not create access and refresh tokens")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("password changed, please login again"))
+		return
+	}
+
+	if err = SetAuthCookies(c, out.AccessToken, out.RefreshToken, s.conf.Token.CookieDomain); err != nil {
+		sentry.Error(c).Err(err).Msg("could not set access and refresh token cookies")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("password changed, please login again"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteAccount deletes the authenticated user and all of their readings, notes, tags,
+// subscriptions, and tokens, then clears the authentication cookies. The user must
+// confirm their current password so that a stolen token cannot delete the account.
+func (s *Server) DeleteAccount(c *gin.Context) {
+	var (
+		err  error
+		in   *api.DeleteAccountRequest
+		user *users.User
+	)
+
+	if err = c.BindJSON(&in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("could not parse delete account request"))
+		return
+	}
+
+	if in.Password == "" {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("password is required"))
+		return
+	}
+
+	if user, err = s.currentUser(c); err != nil {
+		return
+	}
+
+	if err = verifyPassword(c, user, in.Password); err != nil {
+		return
+	}
+
+	if err = user.Delete(c.Request.Context()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("user not found"))
+			return
+		}
+
+		sentry.Error(c).Err(err).Msg("could not delete user from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not delete account"))
+		return
+	}
+
+	c.SetCookie(AccessTokenCookie, "", -1, "/", s.conf.Token.CookieDomain, true, true)
+	c.SetCookie(RefreshTokenCookie, "", -1, "/", s.conf.Token.CookieDomain, true, true)
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Fetches the authenticated user from the database, writing an error response to the
+// request and returning an error if the user could not be fetched.
+func (s *Server) currentUser(c *gin.Context) (user *users.User, err error) {
+	var userID int64
+	if userID, err = GetUserID(c); err != nil {
+		sentry.Error(c).Err(err).Msg("could not parse user id")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return nil, err
+	}
+
+	if user, err = users.UserFromID(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse("user not found"))
+			return nil, err
+		}
+
+		sentry.Error(c).Err(err).Msg("could not fetch user from database")
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("could not process request"))
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Verifies the current password of the user before a sensitive change to their account,
+// writing an error response to the request and returning an error if it is incorrect.
+func verifyPassword(c *gin.Context, user *users.User, password string) (err error) {
+	var verified bool
+	if verified, err = user.VerifyPassword(c.Request.Context(), password); err != nil || !verified {
+		if err != nil {
+			c.Error(err)
+		} else {
+			err = errors.New("incorrect password")
+		}
+		c.JSON(http.StatusForbidden, api.ErrorResponse("current password is incorrect"))
+		return err
+	}
+	return nil
+}
+
+func apiUser(model *users.User, role string) *api.User {
+	return &api.User{
+		ID:              model.ID,
+		FullName:        model.FullName.String,
+		Email:           model.Email,
+		EmailVerified:   api.Timestamp{Time: model.EmailVerified.Time},
+		Disabled:        api.Timestamp{Time: model.Disabled.Time},
+		Username:        model.Username,
+		Role:            role,
+		LastSeen:        api.Timestamp{Time: model.LastSeen.Time},
+		PasswordChanged: api.Timestamp{Time: model.PasswordChanged.Time},
+		Created:         api.Timestamp{Time: model.Created},
+		Modified:        api.Timestamp{Time: model.Modified},
+	}
+}
+
+// Returns the title of the role of the user or an empty string if it cannot be fetched.
+func roleTitle(c *gin.Context, user *users.User) string {
+	role, err := user.Role(c.Request.Context(), false)
+	if err != nil {
+		sentry.Warn(c).Err(err).Msg("could not fetch role of user")
+		return ""
+	}
+	return role.Title
+}
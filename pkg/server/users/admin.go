@@ -0,0 +1,211 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/utils/pagination"
+)
+
+const (
+	listUsersSQL = "SELECT id, full_name, email, email_verified, disabled, username, role_id, last_seen, pwchanged, created, modified FROM users"
+)
+
+// List all users ordered by when they registered, optionally filtering the users whose
+// username, email, or full name contains the query. Users are paginated by offset in
+// the same manner as readings; if a previous page cursor is specified it must have been
+// created with the same query, otherwise pagination.ErrTokenQueryMismatch is returned.
+func List(ctx context.Context, query string, prevPage *pagination.Cursor) (users []*User, cursor *pagination.Cursor, err error) {
+	query = strings.TrimSpace(query)
+	fingerprint := pagination.Fingerprint("users", query)
+	if prevPage, err = pagination.Bind(prevPage, fingerprint); err != nil {
+		return nil, nil, err
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var sqlq strings.Builder
+	sqlq.WriteString(listUsersSQL)
+
+	params := []any{
+		sql.Named("pageSize", prevPage.Size+1),
+		sql.Named("offset", prevPage.End),
+	}
+
+	if query != "" {
+		sqlq.WriteString(" WHERE username ILIKE :pattern OR email ILIKE :pattern OR full_name ILIKE :pattern")
+		params = append(params, sql.Named("pattern", "%"+escapeLike(query)+"%"))
+	}
+
+	// Add the limit as the page size + 1 to perform a has next page check
+	sqlq.WriteString(" ORDER BY created, id LIMIT :pageSize OFFSET :offset")
+
+	// Prep the query to convert named arguments into positional arguments
+	qs, args := db.Prep(sqlq.String(), params...)
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(qs, args...); err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	nRows := uint32(0)
+	users = make([]*User, 0, prevPage.Size)
+	for rows.Next() {
+		// The query will request one additional row past the page size to check if
+		// there is a next page. No rows should be processed after the page size.
+		nRows++
+		if nRows > prevPage.Size {
+			continue
+		}
+
+		user := &User{}
+		if err = rows.Scan(&user.ID, &user.FullName, &user.Email, &user.EmailVerified, &user.Disabled, &user.Username, &user.RoleID, &user.LastSeen, &user.PasswordChanged, &user.Created, &user.Modified); err != nil {
+			return nil, nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	tx.Commit()
+
+	if len(users) > 0 && nRows > prevPage.Size {
+		cursor = pagination.New(prevPage.End, prevPage.End+int64(len(users)), prevPage.Size)
+		cursor.Filter = fingerprint
+	}
+	return users, cursor, nil
+}
+
+// Escapes the wildcards in a LIKE pattern so that the query is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+const (
+	setRoleSQL = "UPDATE users SET role_id=$2 WHERE id=$1 RETURNING modified"
+)
+
+// SetRole assigns the role to the user; the permissions of the role are granted to the
+// user the next time their access token is refreshed. Returns ErrUnknownRole if the
+// role does not exist and sql.ErrNoRows if the user does not exist.
+func (u *User) SetRole(ctx context.Context, role *Role) (err error) {
+	if u.ID < 1 {
+		return ErrNoUserID
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow(setRoleSQL, u.ID, role.ID).Scan(&u.Modified); err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrUnknownRole
+		}
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	u.RoleID, u.role, u.permissions = role.ID, role, nil
+	return nil
+}
+
+const (
+	setDisabledSQL = "UPDATE users SET disabled=CASE WHEN $2 THEN COALESCE(disabled, NOW()) ELSE NULL END WHERE id=$1 RETURNING disabled, modified"
+)
+
+// SetDisabled disables or enables the account of the user. Disabled users cannot login
+// or refresh their access tokens but none of their data is deleted. Returns
+// sql.ErrNoRows if the user does not exist.
+func (u *User) SetDisabled(ctx context.Context, disabled bool) (err error) {
+	if u.ID < 1 {
+		return ErrNoUserID
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow(setDisabledSQL, u.ID, disabled).Scan(&u.Disabled, &u.Modified); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// IsDisabled returns true if the account of the user has been disabled.
+func (u *User) IsDisabled() bool {
+	return u.Disabled.Valid
+}
+
+const (
+	listRolesSQL     = "SELECT id, title, description, created, modified FROM roles ORDER BY id"
+	roleFromTitleSQL = "SELECT id, title, description, created, modified FROM roles WHERE lower(title)=lower($1)"
+)
+
+// ListRoles returns all of the roles that can be assigned to users.
+func ListRoles(ctx context.Context) (roles []*Role, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(listRolesSQL); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles = make([]*Role, 0, 3)
+	for rows.Next() {
+		role := &Role{}
+		if err = rows.Scan(&role.ID, &role.Title, &role.Description, &role.Created, &role.Modified); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return roles, nil
+}
+
+// RoleFromTitle gets the role with the title, ignoring case. Returns ErrUnknownRole if
+// there is no role with the title.
+func RoleFromTitle(ctx context.Context, title string) (role *Role, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	role = &Role{}
+	if err = tx.QueryRow(roleFromTitleSQL, strings.TrimSpace(title)).Scan(&role.ID, &role.Title, &role.Description, &role.Created, &role.Modified); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUnknownRole
+		}
+		return nil, err
+	}
+
+	tx.Commit()
+	return role, nil
+}
@@ -1,9 +1,18 @@
 package users
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/bbengfort/epistolary/pkg/utils/pagination"
+)
 
 // Standard errors for database operations and checking.
 var (
-	ErrNoUserID      = errors.New("this operation requires a user id")
-	ErrNotDerivedKey = errors.New("passwords must be stored as a derived key")
+	ErrNoUserID        = errors.New("this operation requires a user id")
+	ErrNotDerivedKey   = errors.New("passwords must be stored as a derived key")
+	ErrInvalidToken    = errors.New("token is invalid or has expired")
+	ErrMissingPageSize = pagination.ErrMissingPageSize
+	ErrUnknownRole     = errors.New("role does not exist")
+
+	ErrTokenRecentlySent = errors.New("a token was sent recently, please wait before requesting another")
 )
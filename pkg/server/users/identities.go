@@ -0,0 +1,89 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+)
+
+const (
+	getUserIdentitySQL = "SELECT u.id, u.full_name, u.email, u.email_verified, u.disabled, u.username, u.role_id, u.last_seen, u.pwchanged, u.failed_logins, u.locked_until, u.created, u.modified FROM user_identities i JOIN users u ON u.id=i.user_id WHERE i.provider=$1 AND i.subject=$2"
+)
+
+// UserFromIdentity gets the user that is linked to the subject of the identity provider
+// and populates the role and permissions of the user. Returns sql.ErrNoRows if the
+// identity has not been linked to a user.
+func UserFromIdentity(ctx context.Context, provider, subject string) (user *User, err error) {
+	user = &User{}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow(getUserIdentitySQL, provider, subject).Scan(&user.ID, &user.FullName, &user.Email, &user.EmailVerified, &user.Disabled, &user.Username, &user.RoleID, &user.LastSeen, &user.PasswordChanged, &user.FailedLogins, &user.LockedUntil, &user.Created, &user.Modified); err != nil {
+		return nil, err
+	}
+
+	if err = user.fetchRole(tx); err != nil {
+		return nil, err
+	}
+
+	if err = user.fetchPermissions(tx); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return user, nil
+}
+
+const (
+	linkIdentitySQL   = "INSERT INTO user_identities (provider, subject, user_id, email, last_login) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (provider, subject) DO UPDATE SET email=EXCLUDED.email, last_login=EXCLUDED.last_login WHERE user_identities.user_id=EXCLUDED.user_id"
+	verifyIdentitySQL = "UPDATE users SET email_verified=$3 WHERE id=$1 AND email=$2 AND email_verified IS NULL"
+)
+
+// LinkIdentity links the subject of the identity provider to the user or records the
+// login if it is already linked. An identity can only be linked to one user; sql.ErrNoRows
+// is returned if it is linked to another user. If the provider has verified the email
+// address and it is the email address of the user, the email of the user is verified.
+func (u *User) LinkIdentity(ctx context.Context, provider, subject, email string, verified bool) (err error) {
+	if u.ID < 1 {
+		return ErrNoUserID
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	var result sql.Result
+	if result, err = tx.Exec(linkIdentitySQL, provider, subject, u.ID, email, now); err != nil {
+		return err
+	}
+
+	if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return sql.ErrNoRows
+	}
+
+	verify := verified && email == u.Email && !u.EmailVerified.Valid
+	if verify {
+		if _, err = tx.Exec(verifyIdentitySQL, u.ID, email, now); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	if verify {
+		u.EmailVerified = sql.NullTime{Valid: true, Time: now}
+	}
+	return nil
+}
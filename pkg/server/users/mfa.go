@@ -0,0 +1,281 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/server/passwd"
+)
+
+var (
+	ErrMFAEnabled          = errors.New("multi-factor authentication is already enabled")
+	ErrMFANotEnrolled      = errors.New("multi-factor authentication enrollment has not been started")
+	ErrCodeReused          = errors.New("authentication code has already been used")
+	ErrInvalidRecoveryCode = errors.New("invalid or already used recovery code")
+)
+
+// MFA is the TOTP authenticator that the user has enrolled as a second factor. MFA is
+// only enabled once the user has confirmed that their authenticator generates valid
+// codes; until then the enrollment can be restarted with a new secret.
+type MFA struct {
+	UserID      int64
+	Secret      string
+	Enabled     sql.NullTime
+	LastCounter int64
+	Created     time.Time
+	Modified    time.Time
+}
+
+// IsEnabled returns true if the user must verify a code from the authenticator to login.
+func (m *MFA) IsEnabled() bool {
+	return m != nil && m.Enabled.Valid
+}
+
+const (
+	getMFASQL = "SELECT secret, enabled, last_counter, created, modified FROM user_mfa WHERE user_id=$1"
+)
+
+// GetMFA returns the authenticator of the user or sql.ErrNoRows if the user has not
+// enrolled an authenticator.
+func GetMFA(ctx context.Context, userID int64) (mfa *MFA, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	mfa = &MFA{UserID: userID}
+	if err = tx.QueryRow(getMFASQL, userID).Scan(&mfa.Secret, &mfa.Enabled, &mfa.LastCounter, &mfa.Created, &mfa.Modified); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return mfa, nil
+}
+
+const (
+	enrollMFASQL = "INSERT INTO user_mfa (user_id, secret) VALUES ($1, $2) ON CONFLICT (user_id) DO UPDATE SET secret=EXCLUDED.secret, last_counter=0 WHERE user_mfa.enabled IS NULL RETURNING created, modified"
+)
+
+// EnrollMFA starts the enrollment of an authenticator with the TOTP secret, replacing
+// the secret of a previous enrollment that was not confirmed. Returns ErrMFAEnabled if
+// the user has already enabled MFA.
+func (u *User) EnrollMFA(ctx context.Context, secret string) (mfa *MFA, err error) {
+	if u.ID < 1 {
+		return nil, ErrNoUserID
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	mfa = &MFA{UserID: u.ID, Secret: secret}
+	if err = tx.QueryRow(enrollMFASQL, u.ID, secret).Scan(&mfa.Created, &mfa.Modified); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMFAEnabled
+		}
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return mfa, nil
+}
+
+const (
+	enableMFASQL = "UPDATE user_mfa SET enabled=NOW(), last_counter=$2 WHERE user_id=$1 AND enabled IS NULL RETURNING enabled, modified"
+)
+
+// Enable MFA once the user has confirmed a code from the authenticator, storing the time
+// step of the code so that it cannot be used to login and replacing the recovery codes
+// of the user with the hashes of the new recovery codes.
+func (m *MFA) Enable(ctx context.Context, counter int64, recoveryCodes []string) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow(enableMFASQL, m.UserID, counter).Scan(&m.Enabled, &m.Modified); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMFAEnabled
+		}
+		return err
+	}
+
+	if err = replaceRecoveryCodes(tx, m.UserID, recoveryCodes); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	m.LastCounter = counter
+	return nil
+}
+
+const (
+	useCodeSQL = "UPDATE user_mfa SET last_counter=$2 WHERE user_id=$1 AND enabled IS NOT NULL AND last_counter < $2"
+)
+
+// UseCode records the time step of a valid code from the authenticator that was used to
+// login. Returns ErrCodeReused if the code, or a later code, has already been used so
+// that an intercepted code cannot be replayed.
+func (m *MFA) UseCode(ctx context.Context, counter int64) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(useCodeSQL, m.UserID, counter); err != nil {
+		return err
+	}
+
+	if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return ErrCodeReused
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	m.LastCounter = counter
+	return nil
+}
+
+const (
+	disableMFASQL = "DELETE FROM user_mfa WHERE user_id=$1"
+)
+
+// DisableMFA removes the authenticator and the recovery codes of the user. Returns
+// sql.ErrNoRows if the user has not enrolled an authenticator.
+func DisableMFA(ctx context.Context, userID int64) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(disableMFASQL, userID); err != nil {
+		return err
+	}
+
+	if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err = tx.Exec(clearRecoveryCodesSQL, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const (
+	recoveryCodesSQL      = "SELECT id, code FROM recovery_codes WHERE user_id=$1 FOR UPDATE"
+	useRecoveryCodeSQL    = "DELETE FROM recovery_codes WHERE id=$1"
+	countRecoveryCodesSQL = "SELECT count(*) FROM recovery_codes WHERE user_id=$1"
+	clearRecoveryCodesSQL = "DELETE FROM recovery_codes WHERE user_id=$1"
+	createRecoveryCodeSQL = "INSERT INTO recovery_codes (code, user_id) VALUES ($1, $2)"
+)
+
+// UseRecoveryCode verifies the normalized recovery code against the derived keys of the
+// recovery codes of the user and deletes the code that matches so that it can only be
+// used once. Returns ErrInvalidRecoveryCode if the user has no such code.
+func UseRecoveryCode(ctx context.Context, userID int64, code string) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(recoveryCodesSQL, userID); err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var codeID int64
+	for rows.Next() {
+		var (
+			id         int64
+			derivedKey string
+		)
+
+		if err = rows.Scan(&id, &derivedKey); err != nil {
+			return err
+		}
+
+		if verified, _ := passwd.VerifyDerivedKey(derivedKey, code); verified {
+			codeID = id
+			break
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	if codeID == 0 {
+		return ErrInvalidRecoveryCode
+	}
+
+	if _, err = tx.Exec(useRecoveryCodeSQL, codeID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CountRecoveryCodes returns the number of unused recovery codes of the user.
+func CountRecoveryCodes(ctx context.Context, userID int64) (count int64, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow(countRecoveryCodesSQL, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	tx.Commit()
+	return count, nil
+}
+
+// ReplaceRecoveryCodes replaces the recovery codes of the user with the hashes of the
+// new recovery codes so that previously issued codes can no longer be used.
+func ReplaceRecoveryCodes(ctx context.Context, userID int64, recoveryCodes []string) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = replaceRecoveryCodes(tx, userID, recoveryCodes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func replaceRecoveryCodes(tx *sql.Tx, userID int64, recoveryCodes []string) (err error) {
+	if _, err = tx.Exec(clearRecoveryCodesSQL, userID); err != nil {
+		return err
+	}
+
+	for _, code := range recoveryCodes {
+		if _, err = tx.Exec(createRecoveryCodeSQL, code, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
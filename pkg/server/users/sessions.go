@@ -0,0 +1,175 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+)
+
+// Session records the refresh token issued to a device the user has logged in from. The
+// ID of the session is the ID shared by the access and refresh tokens, which is kept
+// when the tokens are refreshed so that the session can be revoked at any time. If the
+// tokens were issued to an OAuth client, the session records the client ID so that only
+// that client can refresh or revoke the session.
+type Session struct {
+	ID        string
+	UserID    int64
+	ClientID  sql.NullString
+	UserAgent sql.NullString
+	ClientIP  sql.NullString
+	LastUsed  sql.NullTime
+	Expires   time.Time
+	Created   time.Time
+	Modified  time.Time
+}
+
+const (
+	createSessionSQL = "INSERT INTO sessions (id, user_id, client_id, user_agent, client_ip, last_used, expires, created, modified) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)"
+)
+
+// Create the session in the database when the user logs in.
+func (s *Session) Create(ctx context.Context) (err error) {
+	if s.UserID < 1 {
+		return ErrNoUserID
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	s.Created = time.Now()
+	s.Modified = s.Created
+	s.LastUsed = sql.NullTime{Valid: true, Time: s.Created}
+
+	if _, err = tx.Exec(createSessionSQL, s.ID, s.UserID, s.ClientID, s.UserAgent, s.ClientIP, s.LastUsed, s.Expires, s.Created); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const (
+	refreshSessionSQL = "UPDATE sessions SET user_agent=$4, client_ip=$5, last_used=NOW(), expires=$6 WHERE id=$1 AND user_id=$2 AND client_id IS NOT DISTINCT FROM $3 AND expires > NOW() RETURNING last_used, created, modified"
+)
+
+// Refresh the session when its refresh token is used to issue new tokens, extending
+// the session to the expiration of the new refresh token. Returns sql.ErrNoRows if the
+// session has been revoked, has expired, or was not created for the client.
+func (s *Session) Refresh(ctx context.Context) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow(refreshSessionSQL, s.ID, s.UserID, s.ClientID, s.UserAgent, s.ClientIP, s.Expires).Scan(&s.LastUsed, &s.Created, &s.Modified); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const (
+	listSessionsSQL = "SELECT id, user_id, user_agent, client_ip, last_used, expires, created, modified FROM sessions WHERE user_id=$1 AND expires > NOW() ORDER BY last_used DESC NULLS LAST, created DESC"
+)
+
+// ListSessions returns the active sessions of the user, most recently used first.
+func ListSessions(ctx context.Context, userID int64) (sessions []*Session, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.Query(listSessionsSQL, userID); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions = make([]*Session, 0)
+	for rows.Next() {
+		session := &Session{}
+		if err = rows.Scan(&session.ID, &session.UserID, &session.UserAgent, &session.ClientIP, &session.LastUsed, &session.Expires, &session.Created, &session.Modified); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tx.Commit()
+	return sessions, nil
+}
+
+const (
+	revokeSessionSQL       = "DELETE FROM sessions WHERE id=$1 AND user_id=$2"
+	revokeClientSessionSQL = "DELETE FROM sessions WHERE id=$1 AND user_id=$2 AND client_id=$3"
+	revokeSessionsSQL      = "DELETE FROM sessions WHERE user_id=$1 AND id<>$2"
+)
+
+// RevokeSession deletes the session of the user so that its refresh token can no longer
+// be used. Returns sql.ErrNoRows if the user does not have a session with the ID.
+func RevokeSession(ctx context.Context, id string, userID int64) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(revokeSessionSQL, id, userID); err != nil {
+		return err
+	}
+
+	if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+// RevokeClientSession deletes the session of the user only if it was created for the
+// OAuth client so that clients cannot revoke the sessions of other clients or devices.
+// Returns sql.ErrNoRows if the user does not have a session with the ID for the client.
+func RevokeClientSession(ctx context.Context, id string, userID int64, clientID string) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(revokeClientSessionSQL, id, userID, clientID); err != nil {
+		return err
+	}
+
+	if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+// RevokeSessions deletes all of the sessions of the user except for the session with
+// the keep ID, if specified, returning the number of sessions that were revoked.
+func RevokeSessions(ctx context.Context, userID int64, keep string) (revoked int64, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(revokeSessionsSQL, userID, keep); err != nil {
+		return 0, err
+	}
+
+	revoked, _ = result.RowsAffected()
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return revoked, nil
+}
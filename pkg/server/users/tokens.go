@@ -0,0 +1,265 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/server/passwd"
+	"github.com/bbengfort/epistolary/pkg/utils/secrets"
+)
+
+// Verification and reset tokens are emailed to the user and can only be used once.
+const (
+	VerificationTokenTTL = 72 * time.Hour
+	ResetTokenTTL        = 1 * time.Hour
+	TokenResendInterval  = 1 * time.Minute
+)
+
+// Creates a random token secret and the hash of the secret that is stored.
+func newTokenSecret() (secret string, hash []byte, err error) {
+	if secret, err = secrets.New(secrets.DefaultLength); err != nil {
+		return "", nil, err
+	}
+	return secret, secrets.Hash(secret), nil
+}
+
+const (
+	recentVerificationSQL = "SELECT EXISTS(SELECT 1 FROM verification_tokens WHERE user_id=$1 AND created > $2)"
+	clearVerificationSQL  = "DELETE FROM verification_tokens WHERE user_id=$1"
+	createVerificationSQL = "INSERT INTO verification_tokens (user_id, email, token, expires) VALUES ($1, $2, $3, $4)"
+)
+
+// CreateVerificationToken creates a token that verifies the current email address of
+// the user, replacing any previous verification tokens. The returned secret must be
+// emailed to the user since it cannot be recovered. Returns ErrTokenRecentlySent if a
+// token was created within the resend interval to prevent flooding the user's inbox.
+func (u *User) CreateVerificationToken(ctx context.Context) (secret string, err error) {
+	if u.ID < 1 {
+		return "", ErrNoUserID
+	}
+
+	var hash []byte
+	if secret, hash, err = newTokenSecret(); err != nil {
+		return "", err
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var recent bool
+	if err = tx.QueryRow(recentVerificationSQL, u.ID, time.Now().Add(-TokenResendInterval)).Scan(&recent); err != nil {
+		return "", err
+	}
+
+	if recent {
+		return "", ErrTokenRecentlySent
+	}
+
+	if _, err = tx.Exec(clearVerificationSQL, u.ID); err != nil {
+		return "", err
+	}
+
+	if _, err = tx.Exec(createVerificationSQL, u.ID, u.Email, hash, time.Now().Add(VerificationTokenTTL)); err != nil {
+		return "", err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+const (
+	useVerificationSQL = "DELETE FROM verification_tokens WHERE token=$1 RETURNING user_id, email, expires"
+	verifyEmailSQL     = "UPDATE users SET email_verified=NOW() WHERE id=$1 AND email=$2 RETURNING email_verified"
+)
+
+// VerifyEmail uses the verification token to mark the email address of its user as
+// verified. Returns ErrInvalidToken if the token does not exist, has expired, or was
+// created for an email address that the user has since changed.
+func VerifyEmail(ctx context.Context, secret string) (user *User, err error) {
+	if secret == "" {
+		return nil, ErrInvalidToken
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var (
+		userID  int64
+		email   string
+		expires time.Time
+	)
+
+	if err = tx.QueryRow(useVerificationSQL, secrets.Hash(secret)).Scan(&userID, &email, &expires); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	// Delete expired tokens even though they cannot be used
+	if time.Now().After(expires) {
+		if err = tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidToken
+	}
+
+	user = &User{ID: userID, Email: email}
+	if err = tx.QueryRow(verifyEmailSQL, userID, email).Scan(&user.EmailVerified); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+const (
+	recentResetSQL = "SELECT EXISTS(SELECT 1 FROM reset_tokens WHERE user_id=$1 AND created > $2)"
+	createResetSQL = "INSERT INTO reset_tokens (user_id, token, expires) VALUES ($1, $2, $3)"
+)
+
+// CreateResetToken creates a token that allows the user to reset their password. The
+// returned secret must be emailed to the user since it cannot be recovered. Returns
+// ErrTokenRecentlySent if a token was created within the resend interval.
+func (u *User) CreateResetToken(ctx context.Context) (secret string, err error) {
+	if u.ID < 1 {
+		return "", ErrNoUserID
+	}
+
+	var hash []byte
+	if secret, hash, err = newTokenSecret(); err != nil {
+		return "", err
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var recent bool
+	if err = tx.QueryRow(recentResetSQL, u.ID, time.Now().Add(-TokenResendInterval)).Scan(&recent); err != nil {
+		return "", err
+	}
+
+	if recent {
+		return "", ErrTokenRecentlySent
+	}
+
+	if _, err = tx.Exec(createResetSQL, u.ID, hash, time.Now().Add(ResetTokenTTL)); err != nil {
+		return "", err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+const (
+	useResetSQL      = "DELETE FROM reset_tokens WHERE token=$1 RETURNING user_id, expires"
+	resetPasswordSQL = "UPDATE users SET password=$2, pwchanged=$3, email_verified=COALESCE(email_verified, $3) WHERE id=$1 RETURNING email_verified"
+	clearResetSQL    = "DELETE FROM reset_tokens WHERE user_id=$1"
+	clearSessionsSQL = "DELETE FROM sessions WHERE user_id=$1"
+)
+
+// ResetPassword uses the reset token to set the password of its user to the derived
+// key, invalidating any other reset tokens and revoking all sessions of the user. Since
+// the token was emailed to the user, resetting the password also verifies their email
+// address. Returns ErrInvalidToken if the token does not exist or has expired.
+func ResetPassword(ctx context.Context, secret, password string) (user *User, err error) {
+	if secret == "" {
+		return nil, ErrInvalidToken
+	}
+
+	// Sanity checks: password must be a derived key.
+	if !passwd.IsDerivedKey(password) {
+		return nil, ErrNotDerivedKey
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var expires time.Time
+	user = &User{Password: password}
+	if err = tx.QueryRow(useResetSQL, secrets.Hash(secret)).Scan(&user.ID, &expires); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	// Delete expired tokens even though they cannot be used
+	if time.Now().After(expires) {
+		if err = tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidToken
+	}
+
+	user.PasswordChanged = sql.NullTime{Valid: true, Time: time.Now()}
+	if err = tx.QueryRow(resetPasswordSQL, user.ID, user.Password, user.PasswordChanged).Scan(&user.EmailVerified); err != nil {
+		return nil, err
+	}
+
+	for _, query := range []string{clearResetSQL, clearSessionsSQL} {
+		if _, err = tx.Exec(query, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+const (
+	purgeVerificationSQL = "DELETE FROM verification_tokens WHERE expires < NOW()"
+	purgeResetSQL        = "DELETE FROM reset_tokens WHERE expires < NOW()"
+	purgeSessionsSQL     = "DELETE FROM sessions WHERE expires < NOW()"
+)
+
+// PurgeExpiredTokens deletes the verification and reset tokens and the sessions that
+// have expired, returning the number of tokens and sessions that were deleted.
+func PurgeExpiredTokens(ctx context.Context) (purged int64, err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{purgeVerificationSQL, purgeResetSQL, purgeSessionsSQL} {
+		var result sql.Result
+		if result, err = tx.Exec(query); err != nil {
+			return 0, err
+		}
+
+		nRows, _ := result.RowsAffected()
+		purged += nRows
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
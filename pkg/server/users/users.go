@@ -14,11 +14,15 @@ type User struct {
 	ID              int64
 	FullName        sql.NullString
 	Email           string
+	EmailVerified   sql.NullTime
+	Disabled        sql.NullTime
 	Username        string
 	Password        string
 	RoleID          int64
 	LastSeen        sql.NullTime
 	PasswordChanged sql.NullTime
+	FailedLogins    int64
+	LockedUntil     sql.NullTime
 	Created         time.Time
 	Modified        time.Time
 	role            *Role
@@ -26,9 +30,9 @@ type User struct {
 }
 
 const (
-	getUserUnameSQL = "SELECT id, full_name, email, password, role_id, last_seen, pwchanged, created, modified FROM users WHERE username=$1"
-	getUserEmailSQL = "SELECT id, full_name, username, password, role_id, last_seen, pwchanged, created, modified FROM users WHERE email=$1"
-	getUserIDSQL    = "SELECT full_name, email, username, role_id, last_seen, pwchanged, created, modified FROM users WHERE id=$1"
+	getUserUnameSQL = "SELECT id, full_name, email, email_verified, disabled, password, role_id, last_seen, pwchanged, failed_logins, locked_until, created, modified FROM users WHERE username=$1"
+	getUserEmailSQL = "SELECT id, full_name, email_verified, disabled, username, password, role_id, last_seen, pwchanged, failed_logins, locked_until, created, modified FROM users WHERE email=$1"
+	getUserIDSQL    = "SELECT full_name, email, email_verified, disabled, username, role_id, last_seen, pwchanged, failed_logins, locked_until, created, modified FROM users WHERE id=$1"
 )
 
 // UserFromUsername gets a user and populates the role and permissions if claims is true.
@@ -42,7 +46,7 @@ func UserFromUsername(ctx context.Context, username string, claims bool) (user *
 	}
 	defer tx.Rollback()
 
-	if err = tx.QueryRow(getUserUnameSQL, username).Scan(&user.ID, &user.FullName, &user.Email, &user.Password, &user.RoleID, &user.LastSeen, &user.PasswordChanged, &user.Created, &user.Modified); err != nil {
+	if err = tx.QueryRow(getUserUnameSQL, username).Scan(&user.ID, &user.FullName, &user.Email, &user.EmailVerified, &user.Disabled, &user.Password, &user.RoleID, &user.LastSeen, &user.PasswordChanged, &user.FailedLogins, &user.LockedUntil, &user.Created, &user.Modified); err != nil {
 		return nil, err
 	}
 
@@ -73,7 +77,7 @@ func UserFromEmail(ctx context.Context, email string, claims bool) (user *User,
 	}
 	defer tx.Rollback()
 
-	if err = tx.QueryRow(getUserEmailSQL, email).Scan(&user.ID, &user.FullName, &user.Username, &user.Password, &user.RoleID, &user.LastSeen, &user.PasswordChanged, &user.Created, &user.Modified); err != nil {
+	if err = tx.QueryRow(getUserEmailSQL, email).Scan(&user.ID, &user.FullName, &user.EmailVerified, &user.Disabled, &user.Username, &user.Password, &user.RoleID, &user.LastSeen, &user.PasswordChanged, &user.FailedLogins, &user.LockedUntil, &user.Created, &user.Modified); err != nil {
 		return nil, err
 	}
 
@@ -103,7 +107,7 @@ func UserFromID(ctx context.Context, id int64) (user *User, err error) {
 	}
 	defer tx.Rollback()
 
-	if err = tx.QueryRow(getUserIDSQL, id).Scan(&user.FullName, &user.Email, &user.Username, &user.RoleID, &user.LastSeen, &user.PasswordChanged, &user.Created, &user.Modified); err != nil {
+	if err = tx.QueryRow(getUserIDSQL, id).Scan(&user.FullName, &user.Email, &user.EmailVerified, &user.Disabled, &user.Username, &user.RoleID, &user.LastSeen, &user.PasswordChanged, &user.FailedLogins, &user.LockedUntil, &user.Created, &user.Modified); err != nil {
 		return nil, err
 	}
 
@@ -119,8 +123,12 @@ const (
 // Create a user from the model. The ID, Created, and Modified timestamps will be
 // populated on the model after creation. Note that the LastSeen timestamp is ignored
 // and the PasswordChange timestamp is set to now (no matter what it was set to before).
-// TODO: handle specific database errors like uniqueness constraints or other schema errors.
+// Returns ValidationErrors if the email or username is invalid or already taken.
 func (u *User) Create(ctx context.Context) (err error) {
+	if err = u.Validate(); err != nil {
+		return err
+	}
+
 	// Sanity checks: password must be a derived key.
 	if !passwd.IsDerivedKey(u.Password) {
 		return ErrNotDerivedKey
@@ -144,7 +152,7 @@ func (u *User) Create(ctx context.Context) (err error) {
 	defer tx.Rollback()
 
 	if err = tx.QueryRow(createUserSQL, u.FullName, u.Email, u.Username, u.Password, u.RoleID, u.PasswordChanged).Scan(&u.ID); err != nil {
-		return err
+		return uniqueViolation(err)
 	}
 
 	if err = tx.QueryRow(getUserTSSQL, u.ID).Scan(&u.Created, &u.Modified); err != nil {
@@ -156,11 +164,14 @@ func (u *User) Create(ctx context.Context) (err error) {
 }
 
 const (
-	updateUserSQL = "UPDATE users SET full_name=$2, email=$3, username=$4, role_id=$5, last_seen=$6 WHERE id=$1"
+	updateUserSQL = "UPDATE users SET full_name=$2, email=$3, username=$4, role_id=$5, last_seen=$6, email_verified=CASE WHEN email=$3 THEN email_verified ELSE NULL END WHERE id=$1 RETURNING email_verified, modified"
 )
 
 // Update a user's full name, email, username, role id, and/or last seen timestamp. The
-// user must have an ID field populated or ErrNoUserID will be returned.
+// user must have an ID field populated or ErrNoUserID will be returned. Changing the
+// email address of the user clears its verification so the new email must be verified.
+// Returns ValidationErrors if the email or username is already taken by another user
+// and sql.ErrNoRows if the user does not exist.
 func (u *User) Update(ctx context.Context) (err error) {
 	if u.ID < 1 {
 		return ErrNoUserID
@@ -172,8 +183,8 @@ func (u *User) Update(ctx context.Context) (err error) {
 	}
 	defer tx.Rollback()
 
-	if _, err = tx.Exec(updateUserSQL, u.ID, u.FullName, u.Email, u.Username, u.RoleID, u.LastSeen); err != nil {
-		return err
+	if err = tx.QueryRow(updateUserSQL, u.ID, u.FullName, u.Email, u.Username, u.RoleID, u.LastSeen).Scan(&u.EmailVerified, &u.Modified); err != nil {
+		return uniqueViolation(err)
 	}
 
 	tx.Commit()
@@ -216,6 +227,32 @@ func (u *User) UpdatePassword(ctx context.Context) (err error) {
 	return nil
 }
 
+const (
+	getPasswordSQL = "SELECT password FROM users WHERE id=$1"
+)
+
+// VerifyPassword checks the plain text password against the derived key stored for the
+// user. The user must have an ID field populated or ErrNoUserID will be returned.
+func (u *User) VerifyPassword(ctx context.Context, password string) (_ bool, err error) {
+	if u.ID < 1 {
+		return false, ErrNoUserID
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var derivedKey string
+	if err = tx.QueryRow(getPasswordSQL, u.ID).Scan(&derivedKey); err != nil {
+		return false, err
+	}
+
+	tx.Commit()
+	return passwd.VerifyDerivedKey(derivedKey, password)
+}
+
 const (
 	updateLastSeenSQL = "UPDATE users SET last_seen=$2 WHERE id=$1"
 )
@@ -247,6 +284,98 @@ func (u *User) UpdateLastSeen(ctx context.Context) (err error) {
 	return nil
 }
 
+const (
+	failedLoginSQL = "UPDATE users SET failed_logins=CASE WHEN locked_until < NOW() THEN 1 ELSE failed_logins+1 END, locked_until=CASE WHEN $2::INTEGER > 0 AND (CASE WHEN locked_until < NOW() THEN 1 ELSE failed_logins+1 END) >= $2::INTEGER THEN $3 WHEN locked_until < NOW() THEN NULL ELSE locked_until END WHERE id=$1 RETURNING failed_logins, locked_until"
+	resetLoginsSQL = "UPDATE users SET failed_logins=0, locked_until=NULL WHERE id=$1"
+)
+
+// FailedLogin increments the number of consecutive failed logins of the user. Once the
+// number of failures reaches maxFailures the account is locked for the lockout
+// duration; every subsequent failure while the account is locked locks it again. Once
+// the lockout has expired the next failure starts counting from one again, so that a
+// single failure does not lock the account for another lockout duration. A successful
+// login resets the count. If maxFailures is zero the failures are counted but the
+// account is never locked.
+func (u *User) FailedLogin(ctx context.Context, maxFailures int, lockout time.Duration) (err error) {
+	if u.ID < 1 {
+		return ErrNoUserID
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.QueryRow(failedLoginSQL, u.ID, maxFailures, time.Now().Add(lockout)).Scan(&u.FailedLogins, &u.LockedUntil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ResetFailedLogins clears the failed login count and lockout of the user after they
+// have successfully logged in.
+func (u *User) ResetFailedLogins(ctx context.Context) (err error) {
+	if u.ID < 1 {
+		return ErrNoUserID
+	}
+
+	// Avoid a write on every login if there is nothing to reset.
+	if u.FailedLogins == 0 && !u.LockedUntil.Valid {
+		return nil
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(resetLoginsSQL, u.ID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	u.FailedLogins, u.LockedUntil = 0, sql.NullTime{}
+	return nil
+}
+
+// IsLocked returns true if the account is locked because of too many failed logins.
+func (u *User) IsLocked() bool {
+	return u.LockedUntil.Valid && u.LockedUntil.Time.After(time.Now())
+}
+
+const (
+	deleteUserSQL = "DELETE FROM users WHERE id=$1"
+)
+
+// Delete the user along with all of their readings, notes, tags, subscriptions, and
+// tokens. Returns sql.ErrNoRows if the user does not exist.
+func (u *User) Delete(ctx context.Context) (err error) {
+	if u.ID < 1 {
+		return ErrNoUserID
+	}
+
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: false}); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.Exec(deleteUserSQL, u.ID); err != nil {
+		return err
+	}
+
+	if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
 const (
 	userRoleSQL = "SELECT id, title, description, created, modified FROM roles WHERE id=$1"
 )
@@ -0,0 +1,109 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bbengfort/epistolary/pkg/server/config"
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/server/passwd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailedLoginAfterLockout(t *testing.T) {
+	require.NoError(t, db.Connect(config.DatabaseConfig{Testing: true}))
+	t.Cleanup(func() { db.Close() })
+	mock := db.Mock()
+
+	// The lockout of the user has expired after too many failed logins
+	user := &User{
+		ID:           42,
+		FailedLogins: 5,
+		LockedUntil:  sql.NullTime{Valid: true, Time: time.Now().Add(-1 * time.Minute)},
+	}
+	require.False(t, user.IsLocked())
+
+	// The next failure starts counting from one again and clears the expired lockout
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(failedLoginSQL)).
+		WithArgs(int64(42), 5, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"failed_logins", "locked_until"}).AddRow(1, nil))
+	mock.ExpectCommit()
+
+	require.NoError(t, user.FailedLogin(context.Background(), 5, 15*time.Minute))
+	require.Equal(t, int64(1), user.FailedLogins)
+	require.False(t, user.LockedUntil.Valid)
+	require.False(t, user.IsLocked())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// The user is locked out again once the failures reach the maximum; the lockout is
+	// passed to the database as the time that the user is locked until.
+	for failures := int64(2); failures <= 5; failures++ {
+		var locked any
+		if failures == 5 {
+			locked = time.Now().Add(15 * time.Minute)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(failedLoginSQL)).
+			WithArgs(int64(42), 5, lockedUntil(15*time.Minute)).
+			WillReturnRows(sqlmock.NewRows([]string{"failed_logins", "locked_until"}).AddRow(failures, locked))
+		mock.ExpectCommit()
+
+		require.NoError(t, user.FailedLogin(context.Background(), 5, 15*time.Minute))
+		require.Equal(t, failures, user.FailedLogins)
+		require.Equal(t, failures == 5, user.IsLocked(), "unexpected lockout after %d failures", failures)
+	}
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	require.NoError(t, db.Connect(config.DatabaseConfig{Testing: true}))
+	t.Cleanup(func() { db.Close() })
+	mock := db.Mock()
+
+	first, err := passwd.CreateDerivedKey("abcdefghjk")
+	require.NoError(t, err)
+	second, err := passwd.CreateDerivedKey("mnpqrstuvw")
+	require.NoError(t, err)
+
+	expectCodes := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(recoveryCodesSQL)).
+			WithArgs(int64(42)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "code"}).AddRow(1, first).AddRow(2, second))
+	}
+
+	// The recovery code that matches the derived key is deleted
+	expectCodes()
+	mock.ExpectExec(regexp.QuoteMeta(useRecoveryCodeSQL)).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, UseRecoveryCode(context.Background(), 42, "mnpqrstuvw"))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// A recovery code that does not match any derived key is invalid
+	expectCodes()
+	mock.ExpectRollback()
+
+	require.ErrorIs(t, UseRecoveryCode(context.Background(), 42, "xyz23xyz23"), ErrInvalidRecoveryCode)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// Matches a lockout time that is the duration from now.
+type lockedUntil time.Duration
+
+func (d lockedUntil) Match(v driver.Value) bool {
+	ts, ok := v.(time.Time)
+	if !ok {
+		return false
+	}
+
+	delta := time.Until(ts) - time.Duration(d)
+	return delta > -time.Minute && delta <= 0
+}
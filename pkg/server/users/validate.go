@@ -0,0 +1,207 @@
+package users
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+)
+
+// Limits on the usernames, emails, and passwords that users can register with.
+const (
+	MinUsernameLength  = 3
+	MaxUsernameLength  = 32
+	MaxEmailLength     = 254
+	MinPasswordLength  = 8
+	MaxPasswordLength  = 256
+	MinPasswordClasses = 2
+)
+
+// Validation errors are returned wrapped in a FieldError so that the API can report
+// which field of the request was invalid.
+var (
+	ErrEmailRequired    = errors.New("email is required")
+	ErrInvalidEmail     = errors.New("email must be a valid email address")
+	ErrEmailTaken       = errors.New("email address is already registered")
+	ErrUsernameRequired = errors.New("username is required")
+	ErrUsernameLength   = errors.New("username must be between 3 and 32 characters")
+	ErrUsernameCharset  = errors.New("username may only contain letters, numbers, periods, hyphens, and underscores and must start with a letter or number")
+	ErrUsernameTaken    = errors.New("username is already taken")
+	ErrPasswordRequired = errors.New("password is required")
+	ErrPasswordLength   = errors.New("password must be between 8 and 256 characters")
+	ErrPasswordWeak     = errors.New("password must contain at least two of lowercase letters, uppercase letters, numbers, and symbols")
+	ErrPasswordUsername = errors.New("password must not contain the username")
+)
+
+// FieldError describes why the value of a field of a user is invalid.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors collects the field errors of a user so that all of the problems can
+// be reported at once. Use errors.Is to check for a specific validation error.
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, ferr := range e {
+		msgs = append(msgs, ferr.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, ferr := range e {
+		errs = append(errs, ferr)
+	}
+	return errs
+}
+
+func (e ValidationErrors) add(field string, err error) ValidationErrors {
+	if err == nil {
+		return e
+	}
+	return append(e, &FieldError{Field: field, Err: err})
+}
+
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ValidateRegistration checks the email, username, and plain text password of a new
+// user, returning ValidationErrors describing every invalid field or nil if valid.
+func ValidateRegistration(email, username, password string) error {
+	var errs ValidationErrors
+	errs = errs.add("email", ValidateEmail(email))
+	errs = errs.add("username", ValidateUsername(username))
+
+	errs = errs.add("password", ValidateNewPassword(password, username))
+	return errs.err()
+}
+
+// ValidateNewPassword enforces the password strength policy and ensures the password
+// does not contain the username of the user it is being set for.
+func ValidateNewPassword(password, username string) error {
+	if err := ValidatePassword(password); err != nil {
+		return err
+	}
+
+	if username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		return ErrPasswordUsername
+	}
+	return nil
+}
+
+// Validate the email and username of the user; the password is not validated since it
+// must be stored as a derived key.
+func (u *User) Validate() error {
+	var errs ValidationErrors
+	errs = errs.add("email", ValidateEmail(u.Email))
+	errs = errs.add("username", ValidateUsername(u.Username))
+	return errs.err()
+}
+
+// ValidateEmail ensures the email is a bare email address without a display name.
+func ValidateEmail(email string) error {
+	if email == "" {
+		return ErrEmailRequired
+	}
+
+	if len(email) > MaxEmailLength {
+		return ErrInvalidEmail
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return ErrInvalidEmail
+	}
+	return nil
+}
+
+// ValidateUsername ensures the username has a valid length and only contains letters,
+// numbers, periods, hyphens, and underscores, starting with a letter or number.
+func ValidateUsername(username string) error {
+	if username == "" {
+		return ErrUsernameRequired
+	}
+
+	if n := utf8.RuneCountInString(username); n < MinUsernameLength || n > MaxUsernameLength {
+		return ErrUsernameLength
+	}
+
+	for i, r := range username {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+		case i > 0 && (r == '.' || r == '-' || r == '_'):
+		default:
+			return ErrUsernameCharset
+		}
+	}
+	return nil
+}
+
+// ValidatePassword enforces the password strength policy: the password must have a
+// valid length and contain at least two classes of characters.
+func ValidatePassword(password string) error {
+	if password == "" {
+		return ErrPasswordRequired
+	}
+
+	if n := utf8.RuneCountInString(password); n < MinPasswordLength || n > MaxPasswordLength {
+		return ErrPasswordLength
+	}
+
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+
+	if lower+upper+digit+symbol < MinPasswordClasses {
+		return ErrPasswordWeak
+	}
+	return nil
+}
+
+// Maps unique constraint violations on the users table to validation errors.
+func uniqueViolation(err error) error {
+	var pgerr *pq.Error
+	if errors.As(err, &pgerr) && pgerr.Code == "23505" {
+		switch pgerr.Constraint {
+		case "users_email_key":
+			return ValidationErrors{{Field: "email", Err: ErrEmailTaken}}
+		case "users_username_key":
+			return ValidationErrors{{Field: "username", Err: ErrUsernameTaken}}
+		}
+	}
+	return err
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pgerr *pq.Error
+	return errors.As(err, &pgerr) && pgerr.Code == "23503"
+}
@@ -0,0 +1,77 @@
+package users_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bbengfort/epistolary/pkg/server/users"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEmail(t *testing.T) {
+	require.NoError(t, users.ValidateEmail("jane@example.com"))
+	require.NoError(t, users.ValidateEmail("jane.doe+reading@mail.example.co.uk"))
+	require.ErrorIs(t, users.ValidateEmail(""), users.ErrEmailRequired)
+
+	for _, email := range []string{"jane", "jane@", "@example.com", "Jane <jane@example.com>", " jane@example.com", strings.Repeat("a", 250) + "@example.com"} {
+		require.ErrorIs(t, users.ValidateEmail(email), users.ErrInvalidEmail, "expected %q to be invalid", email)
+	}
+}
+
+func TestValidateUsername(t *testing.T) {
+	for _, username := range []string{"jane", "jane.doe", "jane_doe-42", "Zoë", "abc"} {
+		require.NoError(t, users.ValidateUsername(username), "expected %q to be valid", username)
+	}
+
+	require.ErrorIs(t, users.ValidateUsername(""), users.ErrUsernameRequired)
+	require.ErrorIs(t, users.ValidateUsername("jd"), users.ErrUsernameLength)
+	require.ErrorIs(t, users.ValidateUsername(strings.Repeat("j", 33)), users.ErrUsernameLength)
+
+	for _, username := range []string{"jane doe", "_jane", ".jane", "jane@example", "jane/doe"} {
+		require.ErrorIs(t, users.ValidateUsername(username), users.ErrUsernameCharset, "expected %q to be invalid", username)
+	}
+}
+
+func TestValidatePassword(t *testing.T) {
+	for _, password := range []string{"correcthorse1", "Correct Horse", "hunter2hunter2", "パスワードは1234"} {
+		require.NoError(t, users.ValidatePassword(password), "expected %q to be valid", password)
+	}
+
+	require.ErrorIs(t, users.ValidatePassword(""), users.ErrPasswordRequired)
+	require.ErrorIs(t, users.ValidatePassword("Ab1!"), users.ErrPasswordLength)
+	require.ErrorIs(t, users.ValidatePassword(strings.Repeat("Ab1!", 65)), users.ErrPasswordLength)
+	require.ErrorIs(t, users.ValidatePassword("correcthorse"), users.ErrPasswordWeak)
+	require.ErrorIs(t, users.ValidatePassword("1234567890"), users.ErrPasswordWeak)
+}
+
+func TestValidateRegistration(t *testing.T) {
+	require.NoError(t, users.ValidateRegistration("jane@example.com", "jane", "correcthorse1"))
+
+	err := users.ValidateRegistration("jane", "j d", "Jane1234")
+	require.ErrorIs(t, err, users.ErrInvalidEmail)
+	require.ErrorIs(t, err, users.ErrUsernameCharset)
+
+	var verrs users.ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+	require.Len(t, verrs, 2)
+	require.Equal(t, "email", verrs[0].Field)
+	require.Equal(t, "username", verrs[1].Field)
+	require.Equal(t, users.ErrInvalidEmail.Error()+"; "+users.ErrUsernameCharset.Error(), err.Error())
+
+	err = users.ValidateRegistration("jane@example.com", "jane", "JANE-password")
+	require.ErrorIs(t, err, users.ErrPasswordUsername)
+
+	err = users.ValidateRegistration("", "", "")
+	require.ErrorIs(t, err, users.ErrEmailRequired)
+	require.ErrorIs(t, err, users.ErrUsernameRequired)
+	require.ErrorIs(t, err, users.ErrPasswordRequired)
+}
+
+func TestUserValidate(t *testing.T) {
+	user := &users.User{Email: "jane@example.com", Username: "jane"}
+	require.NoError(t, user.Validate())
+
+	user.Username = "jane doe"
+	require.ErrorIs(t, user.Validate(), users.ErrUsernameCharset)
+}
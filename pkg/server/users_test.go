@@ -0,0 +1,120 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/db"
+	"github.com/bbengfort/epistolary/pkg/server/passwd"
+	"github.com/bbengfort/epistolary/pkg/server/tokens"
+)
+
+const testPassword = "correct horse battery staple"
+
+func (suite *epistolaryTestSuite) TestDeleteAccountPassword() {
+	require := suite.Require()
+	tks := suite.accessToken(42)
+
+	// The password is required to delete the account
+	require.Equal(http.StatusBadRequest, suite.doRequest(http.MethodDelete, "/v1/users/me", tks, &api.DeleteAccountRequest{}, nil))
+
+	// The account is not deleted if the password is incorrect
+	suite.expectUser(42, "jane@example.com")
+	suite.expectPassword(42)
+	require.Equal(http.StatusForbidden, suite.doRequest(http.MethodDelete, "/v1/users/me", tks, &api.DeleteAccountRequest{Password: "hunter2"}, nil))
+	require.NoError(db.Mock().ExpectationsWereMet())
+}
+
+func (suite *epistolaryTestSuite) TestUpdateEmailPassword() {
+	require := suite.Require()
+	tks := suite.accessToken(42)
+	in := &api.User{FullName: "Jane Doe", Email: "jane@attacker.com", Username: "jane"}
+
+	// The password is required to change the email address
+	suite.expectUser(42, "jane@example.com")
+	require.Equal(http.StatusBadRequest, suite.doRequest(http.MethodPut, "/v1/users/me", tks, in, nil))
+	require.NoError(db.Mock().ExpectationsWereMet())
+
+	// The email address is not changed if the password is incorrect
+	in.Password = "hunter2"
+	suite.expectUser(42, "jane@example.com")
+	suite.expectPassword(42)
+	require.Equal(http.StatusForbidden, suite.doRequest(http.MethodPut, "/v1/users/me", tks, in, nil))
+	require.NoError(db.Mock().ExpectationsWereMet())
+}
+
+// Returns an access token for the user signed by the token keys of the test server.
+func (suite *epistolaryTestSuite) accessToken(userID int64) string {
+	claims := &tokens.Claims{Name: "Jane Doe", Email: "jane@example.com"}
+	claims.SetSubjectID(userID)
+	return suite.signClaims(claims)
+}
+
+// Returns an access token with the claims signed by the token keys of the test server.
+func (suite *epistolaryTestSuite) signClaims(claims *tokens.Claims) string {
+	require := suite.Require()
+	tm, err := tokens.New(suite.conf.Token)
+	require.NoError(err, "could not create token manager")
+
+	token, err := tm.CreateAccessToken(claims)
+	require.NoError(err, "could not create access token")
+
+	tks, err := tm.Sign(token)
+	require.NoError(err, "could not sign access token")
+	return tks
+}
+
+// Makes an authenticated JSON request to the test server and returns the status code,
+// decoding the response into out if it is not nil.
+func (suite *epistolaryTestSuite) doRequest(method, path, tks string, in, out any) int {
+	require := suite.Require()
+	body, err := json.Marshal(in)
+	require.NoError(err, "could not marshal request")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, suite.srv.URL()+path, bytes.NewReader(body))
+	require.NoError(err, "could not create request")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+tks)
+
+	rep, err := http.DefaultClient.Do(req)
+	require.NoError(err, "could not make request")
+	defer rep.Body.Close()
+
+	if out != nil {
+		require.NoError(json.NewDecoder(rep.Body).Decode(out), "could not decode response")
+	}
+	return rep.StatusCode
+}
+
+// Expects the user to be fetched from the database.
+func (suite *epistolaryTestSuite) expectUser(userID int64, email string) {
+	now := time.Now()
+	mock := db.Mock()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT full_name, email, email_verified, disabled, username, role_id, last_seen, pwchanged, failed_logins, locked_until, created, modified FROM users WHERE id=").
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"full_name", "email", "email_verified", "disabled", "username", "role_id", "last_seen", "pwchanged", "failed_logins", "locked_until", "created", "modified"}).
+			AddRow("Jane Doe", email, now, nil, "jane", 2, now, now, 0, nil, now, now))
+	mock.ExpectCommit()
+}
+
+// Expects the password of the user to be fetched from the database for verification.
+func (suite *epistolaryTestSuite) expectPassword(userID int64) {
+	derivedKey, err := passwd.CreateDerivedKey(testPassword)
+	suite.Require().NoError(err, "could not create derived key")
+
+	mock := db.Mock()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT password FROM users WHERE id=").
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow(derivedKey))
+	mock.ExpectCommit()
+}
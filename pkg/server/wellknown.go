@@ -5,6 +5,7 @@ import (
 	"net/url"
 
 	"github.com/bbengfort/epistolary/pkg/api/v1"
+	"github.com/bbengfort/epistolary/pkg/server/oauth"
 	"github.com/bbengfort/epistolary/pkg/utils/sentry"
 	"github.com/gin-gonic/gin"
 	"github.com/lestrrat-go/jwx/v2/jwa"
@@ -54,8 +55,8 @@ func (s *Server) JWKS(c *gin.Context) {
 
 // Returns a JSON document with the OpenID configuration as defined by the OpenID
 // Connect standard: https://connect2id.com/learn/openid-connect. This document helps
-// clients understand how to authenticate with Epistolary.
-// TODO: once OpenID endpoints have been configured add them to this JSON response
+// clients understand how to authenticate with Epistolary using the authorization code
+// flow with PKCE.
 func (s *Server) OpenIDConfiguration(c *gin.Context) {
 	// Parse the token issuer for the OpenID configuration
 	base, err := url.Parse(s.conf.Token.Issuer)
@@ -66,15 +67,20 @@ func (s *Server) OpenIDConfiguration(c *gin.Context) {
 
 	openid := &api.OpenIDConfiguration{
 		Issuer:                        base.ResolveReference(&url.URL{Path: "/"}).String(),
+		AuthorizationEP:               base.ResolveReference(&url.URL{Path: "/oauth/authorize"}).String(),
+		TokenEP:                       base.ResolveReference(&url.URL{Path: "/oauth/token"}).String(),
+		UserInfoEP:                    base.ResolveReference(&url.URL{Path: "/oauth/userinfo"}).String(),
+		MFAChallengeEP:                base.ResolveReference(&url.URL{Path: "/v1/login/mfa"}).String(),
+		RevocationEP:                  base.ResolveReference(&url.URL{Path: "/oauth/revoke"}).String(),
 		JWKSURI:                       base.ResolveReference(&url.URL{Path: "/.well-known/jwks.json"}).String(),
-		ScopesSupported:               []string{"openid", "profile", "email"},
-		ResponseTypesSupported:        []string{"token", "id_token"},
-		CodeChallengeMethodsSupported: []string{"S256", "plain"},
-		ResponseModesSupported:        []string{"query", "fragment", "form_post"},
+		ScopesSupported:               oauth.Scopes,
+		ResponseTypesSupported:        []string{"code"},
+		CodeChallengeMethodsSupported: []string{"S256"},
+		ResponseModesSupported:        []string{"query"},
 		SubjectTypesSupported:         []string{"public"},
-		IDTokenSigningAlgValues:       []string{"HS256", "RS256"},
-		TokenEndpointAuthMethods:      []string{"client_secret_basic", "client_secret_post"},
-		ClaimsSupported:               []string{"aud", "name", "email", "permissions", "exp", "iat", "iss", "sub"},
+		IDTokenSigningAlgValues:       []string{"RS256"},
+		TokenEndpointAuthMethods:      []string{"client_secret_basic", "client_secret_post", "none"},
+		ClaimsSupported:               []string{"aud", "name", "preferred_username", "email", "email_verified", "nonce", "exp", "iat", "iss", "sub"},
 		RequestURIParameterSupported:  false,
 	}
 
@@ -61,6 +61,12 @@ func (suite *epistolaryTestSuite) TestOpenIDConfiguration() {
 
 	require.Equal("http://localhost:8000/", openid.Issuer)
 	require.Equal("http://localhost:8000/.well-known/jwks.json", openid.JWKSURI)
+	require.Equal("http://localhost:8000/oauth/authorize", openid.AuthorizationEP)
+	require.Equal("http://localhost:8000/oauth/token", openid.TokenEP)
+	require.Equal("http://localhost:8000/oauth/userinfo", openid.UserInfoEP)
+	require.Equal("http://localhost:8000/v1/login/mfa", openid.MFAChallengeEP)
+	require.Equal("http://localhost:8000/oauth/revoke", openid.RevocationEP)
+	require.Equal([]string{"code"}, openid.ResponseTypesSupported)
 }
 
 func (suite *epistolaryTestSuite) TestSecurityTxt() {
@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+)
+
+// Mailer backends that can be configured.
+const (
+	BackendSMTP = "smtp"
+	BackendDump = "dump"
+)
+
+// Mailer configuration for use in application-configuration. The dump backend keeps
+// sent messages in memory and writes them to the dump directory if one is specified;
+// it is the default so that emails can be inspected during local development.
+type Config struct {
+	Backend  string `default:"dump" desc:"the backend used to send email: smtp or dump"`
+	Sender   string `default:"Epistolary <noreply@epistolary.app>" desc:"the from address of emails"`
+	Host     string `desc:"the host of the smtp server"`
+	Port     int    `default:"587" desc:"the port of the smtp server, 465 uses implicit tls"`
+	Username string `desc:"the username to authenticate with the smtp server"`
+	Password string `desc:"the password to authenticate with the smtp server"`
+	DumpDir  string `split_words:"true" desc:"a directory to write emails to when using the dump backend"`
+}
+
+func (c Config) Validate() error {
+	switch c.Backend {
+	case BackendSMTP:
+		if c.Host == "" {
+			return errors.New("invalid configuration: host must be configured for the smtp mailer")
+		}
+	case BackendDump, "":
+	default:
+		return fmt.Errorf("invalid configuration: unknown mailer backend %q", c.Backend)
+	}
+
+	if c.Sender != "" {
+		if _, err := mail.ParseAddress(c.Sender); err != nil {
+			return fmt.Errorf("invalid configuration: could not parse sender: %w", err)
+		}
+	}
+	return nil
+}
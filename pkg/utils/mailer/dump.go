@@ -0,0 +1,65 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Dump keeps sent messages in memory so that they can be inspected by tests and, if a
+// dump directory is configured, writes each message to an .eml file in the directory
+// so that emails can be read during local development.
+type Dump struct {
+	sync.RWMutex
+	conf     Config
+	messages []*Message
+}
+
+// Ensure Dump implements the Mailer interface.
+var _ Mailer = &Dump{}
+
+func NewDump(conf Config) *Dump {
+	return &Dump{conf: conf}
+}
+
+func (d *Dump) Send(_ context.Context, msg *Message) (err error) {
+	var data []byte
+	if data, err = msg.Bytes(sender(d.conf)); err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	d.messages = append(d.messages, msg)
+
+	if d.conf.DumpDir != "" {
+		if err = os.MkdirAll(d.conf.DumpDir, 0o755); err != nil {
+			return err
+		}
+
+		name := fmt.Sprintf("%s-%04d.eml", time.Now().UTC().Format("20060102T150405"), len(d.messages))
+		if err = os.WriteFile(filepath.Join(d.conf.DumpDir, name), data, 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Messages returns the messages that have been sent, oldest first.
+func (d *Dump) Messages() []*Message {
+	d.RLock()
+	defer d.RUnlock()
+	out := make([]*Message, len(d.messages))
+	copy(out, d.messages)
+	return out
+}
+
+// Reset removes all of the sent messages from memory.
+func (d *Dump) Reset() {
+	d.Lock()
+	d.messages = nil
+	d.Unlock()
+}
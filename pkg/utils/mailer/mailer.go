@@ -0,0 +1,121 @@
+/*
+Package mailer sends transactional emails such as email verification and password reset
+messages through a pluggable backend so that emails can be sent over SMTP in production
+and captured in memory or on disk in tests and local development.
+*/
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// DefaultSender is used if the sender is not configured.
+const DefaultSender = "Epistolary <noreply@epistolary.app>"
+
+var (
+	ErrNoRecipient = errors.New("email must have a recipient")
+	ErrNoSubject   = errors.New("email must have a subject")
+)
+
+// Mailer sends plain text emails.
+type Mailer interface {
+	Send(context.Context, *Message) error
+}
+
+// New returns the mailer for the backend specified by the configuration.
+func New(conf Config) (Mailer, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch conf.Backend {
+	case BackendSMTP:
+		return NewSMTP(conf), nil
+	default:
+		return NewDump(conf), nil
+	}
+}
+
+// Message is a plain text email to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+}
+
+// Validate that the message has a recipient and subject.
+func (m *Message) Validate() error {
+	if m.To == "" {
+		return ErrNoRecipient
+	}
+
+	if _, err := mail.ParseAddress(m.To); err != nil {
+		return fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	if m.Subject == "" {
+		return ErrNoSubject
+	}
+	return nil
+}
+
+// Bytes returns the message encoded as an RFC 5322 email from the sender.
+func (m *Message) Bytes(sender string) (_ []byte, err error) {
+	if err = m.Validate(); err != nil {
+		return nil, err
+	}
+
+	var from *mail.Address
+	if from, err = mail.ParseAddress(sender); err != nil {
+		return nil, fmt.Errorf("invalid sender: %w", err)
+	}
+
+	var to *mail.Address
+	if to, err = mail.ParseAddress(m.To); err != nil {
+		return nil, fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	msgid := make([]byte, 16)
+	if _, err = rand.Read(msgid); err != nil {
+		return nil, err
+	}
+
+	_, domain, _ := strings.Cut(from.Address, "@")
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(msgid), domain)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(buf)
+	if _, err = qp.Write([]byte(strings.ReplaceAll(m.Text, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+
+	if err = qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func sender(conf Config) string {
+	if conf.Sender == "" {
+		return DefaultSender
+	}
+	return conf.Sender
+}
@@ -0,0 +1,134 @@
+package mailer_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/utils/mailer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	require.NoError(t, mailer.Config{}.Validate())
+	require.NoError(t, mailer.Config{Backend: mailer.BackendDump, Sender: "Epistolary <noreply@example.com>"}.Validate())
+	require.NoError(t, mailer.Config{Backend: mailer.BackendSMTP, Host: "smtp.example.com"}.Validate())
+	require.Error(t, mailer.Config{Backend: mailer.BackendSMTP}.Validate())
+	require.Error(t, mailer.Config{Backend: "carrier-pigeon"}.Validate())
+	require.Error(t, mailer.Config{Sender: "not an email"}.Validate())
+}
+
+func TestMessageBytes(t *testing.T) {
+	msg := &mailer.Message{To: "Jane Doe <jane@example.com>", Subject: "Vérifiez", Text: "Hello\nWorld"}
+	data, err := msg.Bytes("Epistolary <noreply@example.com>")
+	require.NoError(t, err)
+
+	email := string(data)
+	require.Contains(t, email, "From: \"Epistolary\" <noreply@example.com>\r\n")
+	require.Contains(t, email, "To: \"Jane Doe\" <jane@example.com>\r\n")
+	require.Contains(t, email, "Subject: =?utf-8?q?V=C3=A9rifiez?=\r\n")
+	require.Contains(t, email, "Message-ID: <")
+	require.True(t, strings.HasSuffix(email, "\r\n\r\nHello\r\nWorld"))
+
+	_, err = (&mailer.Message{Subject: "Hello"}).Bytes("noreply@example.com")
+	require.ErrorIs(t, err, mailer.ErrNoRecipient)
+
+	_, err = (&mailer.Message{To: "jane@example.com"}).Bytes("noreply@example.com")
+	require.ErrorIs(t, err, mailer.ErrNoSubject)
+}
+
+func TestDump(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "emails")
+	m, err := mailer.New(mailer.Config{Backend: mailer.BackendDump, DumpDir: dir})
+	require.NoError(t, err)
+
+	dump, ok := m.(*mailer.Dump)
+	require.True(t, ok, "expected the dump mailer")
+
+	msg := &mailer.Message{To: "jane@example.com", Subject: "Hello", Text: "Hello World"}
+	require.NoError(t, dump.Send(context.Background(), msg))
+	require.Error(t, dump.Send(context.Background(), &mailer.Message{}))
+	require.Equal(t, []*mailer.Message{msg}, dump.Messages())
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, ".eml", filepath.Ext(files[0].Name()))
+
+	dump.Reset()
+	require.Empty(t, dump.Messages())
+}
+
+func TestSMTP(t *testing.T) {
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer sock.Close()
+
+	// A minimal SMTP server that records the commands and data it receives
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := sock.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var lines []string
+		r := bufio.NewReader(conn)
+		conn.Write([]byte("220 localhost ESMTP\r\n"))
+
+		data := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				break
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+
+			switch {
+			case data && line == ".":
+				data = false
+				conn.Write([]byte("250 OK\r\n"))
+			case data:
+			case strings.HasPrefix(line, "EHLO"):
+				conn.Write([]byte("250 localhost\r\n"))
+			case line == "DATA":
+				data = true
+				conn.Write([]byte("354 Go ahead\r\n"))
+			case line == "QUIT":
+				conn.Write([]byte("221 Bye\r\n"))
+				received <- lines
+				return
+			default:
+				conn.Write([]byte("250 OK\r\n"))
+			}
+		}
+		received <- lines
+	}()
+
+	host, port, _ := net.SplitHostPort(sock.Addr().String())
+	conf := mailer.Config{Backend: mailer.BackendSMTP, Host: host, Sender: "noreply@example.com"}
+	conf.Port, _ = strconv.Atoi(port)
+
+	m, err := mailer.New(conf)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = m.Send(ctx, &mailer.Message{To: "jane@example.com", Subject: "Hello", Text: "Hello World"})
+	require.NoError(t, err)
+
+	lines := <-received
+	require.Contains(t, lines, "MAIL FROM:<noreply@example.com>")
+	require.Contains(t, lines, "RCPT TO:<jane@example.com>")
+	require.Contains(t, lines, "Subject: Hello")
+	require.Contains(t, lines, "Hello World")
+}
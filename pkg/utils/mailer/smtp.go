@@ -0,0 +1,107 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// Port of SMTP servers that require implicit TLS rather than STARTTLS.
+const implicitTLSPort = 465
+
+// SMTP sends email through an SMTP server, upgrading the connection with STARTTLS if
+// the server supports it and authenticating if a username is configured.
+type SMTP struct {
+	conf Config
+}
+
+// Ensure SMTP implements the Mailer interface.
+var _ Mailer = &SMTP{}
+
+func NewSMTP(conf Config) *SMTP {
+	return &SMTP{conf: conf}
+}
+
+// Send the message, the context deadline is used as the deadline of the connection.
+func (s *SMTP) Send(ctx context.Context, msg *Message) (err error) {
+	var data []byte
+	if data, err = msg.Bytes(sender(s.conf)); err != nil {
+		return err
+	}
+
+	var from, to *mail.Address
+	if from, err = mail.ParseAddress(sender(s.conf)); err != nil {
+		return err
+	}
+
+	if to, err = mail.ParseAddress(msg.To); err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.conf.Host, strconv.Itoa(s.conf.Port))
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	tlsConf := &tls.Config{ServerName: s.conf.Host, MinVersion: tls.VersionTLS12}
+
+	var conn net.Conn
+	if s.conf.Port == implicitTLSPort {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: tlsConf}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	var client *smtp.Client
+	if client, err = smtp.NewClient(conn, s.conf.Host); err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if s.conf.Port != implicitTLSPort {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err = client.StartTLS(tlsConf); err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.conf.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", s.conf.Username, s.conf.Password, s.conf.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(from.Address); err != nil {
+		return err
+	}
+
+	if err = client.Rcpt(to.Address); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(data); err != nil {
+		return err
+	}
+
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
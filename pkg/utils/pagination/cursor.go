@@ -9,10 +9,11 @@ import (
 )
 
 type Cursor struct {
-	Start int64
-	End   int64
-	Size  uint32
-	Exp   int64
+	Start  int64
+	End    int64
+	Size   uint32
+	Exp    int64
+	Filter uint64
 }
 
 func (c Cursor) GobEncode() ([]byte, error) {
@@ -34,6 +35,10 @@ func (c Cursor) GobEncode() ([]byte, error) {
 		return nil, err
 	}
 
+	if err := encoder.Encode(c.Filter); err != nil {
+		return nil, err
+	}
+
 	return buf.Bytes(), nil
 }
 
@@ -56,6 +61,10 @@ func (c *Cursor) GobDecode(data []byte) error {
 		return err
 	}
 
+	if err := decoder.Decode(&c.Filter); err != nil {
+		return err
+	}
+
 	return nil
 }
 
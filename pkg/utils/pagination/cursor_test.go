@@ -16,12 +16,14 @@ func TestCursor(t *testing.T) {
 		end     int64
 		size    uint32
 		expires int64
+		filter  uint64
 	}
 
 	testCases := []testCase{
-		{0, 0, 0, 0},
-		{0, 100, 100, time.Now().UnixMilli()},
-		{math.MaxInt64, math.MaxInt64, math.MaxUint32, math.MaxInt64},
+		{0, 0, 0, 0, 0},
+		{0, 100, 100, time.Now().UnixMilli(), 0},
+		{0, 100, 100, time.Now().UnixMilli(), Fingerprint("queued", "created")},
+		{math.MaxInt64, math.MaxInt64, math.MaxUint32, math.MaxInt64, math.MaxUint64},
 	}
 
 	// Append random test cases
@@ -31,12 +33,13 @@ func TestCursor(t *testing.T) {
 			end:     rand.Int63(),
 			size:    rand.Uint32(),
 			expires: rand.Int63(),
+			filter:  rand.Uint64(),
 		}
 		testCases = append(testCases, tc)
 	}
 
 	for i, tc := range testCases {
-		original := Cursor{tc.start, tc.end, tc.size, tc.expires}
+		original := Cursor{tc.start, tc.end, tc.size, tc.expires, tc.filter}
 
 		data, err := original.MarshalText()
 		require.NoError(t, err, "could not encode test case %d cursor", i)
@@ -54,6 +57,6 @@ func TestCursor(t *testing.T) {
 
 func TestCursorExpires(t *testing.T) {
 	now := time.Now().Truncate(time.Millisecond)
-	cursor := Cursor{0, 100, 100, now.UnixMilli()}
+	cursor := Cursor{0, 100, 100, now.UnixMilli(), 0}
 	require.Equal(t, now, cursor.Expires())
 }
@@ -2,6 +2,7 @@ package pagination
 
 import (
 	"errors"
+	"hash/fnv"
 	"time"
 )
 
@@ -17,6 +18,7 @@ var (
 	ErrUnparsableToken    = errors.New("could not parse the next page token")
 	ErrTokenQueryMismatch = errors.New("cannot change query parameters during pagination")
 	ErrPageSizeTooLarge   = errors.New("page size is greater than the maximum allowed page size")
+	ErrMissingPageSize    = errors.New("missing page size in paginated query")
 )
 
 func New(startIndex, endIndex int64, pageSize uint32) *Cursor {
@@ -87,5 +89,49 @@ func (c Cursor) HasExpired() (bool, error) {
 }
 
 func (c Cursor) IsZero() bool {
-	return c.Start == 0 && c.End == 0 && c.Size == 0 && c.Exp == 0
+	return c.Start == 0 && c.End == 0 && c.Size == 0 && c.Exp == 0 && c.Filter == 0
+}
+
+// Fingerprint computes a hash of the query parameters of a paginated request that can
+// be stored as the Filter on a cursor. Parameters must be passed in a stable order so
+// that the same query always produces the same fingerprint.
+func Fingerprint(params ...string) uint64 {
+	hash := fnv.New64a()
+	for _, param := range params {
+		hash.Write([]byte(param))
+		hash.Write([]byte{0})
+	}
+	return hash.Sum64()
+}
+
+// CheckFilter returns ErrTokenQueryMismatch if the cursor was created for a query with
+// a different fingerprint than the one specified.
+func (c Cursor) CheckFilter(fingerprint uint64) error {
+	if c.Filter != fingerprint {
+		return ErrTokenQueryMismatch
+	}
+	return nil
+}
+
+// Bind the cursor of the previous page to the fingerprint of a paginated query. A nil
+// cursor or a cursor without a filter is a request for the first page with the page
+// size of the cursor, so it is bound to the fingerprint; otherwise the cursor must have
+// been created with the same fingerprint or ErrTokenQueryMismatch is returned.
+func Bind(prevPage *Cursor, fingerprint uint64) (_ *Cursor, err error) {
+	if prevPage == nil {
+		prevPage = New(0, 0, 0)
+	}
+
+	if prevPage.Filter == 0 {
+		prevPage.Filter = fingerprint
+	}
+
+	if prevPage.Size <= 0 {
+		return nil, ErrMissingPageSize
+	}
+
+	if err = prevPage.CheckFilter(fingerprint); err != nil {
+		return nil, err
+	}
+	return prevPage, nil
 }
@@ -50,3 +50,43 @@ func TestPaginationIsZero(t *testing.T) {
 	cursor = New(1942, 2042, 100)
 	require.False(t, cursor.IsZero(), "new cursor should not be zero valued")
 }
+
+func TestPaginationFilter(t *testing.T) {
+	fingerprint := Fingerprint("finished", "example.com", "title")
+	require.Equal(t, fingerprint, Fingerprint("finished", "example.com", "title"), "fingerprint should be deterministic")
+	require.NotEqual(t, fingerprint, Fingerprint("finished", "example.com"), "fingerprint should depend on all params")
+	require.NotEqual(t, Fingerprint("ab", "c"), Fingerprint("a", "bc"), "params should be separated in the fingerprint")
+
+	cursor := New(1942, 2042, 100)
+	require.NoError(t, cursor.CheckFilter(0), "new cursor should have no filter")
+	require.ErrorIs(t, cursor.CheckFilter(fingerprint), ErrTokenQueryMismatch)
+
+	cursor.Filter = fingerprint
+	token, err := cursor.PageToken()
+	require.NoError(t, err, "could not create next page token")
+
+	parsed, err := Parse(token)
+	require.NoError(t, err, "could not parse token")
+	require.NoError(t, parsed.CheckFilter(fingerprint), "filter should be preserved by the token")
+	require.ErrorIs(t, parsed.CheckFilter(Fingerprint("queued")), ErrTokenQueryMismatch)
+}
+
+func TestBind(t *testing.T) {
+	fingerprint := Fingerprint("finished", "example.com")
+
+	cursor, err := Bind(nil, fingerprint)
+	require.NoError(t, err, "a nil cursor should be bound to the query")
+	require.Equal(t, fingerprint, cursor.Filter)
+	require.Equal(t, DefaultPageSize, cursor.Size)
+
+	cursor, err = Bind(New(0, 0, 50), fingerprint)
+	require.NoError(t, err, "a cursor without a filter should be bound to the query")
+	require.Equal(t, fingerprint, cursor.Filter)
+	require.Equal(t, uint32(50), cursor.Size)
+
+	_, err = Bind(cursor, Fingerprint("queued"))
+	require.ErrorIs(t, err, ErrTokenQueryMismatch)
+
+	_, err = Bind(&Cursor{}, fingerprint)
+	require.ErrorIs(t, err, ErrMissingPageSize)
+}
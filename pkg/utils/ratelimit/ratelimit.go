@@ -0,0 +1,151 @@
+/*
+Package ratelimit implements in-memory sliding window limiters that lock out a key (such
+as an IP address or a username) once too many failures for the key have occurred
+within the window. A limiter with a limit of zero (or a nil limiter) never locks out
+any key, so limits can be disabled by configuration.
+*/
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter counts the failures of each key within a sliding window. When the number of
+// failures in the window reaches the limit, the key is locked out for the lockout
+// duration and its failures are forgotten. Limiters are safe for concurrent use.
+type Limiter struct {
+	sync.Mutex
+	limit   int
+	window  time.Duration
+	lockout time.Duration
+	keys    map[string]*record
+	pruned  time.Time
+}
+
+type record struct {
+	failures []time.Time
+	locked   time.Time
+}
+
+// New creates a limiter that locks out a key for the lockout duration once limit
+// failures have occurred within the window. If the lockout is not positive then keys
+// are locked out for the duration of the window. A limit of zero disables the limiter.
+func New(limit int, window, lockout time.Duration) *Limiter {
+	if lockout <= 0 {
+		lockout = window
+	}
+
+	return &Limiter{
+		limit:   limit,
+		window:  window,
+		lockout: lockout,
+		keys:    make(map[string]*record),
+		pruned:  time.Now(),
+	}
+}
+
+// Check returns how long the key remains locked out or zero if the key is allowed.
+func (l *Limiter) Check(key string) time.Duration {
+	if l.disabled() {
+		return 0
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	now := time.Now()
+	if rec, ok := l.keys[key]; ok && rec.locked.After(now) {
+		return rec.locked.Sub(now)
+	}
+	return 0
+}
+
+// Fail records a failure for the key, returning how long the key is locked out if the
+// failure caused the limit to be reached (or the key is already locked), otherwise zero.
+func (l *Limiter) Fail(key string) time.Duration {
+	if l.disabled() {
+		return 0
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	rec, ok := l.keys[key]
+	if !ok {
+		rec = &record{}
+		l.keys[key] = rec
+	}
+
+	if rec.locked.After(now) {
+		return rec.locked.Sub(now)
+	}
+
+	rec.failures = append(rec.expire(now.Add(-l.window)), now)
+	if len(rec.failures) >= l.limit {
+		rec.failures = nil
+		rec.locked = now.Add(l.lockout)
+		return l.lockout
+	}
+	return 0
+}
+
+// Reset forgets the failures of the key and lifts any lockout, e.g. after a success.
+func (l *Limiter) Reset(key string) {
+	if l.disabled() {
+		return
+	}
+
+	l.Lock()
+	delete(l.keys, key)
+	l.Unlock()
+}
+
+// Len returns the number of keys that are currently tracked by the limiter.
+func (l *Limiter) Len() int {
+	if l.disabled() {
+		return 0
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	return len(l.keys)
+}
+
+func (l *Limiter) disabled() bool {
+	return l == nil || l.limit <= 0
+}
+
+// Removes the keys that are not locked and have no failures in the window so that the
+// limiter does not grow without bound; at most one sweep is made per window. Must be
+// called while holding the lock.
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.pruned) < l.window {
+		return
+	}
+
+	cutoff := now.Add(-l.window)
+	for key, rec := range l.keys {
+		if rec.locked.After(now) {
+			continue
+		}
+
+		if rec.failures = rec.expire(cutoff); len(rec.failures) == 0 {
+			delete(l.keys, key)
+		}
+	}
+	l.pruned = now
+}
+
+// Returns the failures that occurred after the cutoff; failures are in time order.
+func (r *record) expire(cutoff time.Time) []time.Time {
+	for i, ts := range r.failures {
+		if ts.After(cutoff) {
+			return r.failures[i:]
+		}
+	}
+	return r.failures[:0]
+}
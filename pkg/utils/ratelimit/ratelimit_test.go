@@ -0,0 +1,63 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bbengfort/epistolary/pkg/utils/ratelimit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	limiter := ratelimit.New(3, time.Minute, time.Hour)
+	require.Zero(t, limiter.Check("jane"))
+
+	// Failures below the limit do not lock out the key
+	require.Zero(t, limiter.Fail("jane"))
+	require.Zero(t, limiter.Fail("jane"))
+	require.Zero(t, limiter.Check("jane"))
+
+	// Reaching the limit locks out the key but not other keys
+	require.Equal(t, time.Hour, limiter.Fail("jane"))
+	require.InDelta(t, time.Hour, limiter.Check("jane"), float64(time.Second))
+	require.InDelta(t, time.Hour, limiter.Fail("jane"), float64(time.Second))
+	require.Zero(t, limiter.Check("john"))
+
+	// Resetting the key lifts the lockout and forgets the failures
+	limiter.Reset("jane")
+	require.Zero(t, limiter.Check("jane"))
+	require.Zero(t, limiter.Fail("jane"))
+	require.Zero(t, limiter.Fail("jane"))
+	require.Equal(t, 1, limiter.Len(), "checking a key should not track it")
+}
+
+func TestLimiterWindow(t *testing.T) {
+	window := 50 * time.Millisecond
+	limiter := ratelimit.New(2, window, 0)
+
+	// Failures outside of the window are not counted
+	require.Zero(t, limiter.Fail("jane"))
+	time.Sleep(window + 10*time.Millisecond)
+	require.Zero(t, limiter.Fail("jane"))
+
+	// The lockout defaults to the window
+	require.Equal(t, window, limiter.Fail("jane"))
+	require.NotZero(t, limiter.Check("jane"))
+	time.Sleep(window + 10*time.Millisecond)
+	require.Zero(t, limiter.Check("jane"))
+
+	// Keys without failures in the window are pruned
+	require.Zero(t, limiter.Fail("john"))
+	require.Equal(t, 1, limiter.Len())
+}
+
+func TestLimiterDisabled(t *testing.T) {
+	for _, limiter := range []*ratelimit.Limiter{nil, ratelimit.New(0, time.Minute, time.Minute)} {
+		for i := 0; i < 10; i++ {
+			require.Zero(t, limiter.Fail("jane"))
+		}
+		require.Zero(t, limiter.Check("jane"))
+		require.Zero(t, limiter.Len())
+		limiter.Reset("jane")
+	}
+}
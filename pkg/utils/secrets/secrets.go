@@ -0,0 +1,33 @@
+/*
+Package secrets creates the random secrets that are given to users and clients as bearer
+credentials (API keys, feed tokens, verification tokens, OAuth client secrets, and so
+on) and hashes them for storage. Only the hash of a secret is stored so that a leaked
+database cannot be used to authenticate; since the secrets are long and random, a
+single round of SHA-256 is sufficient and allows the hash to be used for lookups.
+*/
+package secrets
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+
+	"github.com/jxskiss/base62"
+)
+
+// DefaultLength is the number of random bytes in a secret created by New.
+const DefaultLength = 32
+
+// New creates a base62 encoded secret from the specified number of random bytes.
+func New(length int) (_ string, err error) {
+	buf := make([]byte, length)
+	if _, err = rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base62.EncodeToString(buf), nil
+}
+
+// Hash the secret for storage and lookup.
+func Hash(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
@@ -0,0 +1,29 @@
+package secrets_test
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/bbengfort/epistolary/pkg/utils/secrets"
+	"github.com/jxskiss/base62"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	secret, err := secrets.New(secrets.DefaultLength)
+	require.NoError(t, err)
+
+	buf, err := base62.DecodeString(secret)
+	require.NoError(t, err, "secret should be base62 encoded")
+	require.Len(t, buf, secrets.DefaultLength)
+
+	other, err := secrets.New(secrets.DefaultLength)
+	require.NoError(t, err)
+	require.NotEqual(t, secret, other, "secrets should be random")
+}
+
+func TestHash(t *testing.T) {
+	sum := sha256.Sum256([]byte("supersecret"))
+	require.Equal(t, sum[:], secrets.Hash("supersecret"))
+	require.NotEqual(t, secrets.Hash("supersecret"), secrets.Hash("Supersecret"))
+}
@@ -137,6 +137,12 @@ func (e *Event) Int(key string, value int) *Event {
 	return e
 }
 
+func (e *Event) Int64(key string, value int64) *Event {
+	e.extra[key] = value
+	e.zero = e.zero.Int64(key, value)
+	return e
+}
+
 func (e *Event) Uint8(key string, value uint8) *Event {
 	e.extra[key] = value
 	e.zero = e.zero.Uint8(key, value)
@@ -0,0 +1,178 @@
+package urlnorm
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// MaxLength is the maximum length of a normalized link (the size of the link column).
+const MaxLength = 2000
+
+var (
+	ErrEmptyURL      = errors.New("a url is required")
+	ErrInvalidURL    = errors.New("could not parse url")
+	ErrInvalidScheme = errors.New("only http and https urls are supported")
+	ErrMissingHost   = errors.New("url must have a host")
+	ErrURLTooLong    = errors.New("url is longer than 2000 characters")
+)
+
+// Query parameters that are only used to track where a click came from; they never
+// change the content that is returned and are removed during normalization. Any
+// parameter with the utm_ prefix is also removed.
+var trackingParams = map[string]struct{}{
+	"fbclid":  {},
+	"gclid":   {},
+	"dclid":   {},
+	"msclkid": {},
+	"yclid":   {},
+	"igshid":  {},
+	"mc_cid":  {},
+	"mc_eid":  {},
+	"_ga":     {},
+	"_hsenc":  {},
+	"_hsmi":   {},
+}
+
+// Normalize cleans up a link so that it can be stored and fetched: whitespace is
+// trimmed, the https scheme is added if the link has no scheme, the scheme and host
+// are lowercased, default ports and fragments are removed, and tracking parameters
+// are stripped from the query. The scheme is otherwise preserved since some sites are
+// not available over https; use Key to compare links.
+func Normalize(link string) (_ string, err error) {
+	var u *url.URL
+	if u, err = parse(link); err != nil {
+		return "", err
+	}
+
+	if link = u.String(); len(link) > MaxLength {
+		return "", ErrURLTooLong
+	}
+	return link, nil
+}
+
+// Key returns a string that identifies the resource at the link so that variants of
+// the same link can be detected as duplicates. In addition to the normalization
+// performed by Normalize, the scheme and trailing slashes are removed and the query
+// parameters are sorted. The key is not a valid URL and should only be used to compare
+// links with each other.
+func Key(link string) (_ string, err error) {
+	var u *url.URL
+	if u, err = parse(link); err != nil {
+		return "", err
+	}
+
+	var key strings.Builder
+	key.WriteString(u.Host)
+
+	path := strings.TrimRight(u.EscapedPath(), "/")
+	key.WriteString(path)
+
+	if u.RawQuery != "" {
+		key.WriteString("?")
+		key.WriteString(u.Query().Encode())
+	}
+	return key.String(), nil
+}
+
+// SameSite returns true if the links have the same host or the same registrable domain
+// (the public suffix plus one label), e.g. www.example.com and blog.example.com but not
+// example.com and example.org or alice.github.io and bob.github.io. Links that cannot
+// be parsed are never on the same site.
+func SameSite(a, b string) bool {
+	ua, err := parse(a)
+	if err != nil {
+		return false
+	}
+
+	ub, err := parse(b)
+	if err != nil {
+		return false
+	}
+
+	hosta, hostb := ua.Hostname(), ub.Hostname()
+	if hosta == hostb {
+		return true
+	}
+
+	// IP addresses and hosts without a public suffix only match exactly
+	domaina, err := publicsuffix.EffectiveTLDPlusOne(hosta)
+	if err != nil {
+		return false
+	}
+
+	domainb, err := publicsuffix.EffectiveTLDPlusOne(hostb)
+	if err != nil {
+		return false
+	}
+	return domaina == domainb
+}
+
+func parse(link string) (u *url.URL, err error) {
+	if link = strings.TrimSpace(link); link == "" {
+		return nil, ErrEmptyURL
+	}
+
+	// Assume https if the user did not specify a scheme, e.g. example.com/article but
+	// not if the link has an opaque scheme such as mailto:jane@example.com.
+	if !strings.Contains(link, "://") {
+		if prefix, _, ok := strings.Cut(link, ":"); ok && !strings.HasPrefix(link, "//") && !strings.Contains(prefix, ".") && prefix != "localhost" {
+			return nil, ErrInvalidScheme
+		}
+		link = "https://" + strings.TrimPrefix(link, "//")
+	}
+
+	if u, err = url.Parse(link); err != nil {
+		return nil, ErrInvalidURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, ErrInvalidScheme
+	}
+
+	// Lowercase the host, removing the trailing dot of a fully qualified name and the
+	// port if it is the default port for the scheme.
+	host, port := u.Hostname(), u.Port()
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return nil, ErrMissingHost
+	}
+
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+
+	if port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]"
+	} else {
+		u.Host = host
+	}
+
+	// Fragments are only used by the client and do not identify a different resource
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	if u.Path == "" {
+		u.Path = "/"
+		u.RawPath = ""
+	}
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			if _, ok := trackingParams[strings.ToLower(key)]; ok || strings.HasPrefix(strings.ToLower(key), "utm_") {
+				query.Del(key)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+	u.ForceQuery = false
+
+	return u, nil
+}
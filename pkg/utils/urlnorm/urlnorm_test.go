@@ -0,0 +1,118 @@
+package urlnorm_test
+
+import (
+	"testing"
+
+	"github.com/bbengfort/epistolary/pkg/utils/urlnorm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		link     string
+		expected string
+		err      error
+	}{
+		{"https://example.com/article", "https://example.com/article", nil},
+		{"  https://example.com/article  ", "https://example.com/article", nil},
+		{"example.com/article", "https://example.com/article", nil},
+		{"//example.com/article", "https://example.com/article", nil},
+		{"HTTP://Example.COM/Article", "http://example.com/Article", nil},
+		{"https://example.com", "https://example.com/", nil},
+		{"https://example.com.:443/article", "https://example.com/article", nil},
+		{"http://example.com:80/article", "http://example.com/article", nil},
+		{"http://example.com:8080/article", "http://example.com:8080/article", nil},
+		{"https://example.com/article#comments", "https://example.com/article", nil},
+		{"https://example.com/article?", "https://example.com/article", nil},
+		{"https://example.com/article?utm_source=feed&utm_medium=rss", "https://example.com/article", nil},
+		{"https://example.com/article?id=42&fbclid=abc&UTM_Campaign=x", "https://example.com/article?id=42", nil},
+		{"https://example.com/article/", "https://example.com/article/", nil},
+		{"", "", urlnorm.ErrEmptyURL},
+		{"ftp://example.com/file", "", urlnorm.ErrInvalidScheme},
+		{"https:///article", "", urlnorm.ErrMissingHost},
+		{"https://exa mple.com/%zz", "", urlnorm.ErrInvalidURL},
+	}
+
+	for _, tc := range testCases {
+		actual, err := urlnorm.Normalize(tc.link)
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err, "expected error for %q", tc.link)
+			continue
+		}
+
+		require.NoError(t, err, "could not normalize %q", tc.link)
+		require.Equal(t, tc.expected, actual, "unexpected normalization of %q", tc.link)
+	}
+}
+
+func TestKey(t *testing.T) {
+	// All of these links should be considered duplicates of each other
+	duplicates := []string{
+		"https://example.com/posts/hello",
+		"http://example.com/posts/hello",
+		"https://example.com/posts/hello/",
+		"HTTPS://EXAMPLE.COM/posts/hello",
+		"https://example.com:443/posts/hello#intro",
+		"https://example.com/posts/hello?utm_source=twitter&utm_medium=social",
+		"example.com/posts/hello",
+	}
+
+	expected, err := urlnorm.Key(duplicates[0])
+	require.NoError(t, err)
+	require.Equal(t, "example.com/posts/hello", expected)
+
+	for _, link := range duplicates[1:] {
+		key, err := urlnorm.Key(link)
+		require.NoError(t, err)
+		require.Equal(t, expected, key, "expected %q to be a duplicate", link)
+	}
+
+	// Query parameters are sorted so their order does not matter
+	a, err := urlnorm.Key("https://example.com/search?q=hello&page=2")
+	require.NoError(t, err)
+	b, err := urlnorm.Key("https://example.com/search?page=2&q=hello")
+	require.NoError(t, err)
+	require.Equal(t, a, b)
+
+	// These links are not duplicates of the first link
+	distinct := []string{
+		"https://www.example.com/posts/hello",
+		"https://example.com/posts/Hello",
+		"https://example.com/posts/hello?page=2",
+		"https://example.com:8443/posts/hello",
+	}
+
+	for _, link := range distinct {
+		key, err := urlnorm.Key(link)
+		require.NoError(t, err)
+		require.NotEqual(t, expected, key, "expected %q to be distinct", link)
+	}
+
+	_, err = urlnorm.Key("mailto:jane@example.com")
+	require.ErrorIs(t, err, urlnorm.ErrInvalidScheme)
+}
+
+func TestSameSite(t *testing.T) {
+	testCases := []struct {
+		link      string
+		canonical string
+		expected  bool
+	}{
+		{"https://example.com/article", "https://example.com/article?id=42", true},
+		{"https://example.com/article", "http://EXAMPLE.com/amp/article", true},
+		{"https://www.example.com/article", "https://example.com/article", true},
+		{"https://blog.example.co.uk/article", "https://www.example.co.uk/article", true},
+		{"http://127.0.0.1:8080/article", "http://127.0.0.1/article", true},
+		{"https://example.com/article", "https://attacker.com/article", false},
+		{"https://example.com/article", "https://example.com.attacker.com/article", false},
+		{"https://example.co.uk/article", "https://attacker.co.uk/article", false},
+		{"https://alice.github.io/article", "https://bob.github.io/article", false},
+		{"http://127.0.0.1/article", "http://127.0.0.2/article", false},
+		{"https://example.com/article", "mailto:jane@example.com", false},
+		{"https://example.com/article", "", false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, urlnorm.SameSite(tc.link, tc.canonical), "unexpected result for %q and %q", tc.link, tc.canonical)
+	}
+}